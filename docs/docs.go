@@ -15,6 +15,118 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/domains/{domain}/diff": {
+            "get": {
+                "description": "Findings ajoutés, supprimés ou modifiés entre deux scans d'un domaine\nSans paramètre : dernier scan comparé au précédent. Sans from : to comparé à son précédent",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "history"
+                ],
+                "summary": "Différences entre deux scans",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Domaine",
+                        "name": "domain",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du scan de départ (défaut : scan précédant to)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du scan d'arrivée (défaut : dernier scan)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/diff.Diff"
+                        }
+                    },
+                    "400": {
+                        "description": "scan d'un autre domaine",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "scan introuvable ou historique insuffisant",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "erreur serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/domains/{domain}/scans": {
+            "get": {
+                "description": "Scans enregistrés pour un domaine, du plus récent au plus ancien (paginé)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "history"
+                ],
+                "summary": "Historique d'un domaine",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Domaine",
+                        "name": "domain",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nombre de scans par page (défaut 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nombre de scans à sauter",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/store.ScanPage"
+                        }
+                    },
+                    "400": {
+                        "description": "pagination invalide",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "erreur serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/health": {
             "get": {
                 "description": "Vérifie que le serveur est en ligne",
@@ -43,7 +155,7 @@ const docTemplate = `{
         },
         "/scan/all": {
             "get": {
-                "description": "Lance les 5 scanners en parallèle via goroutines",
+                "description": "Lance tous les scanners enregistrés en parallèle (pool de workers) et attend leurs résultats\nPour un scan long, préférer POST /scans (asynchrone)\nOptions par scanner préfixées par son nom : ?ssl.ports=443,8443\u0026dns.types=MX",
                 "produces": [
                     "application/json"
                 ],
@@ -58,6 +170,12 @@ const docTemplate = `{
                         "name": "domain",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Deadline du scan (ex: 30s, 2m)",
+                        "name": "timeout",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -71,7 +189,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "paramètre 'domain' requis",
+                        "description": "paramètre 'domain' requis ou option invalide",
                         "schema": {
                             "type": "string"
                         }
@@ -81,20 +199,26 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "503": {
+                        "description": "file d'attente pleine",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/scan/dns": {
+        "/scan/all/stream": {
             "get": {
-                "description": "Analyse les records DNS du domaine (A, AAAA, MX, NS, TXT)",
+                "description": "Lance tous les scanners et diffuse chaque résultat dès qu'il arrive (Server-Sent Events)\nMême paramètres que /scan/all. Le scan est annulé si le client se déconnecte.",
                 "produces": [
-                    "application/json"
+                    "text/event-stream"
                 ],
                 "tags": [
                     "scanner"
                 ],
-                "summary": "Scan DNS",
+                "summary": "All Scan en flux (SSE)",
                 "parameters": [
                     {
                         "type": "string",
@@ -102,23 +226,29 @@ const docTemplate = `{
                         "name": "domain",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Deadline du scan (ex: 30s, 2m)",
+                        "name": "timeout",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.ScanResult"
+                            "$ref": "#/definitions/jobs.Event"
                         }
                     },
                     "400": {
-                        "description": "paramètre 'domain' requis",
+                        "description": "paramètre 'domain' requis ou option invalide",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "erreur serveur",
+                    "503": {
+                        "description": "file d'attente pleine",
                         "schema": {
                             "type": "string"
                         }
@@ -126,23 +256,36 @@ const docTemplate = `{
                 }
             }
         },
-        "/scan/header": {
+        "/scan/{scanner}": {
             "get": {
-                "description": "Vérifie les headers de sécurité (HSTS, CSP, X-Frame-Options, X-Content-Type-Options)",
+                "description": "Lance un scanner du registre (voir GET /scanners)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "scanner"
                 ],
-                "summary": "Scan Headers HTTP",
+                "summary": "Scan",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nom du scanner",
+                        "name": "scanner",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "description": "Domaine à scanner",
                         "name": "domain",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Deadline du scan (ex: 30s, 2m)",
+                        "name": "timeout",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -153,7 +296,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "paramètre 'domain' requis",
+                        "description": "paramètre 'domain' requis ou option invalide",
                         "schema": {
                             "type": "string"
                         }
@@ -163,44 +306,84 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "503": {
+                        "description": "file d'attente pleine",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/scan/sensitive": {
+        "/scanners": {
             "get": {
-                "description": "Détecte les fichiers sensibles exposés (.env, .git/config, wp-config.php, etc.)",
+                "description": "Retourne les scanners enregistrés avec leur version et le schéma de leurs options",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "scanner"
                 ],
-                "summary": "Scan fichiers sensibles",
+                "summary": "Liste des scanners",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/scanner.Descriptor"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "erreur serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/scans": {
+            "post": {
+                "description": "Crée un job de scan et retourne son ID immédiatement (202)\nSuivre l'avancement avec GET /scans/{id}, annuler avec DELETE /scans/{id}",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scans"
+                ],
+                "summary": "Créer un scan asynchrone",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Domaine à scanner",
-                        "name": "domain",
-                        "in": "query",
-                        "required": true
+                        "description": "Domaine, scanners, options par scanner et deadline",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateScanRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/api.ScanResult"
+                            "$ref": "#/definitions/api.ScanCreated"
                         }
                     },
                     "400": {
-                        "description": "paramètre 'domain' requis",
+                        "description": "requête invalide",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "erreur serveur",
+                    "503": {
+                        "description": "file d'attente pleine",
                         "schema": {
                             "type": "string"
                         }
@@ -208,22 +391,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/scan/ssl": {
+        "/scans/{id}": {
             "get": {
-                "description": "Analyse le certificat TLS du domaine (émetteur, expiration, validité)",
+                "description": "Statut global, progression et résultat de chaque scanner du job\nLes scans terminés restent consultables dans l'historique",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "scanner"
+                    "scans"
                 ],
-                "summary": "Scan SSL/TLS",
+                "summary": "État d'un scan",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Domaine à scanner",
-                        "name": "domain",
-                        "in": "query",
+                        "description": "ID du scan",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -231,17 +414,44 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.ScanResult"
+                            "$ref": "#/definitions/jobs.Snapshot"
                         }
                     },
-                    "400": {
-                        "description": "paramètre 'domain' requis",
+                    "404": {
+                        "description": "scan introuvable",
                         "schema": {
                             "type": "string"
                         }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Annule le job : les scanners en cours s'arrêtent, ceux en attente ne démarrent pas",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scans"
+                ],
+                "summary": "Annuler un scan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID du scan",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/jobs.Snapshot"
+                        }
                     },
-                    "500": {
-                        "description": "erreur serveur",
+                    "404": {
+                        "description": "scan introuvable",
                         "schema": {
                             "type": "string"
                         }
@@ -249,22 +459,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/scan/subdomain": {
+        "/scans/{id}/events": {
             "get": {
-                "description": "Énumère les sous-domaines via Certificate Transparency (crt.sh)",
+                "description": "Diffuse en Server-Sent Events la progression du job : scanner.started, finding (dès que le scanner le trouve),\nscanner.finished, scanner.failed puis job.finished. Heartbeat toutes les 15s.\nLe header Last-Event-ID permet de reprendre après une coupure.",
                 "produces": [
-                    "application/json"
+                    "text/event-stream"
                 ],
                 "tags": [
-                    "scanner"
+                    "scans"
                 ],
-                "summary": "Scan sous-domaines",
+                "summary": "Flux d'un scan (SSE)",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Domaine à scanner",
-                        "name": "domain",
-                        "in": "query",
+                        "description": "ID du scan",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -272,45 +482,1335 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.ScanResult"
+                            "$ref": "#/definitions/jobs.Event"
                         }
                     },
-                    "400": {
-                        "description": "paramètre 'domain' requis",
+                    "404": {
+                        "description": "scan introuvable",
                         "schema": {
                             "type": "string"
                         }
+                    }
+                }
+            }
+        },
+        "/schedules": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "Lister les planifications",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/schedule.Schedule"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Scan récurrent d'un domaine, sur expression cron (\"0 3 * * *\", \"@daily\") ou intervalle (\"6h\")\njitter : décalage aléatoire ajouté à chaque occurrence. missed : catch_up (défaut) ou skip",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "Créer une planification",
+                "parameters": [
+                    {
+                        "description": "Domaine, scanners, options et récurrence",
+                        "name": "schedule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schedule.Schedule"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/schedule.Schedule"
+                        }
                     },
-                    "500": {
-                        "description": "erreur serveur",
+                    "400": {
+                        "description": "planification invalide",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "api.HealthResult": {
-            "type": "object",
-            "properties": {
-                "status": {
-                    "type": "string"
-                }
-            }
         },
-        "api.ScanResult": {
-            "type": "object",
-            "properties": {
-                "domain": {
-                    "type": "string"
-                },
-                "result": {
-                    "type": "string"
-                },
-                "scanner": {
-                    "type": "string"
+        "/schedules/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "Détail d'une planification",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la planification",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schedule.Schedule"
+                        }
+                    },
+                    "404": {
+                        "description": "planification introuvable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Remplace la configuration (mettre \"paused\": true pour suspendre) — la prochaine occurrence est recalculée",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "Modifier une planification",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la planification",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Nouvelle configuration",
+                        "name": "schedule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schedule.Schedule"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schedule.Schedule"
+                        }
+                    },
+                    "400": {
+                        "description": "planification invalide",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "planification introuvable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Les scans déjà lancés et leur historique sont conservés",
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "Supprimer une planification",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la planification",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "planification introuvable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "description": "Les secrets ne sont jamais renvoyés après la création",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Lister les webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/notify.Webhook"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "erreur serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Reçoit un POST JSON signé (HMAC-SHA256) à chaque scan qui remonte des findings nouveaux ou aggravés\nFiltres : min_severity (défaut low), scanners, domains (sous-domaines compris)\nLe secret (généré s'il est absent) n'est renvoyé que dans cette réponse",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Créer un webhook",
+                "parameters": [
+                    {
+                        "description": "URL, secret et filtres",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/notify.Webhook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/notify.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "webhook invalide",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Détail d'un webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID du webhook",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/notify.Webhook"
+                        }
+                    },
+                    "404": {
+                        "description": "webhook introuvable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Remplace la configuration (mettre \"paused\": true pour suspendre) — secret absent = secret conservé",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Modifier un webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID du webhook",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Nouvelle configuration",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/notify.Webhook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/notify.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "webhook invalide",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "webhook introuvable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Supprime aussi son journal de livraisons",
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Supprimer un webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID du webhook",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "webhook introuvable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "Livraisons d'un webhook, de la plus récente à la plus ancienne (paginé)\nChaque livraison garde son payload, son statut, son nombre de tentatives et la dernière réponse",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Journal des livraisons",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID du webhook",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nombre de livraisons par page (défaut 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nombre de livraisons à sauter",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/notify.DeliveryPage"
+                        }
+                    },
+                    "400": {
+                        "description": "pagination invalide",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "webhook introuvable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{delivery}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Détail d'une livraison",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID du webhook",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la livraison",
+                        "name": "delivery",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/notify.Delivery"
+                        }
+                    },
+                    "404": {
+                        "description": "webhook ou livraison introuvable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{delivery}/replay": {
+            "post": {
+                "description": "Renvoie le même payload dans une nouvelle livraison (replay_of pointe vers l'originale)\nUtile après une panne du récepteur : la livraison est signée avec le secret actuel du webhook",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Rejouer une livraison",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID du webhook",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la livraison à rejouer",
+                        "name": "delivery",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/notify.Delivery"
+                        }
+                    },
+                    "404": {
+                        "description": "webhook ou livraison introuvable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.CreateScanRequest": {
+            "type": "object",
+            "properties": {
+                "domain": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "object"
+                },
+                "scanners": {
+                    "description": "Sous-ensemble de scanners à lancer (ex: [\"dns\", \"ssl\"])",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timeout": {
+                    "description": "Deadline du scan (ex: \"30s\")",
+                    "type": "string"
+                }
+            }
+        },
+        "api.HealthResult": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ScanCreated": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "Identifiant du job",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Toujours \"queued\" à la création",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/jobs.Status"
+                        }
+                    ]
+                },
+                "url": {
+                    "description": "Route de suivi : GET /scans/{id}",
+                    "type": "string"
+                }
+            }
+        },
+        "api.ScanResult": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "Struct propre à chaque scanner (DNSRecords, CertificateInfo...)"
+                },
+                "domain": {
+                    "description": "Domaine scanné",
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Message d'erreur si le scanner a échoué (/scan/all)",
+                    "type": "string"
+                },
+                "findings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/scanner.Finding"
+                    }
+                },
+                "partial": {
+                    "description": "true si le scan a été interrompu (timeout, annulation)",
+                    "type": "boolean"
+                },
+                "scanner": {
+                    "description": "Nom du scanner (dns, ssl, header...)",
+                    "type": "string"
+                }
+            }
+        },
+        "diff.Change": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "description": "nil pour une suppression",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/scanner.Finding"
+                        }
+                    ]
+                },
+                "asset": {
+                    "description": "Élément concerné (Finding.Asset)",
+                    "type": "string"
+                },
+                "before": {
+                    "description": "nil pour un ajout",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/scanner.Finding"
+                        }
+                    ]
+                },
+                "id": {
+                    "description": "Identifiant de la vérification (Finding.ID)",
+                    "type": "string"
+                },
+                "kind": {
+                    "$ref": "#/definitions/diff.Kind"
+                },
+                "scanner": {
+                    "type": "string"
+                },
+                "worsened": {
+                    "description": "Nouveau finding non informatif, ou sévérité en hausse",
+                    "type": "boolean"
+                }
+            }
+        },
+        "diff.Diff": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/diff.Change"
+                    }
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "from": {
+                    "description": "ID du scan de départ (le plus ancien)",
+                    "type": "string"
+                },
+                "skipped": {
+                    "description": "Skipped liste les scanners non comparés : absents ou sans résultat dans l'un des deux scans\nSans ça, un scanner en échec ferait apparaître tous ses findings comme supprimés",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "summary": {
+                    "$ref": "#/definitions/diff.Summary"
+                },
+                "to": {
+                    "description": "ID du scan d'arrivée",
+                    "type": "string"
+                }
+            }
+        },
+        "diff.Kind": {
+            "type": "string",
+            "enum": [
+                "added",
+                "removed",
+                "changed"
+            ],
+            "x-enum-comments": {
+                "KindAdded": "Finding absent du scan de départ (nouveau sous-domaine, .env exposé...)",
+                "KindChanged": "Même vérification, même asset, mais sévérité ou preuve différente",
+                "KindRemoved": "Finding disparu (header supprimé, record DNS retiré...)"
+            },
+            "x-enum-descriptions": [
+                "Finding absent du scan de départ (nouveau sous-domaine, .env exposé...)",
+                "Finding disparu (header supprimé, record DNS retiré...)",
+                "Même vérification, même asset, mais sévérité ou preuve différente"
+            ],
+            "x-enum-varnames": [
+                "KindAdded",
+                "KindRemoved",
+                "KindChanged"
+            ]
+        },
+        "diff.Summary": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "integer"
+                },
+                "changed": {
+                    "type": "integer"
+                },
+                "removed": {
+                    "type": "integer"
+                },
+                "worsened": {
+                    "type": "integer"
+                }
+            }
+        },
+        "jobs.Event": {
+            "type": "object",
+            "properties": {
+                "domain": {
+                    "type": "string"
+                },
+                "finding": {
+                    "description": "Événement \"finding\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/scanner.Finding"
+                        }
+                    ]
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "run": {
+                    "description": "Événements scanner.finished / scanner.failed",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/jobs.Run"
+                        }
+                    ]
+                },
+                "scanner": {
+                    "type": "string"
+                },
+                "seq": {
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "Événement job.finished : statut final",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/jobs.Status"
+                        }
+                    ]
+                },
+                "time": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/jobs.EventType"
+                }
+            }
+        },
+        "jobs.EventType": {
+            "type": "string",
+            "enum": [
+                "scanner.started",
+                "finding",
+                "scanner.finished",
+                "scanner.failed",
+                "job.finished"
+            ],
+            "x-enum-comments": {
+                "EventFinding": "Un finding remonté par le scanner",
+                "EventJobFinished": "Dernier événement du job",
+                "EventScannerFailed": "Le scanner a échoué ou a été annulé",
+                "EventScannerFinished": "Le scanner a terminé (résultat éventuellement partiel)",
+                "EventScannerStarted": "Un worker a démarré le scanner"
+            },
+            "x-enum-descriptions": [
+                "Un worker a démarré le scanner",
+                "Un finding remonté par le scanner",
+                "Le scanner a terminé (résultat éventuellement partiel)",
+                "Le scanner a échoué ou a été annulé",
+                "Dernier événement du job"
+            ],
+            "x-enum-varnames": [
+                "EventScannerStarted",
+                "EventFinding",
+                "EventScannerFinished",
+                "EventScannerFailed",
+                "EventJobFinished"
+            ]
+        },
+        "jobs.Progress": {
+            "type": "object",
+            "properties": {
+                "done": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "jobs.Run": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "result": {
+                    "description": "nil tant que le scanner n'a pas fini",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/scanner.Result"
+                        }
+                    ]
+                },
+                "scanner": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/jobs.Status"
+                }
+            }
+        },
+        "jobs.Snapshot": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "progress": {
+                    "$ref": "#/definitions/jobs.Progress"
+                },
+                "scanners": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/jobs.Run"
+                    }
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/jobs.Status"
+                }
+            }
+        },
+        "jobs.Status": {
+            "type": "string",
+            "enum": [
+                "queued",
+                "running",
+                "done",
+                "failed",
+                "canceled"
+            ],
+            "x-enum-comments": {
+                "StatusCanceled": "Annulé par DELETE /scans/{id} ou déconnexion du client",
+                "StatusDone": "Terminé (éventuellement avec des résultats partiels)",
+                "StatusFailed": "Tous les scanners ont échoué",
+                "StatusQueued": "En attente d'un worker",
+                "StatusRunning": "Au moins un scanner en cours"
+            },
+            "x-enum-descriptions": [
+                "En attente d'un worker",
+                "Au moins un scanner en cours",
+                "Terminé (éventuellement avec des résultats partiels)",
+                "Tous les scanners ont échoué",
+                "Annulé par DELETE /scans/{id} ou déconnexion du client"
+            ],
+            "x-enum-varnames": [
+                "StatusQueued",
+                "StatusRunning",
+                "StatusDone",
+                "StatusFailed",
+                "StatusCanceled"
+            ]
+        },
+        "notify.Delivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Erreur de la dernière tentative",
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "description": "Corps envoyé, identique à chaque tentative",
+                    "type": "object"
+                },
+                "replay_of": {
+                    "description": "Livraison rejouée (POST .../replay)",
+                    "type": "string"
+                },
+                "response_code": {
+                    "description": "Status HTTP de la dernière tentative",
+                    "type": "integer"
+                },
+                "scan_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/notify.DeliveryStatus"
+                },
+                "webhook_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "notify.DeliveryPage": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/notify.Delivery"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "notify.DeliveryStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "succeeded",
+                "failed"
+            ],
+            "x-enum-comments": {
+                "DeliveryFailed": "Tentatives épuisées ou erreur définitive (4xx)",
+                "DeliveryPending": "En cours ou en attente d'une nouvelle tentative",
+                "DeliverySucceeded": "Réponse 2xx reçue"
+            },
+            "x-enum-descriptions": [
+                "En cours ou en attente d'une nouvelle tentative",
+                "Réponse 2xx reçue",
+                "Tentatives épuisées ou erreur définitive (4xx)"
+            ],
+            "x-enum-varnames": [
+                "DeliveryPending",
+                "DeliverySucceeded",
+                "DeliveryFailed"
+            ]
+        },
+        "notify.Webhook": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "domains": {
+                    "description": "Domaines concernés, sous-domaines compris",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "min_severity": {
+                    "description": "Filtres — vides = pas de filtre",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/scanner.Severity"
+                        }
+                    ]
+                },
+                "name": {
+                    "type": "string"
+                },
+                "paused": {
+                    "type": "boolean"
+                },
+                "scanners": {
+                    "description": "Scanners concernés (ex: [\"sensitive\", \"ssl\"])",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "description": "Secret signe les livraisons (HMAC-SHA256) — généré s'il est absent, renvoyé uniquement à la création",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "scanner.Descriptor": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "Ce que le scanner analyse",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/scanner.Option"
+                    }
+                },
+                "title": {
+                    "description": "Libellé court (ex: \"SSL/TLS\")",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version du scanner — incrémentée quand ses findings changent",
+                    "type": "string"
+                }
+            }
+        },
+        "scanner.Finding": {
+            "type": "object",
+            "properties": {
+                "asset": {
+                    "description": "Élément concerné (domaine, IP, URL, sous-domaine...)",
+                    "type": "string"
+                },
+                "category": {
+                    "description": "Famille du constat (dns, tls, headers, exposure...)",
+                    "type": "string"
+                },
+                "evidence": {
+                    "description": "Preuve brute (valeur du header, record, status HTTP...)",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Identifiant de la vérification (ex: \"dns.record.mx\")",
+                    "type": "string"
+                },
+                "remediation": {
+                    "description": "Action corrective suggérée (vide pour les findings info)",
+                    "type": "string"
+                },
+                "severity": {
+                    "description": "info, low, medium, high, critical",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/scanner.Severity"
+                        }
+                    ]
+                },
+                "title": {
+                    "description": "Libellé court lisible par un humain",
+                    "type": "string"
+                }
+            }
+        },
+        "scanner.Option": {
+            "type": "object",
+            "properties": {
+                "default": {
+                    "description": "Valeur utilisée si le client ne fournit rien"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "enum": {
+                    "description": "Valeurs autorisées (string et []string)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "max": {
+                    "description": "Borne haute incluse (int et []int)",
+                    "type": "integer"
+                },
+                "min": {
+                    "description": "Borne basse incluse (int et []int)",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/scanner.OptionType"
+                }
+            }
+        },
+        "scanner.OptionType": {
+            "type": "string",
+            "enum": [
+                "string",
+                "int",
+                "bool",
+                "[]string",
+                "[]int"
+            ],
+            "x-enum-comments": {
+                "OptionBool": "Ex: true",
+                "OptionInt": "Ex: 443",
+                "OptionInts": "Ex: [443, 8443] ou \"443,8443\" en query param",
+                "OptionString": "Ex: \"/admin\"",
+                "OptionStrings": "Ex: [\"A\", \"MX\"] ou \"A,MX\" en query param"
+            },
+            "x-enum-descriptions": [
+                "Ex: \"/admin\"",
+                "Ex: 443",
+                "Ex: true",
+                "Ex: [\"A\", \"MX\"] ou \"A,MX\" en query param",
+                "Ex: [443, 8443] ou \"443,8443\" en query param"
+            ],
+            "x-enum-varnames": [
+                "OptionString",
+                "OptionInt",
+                "OptionBool",
+                "OptionStrings",
+                "OptionInts"
+            ]
+        },
+        "scanner.Result": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "Struct propre à chaque scanner (DNSRecords, CertificateInfo...)"
+                },
+                "findings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/scanner.Finding"
+                    }
+                },
+                "partial": {
+                    "description": "true si le scan a été interrompu (timeout, annulation)",
+                    "type": "boolean"
+                }
+            }
+        },
+        "scanner.Severity": {
+            "type": "string",
+            "enum": [
+                "info",
+                "low",
+                "medium",
+                "high",
+                "critical"
+            ],
+            "x-enum-comments": {
+                "SeverityCritical": "Fuite de secrets ou compromission probable",
+                "SeverityHigh": "Exposition réelle à corriger rapidement",
+                "SeverityInfo": "Information neutre (record DNS, émetteur du certificat...)",
+                "SeverityLow": "Bonne pratique manquante sans impact direct",
+                "SeverityMedium": "Faiblesse exploitable dans certaines conditions"
+            },
+            "x-enum-descriptions": [
+                "Information neutre (record DNS, émetteur du certificat...)",
+                "Bonne pratique manquante sans impact direct",
+                "Faiblesse exploitable dans certaines conditions",
+                "Exposition réelle à corriger rapidement",
+                "Fuite de secrets ou compromission probable"
+            ],
+            "x-enum-varnames": [
+                "SeverityInfo",
+                "SeverityLow",
+                "SeverityMedium",
+                "SeverityHigh",
+                "SeverityCritical"
+            ]
+        },
+        "schedule.MissedPolicy": {
+            "type": "string",
+            "enum": [
+                "catch_up",
+                "skip"
+            ],
+            "x-enum-comments": {
+                "MissedCatchUp": "Un seul scan de rattrapage au démarrage, quel que soit le nombre d'occurrences manquées",
+                "MissedSkip": "Les occurrences manquées sont ignorées, on attend la suivante"
+            },
+            "x-enum-descriptions": [
+                "Un seul scan de rattrapage au démarrage, quel que soit le nombre d'occurrences manquées",
+                "Les occurrences manquées sont ignorées, on attend la suivante"
+            ],
+            "x-enum-varnames": [
+                "MissedCatchUp",
+                "MissedSkip"
+            ]
+        },
+        "schedule.Schedule": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "cron": {
+                    "description": "Expression cron (ex: \"0 3 * * *\") — exclusif avec interval",
+                    "type": "string"
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "interval": {
+                    "description": "Intervalle fixe (ex: \"6h\") — exclusif avec cron",
+                    "type": "string"
+                },
+                "jitter": {
+                    "description": "Décalage aléatoire ajouté à chaque occurrence (ex: \"5m\")",
+                    "type": "string"
+                },
+                "last_error": {
+                    "description": "Erreur du dernier lancement (file pleine...)",
+                    "type": "string"
+                },
+                "last_job_id": {
+                    "description": "Job du dernier scan : GET /scans/{id}",
+                    "type": "string"
+                },
+                "last_run_at": {
+                    "type": "string"
+                },
+                "missed": {
+                    "description": "catch_up (défaut) ou skip",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/schedule.MissedPolicy"
+                        }
+                    ]
+                },
+                "name": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "description": "Prochaine occurrence, jitter compris (nil si en pause)",
+                    "type": "string"
+                },
+                "options": {
+                    "description": "Options par scanner, comme POST /scans",
+                    "type": "object"
+                },
+                "paused": {
+                    "description": "true = planification suspendue",
+                    "type": "boolean"
+                },
+                "scanners": {
+                    "description": "Vide = tous les scanners du registre",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timeout": {
+                    "description": "Deadline de chaque scan (ex: \"2m\")",
+                    "type": "string"
+                }
+            }
+        },
+        "store.ScanPage": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "scans": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/store.ScanSummary"
+                    }
+                },
+                "total": {
+                    "description": "Nombre total de scans du domaine",
+                    "type": "integer"
+                }
+            }
+        },
+        "store.ScanSummary": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "findings": {
+                    "description": "Nombre total de findings",
+                    "type": "integer"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "max_severity": {
+                    "description": "Sévérité la plus haute du scan",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/scanner.Severity"
+                        }
+                    ]
+                },
+                "scanners": {
+                    "description": "Nombre de scanners lancés",
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/jobs.Status"
                 }
             }
         }
@@ -5,17 +5,38 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/daviani/go__001/internal/jobs"
 	"github.com/daviani/go__001/internal/scanner"
 )
 
 // makeScanHandler — closure qui retourne un handler HTTP pour un scanner donné
-// Évite la duplication de code : le même pattern gère les 5 routes /scan/*
-// name et s sont "capturés" par la closure et accessibles à chaque requête
-func makeScanHandler(name string, s scanner.Scanner) http.HandlerFunc {
+// Évite la duplication de code : le même pattern gère toutes les routes /scan/<scanner>
+// sc est "capturé" par la closure et accessible à chaque requête
+// Les annotations ci-dessous servent de gabarit : swaggerDoc les duplique pour chaque scanner du registre
+//
+// @Summary     Scan
+// @Description Lance un scanner du registre (voir GET /scanners)
+// @Tags        scanner
+// @Produce     json
+// @Param       scanner path string true "Nom du scanner"
+// @Param       domain query string true "Domaine à scanner"
+// @Param       timeout query string false "Deadline du scan (ex: 30s, 2m)"
+// @Success     200 {object} ScanResult
+// @Failure     400 {string} string "paramètre 'domain' requis ou option invalide"
+// @Failure     500 {string} string "erreur serveur"
+// @Failure     503 {string} string "file d'attente pleine"
+// @Router      /scan/{scanner} [get]
+func (s *Server) makeScanHandler(sc scanner.Scanner) http.HandlerFunc {
+	name := sc.Name()
 	return func(w http.ResponseWriter, r *http.Request) {
-		// r.URL.Query().Get("domain") extrait le query param "domain" de l'URL
-		// Équivalent Express : req.query.domain
-		domain := r.URL.Query().Get("domain")
+		// readScanRequest lit le domaine et les options depuis les query params (GET)
+		// ou le corps JSON (POST) — équivalent Express : req.query / req.body
+		req, err := readScanRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		domain := req.domain
 		// Validation : si le domain est vide, retourne une erreur 400 (Bad Request)
 		// 400 = erreur client ("tu as mal appelé l'API")
 		// 500 = erreur serveur ("le serveur a planté")
@@ -24,21 +45,41 @@ func makeScanHandler(name string, s scanner.Scanner) http.HandlerFunc {
 			return
 		}
 
-		// Lance le scan — peut échouer si le domaine est invalide ou injoignable
-		result, err := s.Scan(domain)
+		// Options validées contre le schéma publié par le scanner (type, enum, bornes)
+		opts, err := req.optionsFor(sc)
 		if err != nil {
-			log.Println(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		timeout, err := s.scanTimeout(req.timeout)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Lance le scan sur le pool de workers et attend son résultat
+		// Peut échouer si le domaine est invalide ou injoignable
+		snap, ok := s.runSync(w, r, jobs.Request{
+			Domain:   domain,
+			Scanners: []scanner.Scanner{sc},
+			Options:  map[string]scanner.Options{name: opts},
+			Timeout:  timeout,
+		})
+		if !ok {
+			return
+		}
+
+		// Un scan interrompu par la deadline n'est pas un échec : on renvoie le résultat partiel
+		run := snap.Runs[0]
+		if run.Status == jobs.StatusFailed {
 			http.Error(w, "erreur interne du serveur", http.StatusInternalServerError)
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
 		// Encode le résultat dans le struct ScanResult et l'envoie en JSON
-		err = json.NewEncoder(w).Encode(ScanResult{
-			Scanner: name,
-			Domain:  domain,
-			Result:  result,
-		})
+		err = json.NewEncoder(w).Encode(runResult(domain, run))
 		if err != nil {
 			log.Println(err)
 			http.Error(w, "erreur interne du serveur", http.StatusInternalServerError)
@@ -73,124 +114,86 @@ func handleHealth() http.HandlerFunc {
 	}
 }
 
-// @Summary     Scan DNS
-// @Description Analyse les records DNS du domaine (A, AAAA, MX, NS, TXT)
-// @Tags        scanner
-// @Produce     json
-// @Param       domain query string true "Domaine à scanner"
-// @Success     200 {object} ScanResult
-// @Failure     400 {string} string "paramètre 'domain' requis"
-// @Failure     500 {string} string "erreur serveur"
-// @Router      /scan/dns [get]
-func handleDNS() http.HandlerFunc {
-	return makeScanHandler("dns", scanner.DNSScanner{})
-}
-
-// @Summary     Scan SSL/TLS
-// @Description Analyse le certificat TLS du domaine (émetteur, expiration, validité)
+// @Summary     Liste des scanners
+// @Description Retourne les scanners enregistrés avec leur version et le schéma de leurs options
 // @Tags        scanner
 // @Produce     json
-// @Param       domain query string true "Domaine à scanner"
-// @Success     200 {object} ScanResult
-// @Failure     400 {string} string "paramètre 'domain' requis"
+// @Success     200 {array} scanner.Descriptor
 // @Failure     500 {string} string "erreur serveur"
-// @Router      /scan/ssl [get]
-func handleSSL() http.HandlerFunc {
-	return makeScanHandler("ssl", scanner.SSLScanner{})
-}
-
-// @Summary     Scan Headers HTTP
-// @Description Vérifie les headers de sécurité (HSTS, CSP, X-Frame-Options, X-Content-Type-Options)
-// @Tags        scanner
-// @Produce     json
-// @Param       domain query string true "Domaine à scanner"
-// @Success     200 {object} ScanResult
-// @Failure     400 {string} string "paramètre 'domain' requis"
-// @Failure     500 {string} string "erreur serveur"
-// @Router      /scan/header [get]
-func handleHeader() http.HandlerFunc {
-	return makeScanHandler("header", scanner.HeaderScanner{})
-}
-
-// @Summary     Scan fichiers sensibles
-// @Description Détecte les fichiers sensibles exposés (.env, .git/config, wp-config.php, etc.)
-// @Tags        scanner
-// @Produce     json
-// @Param       domain query string true "Domaine à scanner"
-// @Success     200 {object} ScanResult
-// @Failure     400 {string} string "paramètre 'domain' requis"
-// @Failure     500 {string} string "erreur serveur"
-// @Router      /scan/sensitive [get]
-func handleSensitive() http.HandlerFunc {
-	return makeScanHandler("sensitive", scanner.SensitiveScanner{})
-}
-
-// @Summary     Scan sous-domaines
-// @Description Énumère les sous-domaines via Certificate Transparency (crt.sh)
-// @Tags        scanner
-// @Produce     json
-// @Param       domain query string true "Domaine à scanner"
-// @Success     200 {object} ScanResult
-// @Failure     400 {string} string "paramètre 'domain' requis"
-// @Failure     500 {string} string "erreur serveur"
-// @Router      /scan/subdomain [get]
-func handleSubdomain() http.HandlerFunc {
-	return makeScanHandler("subdomain", scanner.SubdomainScanner{})
+// @Router      /scanners [get]
+func (s *Server) handleScanners() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		err := json.NewEncoder(w).Encode(s.Scanners.Descriptors())
+		if err != nil {
+			log.Println(err)
+			http.Error(w, "erreur interne du serveur", http.StatusInternalServerError)
+			return
+		}
+	}
 }
 
 // @Summary     All Scan
-// @Description Lance les 5 scanners en parallèle via goroutines
+// @Description Lance tous les scanners enregistrés en parallèle (pool de workers) et attend leurs résultats
+// @Description Pour un scan long, préférer POST /scans (asynchrone)
+// @Description Options par scanner préfixées par son nom : ?ssl.ports=443,8443&dns.types=MX
 // @Tags        scanner
 // @Produce     json
 // @Param 		domain query string true "Domaine à scanner"
+// @Param       timeout query string false "Deadline du scan (ex: 30s, 2m)"
 // @Success     200 {array} ScanResult
-// @Failure     400 {string} string "paramètre 'domain' requis"
+// @Failure     400 {string} string "paramètre 'domain' requis ou option invalide"
 // @Failure     500 {string} string "erreur serveur"
+// @Failure     503 {string} string "file d'attente pleine"
 // @Router      /scan/all [get]
 func (s *Server) handleAll() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		domain := r.URL.Query().Get("domain")
+		req, err := readScanRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		domain := req.domain
 
 		if domain == "" {
 			http.Error(w, "paramètre 'domain' requis", http.StatusBadRequest)
 			return
 		}
 
-		// Channel pour recevoir les résultats des goroutines
-		// Chaque goroutine y envoie un ScanResult quand elle a fini
-		ch := make(chan ScanResult)
-
-		// Lance une goroutine par scanner — exécution en parallèle
-		// sc est passé en paramètre pour éviter les problèmes de closure
-		for _, sc := range s.Scanners {
-			go func(sc scanner.Scanner) {
-				result, err := sc.Scan(domain)
-				// Dans une goroutine, on ne peut pas faire http.Error (pas accès à w)
-				// On met le message d'erreur dans Result à la place
-				if err != nil {
-					log.Println(err)
-					result = "erreur interne du serveur"
-				}
+		// Options par scanner : ?ssl.ports=443,8443 ou {"options": {"ssl": {"ports": [443]}}}
+		scanners := s.Scanners.All()
+		options, err := req.optionsForAll(scanners)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 
-				ch <- ScanResult{
-					Scanner: sc.Name(),
-					Domain:  domain,
-					Result:  result,
-				}
-			}(sc)
+		// Une seule deadline pour tout le scan : elle s'applique à tous les scanners
+		timeout, err := s.scanTimeout(req.timeout)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
 
-		// Collecte les résultats — <-ch bloque jusqu'à recevoir un résultat
-		// On itère autant de fois qu'il y a de scanners
-		var results []ScanResult
+		// Les scanners tournent sur le pool de workers borné (plus de goroutine par requête)
+		// Dans une goroutine, on ne pouvait pas faire http.Error : chaque échec est dans Run.Error
+		snap, ok := s.runSync(w, r, jobs.Request{
+			Domain:   domain,
+			Scanners: scanners,
+			Options:  options,
+			Timeout:  timeout,
+		})
+		if !ok {
+			return
+		}
 
-		for i := 0; i < len(s.Scanners); i++ {
-			result := <-ch
-			results = append(results, result)
+		results := make([]ScanResult, 0, len(snap.Runs))
+		for _, run := range snap.Runs {
+			results = append(results, runResult(domain, run))
 		}
 
 		w.Header().Set("Content-Type", "application/json")
-		err := json.NewEncoder(w).Encode(results)
+		err = json.NewEncoder(w).Encode(results)
 		if err != nil {
 			log.Println(err)
 			http.Error(w, "erreur interne du serveur", http.StatusInternalServerError)
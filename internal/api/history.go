@@ -0,0 +1,198 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/daviani/go__001/internal/diff"
+	"github.com/daviani/go__001/internal/jobs"
+	"github.com/daviani/go__001/internal/store"
+)
+
+// errNoPrevious — pas de scan antérieur à comparer
+var errNoPrevious = errors.New("historique insuffisant : au moins deux scans du domaine sont nécessaires")
+
+// errWrongDomain — un scan demandé dans from/to concerne un autre domaine
+var errWrongDomain = errors.New("le scan ne concerne pas ce domaine")
+
+// readPage lit ?limit= et ?offset= (entiers positifs) — absents = première page par défaut
+func readPage(r *http.Request) (store.Page, error) {
+	var page store.Page
+	// Slice et pas map : l'ordre de parcours d'une map est aléatoire, l'erreur renvoyée ne le serait plus
+	params := []struct {
+		name string
+		dest *int
+	}{{"limit", &page.Limit}, {"offset", &page.Offset}}
+	for _, p := range params {
+		raw := r.URL.Query().Get(p.name)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return store.Page{}, fmt.Errorf("paramètre '%s' invalide (entier positif attendu)", p.name)
+		}
+		*p.dest = n
+	}
+	return page, nil
+}
+
+// findScan cherche un scan en mémoire (en cours ou récent), puis dans l'historique
+// Un job purgé de la mémoire reste ainsi consultable tant qu'il est en base
+func (s *Server) findScan(r *http.Request, id string) (jobs.Snapshot, error) {
+	if job, ok := s.Jobs.Get(id); ok {
+		return job.Snapshot(), nil
+	}
+	return s.Store.GetScan(r.Context(), id)
+}
+
+// @Summary     Historique d'un domaine
+// @Description Scans enregistrés pour un domaine, du plus récent au plus ancien (paginé)
+// @Tags        history
+// @Produce     json
+// @Param       domain path string true "Domaine"
+// @Param       limit query int false "Nombre de scans par page (défaut 20, max 100)"
+// @Param       offset query int false "Nombre de scans à sauter"
+// @Success     200 {object} store.ScanPage
+// @Failure     400 {string} string "pagination invalide"
+// @Failure     500 {string} string "erreur serveur"
+// @Router      /domains/{domain}/scans [get]
+func (s *Server) handleDomainScans() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page, err := readPage(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		history, err := s.Store.ListScans(r.Context(), r.PathValue("domain"), page)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, "erreur interne du serveur", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(history); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// @Summary     État d'un scan
+// @Description Statut global, progression et résultat de chaque scanner du job
+// @Description Les scans terminés restent consultables dans l'historique
+// @Tags        scans
+// @Produce     json
+// @Param       id path string true "ID du scan"
+// @Success     200 {object} jobs.Snapshot
+// @Failure     404 {string} string "scan introuvable"
+// @Router      /scans/{id} [get]
+func (s *Server) handleGetScan() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// r.PathValue lit le segment {id} de la route "GET /scans/{id}" (Go 1.22+)
+		snap, err := s.findScan(r, r.PathValue("id"))
+		if errors.Is(err, store.ErrNotFound) {
+			http.Error(w, "scan introuvable", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Println(err)
+			http.Error(w, "erreur interne du serveur", http.StatusInternalServerError)
+			return
+		}
+		writeSnapshot(w, http.StatusOK, snap)
+	}
+}
+
+// @Summary     Différences entre deux scans
+// @Description Findings ajoutés, supprimés ou modifiés entre deux scans d'un domaine
+// @Description Sans paramètre : dernier scan comparé au précédent. Sans from : to comparé à son précédent
+// @Tags        history
+// @Produce     json
+// @Param       domain path string true "Domaine"
+// @Param       from query string false "ID du scan de départ (défaut : scan précédant to)"
+// @Param       to query string false "ID du scan d'arrivée (défaut : dernier scan)"
+// @Success     200 {object} diff.Diff
+// @Failure     400 {string} string "scan d'un autre domaine"
+// @Failure     404 {string} string "scan introuvable ou historique insuffisant"
+// @Failure     500 {string} string "erreur serveur"
+// @Router      /domains/{domain}/diff [get]
+func (s *Server) handleDomainDiff() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		domain := r.PathValue("domain")
+		query := r.URL.Query()
+
+		to, err := s.diffTarget(r, domain, query.Get("to"))
+		if err != nil {
+			writeHistoryError(w, err)
+			return
+		}
+
+		var from jobs.Snapshot
+		if id := query.Get("from"); id != "" {
+			from, err = s.scanOf(r, domain, id)
+		} else {
+			from, err = s.Store.PreviousScan(r.Context(), to)
+			if errors.Is(err, store.ErrNotFound) {
+				err = errNoPrevious
+			}
+		}
+		if err != nil {
+			writeHistoryError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(diff.Compare(from, to)); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// diffTarget retourne le scan d'arrivée du diff : celui demandé, ou le dernier scan du domaine
+func (s *Server) diffTarget(r *http.Request, domain, id string) (jobs.Snapshot, error) {
+	if id != "" {
+		return s.scanOf(r, domain, id)
+	}
+	latest, err := s.Store.ListScans(r.Context(), domain, store.Page{Limit: 1})
+	if err != nil {
+		return jobs.Snapshot{}, err
+	}
+	if len(latest.Scans) == 0 {
+		return jobs.Snapshot{}, store.ErrNotFound
+	}
+	return s.Store.GetScan(r.Context(), latest.Scans[0].ID)
+}
+
+// scanOf lit un scan de l'historique et vérifie qu'il concerne bien le domaine
+// Seuls les scans enregistrés (donc terminés) sont comparables
+func (s *Server) scanOf(r *http.Request, domain, id string) (jobs.Snapshot, error) {
+	snap, err := s.Store.GetScan(r.Context(), id)
+	if err != nil {
+		return jobs.Snapshot{}, err
+	}
+	if snap.Domain != domain {
+		return jobs.Snapshot{}, fmt.Errorf("%w : %s", errWrongDomain, id)
+	}
+	return snap, nil
+}
+
+// writeHistoryError traduit une erreur de lecture de l'historique en réponse HTTP
+func writeHistoryError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		http.Error(w, "scan introuvable", http.StatusNotFound)
+	case errors.Is(err, errNoPrevious):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, errWrongDomain):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		log.Println(err)
+		http.Error(w, "erreur interne du serveur", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daviani/go__001/internal/jobs"
+	"github.com/daviani/go__001/internal/store"
+)
+
+// openStore ouvre une base SQLite en mémoire, fermée à la fin du test
+func openStore(t *testing.T) *store.SQLite {
+	t.Helper()
+	db, err := store.OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// newHistoryServer crée un serveur adossé à une base en mémoire contenant n scans de example.com
+// Le scan i est créé i minutes après le premier : scan-<n-1> est le plus récent
+func newHistoryServer(t *testing.T, n int) *Server {
+	t.Helper()
+	db := openStore(t)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range n {
+		created := start.Add(time.Duration(i) * time.Minute)
+		snap := jobs.Snapshot{
+			ID:         fmt.Sprintf("scan-%d", i),
+			Domain:     "example.com",
+			Status:     jobs.StatusDone,
+			CreatedAt:  created,
+			FinishedAt: &created,
+			Runs:       []jobs.Run{{Scanner: "fake", Status: jobs.StatusDone}},
+		}
+		if err := db.SaveScan(context.Background(), snap); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := newTestServer(jobs.Config{Workers: 1}, fakeScanner{name: "fake"})
+	s.Store = db
+	return s
+}
+
+// TestReadPage — limit et offset lus depuis la query, absents = zéro (valeurs par défaut du store)
+func TestReadPage(t *testing.T) {
+	tests := []struct {
+		query string
+		want  store.Page
+		err   string
+	}{
+		{query: "", want: store.Page{}},
+		{query: "limit=5", want: store.Page{Limit: 5}},
+		{query: "limit=5&offset=10", want: store.Page{Limit: 5, Offset: 10}},
+		{query: "limit=abc", err: "'limit'"},
+		{query: "offset=-1", err: "'offset'"},
+		// Deux paramètres invalides : toujours limit d'abord
+		{query: "offset=x&limit=y", err: "'limit'"},
+	}
+
+	for _, tt := range tests {
+		page, err := readPage(httptest.NewRequest(http.MethodGet, "/domains/example.com/scans?"+tt.query, nil))
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%q: got %v, want erreur sur %s", tt.query, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || page != tt.want {
+			t.Errorf("%q: got %+v %v, want %+v", tt.query, page, err, tt.want)
+		}
+	}
+}
+
+// TestServer_DomainScans — historique paginé, du plus récent au plus ancien ; pagination invalide → 400
+func TestServer_DomainScans(t *testing.T) {
+	s := newHistoryServer(t, 3)
+
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/domains/example.com/scans?limit=1&offset=1", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d, want %d (%s)", rec.Code, http.StatusOK, rec.Body)
+	}
+	var page store.ScanPage
+	if err := json.NewDecoder(rec.Body).Decode(&page); err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 3 || page.Limit != 1 || page.Offset != 1 || len(page.Scans) != 1 || page.Scans[0].ID != "scan-1" {
+		t.Errorf("got %+v, want scan-1 seul sur 3", page)
+	}
+
+	for _, query := range []string{"limit=abc", "offset=-1"} {
+		rec := httptest.NewRecorder()
+		s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/domains/example.com/scans?"+query, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d, want %d", query, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+// TestServer_GetScan — un scan absent de la mémoire est relu depuis l'historique, un ID inconnu → 404
+func TestServer_GetScan(t *testing.T) {
+	s := newHistoryServer(t, 1)
+
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/scans/scan-0", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d, want %d (%s)", rec.Code, http.StatusOK, rec.Body)
+	}
+	var snap jobs.Snapshot
+	if err := json.NewDecoder(rec.Body).Decode(&snap); err != nil {
+		t.Fatal(err)
+	}
+	if snap.ID != "scan-0" || snap.Domain != "example.com" {
+		t.Errorf("got %s %s, want scan-0 example.com", snap.ID, snap.Domain)
+	}
+
+	rec = httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/scans/inconnu", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("got %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
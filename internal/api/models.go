@@ -1,11 +1,25 @@
 package api
 
-import "github.com/daviani/go__001/internal/scanner"
+import (
+	"time"
+
+	"github.com/daviani/go__001/internal/jobs"
+	"github.com/daviani/go__001/internal/notify"
+	"github.com/daviani/go__001/internal/scanner"
+	"github.com/daviani/go__001/internal/schedule"
+	"github.com/daviani/go__001/internal/store"
+)
 
 // Server contient la configuration du serveur HTTP et la liste des scanners disponibles
 type Server struct {
-	Port     int               // Port d'écoute (ex: 8082)
-	Scanners []scanner.Scanner // Slice des scanners — utilisée par handleAll pour les goroutines
+	Port        int                 // Port d'écoute (ex: 8082)
+	Scanners    *scanner.Registry   // Registre des scanners — routes /scan/*, /scanners et doc Swagger en découlent
+	Jobs        *jobs.Manager       // Pool de workers qui exécute tous les scans (synchrones et asynchrones)
+	ScanTimeout time.Duration       // Deadline d'un scan complet (surchargeable par ?timeout=)
+	Store       store.Store         // Historique des scans terminés (GET /domains/{domain}/scans)
+	Schedules   *schedule.Scheduler // Scans récurrents (CRUD /schedules)
+	Webhooks    *notify.Notifier    // Notifications des nouveaux findings (CRUD /webhooks)
+	Heartbeat   time.Duration       // Intervalle des heartbeats des flux SSE (0 = 15s)
 }
 
 // HealthResult — réponse JSON pour GET /health
@@ -14,8 +28,37 @@ type HealthResult struct {
 }
 
 // ScanResult — réponse JSON pour les routes /scan/*
+// scanner.Result est embarqué : ses champs (findings, data) apparaissent au même niveau en JSON
 type ScanResult struct {
-	Scanner string `json:"scanner"` // Nom du scanner (dns, ssl, header...)
-	Domain  string `json:"domain"`  // Domaine scanné
-	Result  string `json:"result"`  // Résultat du scan (texte brut)
+	Scanner        string `json:"scanner"` // Nom du scanner (dns, ssl, header...)
+	Domain         string `json:"domain"`  // Domaine scanné
+	scanner.Result        // Findings structurés + données brutes du scanner
+	Error          string `json:"error,omitempty"` // Message d'erreur si le scanner a échoué (/scan/all)
+}
+
+// ScanCreated — réponse JSON de POST /scans (202 Accepted)
+type ScanCreated struct {
+	ID     string      `json:"id"`     // Identifiant du job
+	Status jobs.Status `json:"status"` // Toujours "queued" à la création
+	URL    string      `json:"url"`    // Route de suivi : GET /scans/{id}
+}
+
+// newScanResult construit la réponse JSON d'un scanner
+// Findings n'est jamais nil → le client reçoit toujours un tableau ([] et pas null)
+func newScanResult(name, domain string, result scanner.Result) ScanResult {
+	if result.Findings == nil {
+		result.Findings = []scanner.Finding{}
+	}
+	return ScanResult{Scanner: name, Domain: domain, Result: result}
+}
+
+// runResult convertit l'exécution d'un scanner dans un job en réponse /scan/*
+func runResult(domain string, run jobs.Run) ScanResult {
+	var result scanner.Result
+	if run.Result != nil {
+		result = *run.Result
+	}
+	scanResult := newScanResult(run.Scanner, domain, result)
+	scanResult.Error = run.Error
+	return scanResult
 }
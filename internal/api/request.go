@@ -0,0 +1,125 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/daviani/go__001/internal/scanner"
+)
+
+// maxRequestBody — taille maximale du corps JSON d'une requête de scan (1 Mo)
+const maxRequestBody = 1 << 20
+
+// ScanRequest — corps JSON accepté en POST sur /scan/*
+// Sur /scan/{scanner} : Options = options du scanner ({"ports": [443, 8443]})
+// Sur /scan/all : Options = options par scanner ({"ssl": {"ports": [443]}, "dns": {...}})
+type ScanRequest struct {
+	Domain  string                     `json:"domain"`
+	Timeout string                     `json:"timeout,omitempty"` // Deadline du scan (ex: "30s")
+	Options map[string]json.RawMessage `json:"options,omitempty" swaggertype:"object"`
+}
+
+// reservedParams — query params qui ne sont pas des options de scanner
+var reservedParams = map[string]bool{"domain": true, "timeout": true}
+
+// scanRequest — requête de scan normalisée, qu'elle vienne de query params (GET) ou d'un JSON (POST)
+type scanRequest struct {
+	domain  string
+	timeout string
+	query   url.Values                 // Options en query params (GET)
+	options map[string]json.RawMessage // Options en JSON (POST)
+	isJSON  bool
+}
+
+// readScanRequest lit domain, timeout et options depuis la requête HTTP
+// GET → query params | POST → corps JSON (ScanRequest)
+func readScanRequest(r *http.Request) (scanRequest, error) {
+	if r.Method != http.MethodPost {
+		query := r.URL.Query()
+		req := scanRequest{
+			domain:  query.Get("domain"),
+			timeout: query.Get("timeout"),
+			query:   url.Values{},
+		}
+		for key, values := range query {
+			if !reservedParams[key] {
+				req.query[key] = values
+			}
+		}
+		return req, nil
+	}
+
+	// http.MaxBytesReader coupe la lecture au-delà de la limite → protège contre les gros payloads
+	var body ScanRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxRequestBody))
+	if err := decoder.Decode(&body); err != nil {
+		return scanRequest{}, errors.New("corps JSON invalide")
+	}
+	return scanRequest{
+		domain:  body.Domain,
+		timeout: body.Timeout,
+		options: body.Options,
+		isJSON:  true,
+	}, nil
+}
+
+// optionsFor valide les options d'un scanner lancé seul (/scan/{scanner})
+func (req scanRequest) optionsFor(sc scanner.Scanner) (scanner.Options, error) {
+	if req.isJSON {
+		raw, err := json.Marshal(req.options)
+		if err != nil {
+			return nil, err
+		}
+		return sc.Schema().ParseJSON(raw)
+	}
+	return sc.Schema().ParseQuery(req.query)
+}
+
+// optionsForAll valide les options de chaque scanner lancé par /scan/all
+// Query params préfixés par le nom du scanner : ?ssl.ports=443,8443&dns.types=MX
+// Un préfixe qui ne correspond à aucun scanner est refusé
+func (req scanRequest) optionsForAll(scanners []scanner.Scanner) (map[string]scanner.Options, error) {
+	byName := make(map[string]scanner.Scanner, len(scanners))
+	perScanner := make(map[string]url.Values)
+	for _, sc := range scanners {
+		byName[sc.Name()] = sc
+		perScanner[sc.Name()] = url.Values{}
+	}
+
+	if req.isJSON {
+		for name := range req.options {
+			if byName[name] == nil {
+				return nil, fmt.Errorf("scanner inconnu : %s", name)
+			}
+		}
+	} else {
+		for key, values := range req.query {
+			// strings.Cut("ssl.ports", ".") → "ssl", "ports", true
+			name, option, ok := strings.Cut(key, ".")
+			if !ok || byName[name] == nil {
+				return nil, fmt.Errorf("paramètre inconnu : %s (format attendu : scanner.option)", key)
+			}
+			perScanner[name][option] = values
+		}
+	}
+
+	all := make(map[string]scanner.Options, len(scanners))
+	for name, sc := range byName {
+		var opts scanner.Options
+		var err error
+		if req.isJSON {
+			opts, err = sc.Schema().ParseJSON(req.options[name])
+		} else {
+			opts, err = sc.Schema().ParseQuery(perScanner[name])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s : %w", name, err)
+		}
+		all[name] = opts
+	}
+	return all, nil
+}
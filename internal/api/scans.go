@@ -0,0 +1,163 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/daviani/go__001/internal/jobs"
+)
+
+// CreateScanRequest — corps JSON de POST /scans
+// Scanners vide = tous les scanners du registre
+type CreateScanRequest struct {
+	ScanRequest
+	Scanners []string `json:"scanners,omitempty"` // Sous-ensemble de scanners à lancer (ex: ["dns", "ssl"])
+}
+
+// submit place un job dans le pool et traduit les erreurs du gestionnaire en réponses HTTP
+// Retourne ok = false si une réponse d'erreur a déjà été écrite
+func (s *Server) submit(w http.ResponseWriter, req jobs.Request) (*jobs.Job, bool) {
+	job, err := s.Jobs.Submit(req)
+	if errors.Is(err, jobs.ErrQueueFull) {
+		// 503 + Retry-After : le client peut réessayer plus tard
+		w.Header().Set("Retry-After", "10")
+		http.Error(w, "file d'attente pleine, réessayer plus tard", http.StatusServiceUnavailable)
+		return nil, false
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	return job, true
+}
+
+// jobRequest construit et valide un job à partir d'une description JSON (POST /scans, planifications)
+// names vide = tous les scanners du registre ; options indexées par nom de scanner
+func (s *Server) jobRequest(domain string, names []string, options map[string]json.RawMessage, rawTimeout string) (jobs.Request, error) {
+	if domain == "" {
+		return jobs.Request{}, errors.New("paramètre 'domain' requis")
+	}
+
+	// Sélection des scanners : tous par défaut, sinon ceux demandés (nom inconnu → erreur)
+	// Un nom répété n'est lancé qu'une fois : un job ne dépasse jamais la taille du registre
+	scanners := s.Scanners.All()
+	if len(names) > 0 {
+		scanners = nil
+		seen := make(map[string]bool, len(names))
+		for _, name := range names {
+			sc, ok := s.Scanners.Get(name)
+			if !ok {
+				return jobs.Request{}, fmt.Errorf("scanner inconnu : %s", name)
+			}
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			scanners = append(scanners, sc)
+		}
+	}
+
+	req := scanRequest{domain: domain, timeout: rawTimeout, options: options, isJSON: true}
+	opts, err := req.optionsForAll(scanners)
+	if err != nil {
+		return jobs.Request{}, err
+	}
+	timeout, err := s.scanTimeout(rawTimeout)
+	if err != nil {
+		return jobs.Request{}, err
+	}
+
+	return jobs.Request{Domain: domain, Scanners: scanners, Options: opts, Timeout: timeout}, nil
+}
+
+// runSync lance un job et attend sa fin — utilisé par les routes synchrones /scan/*
+// Si le client se déconnecte avant la fin, le job est annulé (plus personne pour lire le résultat)
+func (s *Server) runSync(w http.ResponseWriter, r *http.Request, req jobs.Request) (jobs.Snapshot, bool) {
+	job, ok := s.submit(w, req)
+	if !ok {
+		return jobs.Snapshot{}, false
+	}
+
+	select {
+	case <-job.Done():
+		return job.Snapshot(), true
+	case <-r.Context().Done():
+		s.Jobs.Cancel(job.ID())
+		return jobs.Snapshot{}, false
+	}
+}
+
+// @Summary     Créer un scan asynchrone
+// @Description Crée un job de scan et retourne son ID immédiatement (202)
+// @Description Suivre l'avancement avec GET /scans/{id}, annuler avec DELETE /scans/{id}
+// @Tags        scans
+// @Accept      json
+// @Produce     json
+// @Param       request body CreateScanRequest true "Domaine, scanners, options par scanner et deadline"
+// @Success     202 {object} ScanCreated
+// @Failure     400 {string} string "requête invalide"
+// @Failure     503 {string} string "file d'attente pleine"
+// @Router      /scans [post]
+func (s *Server) handleCreateScan() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body CreateScanRequest
+		err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&body)
+		if err != nil {
+			http.Error(w, "corps JSON invalide", http.StatusBadRequest)
+			return
+		}
+
+		req, err := s.jobRequest(body.Domain, body.Scanners, body.Options, body.Timeout)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		job, ok := s.submit(w, req)
+		if !ok {
+			return
+		}
+
+		// 202 Accepted + Location : convention REST pour une ressource créée de façon asynchrone
+		url := "/scans/" + job.ID()
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", url)
+		w.WriteHeader(http.StatusAccepted)
+		err = json.NewEncoder(w).Encode(ScanCreated{ID: job.ID(), Status: jobs.StatusQueued, URL: url})
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// @Summary     Annuler un scan
+// @Description Annule le job : les scanners en cours s'arrêtent, ceux en attente ne démarrent pas
+// @Tags        scans
+// @Produce     json
+// @Param       id path string true "ID du scan"
+// @Success     202 {object} jobs.Snapshot
+// @Failure     404 {string} string "scan introuvable"
+// @Router      /scans/{id} [delete]
+func (s *Server) handleCancelScan() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		job, ok := s.Jobs.Cancel(r.PathValue("id"))
+		if !ok {
+			http.Error(w, "scan introuvable", http.StatusNotFound)
+			return
+		}
+		// 202 : l'annulation est demandée, les scanners en cours s'arrêtent de façon asynchrone
+		writeSnapshot(w, http.StatusAccepted, job.Snapshot())
+	}
+}
+
+// writeSnapshot encode l'état d'un job en JSON avec le status HTTP donné
+func writeSnapshot(w http.ResponseWriter, status int, snap jobs.Snapshot) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(snap); err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daviani/go__001/internal/jobs"
+	"github.com/daviani/go__001/internal/scanner"
+)
+
+// fakeScanner — scanner de test : retourne un finding, après fermeture de release si défini
+type fakeScanner struct {
+	name    string
+	release chan struct{}
+}
+
+func (f fakeScanner) Name() string           { return f.name }
+func (f fakeScanner) Info() scanner.Info     { return scanner.Info{Title: f.name} }
+func (f fakeScanner) Schema() scanner.Schema { return nil }
+
+func (f fakeScanner) Scan(ctx context.Context, domain string, opts scanner.Options) (scanner.Result, error) {
+	if f.release != nil {
+		select {
+		case <-f.release:
+		case <-ctx.Done():
+			return scanner.Result{}, ctx.Err()
+		}
+	}
+	return scanner.Result{Findings: []scanner.Finding{{ID: f.name + ".ok", Asset: domain}}}, nil
+}
+
+// newTestServer crée un serveur avec un petit gestionnaire de jobs et les scanners donnés
+func newTestServer(cfg jobs.Config, scanners ...scanner.Scanner) *Server {
+	return &Server{Scanners: scanner.NewRegistry(scanners...), Jobs: jobs.NewManager(cfg)}
+}
+
+// serve démarre un vrai serveur HTTP sur les routes de s (nécessaire pour lire un flux SSE en cours)
+func serve(t *testing.T, s *Server) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(s.routes())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// TestServer_CreateScan — POST /scans répond 202 avec l'URL du job dans Location
+func TestServer_CreateScan(t *testing.T) {
+	s := newTestServer(jobs.Config{Workers: 1}, fakeScanner{name: "fake"})
+
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/scans", strings.NewReader(`{"domain": "example.com"}`)))
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("got %d, want %d (%s)", rec.Code, http.StatusAccepted, rec.Body)
+	}
+	var created ScanCreated
+	if err := json.NewDecoder(rec.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+	if loc := rec.Header().Get("Location"); loc != "/scans/"+created.ID || created.URL != loc {
+		t.Errorf("got Location %q, url %q, want /scans/%s", loc, created.URL, created.ID)
+	}
+
+	job, ok := s.Jobs.Get(created.ID)
+	if !ok {
+		t.Fatalf("job %s introuvable", created.ID)
+	}
+	select {
+	case <-job.Done():
+	case <-time.After(time.Second):
+		t.Fatal("job not finished after 1s")
+	}
+	if snap := job.Snapshot(); snap.Domain != "example.com" || snap.Status != jobs.StatusDone {
+		t.Errorf("got %s %s, want example.com done", snap.Domain, snap.Status)
+	}
+}
+
+// TestServer_CreateScan_Invalid — corps illisible, domaine manquant ou scanner inconnu → 400
+func TestServer_CreateScan_Invalid(t *testing.T) {
+	s := newTestServer(jobs.Config{Workers: 1}, fakeScanner{name: "fake"})
+
+	for _, body := range []string{`{`, `{"scanners": ["fake"]}`, `{"domain": "example.com", "scanners": ["nope"]}`} {
+		rec := httptest.NewRecorder()
+		s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/scans", strings.NewReader(body)))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d, want %d", body, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+// TestServer_CreateScan_QueueFull — file pleine : 503 avec Retry-After
+func TestServer_CreateScan_QueueFull(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	slow := fakeScanner{name: "slow", release: release}
+	s := newTestServer(jobs.Config{Workers: 1, QueueSize: 1}, slow)
+
+	// 1er job : occupe le worker | 2e job : remplit la file | 3e job : refusé
+	if _, err := s.Jobs.Submit(jobs.Request{Domain: "a.com", Scanners: []scanner.Scanner{slow}}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, err := s.Jobs.Submit(jobs.Request{Domain: "b.com", Scanners: []scanner.Scanner{slow}}); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/scans", strings.NewReader(`{"domain": "c.com"}`)))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("got %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("Retry-After manquant")
+	}
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/daviani/go__001/internal/jobs"
+	"github.com/daviani/go__001/internal/schedule"
+)
+
+// LaunchSchedule lance le scan d'une planification (schedule.Launcher)
+// Même chemin que POST /scans : le job et son historique sont identiques à ceux d'un scan à la demande
+func (s *Server) LaunchSchedule(sch schedule.Schedule) (*jobs.Job, error) {
+	req, err := s.jobRequest(sch.Domain, sch.Scanners, sch.Options, sch.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	return s.Jobs.Submit(req)
+}
+
+// readSchedule décode le corps JSON d'une planification et valide ses scanners, options et timeout
+// La récurrence (cron, interval, jitter...) est validée par le scheduler
+func (s *Server) readSchedule(w http.ResponseWriter, r *http.Request) (schedule.Schedule, bool) {
+	var sch schedule.Schedule
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&sch); err != nil {
+		http.Error(w, "corps JSON invalide", http.StatusBadRequest)
+		return schedule.Schedule{}, false
+	}
+	if _, err := s.jobRequest(sch.Domain, sch.Scanners, sch.Options, sch.Timeout); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return schedule.Schedule{}, false
+	}
+	return sch, true
+}
+
+// writeJSON encode v en JSON avec le status HTTP donné (planifications, webhooks...)
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Println(err)
+	}
+}
+
+// writeScheduleError traduit une erreur du scheduler en réponse HTTP
+func writeScheduleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, schedule.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, schedule.ErrInvalid):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		log.Println(err)
+		http.Error(w, "erreur interne du serveur", http.StatusInternalServerError)
+	}
+}
+
+// @Summary     Lister les planifications
+// @Tags        schedules
+// @Produce     json
+// @Success     200 {array} schedule.Schedule
+// @Router      /schedules [get]
+func (s *Server) handleListSchedules() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, s.Schedules.List())
+	}
+}
+
+// @Summary     Créer une planification
+// @Description Scan récurrent d'un domaine, sur expression cron ("0 3 * * *", "@daily") ou intervalle ("6h")
+// @Description jitter : décalage aléatoire ajouté à chaque occurrence. missed : catch_up (défaut) ou skip
+// @Tags        schedules
+// @Accept      json
+// @Produce     json
+// @Param       schedule body schedule.Schedule true "Domaine, scanners, options et récurrence"
+// @Success     201 {object} schedule.Schedule
+// @Failure     400 {string} string "planification invalide"
+// @Router      /schedules [post]
+func (s *Server) handleCreateSchedule() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sch, ok := s.readSchedule(w, r)
+		if !ok {
+			return
+		}
+
+		created, err := s.Schedules.Create(r.Context(), sch)
+		if err != nil {
+			writeScheduleError(w, err)
+			return
+		}
+		w.Header().Set("Location", "/schedules/"+created.ID)
+		writeJSON(w, http.StatusCreated, created)
+	}
+}
+
+// @Summary     Détail d'une planification
+// @Tags        schedules
+// @Produce     json
+// @Param       id path string true "ID de la planification"
+// @Success     200 {object} schedule.Schedule
+// @Failure     404 {string} string "planification introuvable"
+// @Router      /schedules/{id} [get]
+func (s *Server) handleGetSchedule() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sch, err := s.Schedules.Get(r.PathValue("id"))
+		if err != nil {
+			writeScheduleError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, sch)
+	}
+}
+
+// @Summary     Modifier une planification
+// @Description Remplace la configuration (mettre "paused": true pour suspendre) — la prochaine occurrence est recalculée
+// @Tags        schedules
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "ID de la planification"
+// @Param       schedule body schedule.Schedule true "Nouvelle configuration"
+// @Success     200 {object} schedule.Schedule
+// @Failure     400 {string} string "planification invalide"
+// @Failure     404 {string} string "planification introuvable"
+// @Router      /schedules/{id} [put]
+func (s *Server) handleUpdateSchedule() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sch, ok := s.readSchedule(w, r)
+		if !ok {
+			return
+		}
+
+		updated, err := s.Schedules.Update(r.Context(), r.PathValue("id"), sch)
+		if err != nil {
+			writeScheduleError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, updated)
+	}
+}
+
+// @Summary     Supprimer une planification
+// @Description Les scans déjà lancés et leur historique sont conservés
+// @Tags        schedules
+// @Param       id path string true "ID de la planification"
+// @Success     204
+// @Failure     404 {string} string "planification introuvable"
+// @Router      /schedules/{id} [delete]
+func (s *Server) handleDeleteSchedule() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := s.Schedules.Delete(r.Context(), r.PathValue("id")); err != nil {
+			writeScheduleError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
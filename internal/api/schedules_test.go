@@ -0,0 +1,113 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/daviani/go__001/internal/jobs"
+	"github.com/daviani/go__001/internal/schedule"
+)
+
+// newScheduleServer crée un serveur dont les planifications sont enregistrées dans une base en mémoire
+// La boucle du scheduler n'est pas démarrée : seules les routes CRUD sont testées
+func newScheduleServer(t *testing.T) *Server {
+	t.Helper()
+	s := newTestServer(jobs.Config{Workers: 1}, fakeScanner{name: "fake"})
+	s.Schedules = schedule.New(openStore(t), s.LaunchSchedule, schedule.Config{})
+	return s
+}
+
+// do envoie une requête aux routes du serveur et retourne la réponse enregistrée
+func do(s *Server, method, target, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return rec
+}
+
+// TestServer_Schedules — création, lecture, modification puis suppression d'une planification
+func TestServer_Schedules(t *testing.T) {
+	s := newScheduleServer(t)
+
+	rec := do(s, http.MethodPost, "/schedules", `{"domain": "example.com", "cron": "0 3 * * *", "jitter": "5m", "missed": "skip"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create: got %d, want %d (%s)", rec.Code, http.StatusCreated, rec.Body)
+	}
+	var created schedule.Schedule
+	if err := json.NewDecoder(rec.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Header().Get("Location") != "/schedules/"+created.ID || created.NextRunAt == nil || created.Missed != schedule.MissedSkip {
+		t.Errorf("got %+v (Location %q), want a scheduled run at /schedules/%s", created, rec.Header().Get("Location"), created.ID)
+	}
+
+	var list []schedule.Schedule
+	if rec := do(s, http.MethodGet, "/schedules", ""); rec.Code != http.StatusOK || json.NewDecoder(rec.Body).Decode(&list) != nil || len(list) != 1 {
+		t.Errorf("list: got %d %+v, want the created schedule", rec.Code, list)
+	}
+	if rec := do(s, http.MethodGet, "/schedules/"+created.ID, ""); rec.Code != http.StatusOK {
+		t.Errorf("get: got %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	rec = do(s, http.MethodPut, "/schedules/"+created.ID, `{"domain": "example.com", "interval": "6h", "paused": true}`)
+	var updated schedule.Schedule
+	if rec.Code != http.StatusOK || json.NewDecoder(rec.Body).Decode(&updated) != nil {
+		t.Fatalf("update: got %d, want %d (%s)", rec.Code, http.StatusOK, rec.Body)
+	}
+	if updated.ID != created.ID || updated.Interval != "6h" || updated.Cron != "" || updated.NextRunAt != nil {
+		t.Errorf("update: got %+v, want a paused 6h schedule", updated)
+	}
+
+	if rec := do(s, http.MethodDelete, "/schedules/"+created.ID, ""); rec.Code != http.StatusNoContent {
+		t.Errorf("delete: got %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if rec := do(s, http.MethodGet, "/schedules/"+created.ID, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("get after delete: got %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+// TestServer_CreateSchedule_Invalid — récurrence, jitter, politique de rattrapage ou scan invalides → 400
+func TestServer_CreateSchedule_Invalid(t *testing.T) {
+	s := newScheduleServer(t)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"JSON illisible", `{`},
+		{"domaine manquant", `{"cron": "0 3 * * *"}`},
+		{"sans récurrence", `{"domain": "example.com"}`},
+		{"cron invalide", `{"domain": "example.com", "cron": "61 * * * *"}`},
+		{"cron et interval", `{"domain": "example.com", "cron": "@daily", "interval": "6h"}`},
+		{"interval trop court", `{"domain": "example.com", "interval": "10s"}`},
+		{"jitter illisible", `{"domain": "example.com", "cron": "@daily", "jitter": "bientôt"}`},
+		{"jitter négatif", `{"domain": "example.com", "cron": "@daily", "jitter": "-5m"}`},
+		{"missed inconnu", `{"domain": "example.com", "cron": "@daily", "missed": "later"}`},
+		{"scanner inconnu", `{"domain": "example.com", "cron": "@daily", "scanners": ["nope"]}`},
+		{"timeout invalide", `{"domain": "example.com", "cron": "@daily", "timeout": "1h"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := do(s, http.MethodPost, "/schedules", tt.body); rec.Code != http.StatusBadRequest {
+				t.Errorf("got %d, want %d (%s)", rec.Code, http.StatusBadRequest, rec.Body)
+			}
+		})
+	}
+	if len(s.Schedules.List()) != 0 {
+		t.Error("invalid schedule saved")
+	}
+}
+
+// TestServer_Schedules_NotFound — planification inconnue → 404 en lecture, modification et suppression
+func TestServer_Schedules_NotFound(t *testing.T) {
+	s := newScheduleServer(t)
+
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodDelete} {
+		rec := do(s, method, "/schedules/inconnu", `{"domain": "example.com", "cron": "@daily"}`)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%s: got %d, want %d", method, rec.Code, http.StatusNotFound)
+		}
+	}
+}
@@ -1,49 +1,100 @@
 package api
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"net"
 	"net/http"
+	"time"
 
 	_ "github.com/daviani/go__001/docs"          // Blank import — enregistre la spec Swagger au démarrage via init()
 	httpSwagger "github.com/swaggo/http-swagger" // Middleware servant l'interface Swagger UI
+	"github.com/swaggo/swag"
 )
 
-// Start enregistre les routes HTTP et démarre le serveur
-// Toutes les routes sont enregistrées AVANT ListenAndServe (qui est bloquant)
-func (s *Server) Start() {
+// routes construit le routeur HTTP du serveur
+// Les routes /scan/<nom> sont dérivées du registre : un scanner enregistré = une route
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
 
 	// Swagger UI — documentation interactive de l'API sur /swagger/index.html
-	http.HandleFunc("/swagger/", httpSwagger.Handler())
+	// La spec est celle de swaggerDoc : spec statique + une route par scanner du registre
+	mux.HandleFunc("/swagger/", httpSwagger.Handler(httpSwagger.InstanceName(swaggerInstance)))
 
-	http.HandleFunc("/health", handleHealth())
+	mux.HandleFunc("/health", handleHealth())
 
-	http.HandleFunc("/scan/dns", handleDNS())
+	mux.HandleFunc("/scanners", s.handleScanners())
 
-	http.HandleFunc("/scan/ssl", handleSSL())
+	for _, sc := range s.Scanners.All() {
+		mux.HandleFunc("/scan/"+sc.Name(), s.makeScanHandler(sc))
+	}
 
-	http.HandleFunc("/scan/header", handleHeader())
+	mux.HandleFunc("/scan/all", s.handleAll())
+	mux.HandleFunc("GET /scan/all/stream", s.handleAllStream())
 
-	http.HandleFunc("/scan/sensitive", handleSensitive())
+	// Scans asynchrones — patterns "MÉTHODE /chemin/{param}" du ServeMux (Go 1.22+)
+	mux.HandleFunc("POST /scans", s.handleCreateScan())
+	mux.HandleFunc("GET /scans/{id}", s.handleGetScan())
+	mux.HandleFunc("DELETE /scans/{id}", s.handleCancelScan())
+	mux.HandleFunc("GET /scans/{id}/events", s.handleScanEvents())
 
-	http.HandleFunc("/scan/subdomain", handleSubdomain())
+	// Historique persistant
+	mux.HandleFunc("GET /domains/{domain}/scans", s.handleDomainScans())
+	mux.HandleFunc("GET /domains/{domain}/diff", s.handleDomainDiff())
 
-	http.HandleFunc("/scan/all", s.handleAll())
+	// Scans planifiés
+	mux.HandleFunc("GET /schedules", s.handleListSchedules())
+	mux.HandleFunc("POST /schedules", s.handleCreateSchedule())
+	mux.HandleFunc("GET /schedules/{id}", s.handleGetSchedule())
+	mux.HandleFunc("PUT /schedules/{id}", s.handleUpdateSchedule())
+	mux.HandleFunc("DELETE /schedules/{id}", s.handleDeleteSchedule())
 
-	// Démarrage du serveur — ListenAndServe est bloquant
-	// Le programme reste ici et écoute les connexions entrantes
-	fmt.Println("Serveur démarré sur le port :", s.Port)
+	// Webhooks et journal de leurs livraisons
+	mux.HandleFunc("GET /webhooks", s.handleListWebhooks())
+	mux.HandleFunc("POST /webhooks", s.handleCreateWebhook())
+	mux.HandleFunc("GET /webhooks/{id}", s.handleGetWebhook())
+	mux.HandleFunc("PUT /webhooks/{id}", s.handleUpdateWebhook())
+	mux.HandleFunc("DELETE /webhooks/{id}", s.handleDeleteWebhook())
+	mux.HandleFunc("GET /webhooks/{id}/deliveries", s.handleWebhookDeliveries())
+	mux.HandleFunc("GET /webhooks/{id}/deliveries/{delivery}", s.handleGetDelivery())
+	mux.HandleFunc("POST /webhooks/{id}/deliveries/{delivery}/replay", s.handleReplayDelivery())
+
+	return corsMiddleware(mux)
+}
+
+// shutdownTimeout — délai laissé aux requêtes en cours pour se terminer à l'arrêt du serveur
+const shutdownTimeout = 10 * time.Second
+
+// Start enregistre les routes HTTP et démarre le serveur
+// Toutes les routes sont enregistrées AVANT ListenAndServe (qui est bloquant)
+// Start rend la main quand ctx est annulé (SIGINT/SIGTERM dans main) : arrêt propre, puis retour
+// → les defer de main (fermeture de la base) s'exécutent
+func (s *Server) Start(ctx context.Context) error {
+	// Enregistre la doc dynamique auprès de swag (lue par httpSwagger via InstanceName)
+	swag.Register(swaggerInstance, swaggerDoc{registry: s.Scanners})
 
 	// fmt.Sprintf(":%d", s.Port) convertit l'int en string formatée (ex: 8082 → ":8082")
-	// nil = utilise le routeur par défaut (celui où on a enregistré les routes avec HandleFunc)
-	err := http.ListenAndServe(
-		fmt.Sprintf(":%d", s.Port),
-		corsMiddleware(http.DefaultServeMux),
-	)
-
-	// Si ListenAndServe retourne, c'est qu'il y a eu une erreur (ex: port déjà pris)
-	// log.Fatal affiche l'erreur ET arrête le programme (exit code 1)
-	if err != nil {
-		log.Fatal(err)
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%d", s.Port),
+		Handler: s.routes(),
+		// Le contexte des requêtes dérive de ctx : les flux SSE se ferment à l'arrêt au lieu de le bloquer
+		BaseContext: func(net.Listener) context.Context { return ctx },
 	}
+
+	// ListenAndServe est bloquant : il tourne dans une goroutine pendant qu'on attend le signal d'arrêt
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+	fmt.Println("Serveur démarré sur le port :", s.Port)
+
+	select {
+	case err := <-errc:
+		// ListenAndServe a rendu la main tout seul : erreur de démarrage (ex: port déjà pris)
+		return err
+	case <-ctx.Done():
+	}
+
+	// Shutdown arrête d'accepter les connexions puis attend la fin des requêtes en cours
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
 }
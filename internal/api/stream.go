@@ -0,0 +1,162 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/daviani/go__001/internal/jobs"
+)
+
+// defaultHeartbeat — fréquence des commentaires SSE envoyés quand rien ne se passe (si Server.Heartbeat est vide)
+// Sans trafic, les proxys (nginx, Cloudflare...) ferment la connexion au bout de 60-100s
+const defaultHeartbeat = 15 * time.Second
+
+// streamJob diffuse les événements d'un job en Server-Sent Events jusqu'à sa fin
+// Format SSE : "id: <seq>\nevent: <type>\ndata: <json>\n\n" — lu nativement par EventSource côté navigateur
+// from = premier événement à envoyer (reprise après Last-Event-ID)
+// Retourne false si le client s'est déconnecté avant la fin du job
+func (s *Server) streamJob(w http.ResponseWriter, r *http.Request, job *jobs.Job, from int) bool {
+	// http.ResponseController donne accès à Flush() même derrière un middleware
+	rc := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// Désactive le buffering de nginx : sans ça, les événements arrivent tous à la fin
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	_ = rc.Flush()
+
+	interval := s.Heartbeat
+	if interval <= 0 {
+		interval = defaultHeartbeat
+	}
+	heartbeat := time.NewTicker(interval)
+	defer heartbeat.Stop()
+
+	seq := from
+	for {
+		events, wait, finished := job.EventsSince(seq)
+		for _, e := range events {
+			if err := writeEvent(w, e); err != nil {
+				log.Println(err)
+				return false
+			}
+			seq = e.Seq + 1
+		}
+		if err := rc.Flush(); err != nil {
+			return false
+		}
+
+		// Job terminé et journal entièrement envoyé → fin du flux
+		if finished {
+			return true
+		}
+
+		select {
+		case <-wait:
+			// Nouvel événement disponible → on reboucle
+		case <-heartbeat.C:
+			// Ligne commençant par ":" = commentaire SSE, ignoré par EventSource
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return false
+			}
+			_ = rc.Flush()
+		case <-r.Context().Done():
+			return false
+		}
+	}
+}
+
+// writeEvent écrit un événement au format SSE
+func writeEvent(w http.ResponseWriter, e jobs.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.Seq, e.Type, data)
+	return err
+}
+
+// @Summary     Flux d'un scan (SSE)
+// @Description Diffuse en Server-Sent Events la progression du job : scanner.started, finding (dès que le scanner le trouve),
+// @Description scanner.finished, scanner.failed puis job.finished. Heartbeat toutes les 15s.
+// @Description Le header Last-Event-ID permet de reprendre après une coupure.
+// @Tags        scans
+// @Produce     text/event-stream
+// @Param       id path string true "ID du scan"
+// @Success     200 {object} jobs.Event
+// @Failure     404 {string} string "scan introuvable"
+// @Router      /scans/{id}/events [get]
+func (s *Server) handleScanEvents() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		job, ok := s.Jobs.Get(r.PathValue("id"))
+		if !ok {
+			http.Error(w, "scan introuvable", http.StatusNotFound)
+			return
+		}
+
+		// EventSource renvoie automatiquement le dernier id reçu lors d'une reconnexion
+		from := 0
+		if last, err := strconv.Atoi(r.Header.Get("Last-Event-ID")); err == nil {
+			from = last + 1
+		}
+		s.streamJob(w, r, job, from)
+	}
+}
+
+// @Summary     All Scan en flux (SSE)
+// @Description Lance tous les scanners et diffuse chaque résultat dès qu'il arrive (Server-Sent Events)
+// @Description Même paramètres que /scan/all. Le scan est annulé si le client se déconnecte.
+// @Tags        scanner
+// @Produce     text/event-stream
+// @Param       domain query string true "Domaine à scanner"
+// @Param       timeout query string false "Deadline du scan (ex: 30s, 2m)"
+// @Success     200 {object} jobs.Event
+// @Failure     400 {string} string "paramètre 'domain' requis ou option invalide"
+// @Failure     503 {string} string "file d'attente pleine"
+// @Router      /scan/all/stream [get]
+func (s *Server) handleAllStream() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, err := readScanRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.domain == "" {
+			http.Error(w, "paramètre 'domain' requis", http.StatusBadRequest)
+			return
+		}
+
+		scanners := s.Scanners.All()
+		options, err := req.optionsForAll(scanners)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		timeout, err := s.scanTimeout(req.timeout)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		job, ok := s.submit(w, jobs.Request{
+			Domain:   req.domain,
+			Scanners: scanners,
+			Options:  options,
+			Timeout:  timeout,
+		})
+		if !ok {
+			return
+		}
+
+		// Client parti avant la fin → plus personne pour lire : on libère les workers
+		if !s.streamJob(w, r, job, 0) {
+			s.Jobs.Cancel(job.ID())
+		}
+	}
+}
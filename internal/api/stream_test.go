@@ -0,0 +1,160 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daviani/go__001/internal/jobs"
+	"github.com/daviani/go__001/internal/scanner"
+)
+
+// openStream ouvre un flux SSE (échec du test si la réponse n'est pas un 200 text/event-stream)
+// Le client coupe la lecture au bout de 2s : un flux qui ne se ferme pas fait échouer le test au lieu de le bloquer
+func openStream(t *testing.T, req *http.Request) *bufio.Scanner {
+	t.Helper()
+	client := &http.Client{Timeout: 2 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("got %d %s, want 200 text/event-stream", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	return bufio.NewScanner(resp.Body)
+}
+
+// readIDs lit le flux jusqu'à sa fermeture et retourne les ids et types des événements reçus
+func readIDs(t *testing.T, lines *bufio.Scanner) (ids []int, types []string) {
+	t.Helper()
+	for lines.Scan() {
+		if v, ok := strings.CutPrefix(lines.Text(), "id: "); ok {
+			id, err := strconv.Atoi(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, id)
+		}
+		if v, ok := strings.CutPrefix(lines.Text(), "event: "); ok {
+			types = append(types, v)
+		}
+	}
+	if err := lines.Err(); err != nil {
+		t.Fatalf("flux non terminé : %v", err)
+	}
+	return ids, types
+}
+
+// TestServer_ScanEvents_Heartbeat — un job silencieux reçoit des heartbeats, puis le flux se ferme après job.finished
+func TestServer_ScanEvents_Heartbeat(t *testing.T) {
+	release := make(chan struct{})
+	slow := fakeScanner{name: "slow", release: release}
+	s := newTestServer(jobs.Config{Workers: 1}, slow)
+	s.Heartbeat = 10 * time.Millisecond
+	ts := serve(t, s)
+
+	job, err := s.Jobs.Submit(jobs.Request{Domain: "a.com", Scanners: []scanner.Scanner{slow}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/scans/"+job.ID()+"/events", nil)
+	lines := openStream(t, req)
+
+	for lines.Scan() && lines.Text() != ": heartbeat" {
+	}
+	if lines.Text() != ": heartbeat" {
+		t.Fatalf("heartbeat non reçu (%v)", lines.Err())
+	}
+
+	close(release)
+	_, types := readIDs(t, lines)
+	if len(types) == 0 || types[len(types)-1] != string(jobs.EventJobFinished) {
+		t.Errorf("got %v, want flux terminé par %s", types, jobs.EventJobFinished)
+	}
+}
+
+// TestServer_ScanEvents_LastEventID — une reconnexion reprend après le dernier événement reçu
+func TestServer_ScanEvents_LastEventID(t *testing.T) {
+	s := newTestServer(jobs.Config{Workers: 1}, fakeScanner{name: "fake"})
+	ts := serve(t, s)
+
+	job, err := s.Jobs.Submit(jobs.Request{Domain: "a.com", Scanners: []scanner.Scanner{fakeScanner{name: "fake"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-job.Done()
+	all, _, _ := job.EventsSince(0)
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/scans/"+job.ID()+"/events", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	ids, _ := readIDs(t, openStream(t, req))
+
+	if len(ids) != len(all)-2 {
+		t.Fatalf("got ids %v, want %d événements à partir de 2", ids, len(all)-2)
+	}
+	for i, id := range ids {
+		if id != i+2 {
+			t.Errorf("got ids %v, want reprise à 2", ids)
+			break
+		}
+	}
+}
+
+// TestServer_ScanEvents_NotFound — job inconnu → 404
+func TestServer_ScanEvents_NotFound(t *testing.T) {
+	s := newTestServer(jobs.Config{Workers: 1}, fakeScanner{name: "fake"})
+	ts := serve(t, s)
+
+	resp, err := http.Get(ts.URL + "/scans/inconnu/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("got %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
+
+// TestServer_AllStream_Disconnect — le client qui se déconnecte annule le scan lancé par /scan/all/stream
+func TestServer_AllStream_Disconnect(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	s := newTestServer(jobs.Config{Workers: 1}, fakeScanner{name: "slow", release: release})
+	ts := serve(t, s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/scan/all/stream?domain=a.com", nil)
+	lines := openStream(t, req)
+
+	// Premier événement (scanner.started) : donne l'ID du job
+	var first jobs.Event
+	for lines.Scan() {
+		if data, ok := strings.CutPrefix(lines.Text(), "data: "); ok {
+			if err := json.Unmarshal([]byte(data), &first); err != nil {
+				t.Fatal(err)
+			}
+			break
+		}
+	}
+	job, ok := s.Jobs.Get(first.JobID)
+	if !ok {
+		t.Fatalf("job %q introuvable", first.JobID)
+	}
+
+	cancel()
+	select {
+	case <-job.Done():
+	case <-time.After(time.Second):
+		t.Fatal("job not canceled after 1s")
+	}
+	if status := job.Snapshot().Status; status != jobs.StatusCanceled {
+		t.Errorf("got %s, want %s", status, jobs.StatusCanceled)
+	}
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/daviani/go__001/docs"
+	"github.com/daviani/go__001/internal/scanner"
+)
+
+// swaggerInstance — nom sous lequel la doc dynamique est enregistrée auprès de swag
+const swaggerInstance = "gosentry"
+
+// scanRouteTemplate — route générique annotée sur makeScanHandler, remplacée par une route par scanner
+const scanRouteTemplate = "/scan/{scanner}"
+
+// swaggerDoc — spec Swagger générée à partir du registre de scanners
+// Implémente swag.Swagger (méthode ReadDoc) : la spec statique de docs/ est complétée
+// avec une route /scan/<nom> par scanner enregistré, options comprises
+type swaggerDoc struct {
+	registry *scanner.Registry
+}
+
+// ReadDoc retourne la spec JSON servie sur /swagger/doc.json
+// En cas d'erreur, on retombe sur la spec statique plutôt que de casser la doc
+func (d swaggerDoc) ReadDoc() string {
+	static := docs.SwaggerInfo.ReadDoc()
+
+	// map[string]any : on manipule la spec comme un JSON générique (pas besoin de typer tout Swagger 2.0)
+	var spec map[string]any
+	if err := json.Unmarshal([]byte(static), &spec); err != nil {
+		log.Println(err)
+		return static
+	}
+
+	paths, _ := spec["paths"].(map[string]any)
+	template, ok := paths[scanRouteTemplate].(map[string]any)
+	if !ok {
+		return static
+	}
+	delete(paths, scanRouteTemplate)
+
+	for _, desc := range d.registry.Descriptors() {
+		paths["/scan/"+desc.Name] = scanRoute(template, desc)
+	}
+
+	out, err := json.Marshal(spec)
+	if err != nil {
+		log.Println(err)
+		return static
+	}
+	return string(out)
+}
+
+// scanRoute construit la route Swagger d'un scanner à partir du gabarit
+// Le paramètre de chemin {scanner} disparaît, les options du schéma deviennent des query params
+func scanRoute(template map[string]any, desc scanner.Descriptor) map[string]any {
+	get, _ := template["get"].(map[string]any)
+
+	// Copie superficielle : on ne modifie pas le gabarit partagé entre les scanners
+	op := make(map[string]any, len(get))
+	for k, v := range get {
+		op[k] = v
+	}
+	op["summary"] = "Scan " + desc.Title
+	op["description"] = desc.Description + " (v" + desc.Version + ")"
+	op["operationId"] = "scan-" + desc.Name
+
+	var params []any
+	existing, _ := get["parameters"].([]any)
+	for _, p := range existing {
+		if param, ok := p.(map[string]any); ok && param["in"] == "path" {
+			continue
+		}
+		params = append(params, p)
+	}
+	for _, o := range desc.Options {
+		params = append(params, optionParam(o))
+	}
+	op["parameters"] = params
+
+	return map[string]any{"get": op}
+}
+
+// optionParam convertit une option de scanner en paramètre Swagger 2.0 (query)
+func optionParam(o scanner.Option) map[string]any {
+	param := map[string]any{
+		"name":        o.Name,
+		"in":          "query",
+		"description": o.Description,
+		"required":    false,
+	}
+	if o.Default != nil {
+		param["default"] = o.Default
+	}
+
+	// Types Swagger : string, integer, boolean, array (listes séparées par des virgules)
+	item := map[string]any{}
+	switch o.Type {
+	case scanner.OptionInt:
+		param["type"] = "integer"
+		item = param
+	case scanner.OptionBool:
+		param["type"] = "boolean"
+	case scanner.OptionStrings:
+		param["type"] = "array"
+		param["collectionFormat"] = "csv"
+		item["type"] = "string"
+		param["items"] = item
+	case scanner.OptionInts:
+		param["type"] = "array"
+		param["collectionFormat"] = "csv"
+		item["type"] = "integer"
+		param["items"] = item
+	default:
+		param["type"] = "string"
+		item = param
+	}
+
+	// Enum et bornes s'appliquent à la valeur (ou à chaque élément d'une liste)
+	if len(o.Enum) > 0 {
+		item["enum"] = o.Enum
+	}
+	if o.Min != nil {
+		item["minimum"] = *o.Min
+	}
+	if o.Max != nil {
+		item["maximum"] = *o.Max
+	}
+	return param
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"fmt"
+	"time"
+)
+
+// defaultScanTimeout — deadline d'un scan si ni Server.ScanTimeout ni ?timeout= ne sont fournis
+const defaultScanTimeout = 60 * time.Second
+
+// maxScanTimeout — borne haute de ?timeout= : un client ne peut pas monopoliser le serveur
+const maxScanTimeout = 5 * time.Minute
+
+// scanTimeout retourne la deadline d'un scan
+// rawTimeout vient de ?timeout= ou du champ JSON (ex: "30s", "2m") — vide = Server.ScanTimeout
+func (s *Server) scanTimeout(rawTimeout string) (time.Duration, error) {
+	timeout := s.ScanTimeout
+	if timeout <= 0 {
+		timeout = defaultScanTimeout
+	}
+
+	if rawTimeout != "" {
+		// time.ParseDuration accepte "500ms", "30s", "1m30s"...
+		d, err := time.ParseDuration(rawTimeout)
+		if err != nil || d <= 0 || d > maxScanTimeout {
+			return 0, fmt.Errorf("paramètre 'timeout' invalide (durée entre 0 et %s attendue)", maxScanTimeout)
+		}
+		timeout = d
+	}
+	return timeout, nil
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/daviani/go__001/internal/notify"
+)
+
+// readWebhook décode le corps JSON d'un webhook — la validation (URL, sévérité) est faite par le notifier
+func readWebhook(w http.ResponseWriter, r *http.Request) (notify.Webhook, bool) {
+	var hook notify.Webhook
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&hook); err != nil {
+		http.Error(w, "corps JSON invalide", http.StatusBadRequest)
+		return notify.Webhook{}, false
+	}
+	return hook, true
+}
+
+// writeWebhookError traduit une erreur du notifier en réponse HTTP
+func writeWebhookError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, notify.ErrNotFound), errors.Is(err, notify.ErrDeliveryNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, notify.ErrInvalid):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		log.Println(err)
+		http.Error(w, "erreur interne du serveur", http.StatusInternalServerError)
+	}
+}
+
+// @Summary     Lister les webhooks
+// @Description Les secrets ne sont jamais renvoyés après la création
+// @Tags        webhooks
+// @Produce     json
+// @Success     200 {array} notify.Webhook
+// @Failure     500 {string} string "erreur serveur"
+// @Router      /webhooks [get]
+func (s *Server) handleListWebhooks() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		hooks, err := s.Webhooks.List(r.Context())
+		if err != nil {
+			writeWebhookError(w, err)
+			return
+		}
+		if hooks == nil {
+			hooks = []notify.Webhook{}
+		}
+		writeJSON(w, http.StatusOK, hooks)
+	}
+}
+
+// @Summary     Créer un webhook
+// @Description Reçoit un POST JSON signé (HMAC-SHA256) à chaque scan qui remonte des findings nouveaux ou aggravés
+// @Description Filtres : min_severity (défaut low), scanners, domains (sous-domaines compris)
+// @Description Le secret (généré s'il est absent) n'est renvoyé que dans cette réponse
+// @Tags        webhooks
+// @Accept      json
+// @Produce     json
+// @Param       webhook body notify.Webhook true "URL, secret et filtres"
+// @Success     201 {object} notify.Webhook
+// @Failure     400 {string} string "webhook invalide"
+// @Router      /webhooks [post]
+func (s *Server) handleCreateWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		hook, ok := readWebhook(w, r)
+		if !ok {
+			return
+		}
+
+		created, err := s.Webhooks.Create(r.Context(), hook)
+		if err != nil {
+			writeWebhookError(w, err)
+			return
+		}
+		w.Header().Set("Location", "/webhooks/"+created.ID)
+		writeJSON(w, http.StatusCreated, created)
+	}
+}
+
+// @Summary     Détail d'un webhook
+// @Tags        webhooks
+// @Produce     json
+// @Param       id path string true "ID du webhook"
+// @Success     200 {object} notify.Webhook
+// @Failure     404 {string} string "webhook introuvable"
+// @Router      /webhooks/{id} [get]
+func (s *Server) handleGetWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		hook, err := s.Webhooks.Get(r.Context(), r.PathValue("id"))
+		if err != nil {
+			writeWebhookError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, hook)
+	}
+}
+
+// @Summary     Modifier un webhook
+// @Description Remplace la configuration (mettre "paused": true pour suspendre) — secret absent = secret conservé
+// @Tags        webhooks
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "ID du webhook"
+// @Param       webhook body notify.Webhook true "Nouvelle configuration"
+// @Success     200 {object} notify.Webhook
+// @Failure     400 {string} string "webhook invalide"
+// @Failure     404 {string} string "webhook introuvable"
+// @Router      /webhooks/{id} [put]
+func (s *Server) handleUpdateWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		hook, ok := readWebhook(w, r)
+		if !ok {
+			return
+		}
+
+		updated, err := s.Webhooks.Update(r.Context(), r.PathValue("id"), hook)
+		if err != nil {
+			writeWebhookError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, updated)
+	}
+}
+
+// @Summary     Supprimer un webhook
+// @Description Supprime aussi son journal de livraisons
+// @Tags        webhooks
+// @Param       id path string true "ID du webhook"
+// @Success     204
+// @Failure     404 {string} string "webhook introuvable"
+// @Router      /webhooks/{id} [delete]
+func (s *Server) handleDeleteWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := s.Webhooks.Delete(r.Context(), r.PathValue("id")); err != nil {
+			writeWebhookError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// @Summary     Journal des livraisons
+// @Description Livraisons d'un webhook, de la plus récente à la plus ancienne (paginé)
+// @Description Chaque livraison garde son payload, son statut, son nombre de tentatives et la dernière réponse
+// @Tags        webhooks
+// @Produce     json
+// @Param       id path string true "ID du webhook"
+// @Param       limit query int false "Nombre de livraisons par page (défaut 20, max 100)"
+// @Param       offset query int false "Nombre de livraisons à sauter"
+// @Success     200 {object} notify.DeliveryPage
+// @Failure     400 {string} string "pagination invalide"
+// @Failure     404 {string} string "webhook introuvable"
+// @Router      /webhooks/{id}/deliveries [get]
+func (s *Server) handleWebhookDeliveries() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page, err := readPage(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		deliveries, err := s.Webhooks.Deliveries(r.Context(), r.PathValue("id"), page.Limit, page.Offset)
+		if err != nil {
+			writeWebhookError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, deliveries)
+	}
+}
+
+// @Summary     Détail d'une livraison
+// @Tags        webhooks
+// @Produce     json
+// @Param       id path string true "ID du webhook"
+// @Param       delivery path string true "ID de la livraison"
+// @Success     200 {object} notify.Delivery
+// @Failure     404 {string} string "webhook ou livraison introuvable"
+// @Router      /webhooks/{id}/deliveries/{delivery} [get]
+func (s *Server) handleGetDelivery() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		d, err := s.Webhooks.Delivery(r.Context(), r.PathValue("id"), r.PathValue("delivery"))
+		if err != nil {
+			writeWebhookError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, d)
+	}
+}
+
+// @Summary     Rejouer une livraison
+// @Description Renvoie le même payload dans une nouvelle livraison (replay_of pointe vers l'originale)
+// @Description Utile après une panne du récepteur : la livraison est signée avec le secret actuel du webhook
+// @Tags        webhooks
+// @Produce     json
+// @Param       id path string true "ID du webhook"
+// @Param       delivery path string true "ID de la livraison à rejouer"
+// @Success     202 {object} notify.Delivery
+// @Failure     404 {string} string "webhook ou livraison introuvable"
+// @Router      /webhooks/{id}/deliveries/{delivery}/replay [post]
+func (s *Server) handleReplayDelivery() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		d, err := s.Webhooks.Replay(r.Context(), r.PathValue("id"), r.PathValue("delivery"))
+		if err != nil {
+			writeWebhookError(w, err)
+			return
+		}
+		w.Header().Set("Location", "/webhooks/"+d.WebhookID+"/deliveries/"+d.ID)
+		writeJSON(w, http.StatusAccepted, d)
+	}
+}
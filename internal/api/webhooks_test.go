@@ -0,0 +1,179 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/daviani/go__001/internal/jobs"
+	"github.com/daviani/go__001/internal/notify"
+	"github.com/daviani/go__001/internal/scanner"
+)
+
+// newWebhookServer crée un serveur dont les webhooks et leurs livraisons sont enregistrés dans une base en mémoire
+func newWebhookServer(t *testing.T) *Server {
+	t.Helper()
+	s := newTestServer(jobs.Config{Workers: 1}, fakeScanner{name: "fake"})
+	s.Webhooks = notify.New(openStore(t), notify.Config{})
+	return s
+}
+
+// receiver démarre un récepteur de webhooks qui accepte toutes les livraisons (204)
+func receiver(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// delivered attend la fin d'une livraison via GET /webhooks/{id}/deliveries/{delivery} (échec au-delà de 2s)
+func delivered(t *testing.T, s *Server, hookID, deliveryID string) notify.Delivery {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		rec := do(s, http.MethodGet, "/webhooks/"+hookID+"/deliveries/"+deliveryID, "")
+		var d notify.Delivery
+		if rec.Code != http.StatusOK || json.NewDecoder(rec.Body).Decode(&d) != nil {
+			t.Fatalf("got %d, want %d (%s)", rec.Code, http.StatusOK, rec.Body)
+		}
+		if d.Status != notify.DeliveryPending {
+			return d
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("delivery still pending after 2s")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestServer_Webhooks — création, liste, livraison, rejeu puis suppression d'un webhook
+func TestServer_Webhooks(t *testing.T) {
+	s := newWebhookServer(t)
+	srv := receiver(t)
+
+	rec := do(s, http.MethodPost, "/webhooks", `{"url": "`+srv.URL+`"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create: got %d, want %d (%s)", rec.Code, http.StatusCreated, rec.Body)
+	}
+	var hook notify.Webhook
+	if err := json.NewDecoder(rec.Body).Decode(&hook); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Header().Get("Location") != "/webhooks/"+hook.ID || hook.Secret == "" {
+		t.Errorf("got %+v (Location %q), want a generated secret at /webhooks/%s", hook, rec.Header().Get("Location"), hook.ID)
+	}
+
+	// Le secret n'est plus jamais renvoyé après la création
+	var list []notify.Webhook
+	if rec := do(s, http.MethodGet, "/webhooks", ""); rec.Code != http.StatusOK || json.NewDecoder(rec.Body).Decode(&list) != nil {
+		t.Fatalf("list: got %d, want %d", rec.Code, http.StatusOK)
+	}
+	if len(list) != 1 || list[0].ID != hook.ID || list[0].Secret != "" {
+		t.Errorf("list: got %+v, want the webhook without its secret", list)
+	}
+
+	// Un scan avec un finding nouveau déclenche une livraison
+	snap := jobs.Snapshot{ID: "scan-1", Domain: "example.com", Status: jobs.StatusDone, Runs: []jobs.Run{{
+		Scanner: "fake", Status: jobs.StatusDone,
+		Result: &scanner.Result{Findings: []scanner.Finding{{ID: "fake.exposed", Severity: scanner.SeverityHigh, Asset: "example.com"}}},
+	}}}
+	if err := s.Webhooks.ScanFinished(context.Background(), jobs.Snapshot{}, snap); err != nil {
+		t.Fatal(err)
+	}
+
+	var page notify.DeliveryPage
+	if rec := do(s, http.MethodGet, "/webhooks/"+hook.ID+"/deliveries", ""); rec.Code != http.StatusOK || json.NewDecoder(rec.Body).Decode(&page) != nil {
+		t.Fatalf("deliveries: got %d, want %d", rec.Code, http.StatusOK)
+	}
+	if page.Total != 1 || len(page.Deliveries) != 1 {
+		t.Fatalf("deliveries: got %+v, want one delivery", page)
+	}
+	original := delivered(t, s, hook.ID, page.Deliveries[0].ID)
+	if original.Status != notify.DeliverySucceeded {
+		t.Errorf("got %s, want %s", original.Status, notify.DeliverySucceeded)
+	}
+
+	rec = do(s, http.MethodPost, "/webhooks/"+hook.ID+"/deliveries/"+original.ID+"/replay", "")
+	var replay notify.Delivery
+	if rec.Code != http.StatusAccepted || json.NewDecoder(rec.Body).Decode(&replay) != nil {
+		t.Fatalf("replay: got %d, want %d (%s)", rec.Code, http.StatusAccepted, rec.Body)
+	}
+	if replay.ReplayOf != original.ID || replay.ID == original.ID || rec.Header().Get("Location") != "/webhooks/"+hook.ID+"/deliveries/"+replay.ID {
+		t.Errorf("replay: got %+v, want a new delivery pointing to %s", replay, original.ID)
+	}
+	if d := delivered(t, s, hook.ID, replay.ID); d.Status != notify.DeliverySucceeded || string(d.Payload) != string(original.Payload) {
+		t.Errorf("replay: got %s, want the original payload delivered", d.Status)
+	}
+
+	if rec := do(s, http.MethodDelete, "/webhooks/"+hook.ID, ""); rec.Code != http.StatusNoContent {
+		t.Errorf("delete: got %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if rec := do(s, http.MethodGet, "/webhooks/"+hook.ID, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("get after delete: got %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+// TestServer_CreateWebhook_Invalid — corps illisible, URL invalide ou sévérité inconnue → 400
+func TestServer_CreateWebhook_Invalid(t *testing.T) {
+	s := newWebhookServer(t)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"JSON illisible", `{`},
+		{"URL manquante", `{}`},
+		{"URL relative", `{"url": "/hook"}`},
+		{"schéma non HTTP", `{"url": "ftp://example.com/hook"}`},
+		{"sévérité inconnue", `{"url": "https://example.com/hook", "min_severity": "urgent"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := do(s, http.MethodPost, "/webhooks", tt.body); rec.Code != http.StatusBadRequest {
+				t.Errorf("got %d, want %d (%s)", rec.Code, http.StatusBadRequest, rec.Body)
+			}
+		})
+	}
+
+	var list []notify.Webhook
+	if rec := do(s, http.MethodGet, "/webhooks", ""); json.NewDecoder(rec.Body).Decode(&list) != nil || len(list) != 0 {
+		t.Errorf("got %+v, want no webhook saved", list)
+	}
+}
+
+// TestServer_Webhooks_NotFound — webhook ou livraison inconnus → 404, y compris pour le rejeu
+func TestServer_Webhooks_NotFound(t *testing.T) {
+	s := newWebhookServer(t)
+	rec := do(s, http.MethodPost, "/webhooks", `{"url": "https://example.com/hook"}`)
+	var hook notify.Webhook
+	if err := json.NewDecoder(rec.Body).Decode(&hook); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method, target, body string
+	}{
+		{http.MethodGet, "/webhooks/inconnu", ""},
+		{http.MethodPut, "/webhooks/inconnu", `{"url": "https://example.com/hook"}`},
+		{http.MethodDelete, "/webhooks/inconnu", ""},
+		{http.MethodGet, "/webhooks/inconnu/deliveries", ""},
+		{http.MethodGet, "/webhooks/" + hook.ID + "/deliveries/inconnue", ""},
+		{http.MethodPost, "/webhooks/inconnu/deliveries/inconnue/replay", ""},
+		{http.MethodPost, "/webhooks/" + hook.ID + "/deliveries/inconnue/replay", ""},
+	}
+	for _, tt := range tests {
+		if rec := do(s, tt.method, tt.target, tt.body); rec.Code != http.StatusNotFound {
+			t.Errorf("%s %s: got %d, want %d", tt.method, tt.target, rec.Code, http.StatusNotFound)
+		}
+	}
+
+	// Une URL invalide est refusée aussi en modification
+	if rec := do(s, http.MethodPut, "/webhooks/"+hook.ID, `{"url": "not a url"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("update: got %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,153 @@
+package csp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Issue — faiblesse relevée dans une politique
+type Issue struct {
+	Check     string // Identifiant de la vérification (voir Checks)
+	Directive string // Directive en cause (vide pour une directive absente sans repli)
+	Detail    string
+}
+
+// Vérifications menées par Evaluate
+const (
+	CheckScriptSrc      = "script_src"      // Ni script-src ni default-src : scripts de toute origine
+	CheckUnsafeInline   = "unsafe_inline"   // 'unsafe-inline' actif (sans nonce ni hash qui l'annulent)
+	CheckUnsafeEval     = "unsafe_eval"     // eval() et new Function() autorisés
+	CheckWildcard       = "wildcard"        // *, ou schéma seul (https:, data:) dans script-src / object-src
+	CheckBypassHost     = "bypass_host"     // Hôte autorisé qui sert du JSONP ou des bibliothèques arbitraires
+	CheckObjectSrc      = "object_src"      // Ni object-src ni default-src : plugins <object>/<embed> autorisés
+	CheckBaseURI        = "base_uri"        // base-uri absent : <base> injecté détourne les scripts relatifs
+	CheckFrameAncestors = "frame_ancestors" // frame-ancestors absent : pas de protection anti-clickjacking
+	CheckReporting      = "reporting"       // Aucune remontée de violations, ou groupe report-to non déclaré
+)
+
+// Checks — toutes les vérifications, dans l'ordre du rapport
+var Checks = []string{
+	CheckScriptSrc, CheckUnsafeInline, CheckUnsafeEval, CheckWildcard, CheckBypassHost,
+	CheckObjectSrc, CheckBaseURI, CheckFrameAncestors, CheckReporting,
+}
+
+// bypassHosts — hôtes qui, autorisés dans script-src, permettent d'exécuter un script choisi par l'attaquant :
+// points d'accès JSONP (callback=alert) ou bibliothèques arbitraires (AngularJS et ses templates)
+var bypassHosts = []struct {
+	host, reason string
+}{
+	{"ajax.googleapis.com", "héberge AngularJS (injection de template)"},
+	{"cdnjs.cloudflare.com", "héberge AngularJS et des milliers de bibliothèques"},
+	{"cdn.jsdelivr.net", "sert n'importe quel paquet npm ou dépôt GitHub"},
+	{"unpkg.com", "sert n'importe quel paquet npm"},
+	{"raw.githubusercontent.com", "sert n'importe quel fichier d'un dépôt public"},
+	{"www.google.com", "points d'accès JSONP"},
+	{"accounts.google.com", "points d'accès JSONP"},
+	{"www.youtube.com", "points d'accès JSONP"},
+	{"www.google-analytics.com", "points d'accès JSONP"},
+	{"www.googletagmanager.com", "exécute les scripts configurés dans n'importe quel conteneur GTM"},
+}
+
+// Evaluate relève les faiblesses d'une politique
+// endpoints — groupes déclarés par Reporting-Endpoints ou Report-To, pour vérifier la directive report-to
+// Équivalent JS : les règles de csp-evaluator (Google), réduites à celles qui comptent pour un audit
+func Evaluate(p Policy, endpoints map[string]string) []Issue {
+	var issues []Issue
+	add := func(check, directive, format string, args ...any) {
+		issues = append(issues, Issue{Check: check, Directive: directive, Detail: fmt.Sprintf(format, args...)})
+	}
+
+	script, hasScript := p.Effective("script-src")
+	strictDynamic := hasScript && script.Has(KeywordStrictDynamic)
+	if !hasScript {
+		add(CheckScriptSrc, "", "ni script-src ni default-src : scripts de toute origine autorisés")
+	} else {
+		nonceOrHash := false
+		for _, s := range script.Sources() {
+			if s.Kind == SourceNonce || s.Kind == SourceHash {
+				nonceOrHash = true
+			}
+		}
+		// Un nonce ou un hash fait ignorer 'unsafe-inline' (CSP2+) : il ne reste que pour les vieux navigateurs
+		if script.Has(KeywordUnsafeInline) && !nonceOrHash {
+			add(CheckUnsafeInline, script.Name, "'unsafe-inline' dans %s : tout script inline injecté s'exécute", script.Name)
+		}
+		if script.Has(KeywordUnsafeEval) {
+			add(CheckUnsafeEval, script.Name, "'unsafe-eval' dans %s : eval() et new Function() autorisés", script.Name)
+		}
+	}
+
+	// 'strict-dynamic' fait ignorer les listes d'hôtes et de schémas : ni joker ni hôte de contournement ne compte
+	// object-src retombe souvent sur la même directive que script-src (default-src) : elle n'est lue qu'une fois
+	object, hasObject := p.Effective("object-src")
+	type target struct {
+		directive Directive
+		script    bool // Les hôtes de contournement ne concernent que les scripts
+	}
+	var targets []target
+	if hasScript && !strictDynamic {
+		targets = append(targets, target{script, true})
+	}
+	if hasObject && (object.Name != script.Name || strictDynamic) {
+		targets = append(targets, target{object, false})
+	}
+	for _, t := range targets {
+		var broad, bypass []string
+		for _, s := range t.directive.Sources() {
+			switch s.Kind {
+			case SourceWildcard:
+				broad = append(broad, s.Raw)
+			case SourceScheme:
+				switch strings.ToLower(s.Raw) {
+				case "https:", "http:", "data:", "blob:":
+					broad = append(broad, s.Raw)
+				}
+			case SourceHost:
+				// Joker sur un domaine de premier niveau (*.com) : équivaut à *
+				if strings.HasPrefix(s.Host, "*.") && !strings.Contains(s.Host[2:], ".") {
+					broad = append(broad, s.Raw)
+				}
+				if reason := bypassReason(s.Host); t.script && reason != "" {
+					bypass = append(bypass, s.Raw+" ("+reason+")")
+				}
+			}
+		}
+		name := t.directive.Name
+		if len(broad) > 0 {
+			add(CheckWildcard, name, "%s autorise %s : n'importe quel contenu de ces origines se charge", name, strings.Join(broad, ", "))
+		}
+		if len(bypass) > 0 {
+			add(CheckBypassHost, name, "%s autorise %s", name, strings.Join(bypass, ", "))
+		}
+	}
+
+	if !hasObject {
+		add(CheckObjectSrc, "", "ni object-src ni default-src : plugins <object> et <embed> de toute origine")
+	}
+	if _, ok := p.Directive("base-uri"); !ok {
+		add(CheckBaseURI, "", "base-uri absent : une balise <base> injectée détourne les scripts à URL relative")
+	}
+	if _, ok := p.Directive("frame-ancestors"); !ok {
+		add(CheckFrameAncestors, "", "frame-ancestors absent : la page peut être intégrée par n'importe quel site")
+	}
+
+	_, reportURI := p.Directive("report-uri")
+	switch group := p.ReportTo(); {
+	case group != "" && endpoints[group] == "":
+		add(CheckReporting, "report-to", "groupe report-to %q déclaré ni dans Reporting-Endpoints ni dans Report-To", group)
+	case group == "" && !reportURI:
+		add(CheckReporting, "", "ni report-uri ni report-to : les violations ne sont pas remontées")
+	}
+	return issues
+}
+
+// bypassReason retourne pourquoi host permet de contourner la politique ("" si hôte sûr)
+// Un joker couvre les hôtes de la liste : *.googleapis.com autorise ajax.googleapis.com
+func bypassReason(host string) string {
+	for _, known := range bypassHosts {
+		if host == known.host || (strings.HasPrefix(host, "*.") && strings.HasSuffix(known.host, host[1:])) {
+			return known.host + " : " + known.reason
+		}
+	}
+	return ""
+}
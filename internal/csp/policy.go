@@ -0,0 +1,226 @@
+package csp
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Policy — une politique Content-Security-Policy : directives dans l'ordre du header
+type Policy struct {
+	Directives []Directive
+	ReportOnly bool     // Reçue par Content-Security-Policy-Report-Only : rien n'est bloqué
+	Duplicates []string // Directives répétées — seule la première occurrence compte (CSP3 §2.2)
+}
+
+// Directive — nom en minuscules et valeurs brutes (sources, groupe report-to, jetons sandbox...)
+type Directive struct {
+	Name   string
+	Values []string
+}
+
+// SourceKind — nature d'une expression de source
+type SourceKind int
+
+const (
+	SourceKeyword  SourceKind = iota // 'self', 'none', 'unsafe-inline', 'strict-dynamic'...
+	SourceNonce                      // 'nonce-<base64>'
+	SourceHash                       // 'sha256-<base64>', 'sha384-...', 'sha512-...'
+	SourceScheme                     // https:, data:, blob:
+	SourceHost                       // example.com, *.cdn.example.com, https://example.com/js/
+	SourceWildcard                   // *
+)
+
+// Source — une expression de source analysée
+type Source struct {
+	Raw  string
+	Kind SourceKind
+	Host string // SourceHost : hôte en minuscules, sans schéma, port ni chemin
+}
+
+// Mots-clés de source — toujours entre apostrophes, insensibles à la casse
+const (
+	KeywordSelf          = "'self'"
+	KeywordNone          = "'none'"
+	KeywordUnsafeInline  = "'unsafe-inline'"
+	KeywordUnsafeEval    = "'unsafe-eval'"
+	KeywordStrictDynamic = "'strict-dynamic'"
+)
+
+// Parse lit la valeur d'un header CSP — une virgule sépare plusieurs politiques, toutes appliquées
+// (c'est aussi la forme de plusieurs headers joints) ; reportOnly marque les politiques Report-Only
+func Parse(value string, reportOnly bool) []Policy {
+	var policies []Policy
+	for _, raw := range strings.Split(value, ",") {
+		p := Policy{ReportOnly: reportOnly}
+		seen := make(map[string]bool)
+		for _, directive := range strings.Split(raw, ";") {
+			fields := strings.Fields(directive)
+			if len(fields) == 0 {
+				continue
+			}
+			name := strings.ToLower(fields[0])
+			if seen[name] {
+				p.Duplicates = append(p.Duplicates, name)
+				continue
+			}
+			seen[name] = true
+			p.Directives = append(p.Directives, Directive{Name: name, Values: fields[1:]})
+		}
+		if len(p.Directives) > 0 {
+			policies = append(policies, p)
+		}
+	}
+	return policies
+}
+
+// ParseHeaders lit toutes les politiques d'une réponse : headers appliqués puis Report-Only
+func ParseHeaders(enforced, reportOnly []string) []Policy {
+	var policies []Policy
+	for _, value := range enforced {
+		policies = append(policies, Parse(value, false)...)
+	}
+	for _, value := range reportOnly {
+		policies = append(policies, Parse(value, true)...)
+	}
+	return policies
+}
+
+// Directive retourne la directive name telle qu'écrite dans la politique
+func (p Policy) Directive(name string) (Directive, bool) {
+	for _, d := range p.Directives {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	return Directive{}, false
+}
+
+// fallbacks — directives consultées, dans l'ordre, quand une directive de chargement est absente
+// base-uri, form-action, frame-ancestors... n'ont pas de repli : absentes, elles n'imposent rien
+var fallbacks = map[string][]string{
+	"script-src":      {"default-src"},
+	"script-src-elem": {"script-src", "default-src"},
+	"script-src-attr": {"script-src", "default-src"},
+	"style-src":       {"default-src"},
+	"style-src-elem":  {"style-src", "default-src"},
+	"style-src-attr":  {"style-src", "default-src"},
+	"worker-src":      {"child-src", "script-src", "default-src"},
+	"frame-src":       {"child-src", "default-src"},
+	"child-src":       {"default-src"},
+	"connect-src":     {"default-src"},
+	"font-src":        {"default-src"},
+	"img-src":         {"default-src"},
+	"manifest-src":    {"default-src"},
+	"media-src":       {"default-src"},
+	"object-src":      {"default-src"},
+}
+
+// Effective retourne la directive réellement appliquée pour name, repli sur default-src compris
+func (p Policy) Effective(name string) (Directive, bool) {
+	if d, ok := p.Directive(name); ok {
+		return d, true
+	}
+	for _, fallback := range fallbacks[name] {
+		if d, ok := p.Directive(fallback); ok {
+			return d, true
+		}
+	}
+	return Directive{}, false
+}
+
+// Sources analyse les valeurs de la directive comme expressions de source
+func (d Directive) Sources() []Source {
+	sources := make([]Source, 0, len(d.Values))
+	for _, value := range d.Values {
+		sources = append(sources, ParseSource(value))
+	}
+	return sources
+}
+
+// Has indique si la directive contient le mot-clé keyword ('self', 'unsafe-inline'...)
+func (d Directive) Has(keyword string) bool {
+	for _, value := range d.Values {
+		if strings.EqualFold(value, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSource classe une expression de source
+// Équivalent JS : le découpage que fait un navigateur de chaque jeton d'une directive fetch
+func ParseSource(raw string) Source {
+	s := Source{Raw: raw}
+	lower := strings.ToLower(raw)
+	switch {
+	case raw == "*":
+		s.Kind = SourceWildcard
+	case strings.HasPrefix(lower, "'nonce-"):
+		s.Kind = SourceNonce
+	case strings.HasPrefix(lower, "'sha256-"), strings.HasPrefix(lower, "'sha384-"), strings.HasPrefix(lower, "'sha512-"):
+		s.Kind = SourceHash
+	case strings.HasPrefix(raw, "'"):
+		s.Kind = SourceKeyword
+	case strings.HasSuffix(raw, ":") && !strings.Contains(raw, "/"):
+		s.Kind = SourceScheme
+	default:
+		s.Kind = SourceHost
+		host := lower
+		if _, rest, ok := strings.Cut(host, "://"); ok {
+			host = rest
+		}
+		host, _, _ = strings.Cut(host, "/")
+		// Port en fin d'hôte (example.com:8443, *.example.com:*)
+		if i := strings.LastIndex(host, ":"); i >= 0 {
+			host = host[:i]
+		}
+		s.Host = host
+	}
+	return s
+}
+
+// ReportTo retourne le groupe de la directive report-to ("" si absente)
+func (p Policy) ReportTo() string {
+	if d, ok := p.Directive("report-to"); ok && len(d.Values) > 0 {
+		return d.Values[0]
+	}
+	return ""
+}
+
+// ParseReportingEndpoints lit le header Reporting-Endpoints : csp="https://r.example/csp", default="..."
+// Retourne nom du groupe → URL
+func ParseReportingEndpoints(value string) map[string]string {
+	endpoints := make(map[string]string)
+	for _, member := range strings.Split(value, ",") {
+		name, url, ok := strings.Cut(strings.TrimSpace(member), "=")
+		if !ok {
+			continue
+		}
+		endpoints[strings.TrimSpace(name)] = strings.Trim(strings.TrimSpace(url), `"`)
+	}
+	return endpoints
+}
+
+// ParseReportTo lit l'ancien header Report-To : objets JSON séparés par des virgules
+// {"group":"csp","max_age":10886400,"endpoints":[{"url":"https://r.example/csp"}]} — groupe par défaut "default"
+func ParseReportTo(value string) map[string]string {
+	var groups []struct {
+		Group     string `json:"group"`
+		Endpoints []struct {
+			URL string `json:"url"`
+		} `json:"endpoints"`
+	}
+	endpoints := make(map[string]string)
+	if err := json.Unmarshal([]byte("["+value+"]"), &groups); err != nil {
+		return endpoints
+	}
+	for _, g := range groups {
+		if g.Group == "" {
+			g.Group = "default"
+		}
+		if len(g.Endpoints) > 0 {
+			endpoints[g.Group] = g.Endpoints[0].URL
+		}
+	}
+	return endpoints
+}
@@ -0,0 +1,121 @@
+package csp
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestParse — plusieurs politiques, directives répétées, repli sur default-src
+func TestParse(t *testing.T) {
+	policies := Parse("default-src 'self'; SCRIPT-SRC 'self' https://cdn.example.com; script-src *, frame-ancestors 'none'", false)
+	if len(policies) != 2 {
+		t.Fatalf("got %d policies, want 2", len(policies))
+	}
+
+	p := policies[0]
+	script, ok := p.Effective("script-src")
+	if !ok || !slices.Equal(script.Values, []string{"'self'", "https://cdn.example.com"}) {
+		t.Errorf("got script-src %+v, want first occurrence", script)
+	}
+	if !slices.Equal(p.Duplicates, []string{"script-src"}) {
+		t.Errorf("got duplicates %v, want [script-src]", p.Duplicates)
+	}
+	if object, ok := p.Effective("object-src"); !ok || object.Name != "default-src" {
+		t.Errorf("got object-src %+v, want default-src fallback", object)
+	}
+	// base-uri n'a pas de repli
+	if _, ok := p.Effective("base-uri"); ok {
+		t.Error("got base-uri, want none")
+	}
+	if _, ok := policies[1].Effective("script-src"); ok {
+		t.Error("second policy : got script-src, want none")
+	}
+}
+
+// TestParseSource — classement des expressions de source
+func TestParseSource(t *testing.T) {
+	tests := []struct {
+		raw  string
+		kind SourceKind
+		host string
+	}{
+		{"*", SourceWildcard, ""},
+		{"'SELF'", SourceKeyword, ""},
+		{"'nonce-r4nd0m'", SourceNonce, ""},
+		{"'sha256-abc='", SourceHash, ""},
+		{"https:", SourceScheme, ""},
+		{"https://CDN.example.com:8443/js/", SourceHost, "cdn.example.com"},
+		{"*.googleapis.com", SourceHost, "*.googleapis.com"},
+	}
+	for _, tt := range tests {
+		if got := ParseSource(tt.raw); got.Kind != tt.kind || got.Host != tt.host {
+			t.Errorf("%s : got %+v, want kind %d host %q", tt.raw, got, tt.kind, tt.host)
+		}
+	}
+}
+
+// TestEvaluate — faiblesses relevées selon la politique
+func TestEvaluate(t *testing.T) {
+	// Directives qui ferment les contrôles hors sujet dans chaque cas
+	const closed = "; object-src 'none'; base-uri 'none'; frame-ancestors 'none'; report-uri /csp"
+	tests := []struct {
+		name   string
+		policy string
+		want   []string
+	}{
+		{
+			name:   "politique stricte à nonce",
+			policy: "script-src 'nonce-abc' 'strict-dynamic' https: 'unsafe-inline'" + closed,
+		},
+		{
+			name:   "unsafe-inline et unsafe-eval",
+			policy: "default-src 'self' 'unsafe-inline' 'unsafe-eval'" + closed,
+			want:   []string{CheckUnsafeInline, CheckUnsafeEval},
+		},
+		{
+			name:   "jokers et hôte JSONP",
+			policy: "script-src 'self' https: *.googleapis.com" + closed,
+			want:   []string{CheckWildcard, CheckBypassHost},
+		},
+		{
+			name:   "object-src ouvert",
+			policy: "script-src 'self'; object-src *; base-uri 'self'; frame-ancestors 'self'; report-uri /csp",
+			want:   []string{CheckWildcard},
+		},
+		{
+			name:   "directives absentes",
+			policy: "img-src 'self'",
+			want:   []string{CheckScriptSrc, CheckObjectSrc, CheckBaseURI, CheckFrameAncestors, CheckReporting},
+		},
+		{
+			name:   "groupe report-to non déclaré",
+			policy: "default-src 'self'; base-uri 'none'; frame-ancestors 'none'; report-to absent",
+			want:   []string{CheckReporting},
+		},
+		{
+			name:   "groupe report-to déclaré",
+			policy: "default-src 'self'; base-uri 'none'; frame-ancestors 'none'; report-to csp",
+		},
+	}
+
+	endpoints := ParseReportingEndpoints(`csp="https://r.example/csp", default="https://r.example/"`)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, issue := range Evaluate(Parse(tt.policy, false)[0], endpoints) {
+				got = append(got, issue.Check)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseReportTo — ancien header Report-To (JSON), groupe "default" implicite
+func TestParseReportTo(t *testing.T) {
+	got := ParseReportTo(`{"group":"csp","max_age":86400,"endpoints":[{"url":"https://r.example/csp"}]}, {"max_age":86400,"endpoints":[{"url":"https://r.example/"}]}`)
+	if got["csp"] != "https://r.example/csp" || got["default"] != "https://r.example/" {
+		t.Errorf("got %v", got)
+	}
+}
@@ -0,0 +1,232 @@
+package diff
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+
+	"github.com/daviani/go__001/internal/jobs"
+	"github.com/daviani/go__001/internal/scanner"
+)
+
+// Kind — nature d'un changement entre deux scans
+type Kind string
+
+const (
+	KindAdded   Kind = "added"   // Finding absent du scan de départ (nouveau sous-domaine, .env exposé...)
+	KindRemoved Kind = "removed" // Finding disparu (header supprimé, record DNS retiré...)
+	KindChanged Kind = "changed" // Même vérification, même asset, mais sévérité ou preuve différente
+)
+
+// Change — un finding ajouté, supprimé ou modifié entre deux scans
+type Change struct {
+	Kind     Kind             `json:"kind"`
+	Scanner  string           `json:"scanner"`
+	ID       string           `json:"id"`               // Identifiant de la vérification (Finding.ID)
+	Asset    string           `json:"asset"`            // Élément concerné (Finding.Asset)
+	Before   *scanner.Finding `json:"before,omitempty"` // nil pour un ajout
+	After    *scanner.Finding `json:"after,omitempty"`  // nil pour une suppression
+	Worsened bool             `json:"worsened"`         // Nouveau finding non informatif, ou sévérité en hausse
+}
+
+// Summary — nombre de changements par nature
+type Summary struct {
+	Added    int `json:"added"`
+	Removed  int `json:"removed"`
+	Changed  int `json:"changed"`
+	Worsened int `json:"worsened"`
+}
+
+// Diff — différences entre deux scans d'un même domaine
+type Diff struct {
+	Domain  string   `json:"domain"`
+	From    string   `json:"from"` // ID du scan de départ (le plus ancien)
+	To      string   `json:"to"`   // ID du scan d'arrivée
+	Summary Summary  `json:"summary"`
+	Changes []Change `json:"changes"`
+	// Skipped liste les scanners non comparés : absents ou sans résultat dans l'un des deux scans
+	// Sans ça, un scanner en échec ferait apparaître tous ses findings comme supprimés
+	Skipped []string `json:"skipped"`
+}
+
+// key — identité stable d'un finding d'un scan à l'autre (voir scanner.Finding)
+type key struct {
+	id    string
+	asset string
+}
+
+// Compare calcule les changements de findings entre deux scans
+// Seuls les scanners ayant produit un résultat dans les deux scans sont comparés
+// Un résultat partiel dans le scan d'arrivée ne produit pas de suppressions (findings peut-être pas encore vus)
+func Compare(from, to jobs.Snapshot) Diff {
+	d := Diff{Domain: to.Domain, From: from.ID, To: to.ID, Changes: []Change{}, Skipped: []string{}}
+
+	before := results(from)
+	after := results(to)
+
+	for _, name := range scannerNames(from, to) {
+		old, hasOld := before[name]
+		cur, hasCur := after[name]
+		if !hasOld || !hasCur {
+			d.Skipped = append(d.Skipped, name)
+			continue
+		}
+		d.Changes = append(d.Changes, compareResults(name, old, cur)...)
+	}
+
+	for _, c := range d.Changes {
+		switch c.Kind {
+		case KindAdded:
+			d.Summary.Added++
+		case KindRemoved:
+			d.Summary.Removed++
+		case KindChanged:
+			d.Summary.Changed++
+		}
+		if c.Worsened {
+			d.Summary.Worsened++
+		}
+	}
+	return d
+}
+
+// Baseline traite le premier scan d'un domaine : sans scan de départ, chaque finding est un ajout
+func Baseline(to jobs.Snapshot) Diff {
+	return Compare(jobs.Snapshot{Domain: to.Domain, Runs: emptyRuns(to)}, to)
+}
+
+// Against compare to à une référence assemblée scanner par scanner (voir store.PreviousRuns)
+// Un scanner sans run dans reference est comparé à un résultat vide, comme dans Baseline
+func Against(reference, to jobs.Snapshot) Diff {
+	known := results(reference)
+	runs := slices.Clone(reference.Runs)
+	for _, run := range emptyRuns(to) {
+		if _, ok := known[run.Scanner]; !ok {
+			runs = append(runs, run)
+		}
+	}
+	reference.Domain = to.Domain
+	reference.Runs = runs
+	return Compare(reference, to)
+}
+
+// emptyRuns retourne les scanners de snap avec un résultat vide (aucun finding)
+func emptyRuns(snap jobs.Snapshot) []jobs.Run {
+	runs := make([]jobs.Run, len(snap.Runs))
+	for i, run := range snap.Runs {
+		runs[i] = jobs.Run{Scanner: run.Scanner, Status: jobs.StatusDone, Result: &scanner.Result{}}
+	}
+	return runs
+}
+
+// compareResults compare les findings d'un scanner entre deux résultats
+// Plusieurs findings peuvent partager une clé (deux records DKIM sous le même sélecteur) : chaque clé porte
+// un multiset de findings. Les findings identiques sont appariés d'abord (l'ordre de découverte peut varier
+// d'un scan à l'autre), les restants dans l'ordre ; le surplus d'un côté est ajouté ou supprimé
+// Ordre de sortie stable : ajouts et modifications dans l'ordre du nouveau scan, puis suppressions
+func compareResults(name string, old, cur *scanner.Result) []Change {
+	oldByKey := index(old.Findings)
+	matched := make(map[*scanner.Finding]*scanner.Finding, len(cur.Findings)) // Finding du nouveau scan → son pendant dans l'ancien
+	used := make(map[*scanner.Finding]bool, len(old.Findings))
+
+	pair := func(accept func(prev, f *scanner.Finding) bool) {
+		for i := range cur.Findings {
+			f := &cur.Findings[i]
+			if matched[f] != nil {
+				continue
+			}
+			for _, prev := range oldByKey[key{f.ID, f.Asset}] {
+				if !used[prev] && accept(prev, f) {
+					matched[f] = prev
+					used[prev] = true
+					break
+				}
+			}
+		}
+	}
+	pair(func(prev, f *scanner.Finding) bool { return same(*prev, *f) })
+	pair(func(prev, f *scanner.Finding) bool { return true })
+
+	var changes []Change
+	for i := range cur.Findings {
+		f := &cur.Findings[i]
+		prev := matched[f]
+		switch {
+		case prev == nil:
+			changes = append(changes, Change{
+				Kind: KindAdded, Scanner: name, ID: f.ID, Asset: f.Asset, After: f,
+				Worsened: f.Severity.Rank() > scanner.SeverityInfo.Rank(),
+			})
+		case !same(*prev, *f):
+			changes = append(changes, Change{
+				Kind: KindChanged, Scanner: name, ID: f.ID, Asset: f.Asset, Before: prev, After: f,
+				Worsened: f.Severity.Rank() > prev.Severity.Rank(),
+			})
+		}
+	}
+
+	if cur.Partial {
+		return changes
+	}
+	for i := range old.Findings {
+		f := &old.Findings[i]
+		if !used[f] {
+			changes = append(changes, Change{Kind: KindRemoved, Scanner: name, ID: f.ID, Asset: f.Asset, Before: f})
+		}
+	}
+	return changes
+}
+
+// volatile — parties de preuve qui changent d'un scan à l'autre sans que la cible change :
+// compteurs de jours ("Expire dans 42 jour(s)", "(12 jours)") et dates (expiration des signatures DNSSEC)
+var volatile = regexp.MustCompile(`\d{4}-\d{2}-\d{2}|\d+ jour(?:s|\(s\))?`)
+
+// same indique si deux findings de même clé sont identiques pour l'utilisateur
+// Le titre et la remédiation sont ignorés : ils varient avec les versions des scanners, pas avec la cible
+// Les compteurs de jours et les dates de la preuve aussi : sinon un certificat ou une zone DNSSEC
+// apparaîtraient modifiés à chaque scan. Un franchissement de seuil reste visible par la sévérité
+func same(a, b scanner.Finding) bool {
+	return a.Severity == b.Severity && stable(a.Evidence) == stable(b.Evidence)
+}
+
+// stable retire de la preuve ses parties volatiles
+func stable(evidence string) string {
+	return volatile.ReplaceAllString(evidence, "#")
+}
+
+// index range les findings par clé (ID, Asset), dans leur ordre d'origine — aucun doublon n'est écrasé
+func index(findings []scanner.Finding) map[key][]*scanner.Finding {
+	byKey := make(map[key][]*scanner.Finding, len(findings))
+	for i := range findings {
+		k := key{findings[i].ID, findings[i].Asset}
+		byKey[k] = append(byKey[k], &findings[i])
+	}
+	return byKey
+}
+
+// results retourne le résultat de chaque scanner ayant abouti, indexé par nom
+func results(snap jobs.Snapshot) map[string]*scanner.Result {
+	byName := make(map[string]*scanner.Result, len(snap.Runs))
+	for _, run := range snap.Runs {
+		if run.Result != nil && run.Status == jobs.StatusDone {
+			byName[run.Scanner] = run.Result
+		}
+	}
+	return byName
+}
+
+// scannerNames retourne les noms des scanners lancés dans l'un ou l'autre scan, triés
+func scannerNames(snaps ...jobs.Snapshot) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, snap := range snaps {
+		for _, run := range snap.Runs {
+			if !seen[run.Scanner] {
+				seen[run.Scanner] = true
+				names = append(names, run.Scanner)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
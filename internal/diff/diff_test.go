@@ -0,0 +1,207 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/daviani/go__001/internal/jobs"
+	"github.com/daviani/go__001/internal/scanner"
+)
+
+// scan construit un scan terminé à partir des résultats de chaque scanner (nil = scanner en échec)
+func scan(id string, results map[string]*scanner.Result) jobs.Snapshot {
+	snap := jobs.Snapshot{ID: id, Domain: "example.com", Status: jobs.StatusDone}
+	for name, result := range results {
+		status := jobs.StatusDone
+		if result == nil {
+			status = jobs.StatusFailed
+		}
+		snap.Runs = append(snap.Runs, jobs.Run{Scanner: name, Status: status, Result: result})
+	}
+	return snap
+}
+
+// TestCompare — ajout, suppression et modification détectés sur la clé (ID, Asset)
+func TestCompare(t *testing.T) {
+	from := scan("a", map[string]*scanner.Result{
+		"header": {Findings: []scanner.Finding{
+			{ID: "header.hsts", Severity: scanner.SeverityInfo, Asset: "example.com", Evidence: "max-age=31536000"},
+			{ID: "header.csp", Severity: scanner.SeverityInfo, Asset: "example.com", Evidence: "default-src 'self'"},
+		}},
+		"subdomain": {Findings: []scanner.Finding{
+			{ID: "subdomain.found", Severity: scanner.SeverityInfo, Asset: "old.example.com"},
+		}},
+	})
+	to := scan("b", map[string]*scanner.Result{
+		"header": {Findings: []scanner.Finding{
+			{ID: "header.hsts", Severity: scanner.SeverityMedium, Asset: "example.com", Evidence: "absent"},
+			{ID: "header.csp", Severity: scanner.SeverityInfo, Asset: "example.com", Evidence: "default-src 'self'"},
+		}},
+		"subdomain": {Findings: []scanner.Finding{
+			{ID: "subdomain.found", Severity: scanner.SeverityInfo, Asset: "new.example.com"},
+		}},
+	})
+
+	d := Compare(from, to)
+	if d.From != "a" || d.To != "b" {
+		t.Errorf("got from %s to %s, want a → b", d.From, d.To)
+	}
+	want := Summary{Added: 1, Removed: 1, Changed: 1, Worsened: 1}
+	if d.Summary != want {
+		t.Errorf("got summary %+v, want %+v", d.Summary, want)
+	}
+
+	changed := d.Changes[0]
+	if changed.Kind != KindChanged || changed.ID != "header.hsts" || !changed.Worsened {
+		t.Errorf("got %+v, want a worsened header.hsts change", changed)
+	}
+	if changed.Before.Severity != scanner.SeverityInfo || changed.After.Severity != scanner.SeverityMedium {
+		t.Errorf("got %s → %s, want info → medium", changed.Before.Severity, changed.After.Severity)
+	}
+
+	// Un sous-domaine info ajouté n'est pas une aggravation
+	if added := d.Changes[1]; added.Kind != KindAdded || added.Asset != "new.example.com" || added.Worsened {
+		t.Errorf("got %+v, want new.example.com added", added)
+	}
+	if removed := d.Changes[2]; removed.Kind != KindRemoved || removed.Asset != "old.example.com" {
+		t.Errorf("got %+v, want old.example.com removed", removed)
+	}
+}
+
+// TestCompare_Duplicates — plusieurs findings de même clé (records DKIM d'un même sélecteur) :
+// un doublon n'en écrase pas un autre, l'ordre de découverte ne produit pas de changement
+func TestCompare_Duplicates(t *testing.T) {
+	dkim := func(evidence string) scanner.Finding {
+		return scanner.Finding{ID: "email.dkim", Severity: scanner.SeverityInfo, Asset: "s1._domainkey.example.com", Evidence: evidence}
+	}
+	from := scan("a", map[string]*scanner.Result{"email": {Findings: []scanner.Finding{dkim("k1"), dkim("k2"), dkim("k3")}}})
+
+	tests := []struct {
+		name string
+		to   []scanner.Finding
+		want Summary
+	}{
+		{"ordre différent", []scanner.Finding{dkim("k3"), dkim("k1"), dkim("k2")}, Summary{}},
+		{"une clé retirée", []scanner.Finding{dkim("k1"), dkim("k3")}, Summary{Removed: 1}},
+		{"une clé ajoutée", []scanner.Finding{dkim("k1"), dkim("k2"), dkim("k3"), dkim("k4")}, Summary{Added: 1}},
+		{"une clé remplacée", []scanner.Finding{dkim("k1"), dkim("k4"), dkim("k3")}, Summary{Changed: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Compare(from, scan("b", map[string]*scanner.Result{"email": {Findings: tt.to}}))
+			if d.Summary != tt.want {
+				t.Errorf("got %+v, want %+v", d.Summary, tt.want)
+			}
+		})
+	}
+}
+
+// TestCompare_Volatile — un compteur de jours ou une date qui avance ne rend pas le finding modifié,
+// contrairement à une sévérité ou une preuve qui change vraiment
+func TestCompare_Volatile(t *testing.T) {
+	expiry := func(severity scanner.Severity, evidence string) *scanner.Result {
+		return &scanner.Result{Findings: []scanner.Finding{
+			{ID: "ssl.expiry", Severity: severity, Asset: "example.com:443", Evidence: evidence},
+		}}
+	}
+	dnssec := func(evidence string) *scanner.Result {
+		return &scanner.Result{Findings: []scanner.Finding{
+			{ID: "dns.dnssec.expiry", Severity: scanner.SeverityInfo, Asset: "example.com", Evidence: evidence},
+		}}
+	}
+
+	tests := []struct {
+		name     string
+		from, to *scanner.Result
+		want     Summary
+	}{
+		{
+			"jours restants",
+			expiry(scanner.SeverityInfo, "Expire dans 60 jour(s)"),
+			expiry(scanner.SeverityInfo, "Expire dans 59 jour(s)"),
+			Summary{},
+		},
+		{
+			"signatures re-signées",
+			dnssec("signatures de example.com valides jusqu'au 2024-03-01 (21 jours)"),
+			dnssec("signatures de example.com valides jusqu'au 2024-03-08 (27 jours)"),
+			Summary{},
+		},
+		{
+			"seuil franchi",
+			expiry(scanner.SeverityInfo, "Expire dans 31 jour(s)"),
+			expiry(scanner.SeverityMedium, "Expire dans 29 jour(s)"),
+			Summary{Changed: 1, Worsened: 1},
+		},
+		{
+			"autre zone",
+			dnssec("signatures de example.com valides jusqu'au 2024-03-01 (21 jours)"),
+			dnssec("signatures de www.example.com valides jusqu'au 2024-03-01 (21 jours)"),
+			Summary{Changed: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Compare(scan("a", map[string]*scanner.Result{"x": tt.from}), scan("b", map[string]*scanner.Result{"x": tt.to}))
+			if d.Summary != tt.want {
+				t.Errorf("got %+v, want %+v", d.Summary, tt.want)
+			}
+		})
+	}
+}
+
+// TestCompare_Skipped — un scanner en échec n'est pas comparé (pas de fausses suppressions)
+func TestCompare_Skipped(t *testing.T) {
+	exposed := &scanner.Result{Findings: []scanner.Finding{
+		{ID: "sensitive.exposed", Severity: scanner.SeverityCritical, Asset: "https://example.com/.env"},
+	}}
+	from := scan("a", map[string]*scanner.Result{"sensitive": exposed, "ssl": {}})
+	to := scan("b", map[string]*scanner.Result{"sensitive": nil, "ssl": {}})
+
+	d := Compare(from, to)
+	if len(d.Changes) != 0 {
+		t.Errorf("got %d changes, want 0", len(d.Changes))
+	}
+	if len(d.Skipped) != 1 || d.Skipped[0] != "sensitive" {
+		t.Errorf("got skipped %v, want [sensitive]", d.Skipped)
+	}
+
+	// Dans l'autre sens, le .env devient un ajout critique
+	d = Compare(scan("c", map[string]*scanner.Result{"sensitive": {}}), scan("d", map[string]*scanner.Result{"sensitive": exposed}))
+	if d.Summary.Added != 1 || d.Summary.Worsened != 1 {
+		t.Errorf("got summary %+v, want 1 worsened addition", d.Summary)
+	}
+}
+
+// TestCompare_Partial — un résultat partiel ne produit pas de suppressions
+func TestCompare_Partial(t *testing.T) {
+	from := scan("a", map[string]*scanner.Result{"subdomain": {Findings: []scanner.Finding{
+		{ID: "subdomain.found", Severity: scanner.SeverityInfo, Asset: "www.example.com"},
+	}}})
+	to := scan("b", map[string]*scanner.Result{"subdomain": {Partial: true}})
+
+	if d := Compare(from, to); d.Summary.Removed != 0 {
+		t.Errorf("got %d removals from a partial result, want 0", d.Summary.Removed)
+	}
+}
+
+// TestBaseline — premier scan : tous les findings sont des ajouts
+func TestBaseline(t *testing.T) {
+	to := scan("a", map[string]*scanner.Result{
+		"header": {Findings: []scanner.Finding{
+			{ID: "header.hsts", Severity: scanner.SeverityMedium, Asset: "example.com"},
+			{ID: "header.csp", Severity: scanner.SeverityInfo, Asset: "example.com"},
+		}},
+		"ssl": nil,
+	})
+
+	d := Baseline(to)
+	if d.From != "" || d.To != "a" {
+		t.Errorf("got from %q to %q, want \"\" → a", d.From, d.To)
+	}
+	if d.Summary != (Summary{Added: 2, Worsened: 1}) {
+		t.Errorf("got summary %+v, want 2 additions, 1 worsened", d.Summary)
+	}
+	if len(d.Skipped) != 1 || d.Skipped[0] != "ssl" {
+		t.Errorf("got skipped %v, want [ssl]", d.Skipped)
+	}
+}
@@ -0,0 +1,119 @@
+package dnsclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// maxTransferRecords — nombre maximal de records lus pendant un transfert
+// Un serveur qui n'envoie jamais le SOA de fin ne doit pas faire grossir la mémoire sans limite
+const maxTransferRecords = 100000
+
+// ErrTransferRefused — le serveur a répondu sans erreur mais sans la zone (pas de SOA en tête de transfert)
+var ErrTransferRefused = errors.New("transfert de zone refusé")
+
+// TransferResult — résultat d'un transfert de zone accepté par le serveur
+type TransferResult struct {
+	Records []dnsmessage.Resource // SOA d'ouverture compris (le SOA de clôture, identique, est omis)
+	// Truncated : le serveur a commencé le transfert (SOA d'ouverture reçu) mais il n'est pas allé jusqu'au
+	// SOA de clôture — limite de records atteinte, timeout ou connexion fermée. La zone est incomplète,
+	// mais le transfert reste ouvert à n'importe qui
+	Truncated bool
+	Reason    string // Cause de l'interruption (vide si le transfert est complet)
+}
+
+// Transfer demande un transfert complet de la zone (AXFR, RFC 5936) à un serveur faisant autorité
+// Le transfert passe toujours en TCP et peut tenir sur plusieurs messages : il se termine au SOA qui le clôt
+// Un serveur correctement configuré refuse : RCodeError (REFUSED, NOTAUTH), ErrTransferRefused ou connexion fermée
+// Une interruption après le SOA d'ouverture n'est pas une erreur : la zone partielle est retournée (TransferResult.Truncated)
+func (c *Client) Transfer(ctx context.Context, server, zone string) (TransferResult, error) {
+	z, err := c.transfer(ctx, server, zone)
+	if err != nil {
+		return TransferResult{}, fmt.Errorf("erreur de transfert de zone %s @%s: %w", zone, server, err)
+	}
+	return z, nil
+}
+
+// transfer envoie la requête AXFR et lit les messages jusqu'au SOA de clôture
+func (c *Client) transfer(ctx context.Context, server, zone string) (TransferResult, error) {
+	qname, err := dnsmessage.NewName(Fqdn(zone))
+	if err != nil {
+		return TransferResult{}, fmt.Errorf("nom DNS invalide %q: %w", zone, err)
+	}
+	question := dnsmessage.Question{Name: qname, Type: dnsmessage.TypeAXFR, Class: dnsmessage.ClassINET}
+	id := uint16(rand.Uint32())
+	query, err := buildQuery(id, question, false, false)
+	if err != nil {
+		return TransferResult{}, err
+	}
+
+	conn, err := c.dial(ctx, "tcp", server)
+	if err != nil {
+		return TransferResult{}, err
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	// Le timeout s'applique à chaque message : une grosse zone peut prendre plus longtemps qu'une requête
+	setDeadline := func() {
+		deadline := time.Now().Add(c.timeout())
+		if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+			deadline = d
+		}
+		_ = conn.SetDeadline(deadline)
+	}
+	setDeadline()
+	if err := WriteTCP(conn, query); err != nil {
+		return TransferResult{}, err
+	}
+
+	var records []dnsmessage.Resource
+	// fail : avant le SOA d'ouverture, le transfert est refusé ou en échec ; après, il est seulement tronqué
+	fail := func(err error) (TransferResult, error) {
+		if len(records) == 0 {
+			return TransferResult{}, err
+		}
+		return TransferResult{Records: records, Truncated: true, Reason: err.Error()}, nil
+	}
+	for {
+		setDeadline()
+		raw, err := ReadTCP(conn)
+		if err != nil {
+			return fail(err)
+		}
+		var msg dnsmessage.Message
+		if err := msg.Unpack(raw); err != nil {
+			return fail(fmt.Errorf("réponse DNS illisible: %w", err))
+		}
+		// Seul le premier message répète la question (RFC 5936 §2.2) : on vérifie l'ID sur tous
+		if msg.Header.ID != id || !msg.Header.Response {
+			return fail(errors.New("réponse DNS ne correspondant pas à la requête"))
+		}
+		if msg.Header.RCode != dnsmessage.RCodeSuccess {
+			return fail(&RCodeError{RCode: msg.Header.RCode})
+		}
+
+		for _, rr := range msg.Answers {
+			if len(records) == 0 && rr.Header.Type != dnsmessage.TypeSOA {
+				return TransferResult{}, ErrTransferRefused
+			}
+			// Un second SOA clôt le transfert
+			if len(records) > 0 && rr.Header.Type == dnsmessage.TypeSOA {
+				return TransferResult{Records: records}, nil
+			}
+			if len(records) == maxTransferRecords {
+				return fail(fmt.Errorf("transfert interrompu au-delà de %d records", maxTransferRecords))
+			}
+			records = append(records, rr)
+		}
+		if len(records) == 0 {
+			return TransferResult{}, ErrTransferRefused
+		}
+	}
+}
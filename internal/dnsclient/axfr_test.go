@@ -0,0 +1,57 @@
+package dnsclient_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/daviani/go__001/internal/dnsclient"
+	"github.com/daviani/go__001/internal/dnsclient/dnstest"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// TestClient_Transfer — zone ouverte transférée en plusieurs messages, zone fermée refusée
+func TestClient_Transfer(t *testing.T) {
+	srv := dnstest.NewServer()
+	defer srv.Close()
+	srv.Add(dnstest.SOA("example.com."), dnstest.SOA("example.org."))
+	for _, host := range []string{"www", "mail", "vpn", "intranet", "staging", "dev"} {
+		srv.Add(dnstest.Record(host+".example.com.", &dnsmessage.AResource{A: [4]byte{192, 0, 2, 1}}))
+	}
+	srv.AllowTransfer("example.com")
+	client := srv.Client()
+	ctx := context.Background()
+
+	zone, err := client.Transfer(ctx, srv.Addr, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// SOA d'ouverture + 6 records A (le SOA de clôture est omis)
+	if len(zone.Records) != 7 || zone.Records[0].Header.Type != dnsmessage.TypeSOA || zone.Truncated {
+		t.Errorf("got %d records (first %s, truncated %v), want 7 starting with SOA", len(zone.Records), zone.Records[0].Header.Type, zone.Truncated)
+	}
+
+	var rcodeErr *dnsclient.RCodeError
+	if _, err := client.Transfer(ctx, srv.Addr, "example.org"); !errors.As(err, &rcodeErr) || rcodeErr.RCode != dnsmessage.RCodeRefused {
+		t.Errorf("got %v, want REFUSED", err)
+	}
+}
+
+// TestClient_Transfer_Truncated — connexion fermée après le SOA d'ouverture : zone partielle, pas une erreur
+func TestClient_Transfer_Truncated(t *testing.T) {
+	srv := dnstest.NewServer()
+	defer srv.Close()
+	srv.Add(dnstest.SOA("example.com."))
+	for _, host := range []string{"www", "mail", "vpn", "intranet", "staging", "dev"} {
+		srv.Add(dnstest.Record(host+".example.com.", &dnsmessage.AResource{A: [4]byte{192, 0, 2, 1}}))
+	}
+	srv.CutTransfer("example.com", 1) // Un seul message de 4 records sur 8
+
+	zone, err := srv.Client().Transfer(context.Background(), srv.Addr, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !zone.Truncated || zone.Reason == "" || len(zone.Records) != 4 || zone.Records[0].Header.Type != dnsmessage.TypeSOA {
+		t.Errorf("got %d records (truncated %v, reason %q), want 4 records of a truncated transfer", len(zone.Records), zone.Truncated, zone.Reason)
+	}
+}
@@ -0,0 +1,282 @@
+package dnsclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// Status — verdict de la validation de la chaîne de confiance
+type Status string
+
+const (
+	StatusSecure   Status = "secure"   // Chaîne complète de la racine jusqu'à la zone du domaine
+	StatusInsecure Status = "insecure" // Zone non signée (aucun DS ni DNSKEY)
+	StatusIsland   Status = "island"   // Zone signée mais sans DS chez le parent : personne ne peut la valider
+	StatusBogus    Status = "bogus"    // Signature invalide ou expirée, DS sans clé correspondante...
+)
+
+// ErrBogus — échec cryptographique de la validation (par opposition à une erreur réseau)
+var ErrBogus = errors.New("validation DNSSEC échouée")
+
+// RootAnchors — ancres de confiance : DS des clés KSK de la racine publiées par l'IANA
+// KSK-2017 (20326) et KSK-2024 (38696), qui la remplace progressivement
+var RootAnchors = []DS{
+	MustParseDS("20326 8 2 E06D44B80B8F1D39A95C0B0D7C65D08458E880409BBC683457104237C7F8EC8D"),
+	MustParseDS("38696 8 2 683D2D0ACB8C9B712A1948B27F741219298D0A450D612C483AF444A4C0FB2B16"),
+}
+
+// Zone — maillon de la chaîne de confiance
+type Zone struct {
+	Name       string    `json:"name"`
+	DS         []DS      `json:"ds,omitempty"`        // DS publiés chez le parent
+	Keys       []DNSKEY  `json:"keys,omitempty"`      // DNSKEY publiées par la zone
+	Expiration time.Time `json:"expiration,omitzero"` // Expiration la plus proche parmi les signatures vérifiées
+}
+
+// Chain — résultat de la validation, zones ordonnées de la racine vers le domaine
+type Chain struct {
+	Status Status `json:"status"`
+	Zones  []Zone `json:"zones"`
+	Reason string `json:"reason,omitempty"` // Explication d'un statut autre que secure
+}
+
+// Zone retourne la zone la plus profonde atteinte (celle du domaine si la chaîne est complète)
+func (c Chain) Zone() Zone {
+	if len(c.Zones) == 0 {
+		return Zone{}
+	}
+	return c.Zones[len(c.Zones)-1]
+}
+
+// ValidateChain valide la chaîne de confiance DNSSEC de domain, de la racine jusqu'à sa zone
+// À chaque niveau : DS chez le parent (signé par le parent) → DNSKEY désignée par le DS → RRset DNSKEY signé par cette clé
+// anchors nil = RootAnchors. L'erreur retournée est réseau : un échec cryptographique donne StatusBogus
+// Limite assumée : les preuves de non-existence (NSEC/NSEC3) ne sont pas vérifiées, l'absence de DS est prise telle quelle
+func (c *Client) ValidateChain(ctx context.Context, domain string, anchors []DS, now time.Time) (Chain, error) {
+	if anchors == nil {
+		anchors = RootAnchors
+	}
+	chain := Chain{Status: StatusSecure}
+
+	root, err := c.validateZone(ctx, ".", anchors, now)
+	chain = chain.with(root)
+	if err != nil {
+		return chain.fail(err)
+	}
+
+	for _, name := range ancestors(domain) {
+		parent := &chain.Zones[len(chain.Zones)-1]
+
+		resp, err := c.Query(ctx, name, TypeDS, true)
+		if err != nil {
+			return chain, err
+		}
+		if resp.Header.RCode == dnsmessage.RCodeNameError {
+			return chain, fmt.Errorf("domaine inexistant : %s", name)
+		}
+
+		// DS présent : délégation signée, on vérifie le DS avec les clés du parent puis on descend
+		if rrset := answers(resp, name, TypeDS); len(rrset) > 0 {
+			exp, err := verifyRRset(rrset, signatures(resp, name, TypeDS), parent.Name, parent.Keys, now)
+			if err != nil {
+				return chain.fail(fmt.Errorf("%w : DS de %s : %v", ErrBogus, name, err))
+			}
+			parent.Expiration = earliest(parent.Expiration, exp)
+
+			var ds []DS
+			for _, rr := range rrset {
+				if d, err := ParseDS(rawData(rr)); err == nil {
+					ds = append(ds, d)
+				}
+			}
+			zone, err := c.validateZone(ctx, name, ds, now)
+			chain = chain.with(zone)
+			if err != nil {
+				return chain.fail(err)
+			}
+			continue
+		}
+
+		// Pas de DS : soit name est un simple nom de la zone parente, soit l'apex d'une zone non signée
+		resp, err = c.Query(ctx, name, dnsmessage.TypeSOA, true)
+		if err != nil {
+			return chain, err
+		}
+		if len(answers(resp, name, dnsmessage.TypeSOA)) == 0 {
+			continue
+		}
+		resp, err = c.Query(ctx, name, TypeDNSKEY, true)
+		if err != nil {
+			return chain, err
+		}
+		zone := Zone{Name: name, Keys: parseKeys(answers(resp, name, TypeDNSKEY))}
+		chain.Zones = append(chain.Zones, zone)
+		if len(zone.Keys) > 0 {
+			chain.Status = StatusIsland
+			chain.Reason = fmt.Sprintf("%s publie des DNSKEY mais aucun DS chez le parent %s", name, parent.Name)
+		} else {
+			chain.Status = StatusInsecure
+			chain.Reason = fmt.Sprintf("%s n'est pas signée (aucun DS chez le parent %s)", name, parent.Name)
+		}
+		return chain, nil
+	}
+
+	// Chaîne complète : on vérifie aussi une signature courante de la zone (SOA), pas seulement ses clés
+	zone := &chain.Zones[len(chain.Zones)-1]
+	resp, err := c.Query(ctx, zone.Name, dnsmessage.TypeSOA, true)
+	if err != nil {
+		return chain, err
+	}
+	rrset := answers(resp, zone.Name, dnsmessage.TypeSOA)
+	if len(rrset) == 0 {
+		return chain.fail(fmt.Errorf("%w : aucun SOA pour %s", ErrBogus, zone.Name))
+	}
+	exp, err := verifyRRset(rrset, signatures(resp, zone.Name, dnsmessage.TypeSOA), zone.Name, zone.Keys, now)
+	if err != nil {
+		return chain.fail(fmt.Errorf("%w : SOA de %s : %v", ErrBogus, zone.Name, err))
+	}
+	zone.Expiration = earliest(zone.Expiration, exp)
+	return chain, nil
+}
+
+// with ajoute une zone à la chaîne — une zone bogus est gardée pour exposer ses clés, une erreur réseau n'en produit pas
+func (c Chain) with(zone Zone) Chain {
+	if zone.Name != "" {
+		c.Zones = append(c.Zones, zone)
+	}
+	return c
+}
+
+// fail marque la chaîne bogus pour un échec cryptographique, ou propage une erreur réseau
+func (c Chain) fail(err error) (Chain, error) {
+	if !errors.Is(err, ErrBogus) {
+		return c, err
+	}
+	c.Status = StatusBogus
+	c.Reason = err.Error()
+	return c, nil
+}
+
+// validateZone récupère les DNSKEY de zone et vérifie qu'une clé désignée par ds signe le RRset DNSKEY
+func (c *Client) validateZone(ctx context.Context, name string, ds []DS, now time.Time) (Zone, error) {
+	resp, err := c.Query(ctx, name, TypeDNSKEY, true)
+	if err != nil {
+		return Zone{}, err
+	}
+	rrset := answers(resp, name, TypeDNSKEY)
+	zone := Zone{Name: name, DS: ds, Keys: parseKeys(rrset)}
+	if len(zone.Keys) == 0 {
+		return zone, fmt.Errorf("%w : DS publié pour %s mais aucune DNSKEY", ErrBogus, name)
+	}
+
+	var trusted []DNSKEY
+	for _, key := range zone.Keys {
+		for _, d := range ds {
+			if d.Matches(name, key) {
+				trusted = append(trusted, key)
+				break
+			}
+		}
+	}
+	if len(trusted) == 0 {
+		return zone, fmt.Errorf("%w : aucune DNSKEY de %s ne correspond au DS du parent", ErrBogus, name)
+	}
+
+	exp, err := verifyRRset(rrset, signatures(resp, name, TypeDNSKEY), name, trusted, now)
+	if err != nil {
+		return zone, fmt.Errorf("%w : DNSKEY de %s : %v", ErrBogus, name, err)
+	}
+	zone.Expiration = exp
+	return zone, nil
+}
+
+// verifyRRset cherche une signature du RRset faite par signer avec l'une des clés keys
+// Retourne l'expiration de la signature valide trouvée
+func verifyRRset(rrset []dnsmessage.Resource, sigs []RRSIG, signer string, keys []DNSKEY, now time.Time) (time.Time, error) {
+	if len(sigs) == 0 {
+		return time.Time{}, errors.New("aucune signature RRSIG")
+	}
+	var lastErr error
+	for _, sig := range sigs {
+		if !strings.EqualFold(Fqdn(sig.SignerName), Fqdn(signer)) {
+			continue
+		}
+		for _, key := range keys {
+			if key.KeyTag() != sig.KeyTag || key.Algorithm != sig.Algorithm {
+				continue
+			}
+			if lastErr = sig.Verify(key, rrset, now); lastErr == nil {
+				return sig.Expiration, nil
+			}
+		}
+	}
+	if lastErr == nil {
+		lastErr = errors.New("aucune signature faite par une clé de confiance")
+	}
+	return time.Time{}, lastErr
+}
+
+// answers filtre les records de la section réponse par nom et type
+func answers(msg *dnsmessage.Message, name string, qtype dnsmessage.Type) []dnsmessage.Resource {
+	var rrset []dnsmessage.Resource
+	for _, rr := range msg.Answers {
+		if rr.Header.Type == qtype && strings.EqualFold(rr.Header.Name.String(), Fqdn(name)) {
+			rrset = append(rrset, rr)
+		}
+	}
+	return rrset
+}
+
+// signatures retourne les RRSIG de la section réponse qui couvrent le type covered de name
+func signatures(msg *dnsmessage.Message, name string, covered dnsmessage.Type) []RRSIG {
+	var sigs []RRSIG
+	for _, rr := range answers(msg, name, TypeRRSIG) {
+		sig, err := ParseRRSIG(rawData(rr))
+		if err == nil && sig.TypeCovered == covered {
+			sigs = append(sigs, sig)
+		}
+	}
+	return sigs
+}
+
+// parseKeys décode les DNSKEY d'un RRset en ignorant les records illisibles
+func parseKeys(rrset []dnsmessage.Resource) []DNSKEY {
+	var keys []DNSKEY
+	for _, rr := range rrset {
+		if key, err := ParseDNSKEY(rawData(rr)); err == nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// rawData retourne les données brutes d'un record inconnu de dnsmessage (DS, RRSIG, DNSKEY)
+func rawData(rr dnsmessage.Resource) []byte {
+	if r, ok := rr.Body.(*dnsmessage.UnknownResource); ok {
+		return r.Data
+	}
+	return nil
+}
+
+// ancestors liste les noms à parcourir sous la racine : "www.example.com" → com., example.com., www.example.com.
+func ancestors(domain string) []string {
+	labels := strings.Split(strings.TrimSuffix(domain, "."), ".")
+	names := make([]string, 0, len(labels))
+	for i := len(labels) - 1; i >= 0; i-- {
+		names = append(names, strings.Join(labels[i:], ".")+".")
+	}
+	return names
+}
+
+// earliest retourne la date la plus proche, en ignorant les dates nulles
+func earliest(a, b time.Time) time.Time {
+	if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+		return b
+	}
+	return a
+}
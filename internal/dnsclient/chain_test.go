@@ -0,0 +1,195 @@
+package dnsclient_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daviani/go__001/internal/dnsclient"
+	"github.com/daviani/go__001/internal/dnsclient/dnstest"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// Package externe (dnsclient_test) : dnstest importe dnsclient, un test interne créerait un cycle d'import
+
+// hierarchy — racine et TLD "com." signés, servis par un serveur de test
+// L'ancre de confiance est la KSK de la racine de test, pas celle de l'IANA
+type hierarchy struct {
+	srv       *dnstest.Server
+	root, com *dnstest.Zone
+}
+
+// newHierarchy démarre le serveur et publie la racine, "com." et la délégation signée entre les deux
+func newHierarchy(t *testing.T) hierarchy {
+	t.Helper()
+	h := hierarchy{srv: dnstest.NewServer(), root: dnstest.NewZone("."), com: dnstest.NewZone("com.")}
+	t.Cleanup(h.srv.Close)
+
+	h.srv.Add(h.root.Records()...)
+	h.srv.Add(h.com.Records()...)
+	h.delegate(h.root, h.com)
+	return h
+}
+
+// delegate publie le DS de child dans parent, signé par parent
+func (h hierarchy) delegate(parent, child *dnstest.Zone) {
+	ds := child.DS(dnsclient.DigestSHA256)
+	h.srv.Add(ds, parent.Sign(ds))
+}
+
+// validate lance la validation de name avec la racine de test comme ancre
+func (h hierarchy) validate(t *testing.T, name string) dnsclient.Chain {
+	t.Helper()
+	chain, err := h.srv.Client().ValidateChain(context.Background(), name, []dnsclient.DS{h.root.Anchor()}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return chain
+}
+
+// TestClient_ValidateChain_Secure — chaîne complète racine → com. → example.com.
+func TestClient_ValidateChain_Secure(t *testing.T) {
+	h := newHierarchy(t)
+	example := dnstest.NewZone("example.com.")
+	h.srv.Add(example.Records()...)
+	h.delegate(h.com, example)
+	www := dnstest.Record("www.example.com.", &dnsmessage.AResource{A: [4]byte{192, 0, 2, 1}})
+	h.srv.Add(www, example.Sign(www))
+
+	// Un sous-domaine sans zone propre est validé via la zone example.com.
+	chain := h.validate(t, "www.example.com")
+	if chain.Status != dnsclient.StatusSecure {
+		t.Fatalf("got status %s (%s), want secure", chain.Status, chain.Reason)
+	}
+	if len(chain.Zones) != 3 {
+		t.Fatalf("got %d zones, want 3 (racine, com., example.com.)", len(chain.Zones))
+	}
+	zone := chain.Zone()
+	if zone.Name != "example.com." || len(zone.Keys) != 2 {
+		t.Errorf("got zone %s with %d keys", zone.Name, len(zone.Keys))
+	}
+	if !zone.Expiration.Equal(example.Expiration) {
+		t.Errorf("got expiration %s, want %s", zone.Expiration, example.Expiration)
+	}
+}
+
+// TestClient_ValidateChain_Insecure — zone non signée : ni DS, ni DNSKEY
+func TestClient_ValidateChain_Insecure(t *testing.T) {
+	h := newHierarchy(t)
+	h.srv.Add(dnstest.SOA("example.com."))
+
+	chain := h.validate(t, "example.com")
+	if chain.Status != dnsclient.StatusInsecure {
+		t.Errorf("got status %s, want insecure", chain.Status)
+	}
+}
+
+// TestClient_ValidateChain_Island — zone signée mais DS jamais publié chez le parent
+func TestClient_ValidateChain_Island(t *testing.T) {
+	h := newHierarchy(t)
+	h.srv.Add(dnstest.NewZone("example.com.").Records()...)
+
+	chain := h.validate(t, "example.com")
+	if chain.Status != dnsclient.StatusIsland {
+		t.Errorf("got status %s, want island", chain.Status)
+	}
+	if len(chain.Zone().Keys) != 2 {
+		t.Errorf("got %d keys, want 2", len(chain.Zone().Keys))
+	}
+}
+
+// TestClient_ValidateChain_Bogus — chaque rupture de la chaîne donne bogus
+func TestClient_ValidateChain_Bogus(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(h hierarchy)
+		reason string
+	}{
+		{
+			// Le parent publie le DS d'une ancienne clé (rotation de KSK ratée)
+			name: "ds mismatch",
+			setup: func(h hierarchy) {
+				h.srv.Add(dnstest.NewZone("example.com.").Records()...)
+				h.delegate(h.com, dnstest.NewZone("example.com."))
+			},
+			reason: "ne correspond",
+		},
+		{
+			// Signatures expirées (re-signature de la zone arrêtée)
+			name: "expired",
+			setup: func(h hierarchy) {
+				example := dnstest.NewZone("example.com.")
+				example.Inception = time.Now().Add(-60 * 24 * time.Hour)
+				example.Expiration = time.Now().Add(-24 * time.Hour)
+				h.srv.Add(example.Records()...)
+				h.delegate(h.com, example)
+			},
+			reason: "expirée",
+		},
+		{
+			// RRset modifié après signature
+			name: "tampered",
+			setup: func(h hierarchy) {
+				example := dnstest.NewZone("example.com.")
+				soa := dnstest.SOA("example.com.")
+				keys := example.Keys()
+				h.srv.Add(append(keys, example.Sign(keys...))...)
+				h.srv.Add(example.Sign(soa))
+				soa.Body.(*dnsmessage.SOAResource).Serial++
+				h.srv.Add(soa)
+				h.delegate(h.com, example)
+			},
+			reason: "SOA",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHierarchy(t)
+			tt.setup(h)
+
+			chain := h.validate(t, "example.com")
+			if chain.Status != dnsclient.StatusBogus {
+				t.Fatalf("got status %s, want bogus", chain.Status)
+			}
+			if !strings.Contains(chain.Reason, tt.reason) {
+				t.Errorf("got reason %q, want %q", chain.Reason, tt.reason)
+			}
+		})
+	}
+}
+
+// TestClient_ValidateChain_RSA — vérification RSA (algorithme 8) et condensat DS SHA-384
+func TestClient_ValidateChain_RSA(t *testing.T) {
+	h := newHierarchy(t)
+	example := dnstest.NewRSAZone("example.com.", dnsclient.AlgRSASHA256, 2048)
+	h.srv.Add(example.Records()...)
+	ds := example.DS(dnsclient.DigestSHA384)
+	h.srv.Add(ds, h.com.Sign(ds))
+
+	chain := h.validate(t, "example.com")
+	if chain.Status != dnsclient.StatusSecure {
+		t.Fatalf("got status %s (%s), want secure", chain.Status, chain.Reason)
+	}
+	if bits := chain.Zone().Keys[0].Bits(); bits != 2048 {
+		t.Errorf("got %d bits, want 2048", bits)
+	}
+}
+
+// TestClient_Query_TCPFallback — réponse trop grande pour l'UDP annoncé → bit TC → nouvelle requête en TCP
+func TestClient_Query_TCPFallback(t *testing.T) {
+	srv := dnstest.NewServer()
+	defer srv.Close()
+	for i := range 40 {
+		srv.Add(dnstest.Record("example.com.", &dnsmessage.TXTResource{TXT: []string{strings.Repeat("x", 100) + string(rune('a'+i%26))}}))
+	}
+
+	resp, err := srv.Client().Query(context.Background(), "example.com", dnsmessage.TypeTXT, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Header.Truncated || len(resp.Answers) != 40 {
+		t.Errorf("got %d answers (truncated=%v), want 40", len(resp.Answers), resp.Header.Truncated)
+	}
+}
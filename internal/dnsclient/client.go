@@ -0,0 +1,280 @@
+package dnsclient
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// Valeurs par défaut du client
+const (
+	defaultTimeout = 5 * time.Second
+	// udpSize — taille de réponse UDP annoncée en EDNS0
+	// 1232 octets évite la fragmentation IP (recommandation du DNS Flag Day 2020)
+	udpSize = 1232
+)
+
+// fallbackServers — résolveurs publics utilisés si /etc/resolv.conf est absent ou vide
+var fallbackServers = []string{"1.1.1.1:53", "8.8.8.8:53"}
+
+// ErrTruncated — réponse tronquée même en TCP (ne devrait pas arriver)
+var ErrTruncated = errors.New("réponse DNS tronquée")
+
+// Client — client DNS bas niveau (format wire, UDP avec repli TCP, EDNS0)
+// net.Resolver ne donne accès ni aux records DNSSEC, ni au choix du serveur interrogé
+// Équivalent JS : le module "dns-packet" + un socket UDP, là où net.Resolver correspond à dns.promises
+type Client struct {
+	Servers     []string      // Résolveurs "ip:port" interrogés dans l'ordre (vide = résolveurs du système)
+	Timeout     time.Duration // Délai par requête et par serveur (0 = 5s)
+	NoRecursion bool          // Requêtes sans bit RD : pour interroger directement un serveur faisant autorité
+
+	// DialContext ouvre les connexions (nil = net.Dialer) — comme http.Transport.DialContext
+	// Permet aux tests de rediriger "ip:53" vers un serveur local
+	DialContext func(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// Query interroge les serveurs dans l'ordre et retourne la première réponse obtenue
+// Une réponse NXDOMAIN ou SERVFAIL est une réponse : seules les erreurs réseau font passer au serveur suivant
+// dnssec active le bit DO (records RRSIG, DNSKEY, DS inclus) et CD (réponses non filtrées par la validation du résolveur)
+func (c *Client) Query(ctx context.Context, name string, qtype dnsmessage.Type, dnssec bool) (*dnsmessage.Message, error) {
+	var lastErr error
+	for _, server := range c.Resolvers() {
+		resp, err := c.Exchange(ctx, server, name, qtype, dnssec)
+		if err == nil {
+			return resp, nil
+		}
+		lastErr = err
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, lastErr
+}
+
+// Exchange envoie une requête à un serveur précis (UDP, puis TCP si la réponse est tronquée)
+func (c *Client) Exchange(ctx context.Context, server, name string, qtype dnsmessage.Type, dnssec bool) (*dnsmessage.Message, error) {
+	qname, err := dnsmessage.NewName(Fqdn(name))
+	if err != nil {
+		return nil, fmt.Errorf("nom DNS invalide %q: %w", name, err)
+	}
+	question := dnsmessage.Question{Name: qname, Type: qtype, Class: dnsmessage.ClassINET}
+
+	id := uint16(rand.Uint32())
+	query, err := buildQuery(id, question, !c.NoRecursion, dnssec)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.exchange(ctx, "udp", server, id, question, query)
+	if err == nil && resp.Header.Truncated {
+		resp, err = c.exchange(ctx, "tcp", server, id, question, query)
+		if err == nil && resp.Header.Truncated {
+			err = ErrTruncated
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erreur de requête DNS %s %s @%s: %w", qtype, name, server, err)
+	}
+	return resp, nil
+}
+
+// buildQuery construit une requête (récursion demandée ou non) avec un record OPT (EDNS0)
+func buildQuery(id uint16, q dnsmessage.Question, recursion, dnssec bool) ([]byte, error) {
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: id, RecursionDesired: recursion, CheckingDisabled: dnssec})
+	b.EnableCompression()
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := b.Question(q); err != nil {
+		return nil, err
+	}
+	if err := b.StartAdditionals(); err != nil {
+		return nil, err
+	}
+	var opt dnsmessage.ResourceHeader
+	if err := opt.SetEDNS0(udpSize, dnsmessage.RCodeSuccess, dnssec); err != nil {
+		return nil, err
+	}
+	if err := b.OPTResource(opt, dnsmessage.OPTResource{}); err != nil {
+		return nil, err
+	}
+	return b.Finish()
+}
+
+// exchange envoie la requête sur une connexion network ("udp" ou "tcp") et lit la réponse correspondante
+func (c *Client) exchange(ctx context.Context, network, server string, id uint16, q dnsmessage.Question, query []byte) (*dnsmessage.Message, error) {
+	conn, err := c.dial(ctx, network, server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// Deadline = la plus proche entre le timeout du client et celle du contexte
+	deadline := time.Now().Add(c.timeout())
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetDeadline(deadline)
+	// Annulation du contexte → débloque immédiatement les lectures en cours
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	if network == "tcp" {
+		return exchangeTCP(conn, id, q, query)
+	}
+	return exchangeUDP(conn, id, q, query)
+}
+
+// dial ouvre une connexion vers server avec DialContext s'il est défini, sinon net.Dialer
+func (c *Client) dial(ctx context.Context, network, server string) (net.Conn, error) {
+	if c.DialContext != nil {
+		return c.DialContext(ctx, network, server)
+	}
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, network, server)
+}
+
+// exchangeUDP envoie un datagramme et ignore les réponses qui ne correspondent pas (ID ou question)
+// Vérifier l'ID et la question protège des réponses usurpées ou en retard
+func exchangeUDP(conn net.Conn, id uint16, q dnsmessage.Question, query []byte) (*dnsmessage.Message, error) {
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 65535)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		var resp dnsmessage.Message
+		if err := resp.Unpack(buf[:n]); err != nil {
+			continue
+		}
+		if matches(&resp, id, q) {
+			return &resp, nil
+		}
+	}
+}
+
+// exchangeTCP envoie la requête préfixée de sa longueur (2 octets) et lit une réponse
+func exchangeTCP(conn net.Conn, id uint16, q dnsmessage.Question, query []byte) (*dnsmessage.Message, error) {
+	if err := WriteTCP(conn, query); err != nil {
+		return nil, err
+	}
+	raw, err := ReadTCP(conn)
+	if err != nil {
+		return nil, err
+	}
+	var resp dnsmessage.Message
+	if err := resp.Unpack(raw); err != nil {
+		return nil, fmt.Errorf("réponse DNS illisible: %w", err)
+	}
+	if !matches(&resp, id, q) {
+		return nil, errors.New("réponse DNS ne correspondant pas à la requête")
+	}
+	return &resp, nil
+}
+
+// WriteTCP écrit un message DNS sur une connexion TCP (préfixe de longueur sur 2 octets, RFC 1035 §4.2.2)
+func WriteTCP(w io.Writer, msg []byte) error {
+	framed := make([]byte, 2+len(msg))
+	binary.BigEndian.PutUint16(framed, uint16(len(msg)))
+	copy(framed[2:], msg)
+	_, err := w.Write(framed)
+	return err
+}
+
+// ReadTCP lit un message DNS préfixé de sa longueur sur une connexion TCP
+func ReadTCP(r io.Reader) ([]byte, error) {
+	var length [2]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return nil, err
+	}
+	msg := make([]byte, binary.BigEndian.Uint16(length[:]))
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// matches vérifie qu'une réponse correspond à la requête (ID, drapeau réponse, question)
+func matches(resp *dnsmessage.Message, id uint16, q dnsmessage.Question) bool {
+	if resp.Header.ID != id || !resp.Header.Response || len(resp.Questions) != 1 {
+		return false
+	}
+	got := resp.Questions[0]
+	return got.Type == q.Type && strings.EqualFold(got.Name.String(), q.Name.String())
+}
+
+// timeout retourne le délai par requête configuré, sinon la valeur par défaut
+func (c *Client) timeout() time.Duration {
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+	return defaultTimeout
+}
+
+// Resolvers retourne les résolveurs interrogés par Query : ceux configurés, sinon ceux du système
+func (c *Client) Resolvers() []string {
+	if len(c.Servers) > 0 {
+		return c.Servers
+	}
+	return SystemServers()
+}
+
+var (
+	systemOnce    sync.Once
+	systemServers []string
+)
+
+// SystemServers retourne les résolveurs de /etc/resolv.conf (lus une seule fois)
+// Sans fichier (Windows, conteneur minimal), on se rabat sur des résolveurs publics
+func SystemServers() []string {
+	systemOnce.Do(func() {
+		systemServers = readResolvConf("/etc/resolv.conf")
+		if len(systemServers) == 0 {
+			systemServers = fallbackServers
+		}
+	})
+	return systemServers
+}
+
+// readResolvConf extrait les lignes "nameserver <ip>" d'un fichier resolv.conf
+func readResolvConf(path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var servers []string
+	lines := bufio.NewScanner(f)
+	for lines.Scan() {
+		fields := strings.Fields(lines.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			if ip := net.ParseIP(fields[1]); ip != nil {
+				servers = append(servers, net.JoinHostPort(ip.String(), "53"))
+			}
+		}
+	}
+	return servers
+}
+
+// Fqdn ajoute le point final d'un nom absolu ("example.com" → "example.com.")
+func Fqdn(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
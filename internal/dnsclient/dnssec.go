@@ -0,0 +1,481 @@
+package dnsclient
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// Types DNSSEC — absents de dnsmessage, qui les expose comme UnknownResource (données brutes)
+const (
+	TypeDS     dnsmessage.Type = 43
+	TypeRRSIG  dnsmessage.Type = 46
+	TypeDNSKEY dnsmessage.Type = 48
+)
+
+// Algorithmes DNSSEC (RFC 8624)
+const (
+	AlgRSAMD5          uint8 = 1
+	AlgDSA             uint8 = 3
+	AlgRSASHA1         uint8 = 5
+	AlgDSANSEC3SHA1    uint8 = 6
+	AlgRSASHA1NSEC3    uint8 = 7
+	AlgRSASHA256       uint8 = 8
+	AlgRSASHA512       uint8 = 10
+	AlgECCGOST         uint8 = 12
+	AlgECDSAP256SHA256 uint8 = 13
+	AlgECDSAP384SHA384 uint8 = 14
+	AlgED25519         uint8 = 15
+	AlgED448           uint8 = 16
+)
+
+// Types de condensat des records DS
+const (
+	DigestSHA1   uint8 = 1
+	DigestSHA256 uint8 = 2
+	DigestSHA384 uint8 = 4
+)
+
+// ErrUnsupportedAlgorithm — algorithme DNSSEC que le validateur ne sait pas vérifier
+var ErrUnsupportedAlgorithm = errors.New("algorithme DNSSEC non supporté")
+
+// AlgorithmName retourne le nom lisible d'un algorithme DNSSEC
+func AlgorithmName(alg uint8) string {
+	switch alg {
+	case AlgRSAMD5:
+		return "RSAMD5"
+	case AlgDSA:
+		return "DSA"
+	case AlgRSASHA1:
+		return "RSASHA1"
+	case AlgDSANSEC3SHA1:
+		return "DSA-NSEC3-SHA1"
+	case AlgRSASHA1NSEC3:
+		return "RSASHA1-NSEC3-SHA1"
+	case AlgRSASHA256:
+		return "RSASHA256"
+	case AlgRSASHA512:
+		return "RSASHA512"
+	case AlgECCGOST:
+		return "ECC-GOST"
+	case AlgECDSAP256SHA256:
+		return "ECDSAP256SHA256"
+	case AlgECDSAP384SHA384:
+		return "ECDSAP384SHA384"
+	case AlgED25519:
+		return "ED25519"
+	case AlgED448:
+		return "ED448"
+	}
+	return fmt.Sprintf("algorithme %d", alg)
+}
+
+// DNSKEY — clé publique d'une zone (RFC 4034 §2)
+type DNSKEY struct {
+	Flags     uint16 `json:"flags"`     // 256 = ZSK, 257 = KSK (bit SEP)
+	Protocol  uint8  `json:"protocol"`  // Toujours 3
+	Algorithm uint8  `json:"algorithm"` // Voir Alg*
+	PublicKey []byte `json:"-"`
+}
+
+// IsKSK indique si la clé porte le bit SEP (Secure Entry Point) : c'est elle que le DS du parent désigne
+func (k DNSKEY) IsKSK() bool { return k.Flags&1 == 1 }
+
+// IsZoneKey indique si la clé peut signer les records de la zone (bit 7)
+func (k DNSKEY) IsZoneKey() bool { return k.Flags&0x0100 != 0 }
+
+// MarshalJSON expose l'identifiant et la taille de la clé plutôt que la clé brute
+func (k DNSKEY) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Flags     uint16 `json:"flags"`
+		Algorithm uint8  `json:"algorithm"`
+		KeyTag    uint16 `json:"key_tag"`
+		Bits      int    `json:"bits,omitempty"`
+	}{k.Flags, k.Algorithm, k.KeyTag(), k.Bits()})
+}
+
+// RData retourne la forme wire de la clé (flags, protocole, algorithme, clé)
+func (k DNSKEY) RData() []byte {
+	data := make([]byte, 4, 4+len(k.PublicKey))
+	binary.BigEndian.PutUint16(data, k.Flags)
+	data[2] = k.Protocol
+	data[3] = k.Algorithm
+	return append(data, k.PublicKey...)
+}
+
+// KeyTag calcule l'identifiant court de la clé (RFC 4034 annexe B) — référencé par les DS et les RRSIG
+func (k DNSKEY) KeyTag() uint16 {
+	var sum uint32
+	for i, b := range k.RData() {
+		if i%2 == 0 {
+			sum += uint32(b) << 8
+		} else {
+			sum += uint32(b)
+		}
+	}
+	sum += sum >> 16
+	return uint16(sum)
+}
+
+// Bits retourne la taille de la clé RSA (0 pour les autres algorithmes)
+func (k DNSKEY) Bits() int {
+	pub, err := k.rsaKey()
+	if err != nil {
+		return 0
+	}
+	return pub.N.BitLen()
+}
+
+// ToDS calcule le DS qui désigne cette clé chez le parent (condensat du nom de la zone + de la clé)
+func (k DNSKEY) ToDS(owner string, digestType uint8) (DS, error) {
+	data := append(canonicalName(owner), k.RData()...)
+	var digest []byte
+	switch digestType {
+	case DigestSHA1:
+		sum := sha1.Sum(data)
+		digest = sum[:]
+	case DigestSHA256:
+		sum := sha256.Sum256(data)
+		digest = sum[:]
+	case DigestSHA384:
+		sum := sha512.Sum384(data)
+		digest = sum[:]
+	default:
+		return DS{}, fmt.Errorf("type de condensat DS non supporté : %d", digestType)
+	}
+	return DS{KeyTag: k.KeyTag(), Algorithm: k.Algorithm, DigestType: digestType, Digest: digest}, nil
+}
+
+// ParseDNSKEY décode la forme wire d'un record DNSKEY
+func ParseDNSKEY(data []byte) (DNSKEY, error) {
+	if len(data) < 4 {
+		return DNSKEY{}, errors.New("record DNSKEY trop court")
+	}
+	return DNSKEY{
+		Flags:     binary.BigEndian.Uint16(data),
+		Protocol:  data[2],
+		Algorithm: data[3],
+		PublicKey: append([]byte(nil), data[4:]...),
+	}, nil
+}
+
+// DS — condensat d'une DNSKEY publié dans la zone parente (RFC 4034 §5)
+type DS struct {
+	KeyTag     uint16 `json:"key_tag"`
+	Algorithm  uint8  `json:"algorithm"`
+	DigestType uint8  `json:"digest_type"`
+	Digest     []byte `json:"-"`
+}
+
+// RData retourne la forme wire du DS
+func (d DS) RData() []byte {
+	data := make([]byte, 4, 4+len(d.Digest))
+	binary.BigEndian.PutUint16(data, d.KeyTag)
+	data[2] = d.Algorithm
+	data[3] = d.DigestType
+	return append(data, d.Digest...)
+}
+
+// String retourne le DS au format zone ("20326 8 2 E06D...")
+func (d DS) String() string {
+	return fmt.Sprintf("%d %d %d %s", d.KeyTag, d.Algorithm, d.DigestType, strings.ToUpper(hex.EncodeToString(d.Digest)))
+}
+
+// ParseDS décode la forme wire d'un record DS
+func ParseDS(data []byte) (DS, error) {
+	if len(data) < 5 {
+		return DS{}, errors.New("record DS trop court")
+	}
+	return DS{
+		KeyTag:     binary.BigEndian.Uint16(data),
+		Algorithm:  data[2],
+		DigestType: data[3],
+		Digest:     append([]byte(nil), data[4:]...),
+	}, nil
+}
+
+// MustParseDS décode un DS au format zone — pour les ancres de confiance déclarées dans le code
+func MustParseDS(s string) DS {
+	var d DS
+	var digest string
+	if _, err := fmt.Sscanf(s, "%d %d %d %s", &d.KeyTag, &d.Algorithm, &d.DigestType, &digest); err != nil {
+		panic("DS invalide : " + s)
+	}
+	raw, err := hex.DecodeString(digest)
+	if err != nil {
+		panic("DS invalide : " + s)
+	}
+	d.Digest = raw
+	return d
+}
+
+// Matches indique si le DS désigne la clé k de la zone owner
+func (d DS) Matches(owner string, k DNSKEY) bool {
+	if d.KeyTag != k.KeyTag() || d.Algorithm != k.Algorithm {
+		return false
+	}
+	computed, err := k.ToDS(owner, d.DigestType)
+	return err == nil && bytes.Equal(computed.Digest, d.Digest)
+}
+
+// RRSIG — signature d'un ensemble de records (RRset) par une clé de zone (RFC 4034 §3)
+type RRSIG struct {
+	TypeCovered dnsmessage.Type `json:"type_covered"`
+	Algorithm   uint8           `json:"algorithm"`
+	Labels      uint8           `json:"labels"`
+	OriginalTTL uint32          `json:"original_ttl"`
+	Expiration  time.Time       `json:"expiration"`
+	Inception   time.Time       `json:"inception"`
+	KeyTag      uint16          `json:"key_tag"`
+	SignerName  string          `json:"signer_name"`
+	Signature   []byte          `json:"-"`
+}
+
+// header retourne la partie signée du RRSIG (tout sauf la signature), nom du signataire en forme canonique
+func (s RRSIG) header() []byte {
+	data := make([]byte, 18, 18+len(s.SignerName)+2)
+	binary.BigEndian.PutUint16(data, uint16(s.TypeCovered))
+	data[2] = s.Algorithm
+	data[3] = s.Labels
+	binary.BigEndian.PutUint32(data[4:], s.OriginalTTL)
+	binary.BigEndian.PutUint32(data[8:], uint32(s.Expiration.Unix()))
+	binary.BigEndian.PutUint32(data[12:], uint32(s.Inception.Unix()))
+	binary.BigEndian.PutUint16(data[16:], s.KeyTag)
+	return append(data, canonicalName(s.SignerName)...)
+}
+
+// RData retourne la forme wire du RRSIG
+func (s RRSIG) RData() []byte {
+	return append(s.header(), s.Signature...)
+}
+
+// ParseRRSIG décode la forme wire d'un record RRSIG
+// Le nom du signataire n'est jamais compressé (RFC 4034 §3.1.7) : lisible sans le reste du message
+func ParseRRSIG(data []byte) (RRSIG, error) {
+	if len(data) < 19 {
+		return RRSIG{}, errors.New("record RRSIG trop court")
+	}
+	signer, n, err := readName(data[18:])
+	if err != nil {
+		return RRSIG{}, fmt.Errorf("record RRSIG invalide: %w", err)
+	}
+	return RRSIG{
+		TypeCovered: dnsmessage.Type(binary.BigEndian.Uint16(data)),
+		Algorithm:   data[2],
+		Labels:      data[3],
+		OriginalTTL: binary.BigEndian.Uint32(data[4:]),
+		Expiration:  time.Unix(int64(binary.BigEndian.Uint32(data[8:])), 0).UTC(),
+		Inception:   time.Unix(int64(binary.BigEndian.Uint32(data[12:])), 0).UTC(),
+		KeyTag:      binary.BigEndian.Uint16(data[16:]),
+		SignerName:  signer,
+		Signature:   append([]byte(nil), data[18+n:]...),
+	}, nil
+}
+
+// SignedData construit les données couvertes par la signature (RFC 4034 §3.1.8.1) :
+// en-tête du RRSIG + chaque record du RRset en forme canonique, triés par RDATA
+func (s RRSIG) SignedData(rrset []dnsmessage.Resource) ([]byte, error) {
+	rdatas := make([][]byte, 0, len(rrset))
+	for _, rr := range rrset {
+		rdata, err := CanonicalRData(rr.Body)
+		if err != nil {
+			return nil, err
+		}
+		rdatas = append(rdatas, rdata)
+	}
+	sort.Slice(rdatas, func(i, j int) bool { return bytes.Compare(rdatas[i], rdatas[j]) < 0 })
+
+	data := s.header()
+	owner := canonicalName(rrset[0].Header.Name.String())
+	for _, rdata := range rdatas {
+		data = append(data, owner...)
+		data = binary.BigEndian.AppendUint16(data, uint16(s.TypeCovered))
+		data = binary.BigEndian.AppendUint16(data, uint16(dnsmessage.ClassINET))
+		data = binary.BigEndian.AppendUint32(data, s.OriginalTTL)
+		data = binary.BigEndian.AppendUint16(data, uint16(len(rdata)))
+		data = append(data, rdata...)
+	}
+	return data, nil
+}
+
+// Verify vérifie la signature d'un RRset avec la clé key, à l'instant now
+func (s RRSIG) Verify(key DNSKEY, rrset []dnsmessage.Resource, now time.Time) error {
+	if len(rrset) == 0 {
+		return errors.New("RRset vide")
+	}
+	if s.KeyTag != key.KeyTag() || s.Algorithm != key.Algorithm {
+		return errors.New("la signature ne correspond pas à la clé")
+	}
+	if now.After(s.Expiration) {
+		return fmt.Errorf("signature expirée depuis le %s", s.Expiration.Format(time.DateOnly))
+	}
+	if now.Before(s.Inception) {
+		return fmt.Errorf("signature valide seulement à partir du %s", s.Inception.Format(time.DateOnly))
+	}
+
+	data, err := s.SignedData(rrset)
+	if err != nil {
+		return err
+	}
+	if err := verifySignature(key, data, s.Signature); err != nil {
+		return fmt.Errorf("signature %s invalide (clé %d): %w", s.TypeCovered, s.KeyTag, err)
+	}
+	return nil
+}
+
+// verifySignature vérifie une signature selon l'algorithme de la clé
+func verifySignature(key DNSKEY, data, sig []byte) error {
+	switch key.Algorithm {
+	case AlgRSASHA1, AlgRSASHA1NSEC3, AlgRSASHA256, AlgRSASHA512:
+		pub, err := key.rsaKey()
+		if err != nil {
+			return err
+		}
+		hash := crypto.SHA256
+		switch key.Algorithm {
+		case AlgRSASHA1, AlgRSASHA1NSEC3:
+			hash = crypto.SHA1
+		case AlgRSASHA512:
+			hash = crypto.SHA512
+		}
+		h := hash.New()
+		h.Write(data)
+		return rsa.VerifyPKCS1v15(pub, hash, h.Sum(nil), sig)
+
+	case AlgECDSAP256SHA256, AlgECDSAP384SHA384:
+		curve, hash, size := elliptic.P256(), crypto.SHA256, 32
+		if key.Algorithm == AlgECDSAP384SHA384 {
+			curve, hash, size = elliptic.P384(), crypto.SHA384, 48
+		}
+		if len(key.PublicKey) != 2*size || len(sig) != 2*size {
+			return errors.New("taille de clé ou de signature ECDSA invalide")
+		}
+		// Clé et signature sont publiées brutes : X||Y et r||s
+		pub := &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(key.PublicKey[:size]),
+			Y:     new(big.Int).SetBytes(key.PublicKey[size:]),
+		}
+		h := hash.New()
+		h.Write(data)
+		r, s := new(big.Int).SetBytes(sig[:size]), new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(pub, h.Sum(nil), r, s) {
+			return errors.New("vérification ECDSA échouée")
+		}
+		return nil
+
+	case AlgED25519:
+		if len(key.PublicKey) != ed25519.PublicKeySize {
+			return errors.New("taille de clé Ed25519 invalide")
+		}
+		if !ed25519.Verify(key.PublicKey, data, sig) {
+			return errors.New("vérification Ed25519 échouée")
+		}
+		return nil
+	}
+	return fmt.Errorf("%w : %s", ErrUnsupportedAlgorithm, AlgorithmName(key.Algorithm))
+}
+
+// rsaKey décode une clé RSA au format RFC 3110 : longueur de l'exposant, exposant, module
+func (k DNSKEY) rsaKey() (*rsa.PublicKey, error) {
+	switch k.Algorithm {
+	case AlgRSAMD5, AlgRSASHA1, AlgRSASHA1NSEC3, AlgRSASHA256, AlgRSASHA512:
+	default:
+		return nil, errors.New("clé non RSA")
+	}
+	key := k.PublicKey
+	if len(key) < 3 {
+		return nil, errors.New("clé RSA trop courte")
+	}
+	expLen, offset := int(key[0]), 1
+	if expLen == 0 {
+		// Exposant de plus de 255 octets : longueur sur 2 octets
+		expLen, offset = int(binary.BigEndian.Uint16(key[1:])), 3
+	}
+	if len(key) <= offset+expLen || expLen > 4 {
+		return nil, errors.New("clé RSA invalide")
+	}
+	exp := new(big.Int).SetBytes(key[offset : offset+expLen])
+	return &rsa.PublicKey{E: int(exp.Int64()), N: new(big.Int).SetBytes(key[offset+expLen:])}, nil
+}
+
+// CanonicalRData retourne la forme wire canonique d'un record : noms en minuscules et non compressés (RFC 4034 §6.2)
+func CanonicalRData(body dnsmessage.ResourceBody) ([]byte, error) {
+	switch r := body.(type) {
+	case *dnsmessage.UnknownResource:
+		return r.Data, nil
+	case *dnsmessage.AResource:
+		return r.A[:], nil
+	case *dnsmessage.AAAAResource:
+		return r.AAAA[:], nil
+	case *dnsmessage.NSResource:
+		return canonicalName(r.NS.String()), nil
+	case *dnsmessage.CNAMEResource:
+		return canonicalName(r.CNAME.String()), nil
+	case *dnsmessage.PTRResource:
+		return canonicalName(r.PTR.String()), nil
+	case *dnsmessage.MXResource:
+		return append(binary.BigEndian.AppendUint16(nil, r.Pref), canonicalName(r.MX.String())...), nil
+	case *dnsmessage.SOAResource:
+		data := append(canonicalName(r.NS.String()), canonicalName(r.MBox.String())...)
+		for _, v := range []uint32{r.Serial, r.Refresh, r.Retry, r.Expire, r.MinTTL} {
+			data = binary.BigEndian.AppendUint32(data, v)
+		}
+		return data, nil
+	case *dnsmessage.TXTResource:
+		var data []byte
+		for _, txt := range r.TXT {
+			data = append(data, byte(len(txt)))
+			data = append(data, txt...)
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("type de record non supporté pour la validation : %T", body)
+}
+
+// canonicalName encode un nom en forme wire canonique : labels en minuscules, sans compression
+func canonicalName(name string) []byte {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	var data []byte
+	if name != "" {
+		for _, label := range strings.Split(name, ".") {
+			data = append(data, byte(len(label)))
+			data = append(data, label...)
+		}
+	}
+	return append(data, 0)
+}
+
+// readName décode un nom non compressé en forme wire, retourne le nom et le nombre d'octets lus
+func readName(data []byte) (string, int, error) {
+	var labels []string
+	for i := 0; i < len(data); {
+		n := int(data[i])
+		if n == 0 {
+			return strings.Join(labels, ".") + ".", i + 1, nil
+		}
+		if n > 63 || i+1+n > len(data) {
+			return "", 0, errors.New("nom mal formé")
+		}
+		labels = append(labels, string(data[i+1:i+1+n]))
+		i += 1 + n
+	}
+	return "", 0, errors.New("nom non terminé")
+}
@@ -0,0 +1,312 @@
+package dnstest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/daviani/go__001/internal/dnsclient"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// Server — serveur DNS faisant autorité, en mémoire, pour les tests (UDP et TCP sur 127.0.0.1)
+// Il répond pour toutes les zones qu'on lui donne : racine, TLD et domaine peuvent cohabiter
+// Équivalent Go de httptest.NewServer, mais pour le DNS
+type Server struct {
+	Addr string // "127.0.0.1:port", identique en UDP et en TCP
+
+	udp net.PacketConn
+	tcp net.Listener
+	wg  sync.WaitGroup
+
+	mu        sync.RWMutex
+	records   map[recordKey][]dnsmessage.Resource
+	transfers map[string]int // Zones ouvertes au transfert (AXFR) → messages envoyés avant de couper (0 = tous)
+}
+
+// recordKey — clé de recherche d'un RRset (nom en minuscules + type)
+type recordKey struct {
+	name  string
+	qtype dnsmessage.Type
+}
+
+// NewServer démarre un serveur sans aucun record — à remplir avec Add
+// Panique si aucun port n'est disponible, comme httptest.NewServer
+func NewServer() *Server {
+	for range 10 {
+		udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			panic(fmt.Sprintf("dnstest: impossible d'écouter en UDP: %v", err))
+		}
+		// Même port en TCP : le client bascule en TCP sur la même adresse quand la réponse est tronquée
+		tcp, err := net.Listen("tcp", udp.LocalAddr().String())
+		if err != nil {
+			udp.Close()
+			continue
+		}
+
+		s := &Server{
+			Addr:      udp.LocalAddr().String(),
+			udp:       udp,
+			tcp:       tcp,
+			records:   make(map[recordKey][]dnsmessage.Resource),
+			transfers: make(map[string]int),
+		}
+		s.wg.Add(2)
+		go s.serveUDP()
+		go s.serveTCP()
+		return s
+	}
+	panic("dnstest: aucun port libre à la fois en UDP et en TCP")
+}
+
+// Client retourne un client DNS qui interroge uniquement ce serveur
+func (s *Server) Client() *dnsclient.Client {
+	return &dnsclient.Client{Servers: []string{s.Addr}}
+}
+
+// Dialer retourne une fonction de connexion (dnsclient.Client.DialContext) qui redirige "ip:port" vers servers[ip]
+// Permet de simuler plusieurs résolveurs ou serveurs faisant autorité sur le port 53 sans privilèges
+func Dialer(servers map[string]*Server) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		srv, ok := servers[host]
+		if !ok {
+			return nil, fmt.Errorf("dnstest: aucun serveur pour %s", address)
+		}
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, network, srv.Addr)
+	}
+}
+
+// Add publie des records (un record par appel ou un RRset entier, signatures comprises)
+func (s *Server) Add(rrs ...dnsmessage.Resource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, rr := range rrs {
+		key := recordKey{strings.ToLower(rr.Header.Name.String()), rr.Header.Type}
+		s.records[key] = append(s.records[key], rr)
+	}
+}
+
+// AllowTransfer autorise le transfert de zone (AXFR) de zone — par défaut, un AXFR reçoit REFUSED
+func (s *Server) AllowTransfer(zone string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.transfers[strings.ToLower(dnsclient.Fqdn(zone))] = 0
+}
+
+// CutTransfer autorise le transfert de zone mais ferme la connexion après messages messages,
+// avant le SOA de clôture — comme un serveur qui tombe ou un pare-feu qui coupe en cours de transfert
+func (s *Server) CutTransfer(zone string, messages int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.transfers[strings.ToLower(dnsclient.Fqdn(zone))] = messages
+}
+
+// Close arrête le serveur et attend la fin des goroutines
+func (s *Server) Close() {
+	s.udp.Close()
+	s.tcp.Close()
+	s.wg.Wait()
+}
+
+// serveUDP répond aux datagrammes jusqu'à la fermeture du socket
+func (s *Server) serveUDP() {
+	defer s.wg.Done()
+	buf := make([]byte, 65535)
+	for {
+		n, addr, err := s.udp.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		if resp := s.handle(buf[:n], true); resp != nil {
+			_, _ = s.udp.WriteTo(resp, addr)
+		}
+	}
+}
+
+// serveTCP accepte les connexions et répond aux requêtes préfixées de leur longueur
+func (s *Server) serveTCP() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.tcp.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			for {
+				query, err := dnsclient.ReadTCP(conn)
+				if err != nil {
+					return
+				}
+				if msgs, cut, ok := s.transfer(query); ok {
+					for _, msg := range msgs {
+						_ = dnsclient.WriteTCP(conn, msg)
+					}
+					if cut {
+						return
+					}
+					continue
+				}
+				if resp := s.handle(query, false); resp != nil {
+					_ = dnsclient.WriteTCP(conn, resp)
+				}
+			}
+		}()
+	}
+}
+
+// handle construit la réponse à une requête : le RRset demandé et ses RRSIG
+// Nom inconnu → NXDOMAIN ; nom connu sans ce type → réponse vide (NODATA)
+// En UDP, une réponse plus grande que la taille annoncée par le client est tronquée (bit TC)
+func (s *Server) handle(raw []byte, udp bool) []byte {
+	var query dnsmessage.Message
+	if err := query.Unpack(raw); err != nil || len(query.Questions) != 1 {
+		return nil
+	}
+	q := query.Questions[0]
+	name := strings.ToLower(q.Name.String())
+
+	resp := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: query.Header.ID, Response: true, Authoritative: true, RecursionDesired: query.Header.RecursionDesired},
+		Questions: query.Questions,
+	}
+
+	s.mu.RLock()
+	resp.Answers = append(resp.Answers, s.records[recordKey{name, q.Type}]...)
+	for _, sig := range s.records[recordKey{name, dnsclient.TypeRRSIG}] {
+		if covers(sig, q.Type) {
+			resp.Answers = append(resp.Answers, sig)
+		}
+	}
+	if len(resp.Answers) == 0 {
+		if !s.exists(name) {
+			resp.Header.RCode = dnsmessage.RCodeNameError
+		}
+		// Réponse négative : le SOA de la zone englobante en section autorité (RFC 2308)
+		resp.Authorities = s.enclosingSOA(name)
+	}
+	s.mu.RUnlock()
+
+	packed, err := resp.Pack()
+	if err != nil {
+		return nil
+	}
+	if udp && len(packed) > maxUDPSize(query) {
+		resp.Header.Truncated = true
+		resp.Answers = nil
+		packed, _ = resp.Pack()
+	}
+	return packed
+}
+
+// transferChunk — nombre de records par message d'un transfert (un vrai serveur remplit jusqu'à 64 Ko)
+// Volontairement petit pour que les tests couvrent les transferts en plusieurs messages
+const transferChunk = 4
+
+// transfer répond à une requête AXFR : REFUSED, ou SOA + records de la zone + SOA, en plusieurs messages
+// cut = true si la connexion doit être fermée après msgs (voir CutTransfer)
+// ok = false si la requête n'est pas un AXFR (traitée alors par handle)
+func (s *Server) transfer(raw []byte) (msgs [][]byte, cut, ok bool) {
+	var query dnsmessage.Message
+	if err := query.Unpack(raw); err != nil || len(query.Questions) != 1 || query.Questions[0].Type != dnsmessage.TypeAXFR {
+		return nil, false, false
+	}
+	zone := strings.ToLower(query.Questions[0].Name.String())
+
+	s.mu.RLock()
+	soa := s.records[recordKey{zone, dnsmessage.TypeSOA}]
+	limit, allowed := s.transfers[zone]
+	allowed = allowed && len(soa) > 0
+	var body []dnsmessage.Resource
+	if allowed {
+		for key, rrs := range s.records {
+			inZone := key.name == zone || strings.HasSuffix(key.name, "."+zone) || zone == "."
+			if inZone && !(key.name == zone && key.qtype == dnsmessage.TypeSOA) {
+				body = append(body, rrs...)
+			}
+		}
+	}
+	s.mu.RUnlock()
+
+	header := dnsmessage.Header{ID: query.Header.ID, Response: true, Authoritative: true}
+	if !allowed {
+		header.RCode = dnsmessage.RCodeRefused
+		resp := dnsmessage.Message{Header: header, Questions: query.Questions}
+		packed, _ := resp.Pack()
+		return [][]byte{packed}, false, true
+	}
+
+	answers := append(append(soa[:1:1], body...), soa[0])
+	for i := 0; i < len(answers); i += transferChunk {
+		resp := dnsmessage.Message{Header: header, Answers: answers[i:min(i+transferChunk, len(answers))]}
+		if i == 0 {
+			resp.Questions = query.Questions
+		}
+		packed, err := resp.Pack()
+		if err != nil {
+			return nil, false, true
+		}
+		msgs = append(msgs, packed)
+	}
+	if limit > 0 && limit < len(msgs) {
+		return msgs[:limit], true, true
+	}
+	return msgs, false, true
+}
+
+// exists indique si le nom porte des records ou en a sous lui (nom intermédiaire, RFC 8020)
+// Appelée sous verrou
+func (s *Server) exists(name string) bool {
+	for key := range s.records {
+		if name == "." || key.name == name || strings.HasSuffix(key.name, "."+name) {
+			return true
+		}
+	}
+	return false
+}
+
+// enclosingSOA retourne le SOA le plus proche de name en remontant les labels
+// Appelée sous verrou
+func (s *Server) enclosingSOA(name string) []dnsmessage.Resource {
+	for {
+		if soa := s.records[recordKey{name, dnsmessage.TypeSOA}]; len(soa) > 0 {
+			return soa[:1]
+		}
+		if name == "." {
+			return nil
+		}
+		_, parent, _ := strings.Cut(name, ".")
+		if parent == "" {
+			parent = "."
+		}
+		name = parent
+	}
+}
+
+// covers indique si un record RRSIG signe le type qtype
+func covers(sig dnsmessage.Resource, qtype dnsmessage.Type) bool {
+	body, ok := sig.Body.(*dnsmessage.UnknownResource)
+	if !ok {
+		return false
+	}
+	parsed, err := dnsclient.ParseRRSIG(body.Data)
+	return err == nil && parsed.TypeCovered == qtype
+}
+
+// maxUDPSize retourne la taille de réponse acceptée par le client : EDNS0 si annoncé, sinon 512 octets
+func maxUDPSize(query dnsmessage.Message) int {
+	for _, rr := range query.Additionals {
+		if rr.Header.Type == dnsmessage.TypeOPT {
+			return int(rr.Header.Class)
+		}
+	}
+	return 512
+}
@@ -0,0 +1,238 @@
+package dnstest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/daviani/go__001/internal/dnsclient"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// ttl — durée de vie des records générés (sans effet : le serveur de test ne met rien en cache)
+const ttl = 3600
+
+// Zone — zone signée de test : une KSK (signe le RRset DNSKEY) et une ZSK (signe le reste)
+// Inception et Expiration bornent les signatures produites ensuite par Sign (modifiables avant signature)
+type Zone struct {
+	Name       string
+	KSK, ZSK   dnsclient.DNSKEY
+	Inception  time.Time
+	Expiration time.Time
+
+	kskKey, zskKey crypto.Signer
+}
+
+// NewZone génère une zone signée en ECDSAP256SHA256 (algorithme 13), signatures valides 30 jours
+func NewZone(name string) *Zone {
+	z := newZone(name)
+	z.kskKey, z.KSK = ecdsaKey(257)
+	z.zskKey, z.ZSK = ecdsaKey(256)
+	return z
+}
+
+// NewRSAZone génère une zone signée en RSA (algorithmes 5, 7, 8 ou 10) avec des clés de bits bits
+func NewRSAZone(name string, alg uint8, bits int) *Zone {
+	z := newZone(name)
+	z.kskKey, z.KSK = rsaKey(257, alg, bits)
+	z.zskKey, z.ZSK = rsaKey(256, alg, bits)
+	return z
+}
+
+// newZone initialise le nom et la période de validité des signatures
+func newZone(name string) *Zone {
+	now := time.Now().Truncate(time.Second)
+	return &Zone{Name: dnsclient.Fqdn(name), Inception: now.Add(-time.Hour), Expiration: now.Add(30 * 24 * time.Hour)}
+}
+
+// Records retourne l'apex de la zone : SOA et RRset DNSKEY, chacun avec sa signature
+func (z *Zone) Records() []dnsmessage.Resource {
+	soa := SOA(z.Name)
+	keys := z.Keys()
+	return append(append(keys, z.Sign(keys...)), soa, z.Sign(soa))
+}
+
+// Keys retourne le RRset DNSKEY de la zone (KSK + ZSK), non signé
+func (z *Zone) Keys() []dnsmessage.Resource {
+	return []dnsmessage.Resource{
+		Raw(z.Name, dnsclient.TypeDNSKEY, z.KSK.RData()),
+		Raw(z.Name, dnsclient.TypeDNSKEY, z.ZSK.RData()),
+	}
+}
+
+// DS retourne le record DS qui désigne la KSK — à publier (et signer) dans la zone parente
+func (z *Zone) DS(digestType uint8) dnsmessage.Resource {
+	ds, err := z.KSK.ToDS(z.Name, digestType)
+	if err != nil {
+		panic(fmt.Sprintf("dnstest: %v", err))
+	}
+	return Raw(z.Name, dnsclient.TypeDS, ds.RData())
+}
+
+// Anchor retourne le DS de la KSK sous forme d'ancre de confiance (zone racine de test)
+func (z *Zone) Anchor() dnsclient.DS {
+	ds, _ := z.KSK.ToDS(z.Name, dnsclient.DigestSHA256)
+	return ds
+}
+
+// Sign signe un RRset (même nom, même type) et retourne le record RRSIG
+// Le RRset DNSKEY est signé par la KSK, tout autre RRset par la ZSK
+func (z *Zone) Sign(rrset ...dnsmessage.Resource) dnsmessage.Resource {
+	header := rrset[0].Header
+	key, signer := z.ZSK, z.zskKey
+	if header.Type == dnsclient.TypeDNSKEY {
+		key, signer = z.KSK, z.kskKey
+	}
+
+	sig := dnsclient.RRSIG{
+		TypeCovered: header.Type,
+		Algorithm:   key.Algorithm,
+		Labels:      labels(header.Name.String()),
+		OriginalTTL: header.TTL,
+		Expiration:  z.Expiration,
+		Inception:   z.Inception,
+		KeyTag:      key.KeyTag(),
+		SignerName:  z.Name,
+	}
+	data, err := sig.SignedData(rrset)
+	if err != nil {
+		panic(fmt.Sprintf("dnstest: %v", err))
+	}
+	sig.Signature = sign(signer, key.Algorithm, data)
+	return Raw(header.Name.String(), dnsclient.TypeRRSIG, sig.RData())
+}
+
+// sign produit une signature au format DNSSEC : r||s pour ECDSA, PKCS#1 v1.5 pour RSA
+func sign(signer crypto.Signer, alg uint8, data []byte) []byte {
+	hash := crypto.SHA256
+	switch alg {
+	case dnsclient.AlgRSASHA1, dnsclient.AlgRSASHA1NSEC3:
+		hash = crypto.SHA1
+	case dnsclient.AlgRSASHA512:
+		hash = crypto.SHA512
+	}
+	h := hash.New()
+	h.Write(data)
+	digest := h.Sum(nil)
+
+	if key, ok := signer.(*ecdsa.PrivateKey); ok {
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest)
+		if err != nil {
+			panic(fmt.Sprintf("dnstest: %v", err))
+		}
+		out := make([]byte, 64)
+		r.FillBytes(out[:32])
+		s.FillBytes(out[32:])
+		return out
+	}
+	out, err := signer.Sign(rand.Reader, digest, hash)
+	if err != nil {
+		panic(fmt.Sprintf("dnstest: %v", err))
+	}
+	return out
+}
+
+// ecdsaKey génère une clé P-256 et sa DNSKEY (clé publique brute X||Y)
+func ecdsaKey(flags uint16) (crypto.Signer, dnsclient.DNSKEY) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(fmt.Sprintf("dnstest: %v", err))
+	}
+	pub := make([]byte, 64)
+	key.X.FillBytes(pub[:32])
+	key.Y.FillBytes(pub[32:])
+	return key, dnsclient.DNSKEY{Flags: flags, Protocol: 3, Algorithm: dnsclient.AlgECDSAP256SHA256, PublicKey: pub}
+}
+
+// rsaKey génère une clé RSA et sa DNSKEY (format RFC 3110 : longueur de l'exposant, exposant, module)
+func rsaKey(flags uint16, alg uint8, bits int) (crypto.Signer, dnsclient.DNSKEY) {
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		panic(fmt.Sprintf("dnstest: %v", err))
+	}
+	exp := binary.BigEndian.AppendUint32(nil, uint32(key.E))
+	for len(exp) > 1 && exp[0] == 0 {
+		exp = exp[1:]
+	}
+	pub := append([]byte{byte(len(exp))}, exp...)
+	pub = append(pub, key.N.Bytes()...)
+	return key, dnsclient.DNSKEY{Flags: flags, Protocol: 3, Algorithm: alg, PublicKey: pub}
+}
+
+// labels compte les labels d'un nom, hors racine (champ Labels du RRSIG)
+func labels(name string) uint8 {
+	name = strings.Trim(name, ".")
+	if name == "" {
+		return 0
+	}
+	return uint8(strings.Count(name, ".") + 1)
+}
+
+// SOA retourne un record SOA minimal pour l'apex name
+func SOA(name string) dnsmessage.Resource {
+	name = dnsclient.Fqdn(name)
+	return Record(name, &dnsmessage.SOAResource{
+		NS:      mustName("ns1." + strings.TrimPrefix(name, ".")),
+		MBox:    mustName("hostmaster." + strings.TrimPrefix(name, ".")),
+		Serial:  1,
+		Refresh: 7200,
+		Retry:   3600,
+		Expire:  1209600,
+		MinTTL:  300,
+	})
+}
+
+// Record construit un record de type connu de dnsmessage (A, MX, TXT...)
+func Record(name string, body dnsmessage.ResourceBody) dnsmessage.Resource {
+	return dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{Name: mustName(name), Type: typeOf(body), Class: dnsmessage.ClassINET, TTL: ttl},
+		Body:   body,
+	}
+}
+
+// Raw construit un record à partir de sa forme wire (DS, DNSKEY, RRSIG, CAA...)
+func Raw(name string, qtype dnsmessage.Type, data []byte) dnsmessage.Resource {
+	return Record(name, &dnsmessage.UnknownResource{Type: qtype, Data: data})
+}
+
+// typeOf retourne le type DNS d'un corps de record (dnsmessage ne l'expose pas)
+func typeOf(body dnsmessage.ResourceBody) dnsmessage.Type {
+	switch b := body.(type) {
+	case *dnsmessage.AResource:
+		return dnsmessage.TypeA
+	case *dnsmessage.AAAAResource:
+		return dnsmessage.TypeAAAA
+	case *dnsmessage.NSResource:
+		return dnsmessage.TypeNS
+	case *dnsmessage.CNAMEResource:
+		return dnsmessage.TypeCNAME
+	case *dnsmessage.SOAResource:
+		return dnsmessage.TypeSOA
+	case *dnsmessage.PTRResource:
+		return dnsmessage.TypePTR
+	case *dnsmessage.MXResource:
+		return dnsmessage.TypeMX
+	case *dnsmessage.TXTResource:
+		return dnsmessage.TypeTXT
+	case *dnsmessage.SRVResource:
+		return dnsmessage.TypeSRV
+	case *dnsmessage.UnknownResource:
+		return b.Type
+	}
+	panic(fmt.Sprintf("dnstest: type de record non géré %T", body))
+}
+
+// mustName convertit un nom en dnsmessage.Name (point final ajouté)
+func mustName(name string) dnsmessage.Name {
+	n, err := dnsmessage.NewName(dnsclient.Fqdn(name))
+	if err != nil {
+		panic(fmt.Sprintf("dnstest: nom invalide %q: %v", name, err))
+	}
+	return n
+}
@@ -0,0 +1,308 @@
+package dnsclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// TypeCAA — record CAA (RFC 8659), absent de dnsmessage comme les types DNSSEC
+const TypeCAA dnsmessage.Type = 257
+
+// types — correspondance nom ↔ type des records interrogeables
+var types = map[string]dnsmessage.Type{
+	"A":      dnsmessage.TypeA,
+	"AAAA":   dnsmessage.TypeAAAA,
+	"CNAME":  dnsmessage.TypeCNAME,
+	"MX":     dnsmessage.TypeMX,
+	"NS":     dnsmessage.TypeNS,
+	"TXT":    dnsmessage.TypeTXT,
+	"SOA":    dnsmessage.TypeSOA,
+	"SRV":    dnsmessage.TypeSRV,
+	"PTR":    dnsmessage.TypePTR,
+	"CAA":    TypeCAA,
+	"DS":     TypeDS,
+	"DNSKEY": TypeDNSKEY,
+	"RRSIG":  TypeRRSIG,
+}
+
+// ParseType retourne le type DNS correspondant à un nom ("MX" → TypeMX)
+func ParseType(name string) (dnsmessage.Type, bool) {
+	t, ok := types[strings.ToUpper(name)]
+	return t, ok
+}
+
+// TypeName retourne le nom d'un type DNS ("MX"), ou "TYPE<n>" pour un type inconnu (RFC 3597)
+func TypeName(t dnsmessage.Type) string {
+	for name, v := range types {
+		if v == t {
+			return name
+		}
+	}
+	return "TYPE" + strconv.Itoa(int(t))
+}
+
+// ErrNXDomain — le nom demandé n'existe pas (RCODE NXDOMAIN)
+var ErrNXDomain = errors.New("domaine inexistant")
+
+// RCodeError — le serveur a répondu par une erreur (SERVFAIL, REFUSED...)
+type RCodeError struct {
+	RCode dnsmessage.RCode
+}
+
+func (e *RCodeError) Error() string {
+	return "réponse DNS en erreur : " + strings.TrimPrefix(e.RCode.String(), "RCode")
+}
+
+// Record — record DNS sous forme lisible (format des fichiers de zone)
+type Record struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	TTL   uint32 `json:"ttl"`
+	Value string `json:"value"` // Ex: "10 mx.example.com." pour un MX, `0 issue "letsencrypt.org"` pour un CAA
+}
+
+// Lookup interroge les résolveurs du client et retourne les records de type qtype
+// Un nom sans record de ce type (NODATA) retourne une liste vide ; un nom inexistant retourne ErrNXDomain
+// Les CNAME suivis par le résolveur sont ignorés : seuls les records du type demandé sont retournés
+func (c *Client) Lookup(ctx context.Context, name string, qtype dnsmessage.Type) ([]Record, error) {
+	resp, err := c.Query(ctx, name, qtype, false)
+	if err != nil {
+		return nil, err
+	}
+	return records(resp, qtype)
+}
+
+// LookupServer est Lookup sur un serveur précis ("ip:port") — comparaison entre résolveurs, serveurs faisant autorité
+func (c *Client) LookupServer(ctx context.Context, server, name string, qtype dnsmessage.Type) ([]Record, error) {
+	resp, err := c.Exchange(ctx, server, name, qtype, false)
+	if err != nil {
+		return nil, err
+	}
+	return records(resp, qtype)
+}
+
+// LookupTXT retourne le texte des records TXT de name, chaînes de chaque record concaténées
+// Même contrat que net.Resolver.LookupTXT (scanner.TXTResolver) : un nom inexistant est une *net.DNSError
+// IsNotFound → le scanner Email passe par les résolveurs configurés (DNS_RESOLVERS) au lieu de ceux du système
+func (c *Client) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	rrs, err := c.Lookup(ctx, name, dnsmessage.TypeTXT)
+	if errors.Is(err, ErrNXDomain) {
+		return nil, &net.DNSError{Err: err.Error(), Name: name, IsNotFound: true}
+	}
+	if err != nil {
+		return nil, err
+	}
+	txt := make([]string, len(rrs))
+	for i, rr := range rrs {
+		txt[i] = rr.Value
+	}
+	return txt, nil
+}
+
+// records convertit la section réponse d'un message en records du type qtype
+func records(resp *dnsmessage.Message, qtype dnsmessage.Type) ([]Record, error) {
+	switch resp.Header.RCode {
+	case dnsmessage.RCodeSuccess:
+	case dnsmessage.RCodeNameError:
+		return nil, ErrNXDomain
+	default:
+		return nil, &RCodeError{RCode: resp.Header.RCode}
+	}
+
+	var out []Record
+	for _, rr := range resp.Answers {
+		if rr.Header.Type != qtype {
+			continue
+		}
+		out = append(out, Record{
+			Name:  rr.Header.Name.String(),
+			Type:  TypeName(qtype),
+			TTL:   rr.Header.TTL,
+			Value: FormatRData(rr.Body),
+		})
+	}
+	return out, nil
+}
+
+// FormatRData retourne la valeur d'un record au format des fichiers de zone
+// TXT : les chaînes d'un même record sont concaténées (comme net.LookupTXT)
+func FormatRData(body dnsmessage.ResourceBody) string {
+	switch r := body.(type) {
+	case *dnsmessage.AResource:
+		return netip.AddrFrom4(r.A).String()
+	case *dnsmessage.AAAAResource:
+		return netip.AddrFrom16(r.AAAA).String()
+	case *dnsmessage.CNAMEResource:
+		return r.CNAME.String()
+	case *dnsmessage.NSResource:
+		return r.NS.String()
+	case *dnsmessage.PTRResource:
+		return r.PTR.String()
+	case *dnsmessage.MXResource:
+		return fmt.Sprintf("%d %s", r.Pref, r.MX.String())
+	case *dnsmessage.TXTResource:
+		return strings.Join(r.TXT, "")
+	case *dnsmessage.SOAResource:
+		return fmt.Sprintf("%s %s %d %d %d %d %d", r.NS.String(), r.MBox.String(), r.Serial, r.Refresh, r.Retry, r.Expire, r.MinTTL)
+	case *dnsmessage.SRVResource:
+		return fmt.Sprintf("%d %d %d %s", r.Priority, r.Weight, r.Port, r.Target.String())
+	case *dnsmessage.UnknownResource:
+		return formatUnknown(r)
+	}
+	return ""
+}
+
+// formatUnknown formate les types absents de dnsmessage (CAA, DS, DNSKEY, RRSIG), sinon au format générique RFC 3597
+func formatUnknown(r *dnsmessage.UnknownResource) string {
+	switch r.Type {
+	case TypeCAA:
+		if caa, err := ParseCAA(r.Data); err == nil {
+			return caa.String()
+		}
+	case TypeDS:
+		if ds, err := ParseDS(r.Data); err == nil {
+			return ds.String()
+		}
+	case TypeDNSKEY:
+		if key, err := ParseDNSKEY(r.Data); err == nil {
+			return fmt.Sprintf("%d %d %d (key tag %d)", key.Flags, key.Protocol, key.Algorithm, key.KeyTag())
+		}
+	case TypeRRSIG:
+		if sig, err := ParseRRSIG(r.Data); err == nil {
+			return fmt.Sprintf("%s %d %s %s", TypeName(sig.TypeCovered), sig.Algorithm, sig.SignerName, sig.Expiration.Format("20060102150405"))
+		}
+	}
+	return fmt.Sprintf(`\# %d %x`, len(r.Data), r.Data)
+}
+
+// CAA — autorisation d'émission de certificats (RFC 8659)
+type CAA struct {
+	Flags uint8  `json:"flags"` // 128 = critique : une AC qui ne comprend pas le tag doit refuser d'émettre
+	Tag   string `json:"tag"`   // issue, issuewild, iodef
+	Value string `json:"value"`
+}
+
+// String retourne le CAA au format zone : `0 issue "letsencrypt.org"`
+func (c CAA) String() string {
+	return fmt.Sprintf("%d %s %q", c.Flags, c.Tag, c.Value)
+}
+
+// ParseCAA décode la forme wire d'un record CAA : flags, longueur du tag, tag, valeur
+func ParseCAA(data []byte) (CAA, error) {
+	if len(data) < 2 || int(data[1]) == 0 || len(data) < 2+int(data[1]) {
+		return CAA{}, errors.New("record CAA invalide")
+	}
+	tagLen := int(data[1])
+	return CAA{Flags: data[0], Tag: strings.ToLower(string(data[2 : 2+tagLen])), Value: string(data[2+tagLen:])}, nil
+}
+
+// RelevantCAA retourne le RRset CAA qui s'applique à name (RFC 8659 §3) et le nom qui le porte
+// On remonte les labels ("www.example.com" → "example.com" → "com") jusqu'au premier RRset non vide
+// Aucun RRset trouvé (owner vide) = toute autorité de certification peut émettre
+// Un nom inexistant n'arrête pas la remontée ; une erreur (SERVFAIL, timeout) l'arrête : une AC refuserait d'émettre
+func (c *Client) RelevantCAA(ctx context.Context, name string) (string, []CAA, error) {
+	for _, candidate := range slices.Backward(ancestors(Fqdn(name))) {
+		resp, err := c.Query(ctx, candidate, TypeCAA, false)
+		if err != nil {
+			return "", nil, err
+		}
+		if _, err := records(resp, TypeCAA); err != nil && !errors.Is(err, ErrNXDomain) {
+			return "", nil, fmt.Errorf("CAA %s : %w", candidate, err)
+		}
+		var set []CAA
+		for _, rr := range resp.Answers {
+			if rr.Header.Type != TypeCAA {
+				continue
+			}
+			caa, err := ParseCAA(rawData(rr))
+			if err != nil {
+				return "", nil, fmt.Errorf("CAA %s : %w", candidate, err)
+			}
+			set = append(set, caa)
+		}
+		if len(set) > 0 {
+			return candidate, set, nil
+		}
+	}
+	return "", nil, nil
+}
+
+// Nameserver — adresse IPv4 d'un serveur faisant autorité
+type Nameserver struct {
+	Name string `json:"name"` // Ex: "ns1.example.com."
+	Addr string `json:"addr"` // "ip:53"
+}
+
+// FindZone retourne l'apex de la zone qui contient name ("www.example.com" → "example.com.")
+// Le SOA est dans la réponse si name est un apex, sinon dans la section autorité
+func (c *Client) FindZone(ctx context.Context, name string) (string, error) {
+	resp, err := c.Query(ctx, name, dnsmessage.TypeSOA, false)
+	if err != nil {
+		return "", err
+	}
+	if resp.Header.RCode == dnsmessage.RCodeNameError {
+		return "", ErrNXDomain
+	}
+	for _, section := range [][]dnsmessage.Resource{resp.Answers, resp.Authorities} {
+		for _, rr := range section {
+			if rr.Header.Type == dnsmessage.TypeSOA {
+				return rr.Header.Name.String(), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("aucun SOA trouvé pour %s", name)
+}
+
+// Authoritative retourne les serveurs faisant autorité pour la zone de name (NS, puis leurs adresses IPv4)
+// Un NS dont le nom ne se résout pas est ignoré : c'est un constat à part (délégation cassée), pas une erreur
+func (c *Client) Authoritative(ctx context.Context, name string) (string, []Nameserver, error) {
+	zone, err := c.FindZone(ctx, name)
+	if err != nil {
+		return "", nil, err
+	}
+	nss, err := c.Lookup(ctx, zone, dnsmessage.TypeNS)
+	if err != nil {
+		return zone, nil, err
+	}
+
+	var servers []Nameserver
+	for _, ns := range nss {
+		addrs, err := c.Lookup(ctx, ns.Value, dnsmessage.TypeA)
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			servers = append(servers, Nameserver{Name: ns.Value, Addr: net.JoinHostPort(addr.Value, "53")})
+		}
+	}
+	return zone, servers, nil
+}
+
+// ParseServers normalise une liste de résolveurs : "1.1.1.1" → "1.1.1.1:53", "2606:4700::1111" → "[2606:4700::1111]:53"
+func ParseServers(raw []string) ([]string, error) {
+	servers := make([]string, 0, len(raw))
+	for _, s := range raw {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if addr, err := netip.ParseAddr(s); err == nil {
+			servers = append(servers, netip.AddrPortFrom(addr, 53).String())
+			continue
+		}
+		addrPort, err := netip.ParseAddrPort(s)
+		if err != nil {
+			return nil, fmt.Errorf("résolveur DNS invalide : %q (attendu ip ou ip:port)", s)
+		}
+		servers = append(servers, addrPort.String())
+	}
+	return servers, nil
+}
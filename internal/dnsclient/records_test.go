@@ -0,0 +1,102 @@
+package dnsclient_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"testing"
+
+	"github.com/daviani/go__001/internal/dnsclient"
+	"github.com/daviani/go__001/internal/dnsclient/dnstest"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// TestParseServers — port 53 ajouté si absent, IPv6 entre crochets, nom d'hôte refusé
+func TestParseServers(t *testing.T) {
+	got, err := dnsclient.ParseServers([]string{"1.1.1.1", " 9.9.9.9:5353", "", "2606:4700::1111"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"1.1.1.1:53", "9.9.9.9:5353", "[2606:4700::1111]:53"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if _, err := dnsclient.ParseServers([]string{"dns.google"}); err == nil {
+		t.Error("expected error for hostname, got nil")
+	}
+}
+
+// TestClient_Lookup — valeurs au format zone, NODATA vide, NXDOMAIN en erreur
+func TestClient_Lookup(t *testing.T) {
+	srv := dnstest.NewServer()
+	defer srv.Close()
+	srv.Add(
+		dnstest.SOA("example.com."),
+		dnstest.Record("example.com.", &dnsmessage.MXResource{Pref: 10, MX: dnsmessage.MustNewName("mx.example.com.")}),
+		dnstest.Raw("example.com.", dnsclient.TypeCAA, append([]byte{128, 5}, "issuepki.goog"...)),
+		dnstest.Record("www.example.com.", &dnsmessage.AResource{A: [4]byte{192, 0, 2, 1}}),
+	)
+	client := srv.Client()
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		qtype dnsmessage.Type
+		want  []string
+	}{
+		{"example.com", dnsmessage.TypeMX, []string{"10 mx.example.com."}},
+		{"example.com", dnsclient.TypeCAA, []string{`128 issue "pki.goog"`}},
+		{"www.example.com", dnsmessage.TypeA, []string{"192.0.2.1"}},
+		{"www.example.com", dnsmessage.TypeAAAA, nil},
+	}
+	for _, tt := range tests {
+		records, err := client.Lookup(ctx, tt.name, tt.qtype)
+		if err != nil {
+			t.Fatalf("%s %s : %v", tt.name, tt.qtype, err)
+		}
+		var got []string
+		for _, r := range records {
+			got = append(got, r.Value)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s %s : got %q, want %q", tt.name, tt.qtype, got, tt.want)
+		}
+	}
+
+	if _, err := client.Lookup(ctx, "missing.example.com", dnsmessage.TypeA); !errors.Is(err, dnsclient.ErrNXDomain) {
+		t.Errorf("got %v, want ErrNXDomain", err)
+	}
+
+	// Sous-domaine sans SOA : la zone est trouvée via la section autorité
+	zone, err := client.FindZone(ctx, "www.example.com")
+	if err != nil || zone != "example.com." {
+		t.Errorf("got zone %q (%v), want example.com.", zone, err)
+	}
+}
+
+// TestClient_LookupTXT — chaînes d'un record concaténées, NXDOMAIN au format de net.DNSError
+func TestClient_LookupTXT(t *testing.T) {
+	srv := dnstest.NewServer()
+	defer srv.Close()
+	srv.Add(
+		dnstest.SOA("example.com."),
+		dnstest.Record("example.com.", &dnsmessage.TXTResource{TXT: []string{"v=spf1 include:_spf.google.com ", "-all"}}),
+	)
+	client := srv.Client()
+	ctx := context.Background()
+
+	txt, err := client.LookupTXT(ctx, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(txt, []string{"v=spf1 include:_spf.google.com -all"}) {
+		t.Errorf("got %q", txt)
+	}
+
+	var dnsErr *net.DNSError
+	if _, err := client.LookupTXT(ctx, "missing.example.com"); !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+		t.Errorf("got %v, want a not found net.DNSError", err)
+	}
+}
@@ -0,0 +1,72 @@
+// Commande gen — régénère le snapshot compact de la preload list embarqué par le package hstspreload
+// Usage (depuis internal/hstspreload, via go generate) : go run ./gen [-in fichier.json] [-date AAAA-MM-JJ] [-o preload.txt.gz]
+// Sans -in, la liste est téléchargée depuis les sources de Chromium (servie en base64 par googlesource)
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/daviani/go__001/internal/hstspreload"
+)
+
+// chromiumURL — transport_security_state_static.json, encodé en base64 par ?format=TEXT
+const chromiumURL = "https://chromium.googlesource.com/chromium/src/+/main/net/http/transport_security_state_static.json?format=TEXT"
+
+func main() {
+	in := flag.String("in", "", "Fichier transport_security_state_static.json déjà téléchargé (vide = Chromium)")
+	out := flag.String("o", "preload.txt.gz", "Fichier compact à écrire")
+	date := flag.String("date", time.Now().UTC().Format(time.DateOnly), "Date de la liste source (avec -in : date du téléchargement)")
+	flag.Parse()
+
+	source, read := chromiumURL, download
+	if *in != "" {
+		source, read = filepath.Base(*in), func() ([]byte, error) { return os.ReadFile(*in) }
+	}
+	data, err := read()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	list, err := hstspreload.Parse(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	header := []string{
+		"Preload list HSTS de Chromium (transport_security_state_static.json), entrées force-https",
+		"Source : " + source + ", liste du " + *date,
+		"Régénérer : go generate ./internal/hstspreload",
+		"Format : un nom par ligne, préfixé par \".\" s'il couvre ses sous-domaines (include_subdomains)",
+	}
+	if err := list.WriteCompact(&buf, header...); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, buf.Bytes(), 0o644); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%s : %d entrées, %d octets\n", *out, list.Len(), buf.Len())
+}
+
+// download récupère la liste de Chromium et décode le base64 de googlesource
+func download() ([]byte, error) {
+	client := &http.Client{Timeout: time.Minute}
+	resp, err := client.Get(chromiumURL)
+	if err != nil {
+		return nil, fmt.Errorf("erreur de téléchargement: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("erreur de téléchargement: %s", resp.Status)
+	}
+	return io.ReadAll(base64.NewDecoder(base64.StdEncoding, resp.Body))
+}
@@ -0,0 +1,189 @@
+package hstspreload
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Rafraîchir le snapshot embarqué : télécharge la liste de Chromium et la réécrit au format compact
+//go:generate go run ./gen -o preload.txt.gz
+
+// snapshot — copie embarquée de la preload list de Chromium, au format compact (voir ParseCompact)
+// Seules les entrées force-https sont gardées : ~150 000 noms, moins de 1 Mo une fois compressés
+//
+//go:embed preload.txt.gz
+var snapshot []byte
+
+// ModeForceHTTPS — seul mode d'une entrée qui impose HTTPS (les autres entrées ne servent qu'à l'épinglage)
+const ModeForceHTTPS = "force-https"
+
+// Entry — une entrée de la preload list
+type Entry struct {
+	Name              string `json:"name"`
+	Policy            string `json:"policy,omitempty"` // Origine de l'inscription : bulk-18-weeks, public-suffix, google...
+	Mode              string `json:"mode,omitempty"`
+	IncludeSubdomains bool   `json:"include_subdomains,omitempty"`
+}
+
+// List — preload list indexée par nom
+type List struct {
+	entries map[string]Entry
+	partial bool
+}
+
+// Parse lit une preload list au format de Chromium
+// Le fichier officiel contient des lignes de commentaires "//", invalides en JSON : elles sont retirées avant décodage
+// Les autres clés du fichier (pinsets) sont ignorées
+func Parse(data []byte) (*List, error) {
+	var clean bytes.Buffer
+	lines := bufio.NewScanner(bytes.NewReader(data))
+	lines.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lines.Scan() {
+		if strings.HasPrefix(strings.TrimSpace(lines.Text()), "//") {
+			continue
+		}
+		clean.Write(lines.Bytes())
+		clean.WriteByte('\n')
+	}
+	if err := lines.Err(); err != nil {
+		return nil, fmt.Errorf("erreur de preload list: %w", err)
+	}
+
+	var file struct {
+		Entries []Entry `json:"entries"`
+	}
+	if err := json.Unmarshal(clean.Bytes(), &file); err != nil {
+		return nil, fmt.Errorf("erreur de preload list: %w", err)
+	}
+	if len(file.Entries) == 0 {
+		return nil, fmt.Errorf("erreur de preload list: aucune entrée")
+	}
+	l := &List{entries: make(map[string]Entry, len(file.Entries))}
+	for _, e := range file.Entries {
+		e.Name = normalize(e.Name)
+		l.entries[e.Name] = e
+	}
+	return l, nil
+}
+
+// ParseCompact lit une preload list au format compact (snapshot embarqué, voir WriteCompact)
+// Texte compressé en gzip, un nom par ligne : "example.com" (nom seul) ou ".example.com" (nom et sous-domaines)
+// Les lignes vides et les commentaires "#" sont ignorés ; toutes les entrées sont en mode force-https
+func ParseCompact(data []byte) (*List, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("erreur de preload list: %w", err)
+	}
+	defer zr.Close()
+
+	l := &List{entries: make(map[string]Entry)}
+	lines := bufio.NewScanner(zr)
+	for lines.Scan() {
+		line := strings.TrimSpace(lines.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, sub := strings.CutPrefix(line, ".")
+		name = normalize(name)
+		l.entries[name] = Entry{Name: name, Mode: ModeForceHTTPS, IncludeSubdomains: sub}
+	}
+	if err := lines.Err(); err != nil {
+		return nil, fmt.Errorf("erreur de preload list: %w", err)
+	}
+	if len(l.entries) == 0 {
+		return nil, fmt.Errorf("erreur de preload list: aucune entrée")
+	}
+	return l, nil
+}
+
+// WriteCompact écrit les entrées force-https de la liste au format de ParseCompact, triées par nom
+// header est recopié en commentaires "#" en tête de fichier (source, date du snapshot)
+func (l *List) WriteCompact(w io.Writer, header ...string) error {
+	names := make([]string, 0, len(l.entries))
+	for name, e := range l.entries {
+		if e.Mode == ModeForceHTTPS {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	zw, err := gzip.NewWriterLevel(w, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	out := bufio.NewWriter(zw)
+	for _, line := range header {
+		fmt.Fprintf(out, "# %s\n", line)
+	}
+	for _, name := range names {
+		if l.entries[name].IncludeSubdomains {
+			out.WriteByte('.')
+		}
+		out.WriteString(name)
+		out.WriteByte('\n')
+	}
+	if err := out.Flush(); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// Load lit une preload list depuis un fichier (ex: transport_security_state_static.json téléchargé)
+func Load(path string) (*List, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erreur de preload list: %w", err)
+	}
+	return Parse(data)
+}
+
+// Snapshot retourne la preload list embarquée — décodée une seule fois, au premier appel
+// Un snapshot illisible (fichier tronqué, corrompu) donne une liste vide marquée partielle :
+// le scanner ne conclut alors à aucune absence plutôt que de signaler tous les domaines
+var Snapshot = sync.OnceValue(func() *List {
+	l, err := ParseCompact(snapshot)
+	if err != nil {
+		log.Println(err)
+		return &List{entries: map[string]Entry{}, partial: true}
+	}
+	return l
+})
+
+// Len retourne le nombre d'entrées de la liste
+func (l *List) Len() int { return len(l.entries) }
+
+// Partial indique une liste incomplète (snapshot embarqué illisible) : Lookup peut confirmer une inscription,
+// mais un domaine introuvable n'est pas pour autant absent de la vraie preload list
+func (l *List) Partial() bool { return l.partial }
+
+// Lookup cherche l'entrée qui impose HTTPS à host : le nom lui-même, ou le parent le plus proche
+// inscrit avec include_subdomains (un TLD comme .dev couvre tous ses domaines)
+// Équivalent de la recherche de TransportSecurityState dans Chromium, réduite au mode force-https
+func (l *List) Lookup(host string) (Entry, bool) {
+	name := normalize(host)
+	for i := 0; ; i++ {
+		if e, ok := l.entries[name]; ok && e.Mode == ModeForceHTTPS && (i == 0 || e.IncludeSubdomains) {
+			return e, true
+		}
+		_, parent, ok := strings.Cut(name, ".")
+		if !ok {
+			return Entry{}, false
+		}
+		name = parent
+	}
+}
+
+// normalize met un nom en minuscules, sans point final
+func normalize(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
@@ -0,0 +1,123 @@
+package hstspreload
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestParse — commentaires "//" du fichier Chromium ignorés, clés inconnues ignorées
+func TestParse(t *testing.T) {
+	l, err := Parse([]byte(`// en-tête de licence
+{
+  // Pinsets
+  "pinsets": [],
+  "entries": [
+    { "name": "Example.com", "policy": "bulk-1-year", "mode": "force-https", "include_subdomains": true },
+    { "name": "exact.org", "policy": "bulk-18-weeks", "mode": "force-https" },
+    { "name": "pins.org", "policy": "custom" },
+    { "name": "dev", "policy": "public-suffix", "mode": "force-https", "include_subdomains": true }
+  ]
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Len() != 4 {
+		t.Errorf("got %d entries, want 4", l.Len())
+	}
+
+	tests := []struct {
+		host  string
+		entry string // "" = non préchargé
+	}{
+		{"example.com", "example.com"},
+		{"www.EXAMPLE.com.", "example.com"},
+		{"exact.org", "exact.org"},
+		{"www.exact.org", ""}, // Pas d'include_subdomains
+		{"pins.org", ""},      // Épinglage seul, pas de force-https
+		{"daviani.dev", "dev"},
+		{"example.net", ""},
+	}
+	for _, tt := range tests {
+		e, ok := l.Lookup(tt.host)
+		if ok != (tt.entry != "") || e.Name != tt.entry {
+			t.Errorf("%s : got %+v (%v), want %q", tt.host, e, ok, tt.entry)
+		}
+	}
+}
+
+// TestParse_Invalid — JSON invalide ou liste vide
+func TestParse_Invalid(t *testing.T) {
+	for _, data := range []string{"{", `{"entries": []}`} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("%q : expected error", data)
+		}
+	}
+}
+
+// TestWriteCompact — aller-retour au format compact : seules les entrées force-https sont gardées
+func TestWriteCompact(t *testing.T) {
+	l, err := Parse([]byte(`{"entries": [
+	  { "name": "example.com", "mode": "force-https", "include_subdomains": true },
+	  { "name": "exact.org", "mode": "force-https" },
+	  { "name": "pins.org", "policy": "custom" }
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := l.WriteCompact(&buf, "en-tête"); err != nil {
+		t.Fatal(err)
+	}
+	compact, err := ParseCompact(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if compact.Len() != 2 {
+		t.Errorf("got %d entries, want 2", compact.Len())
+	}
+	if e, ok := compact.Lookup("www.example.com"); !ok || e.Name != "example.com" {
+		t.Errorf("www.example.com : got %+v (%v), want example.com", e, ok)
+	}
+	if _, ok := compact.Lookup("www.exact.org"); ok {
+		t.Error("www.exact.org : got preloaded, want exact.org only")
+	}
+}
+
+// TestParseCompact_Invalid — données non gzip ou sans entrée
+func TestParseCompact_Invalid(t *testing.T) {
+	var empty bytes.Buffer
+	if err := (&List{}).WriteCompact(&empty, "aucune entrée"); err != nil {
+		t.Fatal(err)
+	}
+	for _, data := range [][]byte{[]byte("example.com\n"), empty.Bytes()} {
+		if _, err := ParseCompact(data); err == nil {
+			t.Errorf("%q : expected error", data)
+		}
+	}
+}
+
+// TestSnapshot — le snapshot embarqué est la liste complète de Chromium : domaines connus inscrits
+func TestSnapshot(t *testing.T) {
+	l := Snapshot()
+	if l.Partial() || l.Len() < 100000 {
+		t.Fatalf("got %d entries (partial %v), want the full Chromium list", l.Len(), l.Partial())
+	}
+	tests := []struct {
+		host  string
+		entry string // "" = non préchargé
+	}{
+		{"github.com", "github.com"},
+		{"gist.github.com", "github.com"}, // include_subdomains
+		{"paypal.com", "paypal.com"},
+		{"www.paypal.com", "www.paypal.com"}, // Inscrit à part : paypal.com ne couvre pas ses sous-domaines
+		{"api.paypal.com", ""},
+		{"daviani.dev", "dev"}, // TLD préchargé en entier
+		{"example.com", ""},
+	}
+	for _, tt := range tests {
+		e, ok := l.Lookup(tt.host)
+		if ok != (tt.entry != "") || e.Name != tt.entry {
+			t.Errorf("%s : got %+v (%v), want %q", tt.host, e, ok, tt.entry)
+		}
+	}
+}
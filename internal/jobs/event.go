@@ -0,0 +1,74 @@
+package jobs
+
+import (
+	"time"
+
+	"github.com/daviani/go__001/internal/scanner"
+)
+
+// EventType — nature d'un événement de progression d'un job
+type EventType string
+
+const (
+	EventScannerStarted  EventType = "scanner.started"  // Un worker a démarré le scanner
+	EventFinding         EventType = "finding"          // Un finding remonté par le scanner
+	EventScannerFinished EventType = "scanner.finished" // Le scanner a terminé (résultat éventuellement partiel)
+	EventScannerFailed   EventType = "scanner.failed"   // Le scanner a échoué ou a été annulé
+	EventJobFinished     EventType = "job.finished"     // Dernier événement du job
+)
+
+// Event — événement de progression d'un job, diffusé en Server-Sent Events
+// Seq est la position de l'événement dans le job : sert d'ID SSE (reprise via Last-Event-ID)
+type Event struct {
+	Seq     int              `json:"seq"`
+	Type    EventType        `json:"type"`
+	JobID   string           `json:"job_id"`
+	Domain  string           `json:"domain"`
+	Scanner string           `json:"scanner,omitempty"`
+	Finding *scanner.Finding `json:"finding,omitempty"` // Événement "finding"
+	Run     *Run             `json:"run,omitempty"`     // Événements scanner.finished / scanner.failed
+	Status  Status           `json:"status,omitempty"`  // Événement job.finished : statut final
+	Time    time.Time        `json:"time"`
+}
+
+// emit ajoute un événement au journal du job et réveille les abonnés
+// Appelé avec j.mu verrouillé
+func (j *Job) emit(e Event) {
+	e.Seq = len(j.events)
+	e.JobID = j.id
+	e.Domain = j.domain
+	e.Time = time.Now()
+	j.events = append(j.events, e)
+
+	// Diffusion : fermer le channel réveille tous les abonnés en attente,
+	// un nouveau channel est créé pour la prochaine attente
+	close(j.notify)
+	j.notify = make(chan struct{})
+}
+
+// EventsSince retourne les événements à partir de la position seq
+// - wait est fermé dès qu'un nouvel événement arrive (à utiliser dans un select)
+// - finished vaut true si le job est terminé : aucun événement ne suivra ceux retournés
+// Le journal est conservé → un abonné tardif rejoue tout depuis le début
+func (j *Job) EventsSince(seq int) (events []Event, wait <-chan struct{}, finished bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if seq < 0 {
+		seq = 0
+	}
+	if seq < len(j.events) {
+		events = append(events, j.events[seq:]...)
+	}
+	return events, j.notify, j.closed()
+}
+
+// closed indique si complete a été appelé (job.finished émis) — appelé avec j.mu verrouillé
+func (j *Job) closed() bool {
+	select {
+	case <-j.done:
+		return true
+	default:
+		return false
+	}
+}
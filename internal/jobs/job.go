@@ -0,0 +1,208 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/daviani/go__001/internal/scanner"
+)
+
+// Status — état d'un job ou de l'exécution d'un scanner dans un job
+type Status string
+
+const (
+	StatusQueued   Status = "queued"   // En attente d'un worker
+	StatusRunning  Status = "running"  // Au moins un scanner en cours
+	StatusDone     Status = "done"     // Terminé (éventuellement avec des résultats partiels)
+	StatusFailed   Status = "failed"   // Tous les scanners ont échoué
+	StatusCanceled Status = "canceled" // Annulé par DELETE /scans/{id} ou déconnexion du client
+)
+
+// Finished indique si le statut est terminal (le job ne bougera plus)
+func (s Status) Finished() bool {
+	return s == StatusDone || s == StatusFailed || s == StatusCanceled
+}
+
+// Run — exécution d'un scanner au sein d'un job
+type Run struct {
+	Scanner    string          `json:"scanner"`
+	Status     Status          `json:"status"`
+	StartedAt  *time.Time      `json:"started_at,omitempty"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+	Result     *scanner.Result `json:"result,omitempty"` // nil tant que le scanner n'a pas fini
+	Error      string          `json:"error,omitempty"`
+}
+
+// Progress — avancement d'un job (nombre de scanners terminés sur le total)
+type Progress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+// Snapshot — copie figée de l'état d'un job, sérialisable en JSON (GET /scans/{id})
+type Snapshot struct {
+	ID         string     `json:"id"`
+	Domain     string     `json:"domain"`
+	Status     Status     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Progress   Progress   `json:"progress"`
+	Runs       []Run      `json:"scanners"`
+}
+
+// Job — scan asynchrone d'un domaine par un ou plusieurs scanners
+// Les champs sont protégés par mu : les workers écrivent pendant que les handlers lisent
+type Job struct {
+	mu         sync.Mutex
+	id         string
+	domain     string
+	status     Status
+	canceled   bool
+	createdAt  time.Time
+	startedAt  *time.Time
+	finishedAt *time.Time
+	runs       []Run
+	streamed   []int   // Findings déjà diffusés pendant l'exécution de chaque scanner (voir found)
+	remaining  int     // Scanners pas encore terminés — le job finit quand il tombe à 0
+	events     []Event // Journal des événements (rejoué aux abonnés SSE)
+
+	ctx    context.Context    // Annulé par Cancel ou à la deadline du job
+	cancel context.CancelFunc // Libère le timer du contexte
+	done   chan struct{}      // Fermé quand le job est terminé
+	notify chan struct{}      // Fermé puis recréé à chaque événement (réveil des abonnés)
+}
+
+// ID retourne l'identifiant du job
+func (j *Job) ID() string { return j.id }
+
+// Done retourne un channel fermé quand le job est terminé
+// Usage : select { case <-job.Done(): ... case <-ctx.Done(): ... }
+func (j *Job) Done() <-chan struct{} { return j.done }
+
+// Snapshot retourne une copie de l'état courant du job
+// La slice runs est copiée → l'appelant peut la lire sans lock
+func (j *Job) Snapshot() Snapshot {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	runs := make([]Run, len(j.runs))
+	copy(runs, j.runs)
+
+	return Snapshot{
+		ID:         j.id,
+		Domain:     j.domain,
+		Status:     j.status,
+		CreatedAt:  j.createdAt,
+		StartedAt:  j.startedAt,
+		FinishedAt: j.finishedAt,
+		Progress:   Progress{Done: len(j.runs) - j.remaining, Total: len(j.runs)},
+		Runs:       runs,
+	}
+}
+
+// isCanceled indique si Cancel a été appelé sur le job
+func (j *Job) isCanceled() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.canceled
+}
+
+// start marque le scanner i comme démarré
+// Retourne false si le job a été annulé entre-temps (le scanner ne doit pas être lancé)
+func (j *Job) start(i int) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.ctx.Err() != nil {
+		return false
+	}
+
+	now := time.Now()
+	j.runs[i].Status = StatusRunning
+	j.runs[i].StartedAt = &now
+	if j.startedAt == nil {
+		j.startedAt = &now
+		j.status = StatusRunning
+	}
+	j.emit(Event{Type: EventScannerStarted, Scanner: j.runs[i].Scanner})
+	return true
+}
+
+// found diffuse un finding remonté par le scanner i pendant son exécution (événement "finding")
+// Ignoré si le scanner est déjà terminé : seul son résultat final compte alors
+func (j *Job) found(i int, f scanner.Finding) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.runs[i].Status != StatusRunning {
+		return
+	}
+	j.streamed[i]++
+	j.emit(Event{Type: EventFinding, Scanner: j.runs[i].Scanner, Finding: &f})
+}
+
+// finish enregistre le résultat du scanner i et fixe le statut final si c'était le dernier
+// Retourne true si c'était le dernier scanner (un seul appel par job retourne true)
+func (j *Job) finish(i int, result *scanner.Result, status Status, errMsg string) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	now := time.Now()
+	j.runs[i].Status = status
+	j.runs[i].FinishedAt = &now
+	j.runs[i].Result = result
+	j.runs[i].Error = errMsg
+
+	// Les findings sont en principe déjà diffusés un par un (found) ; un scanner qui ne passe pas par
+	// WithFindingFunc (scanner externe) voit les siens diffusés ici, puis la fin du scanner avec son résultat complet
+	name := j.runs[i].Scanner
+	if result != nil {
+		for k := j.streamed[i]; k < len(result.Findings); k++ {
+			j.emit(Event{Type: EventFinding, Scanner: name, Finding: &result.Findings[k]})
+		}
+	}
+	run := j.runs[i]
+	eventType := EventScannerFinished
+	if status != StatusDone {
+		eventType = EventScannerFailed
+	}
+	j.emit(Event{Type: eventType, Scanner: name, Run: &run})
+
+	j.remaining--
+	if j.remaining > 0 {
+		return false
+	}
+
+	// Dernier scanner terminé → statut global du job
+	// Le job n'est pas encore clos : le manager appelle OnFinish puis complete
+	j.finishedAt = &now
+	j.status = j.finalStatus()
+	return true
+}
+
+// complete clôt le job : émet job.finished, libère le contexte et ferme Done
+// Appelé après OnFinish → un client réveillé par Done trouve le scan déjà dans l'historique
+func (j *Job) complete() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.emit(Event{Type: EventJobFinished, Status: j.status})
+	j.cancel()
+	close(j.done)
+}
+
+// finalStatus calcule le statut terminal : annulé, échoué (aucun scanner n'a abouti) ou terminé
+// Appelé avec j.mu verrouillé
+func (j *Job) finalStatus() Status {
+	if j.canceled {
+		return StatusCanceled
+	}
+	for _, run := range j.runs {
+		if run.Status == StatusDone {
+			return StatusDone
+		}
+	}
+	return StatusFailed
+}
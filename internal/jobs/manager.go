@@ -0,0 +1,242 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/daviani/go__001/internal/scanner"
+)
+
+// ErrQueueFull — la file d'attente n'a plus la place pour les scanners du job
+var ErrQueueFull = errors.New("file d'attente des scans pleine")
+
+// ErrNoScanner — un job doit lancer au moins un scanner
+var ErrNoScanner = errors.New("aucun scanner à lancer")
+
+// Valeurs par défaut de Config
+const (
+	defaultWorkers   = 4
+	defaultQueueSize = 100
+	defaultRetention = time.Hour
+)
+
+// Config — réglages du gestionnaire de jobs
+type Config struct {
+	Workers         int                      // Nombre de scanners exécutés en parallèle (tous jobs confondus)
+	QueueSize       int                      // Nombre de scanners en attente avant de refuser les jobs
+	MaxJobScanners  int                      // Taille du plus gros job possible (taille du registre) : plancher de QueueSize
+	Retention       time.Duration            // Durée de conservation en mémoire d'un job terminé
+	ScannerTimeouts map[string]time.Duration // Timeout propre à un scanner (ex: "subdomain" → 45s)
+
+	// OnFinish est appelé une fois par job, dans le worker, quand son dernier scanner termine
+	// Sert à persister l'historique sans que jobs ne dépende du stockage
+	OnFinish func(Snapshot)
+}
+
+// Request — description d'un job à lancer
+type Request struct {
+	Domain   string
+	Scanners []scanner.Scanner
+	Options  map[string]scanner.Options // Options validées, indexées par nom de scanner
+	Timeout  time.Duration              // Deadline du job complet (0 = aucune)
+}
+
+// task — exécution d'un scanner d'un job, consommée par un worker
+type task struct {
+	job     *Job
+	index   int // Position du scanner dans job.runs
+	scanner scanner.Scanner
+	opts    scanner.Options
+}
+
+// Manager — exécute les jobs sur un pool borné de workers
+// Les workers consomment une file de tâches (une tâche = un scanner d'un job) :
+// quel que soit le nombre de requêtes, au plus Workers scanners tournent en même temps
+type Manager struct {
+	cfg   Config
+	tasks chan task
+
+	mu   sync.Mutex
+	jobs map[string]*Job
+}
+
+// NewManager crée le gestionnaire et démarre ses workers
+func NewManager(cfg Config) *Manager {
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultWorkers
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
+	// Un job est placé en entier dans la file ou refusé : une file plus petite que le plus gros job
+	// refuserait ce job même avec un gestionnaire inactif
+	if cfg.QueueSize < cfg.MaxJobScanners {
+		cfg.QueueSize = cfg.MaxJobScanners
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = defaultRetention
+	}
+
+	m := &Manager{
+		cfg:   cfg,
+		tasks: make(chan task, cfg.QueueSize),
+		jobs:  make(map[string]*Job),
+	}
+	for i := 0; i < cfg.Workers; i++ {
+		go m.worker()
+	}
+	return m
+}
+
+// Submit crée un job et place ses scanners dans la file — retourne immédiatement
+// ErrQueueFull si la file ne peut pas accueillir tous les scanners du job
+func (m *Manager) Submit(req Request) (*Job, error) {
+	if len(req.Scanners) == 0 {
+		return nil, ErrNoScanner
+	}
+
+	// Contexte détaché de la requête HTTP : le job survit à la réponse 202
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if req.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), req.Timeout)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+
+	job := &Job{
+		id:        newID(),
+		domain:    req.Domain,
+		status:    StatusQueued,
+		createdAt: time.Now(),
+		runs:      make([]Run, len(req.Scanners)),
+		streamed:  make([]int, len(req.Scanners)),
+		remaining: len(req.Scanners),
+		ctx:       ctx,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+		notify:    make(chan struct{}),
+	}
+	for i, sc := range req.Scanners {
+		job.runs[i] = Run{Scanner: sc.Name(), Status: StatusQueued}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Vérification + envoi sous le même lock : Submit est le seul à écrire dans m.tasks,
+	// la place disponible ne peut donc pas diminuer entre le test et l'envoi
+	if cap(m.tasks)-len(m.tasks) < len(req.Scanners) {
+		cancel()
+		return nil, ErrQueueFull
+	}
+
+	m.prune()
+	m.jobs[job.id] = job
+	for i, sc := range req.Scanners {
+		m.tasks <- task{job: job, index: i, scanner: sc, opts: req.Options[sc.Name()]}
+	}
+	return job, nil
+}
+
+// Get retourne le job d'identifiant id
+func (m *Manager) Get(id string) (*Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	return job, ok
+}
+
+// Cancel annule le job id — les scanners en cours s'arrêtent, ceux en attente ne démarrent pas
+// Sans effet sur un job déjà terminé
+func (m *Manager) Cancel(id string) (*Job, bool) {
+	job, ok := m.Get(id)
+	if !ok {
+		return nil, false
+	}
+
+	job.mu.Lock()
+	if !job.status.Finished() {
+		job.canceled = true
+	}
+	job.mu.Unlock()
+
+	job.cancel()
+	return job, true
+}
+
+// worker exécute les tâches de la file une par une, jusqu'à la fin du programme
+func (m *Manager) worker() {
+	for t := range m.tasks {
+		m.execute(t)
+	}
+}
+
+// execute lance un scanner et enregistre son résultat dans le job
+// Si c'était le dernier scanner : OnFinish, puis clôture du job
+func (m *Manager) execute(t task) {
+	result, status, errMsg := m.run(t)
+	if !t.job.finish(t.index, result, status, errMsg) {
+		return
+	}
+	if m.cfg.OnFinish != nil {
+		m.cfg.OnFinish(t.job.Snapshot())
+	}
+	t.job.complete()
+}
+
+// run lance le scanner d'une tâche et traduit son issue en statut
+func (m *Manager) run(t task) (*scanner.Result, Status, string) {
+	name := t.scanner.Name()
+
+	// Job annulé (ou deadline dépassée) pendant l'attente → le scanner n'est pas lancé
+	// La deadline n'est pas une annulation : le scanner est en échec pour timeout, comme après scanner.Run
+	if !t.job.start(t.index) {
+		if errors.Is(t.job.ctx.Err(), context.DeadlineExceeded) && !t.job.isCanceled() {
+			return nil, StatusFailed, "scan interrompu (timeout) avant son démarrage"
+		}
+		return nil, StatusCanceled, "scan annulé avant son démarrage"
+	}
+
+	// Chaque finding est diffusé dès que le scanner le trouve, sans attendre la fin du scanner
+	ctx := scanner.WithFindingFunc(t.job.ctx, func(f scanner.Finding) { t.job.found(t.index, f) })
+	result, err := scanner.Run(ctx, t.scanner, t.job.domain, t.opts, m.cfg.ScannerTimeouts[name])
+	if err == nil {
+		return &result, StatusDone, ""
+	}
+
+	log.Println(err)
+	switch {
+	case t.job.isCanceled():
+		return &result, StatusCanceled, "scan annulé"
+	case result.Partial:
+		// Deadline atteinte : le résultat partiel reste exploitable → statut done
+		return &result, StatusDone, "scan interrompu (timeout)"
+	default:
+		return nil, StatusFailed, "erreur interne du serveur"
+	}
+}
+
+// prune supprime les jobs terminés depuis plus de Retention — appelé avec m.mu verrouillé
+func (m *Manager) prune() {
+	limit := time.Now().Add(-m.cfg.Retention)
+	for id, job := range m.jobs {
+		snap := job.Snapshot()
+		if snap.FinishedAt != nil && snap.FinishedAt.Before(limit) {
+			delete(m.jobs, id)
+		}
+	}
+}
+
+// newID génère un identifiant aléatoire de 16 caractères hexadécimaux
+// crypto/rand (et pas math/rand) : un ID de job ne doit pas être devinable
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,228 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daviani/go__001/internal/scanner"
+)
+
+// fakeScanner — scanner de test : retourne un finding après delay, ou err si défini
+type fakeScanner struct {
+	name  string
+	delay time.Duration
+	err   error
+}
+
+func (f fakeScanner) Name() string           { return f.name }
+func (f fakeScanner) Info() scanner.Info     { return scanner.Info{Title: f.name} }
+func (f fakeScanner) Schema() scanner.Schema { return nil }
+
+func (f fakeScanner) Scan(ctx context.Context, domain string, opts scanner.Options) (scanner.Result, error) {
+	select {
+	case <-time.After(f.delay):
+	case <-ctx.Done():
+		return scanner.Result{}, ctx.Err()
+	}
+	if f.err != nil {
+		return scanner.Result{}, f.err
+	}
+	return scanner.Result{Findings: []scanner.Finding{{ID: f.name + ".ok", Asset: domain}}}, nil
+}
+
+// wait attend la fin du job (échec du test au-delà d'une seconde)
+func wait(t *testing.T, job *Job) Snapshot {
+	t.Helper()
+	select {
+	case <-job.Done():
+	case <-time.After(time.Second):
+		t.Fatal("job not finished after 1s")
+	}
+	return job.Snapshot()
+}
+
+// TestManager_Submit — un job termine avec un résultat par scanner, même si l'un échoue
+func TestManager_Submit(t *testing.T) {
+	m := NewManager(Config{Workers: 2})
+
+	job, err := m.Submit(Request{
+		Domain:   "example.com",
+		Scanners: []scanner.Scanner{fakeScanner{name: "a"}, fakeScanner{name: "b", err: errors.New("boom")}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snap := wait(t, job)
+	if snap.Status != StatusDone {
+		t.Errorf("got status %s, want done", snap.Status)
+	}
+	if snap.Progress != (Progress{Done: 2, Total: 2}) {
+		t.Errorf("got progress %+v, want 2/2", snap.Progress)
+	}
+	if snap.Runs[0].Status != StatusDone || snap.Runs[0].Result == nil {
+		t.Errorf("got run a %+v, want done with result", snap.Runs[0])
+	}
+	if snap.Runs[1].Status != StatusFailed {
+		t.Errorf("got run b status %s, want failed", snap.Runs[1].Status)
+	}
+
+	// Le job reste consultable par son ID
+	if _, ok := m.Get(job.ID()); !ok {
+		t.Errorf("expected job %s to be retrievable", job.ID())
+	}
+}
+
+// TestManager_OnFinish — le hook est appelé une seule fois, avec le job terminé
+func TestManager_OnFinish(t *testing.T) {
+	finished := make(chan Snapshot, 2)
+	m := NewManager(Config{Workers: 2, OnFinish: func(snap Snapshot) { finished <- snap }})
+
+	_, err := m.Submit(Request{
+		Domain:   "example.com",
+		Scanners: []scanner.Scanner{fakeScanner{name: "a"}, fakeScanner{name: "b"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case snap := <-finished:
+		if snap.Status != StatusDone || snap.FinishedAt == nil {
+			t.Errorf("got status %s, want a finished job", snap.Status)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("OnFinish not called after 1s")
+	}
+
+	select {
+	case <-finished:
+		t.Error("OnFinish called twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// TestManager_Cancel — un job annulé s'arrête sans attendre ses scanners lents
+func TestManager_Cancel(t *testing.T) {
+	m := NewManager(Config{Workers: 1})
+
+	job, err := m.Submit(Request{
+		Domain:   "example.com",
+		Scanners: []scanner.Scanner{fakeScanner{name: "slow", delay: time.Hour}, fakeScanner{name: "queued"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Laisse le worker démarrer le premier scanner avant d'annuler
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := m.Cancel(job.ID()); !ok {
+		t.Fatal("expected job to exist")
+	}
+
+	snap := wait(t, job)
+	if snap.Status != StatusCanceled {
+		t.Errorf("got status %s, want canceled", snap.Status)
+	}
+	for _, run := range snap.Runs {
+		if run.Status != StatusCanceled {
+			t.Errorf("got run %s status %s, want canceled", run.Scanner, run.Status)
+		}
+	}
+}
+
+// TestManager_Timeout — deadline du job atteinte : le scanner en cours garde son résultat partiel,
+// celui resté en file est en échec pour timeout, pas annulé
+func TestManager_Timeout(t *testing.T) {
+	m := NewManager(Config{Workers: 1})
+
+	job, err := m.Submit(Request{
+		Domain:   "example.com",
+		Scanners: []scanner.Scanner{fakeScanner{name: "slow", delay: time.Hour}, fakeScanner{name: "queued"}},
+		Timeout:  20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snap := wait(t, job)
+	if snap.Status != StatusDone {
+		t.Errorf("got status %s, want done", snap.Status)
+	}
+	if slow := snap.Runs[0]; slow.Status != StatusDone || slow.Result == nil || !slow.Result.Partial {
+		t.Errorf("got %+v, want a partial done run", slow)
+	}
+	if queued := snap.Runs[1]; queued.Status != StatusFailed || !strings.Contains(queued.Error, "timeout") {
+		t.Errorf("got %+v, want failed on timeout", queued)
+	}
+}
+
+// TestManager_QueueFull — la file bornée refuse les jobs au-delà de sa capacité
+func TestManager_QueueFull(t *testing.T) {
+	m := NewManager(Config{Workers: 1, QueueSize: 1})
+	slow := fakeScanner{name: "slow", delay: time.Hour}
+
+	// 1er job : occupe le worker | 2e job : remplit la file | 3e job : refusé
+	first, _ := m.Submit(Request{Domain: "a.com", Scanners: []scanner.Scanner{slow}})
+	time.Sleep(20 * time.Millisecond)
+	second, err := m.Submit(Request{Domain: "b.com", Scanners: []scanner.Scanner{slow}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.Submit(Request{Domain: "c.com", Scanners: []scanner.Scanner{slow}}); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("got %v, want ErrQueueFull", err)
+	}
+
+	m.Cancel(first.ID())
+	m.Cancel(second.ID())
+}
+
+// TestManager_QueueSmallerThanJob — une file plus petite que le plus gros job est agrandie :
+// un gestionnaire inactif accepte toujours un job complet
+func TestManager_QueueSmallerThanJob(t *testing.T) {
+	all := []scanner.Scanner{fakeScanner{name: "a"}, fakeScanner{name: "b"}, fakeScanner{name: "c"}}
+	m := NewManager(Config{Workers: 1, QueueSize: 1, MaxJobScanners: len(all)})
+
+	job, err := m.Submit(Request{Domain: "a.com", Scanners: all})
+	if err != nil {
+		t.Fatalf("got %v, want job accepté", err)
+	}
+	if snap := wait(t, job); snap.Status != StatusDone {
+		t.Errorf("got %s, want %s", snap.Status, StatusDone)
+	}
+}
+
+// TestJob_EventsSince — journal complet et rejouable : started, finding, finished puis job.finished
+func TestJob_EventsSince(t *testing.T) {
+	m := NewManager(Config{Workers: 1})
+
+	job, err := m.Submit(Request{Domain: "example.com", Scanners: []scanner.Scanner{fakeScanner{name: "a"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wait(t, job)
+
+	events, _, finished := job.EventsSince(0)
+	if !finished {
+		t.Errorf("expected finished job")
+	}
+
+	want := []EventType{EventScannerStarted, EventFinding, EventScannerFinished, EventJobFinished}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d", len(events), len(want))
+	}
+	for i, e := range events {
+		if e.Type != want[i] || e.Seq != i {
+			t.Errorf("event %d: got %s (seq %d), want %s", i, e.Type, e.Seq, want[i])
+		}
+	}
+
+	// Reprise après le 2e événement (Last-Event-ID = 1) → seulement les suivants
+	if events, _, _ := job.EventsSince(2); len(events) != 2 {
+		t.Errorf("got %d events since seq 2, want 2", len(events))
+	}
+}
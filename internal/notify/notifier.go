@@ -0,0 +1,323 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/daviani/go__001/internal/diff"
+	"github.com/daviani/go__001/internal/jobs"
+)
+
+// Valeurs par défaut de Config
+const (
+	defaultMaxAttempts = 6
+	defaultBaseDelay   = 5 * time.Second
+	defaultWorkers     = 4
+	defaultTimeout     = 10 * time.Second
+)
+
+// Config — réglages des livraisons
+type Config struct {
+	MaxAttempts int           // Nombre total de tentatives par livraison
+	BaseDelay   time.Duration // Délai avant la 2e tentative — doublé à chaque échec (5s, 10s, 20s...)
+	Workers     int           // Requêtes HTTP simultanées, tous webhooks confondus
+	Client      *http.Client  // Client HTTP (timeout de 10s par défaut)
+}
+
+// Notifier — envoie les findings nouveaux ou aggravés aux webhooks configurés
+type Notifier struct {
+	repo   Repository
+	cfg    Config
+	ctx    context.Context // Arrête les tentatives en attente (les livraisons restent "pending" en base)
+	slots  chan struct{}   // Sémaphore : limite les requêtes HTTP simultanées
+	client *http.Client
+}
+
+// New crée le notifier — Start reprend les livraisons interrompues par un redémarrage
+func New(repo Repository, cfg Config) *Notifier {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	if cfg.BaseDelay <= 0 {
+		cfg.BaseDelay = defaultBaseDelay
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultWorkers
+	}
+	client := cfg.Client
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+	return &Notifier{
+		repo:   repo,
+		cfg:    cfg,
+		ctx:    context.Background(),
+		slots:  make(chan struct{}, cfg.Workers),
+		client: client,
+	}
+}
+
+// Start relance les livraisons restées en attente (serveur arrêté entre deux tentatives)
+func (n *Notifier) Start(ctx context.Context) error {
+	n.ctx = ctx
+	pending, err := n.repo.PendingDeliveries(ctx)
+	if err != nil {
+		return fmt.Errorf("erreur de reprise des livraisons: %w", err)
+	}
+	for _, d := range pending {
+		go n.deliver(d)
+	}
+	return nil
+}
+
+// ScanFinished notifie les webhooks concernés par les nouveaux findings d'un scan
+// reference = dernier run de chaque scanner dans l'historique du domaine (voir store.PreviousRuns)
+// Un scanner absent de reference n'a jamais tourné : tous ses findings sont nouveaux
+func (n *Notifier) ScanFinished(ctx context.Context, reference jobs.Snapshot, snap jobs.Snapshot) error {
+	changes := diff.Against(reference, snap)
+
+	webhooks, err := n.repo.ListWebhooks(ctx)
+	if err != nil {
+		return fmt.Errorf("erreur de lecture des webhooks: %w", err)
+	}
+
+	for _, w := range webhooks {
+		kept := w.matches(snap.Domain, changes.Changes)
+		if len(kept) == 0 {
+			continue
+		}
+
+		body, err := json.Marshal(Payload{
+			Event:          EventFindings,
+			Domain:         snap.Domain,
+			ScanID:         snap.ID,
+			PreviousScanID: changes.From,
+			Changes:        kept,
+			Time:           time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := n.enqueue(ctx, Delivery{WebhookID: w.ID, Event: EventFindings, ScanID: snap.ID, Payload: body}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enqueue enregistre une nouvelle livraison puis l'envoie en arrière-plan
+// L'enregistrement préalable garantit qu'une livraison n'est jamais perdue, même si le serveur s'arrête
+func (n *Notifier) enqueue(ctx context.Context, d Delivery) (Delivery, error) {
+	d.ID = newID()
+	d.Status = DeliveryPending
+	d.CreatedAt = time.Now()
+	if err := n.repo.SaveDelivery(ctx, d); err != nil {
+		return Delivery{}, err
+	}
+	go n.deliver(d)
+	return d, nil
+}
+
+// deliver envoie une livraison jusqu'au succès, à une erreur définitive ou à l'épuisement des tentatives
+// Entre deux tentatives, le délai double : BaseDelay, 2×BaseDelay, 4×BaseDelay...
+func (n *Notifier) deliver(d Delivery) {
+	for {
+		// Reprise après redémarrage : on respecte le délai prévu avant la prochaine tentative
+		if d.NextAttemptAt != nil {
+			select {
+			case <-time.After(time.Until(*d.NextAttemptAt)):
+			case <-n.ctx.Done():
+				return
+			}
+		}
+
+		w, err := n.repo.GetWebhook(n.ctx, d.WebhookID)
+		if err != nil {
+			// Webhook supprimé entre-temps : ses livraisons ont été supprimées avec lui
+			log.Printf("livraison %s abandonnée : %v", d.ID, err)
+			return
+		}
+
+		code, err := n.send(w, d)
+		d.Attempts++
+		d.ResponseCode = code
+		d.NextAttemptAt = nil
+
+		switch {
+		case err == nil:
+			now := time.Now()
+			d.Status = DeliverySucceeded
+			d.Error = ""
+			d.DeliveredAt = &now
+		case !retryable(code) || d.Attempts >= n.cfg.MaxAttempts:
+			d.Status = DeliveryFailed
+			d.Error = err.Error()
+		default:
+			next := time.Now().Add(n.cfg.BaseDelay << (d.Attempts - 1))
+			d.Error = err.Error()
+			d.NextAttemptAt = &next
+		}
+
+		if err := n.repo.SaveDelivery(n.ctx, d); err != nil {
+			log.Println(err)
+		}
+		if d.Status != DeliveryPending {
+			return
+		}
+	}
+}
+
+// send effectue une tentative : POST du payload signé
+// Retourne le status HTTP (0 si pas de réponse) et une erreur si la réponse n'est pas 2xx
+func (n *Notifier) send(w Webhook, d Delivery) (int, error) {
+	// Attend une place libre : au plus Workers requêtes simultanées
+	select {
+	case n.slots <- struct{}{}:
+		defer func() { <-n.slots }()
+	case <-n.ctx.Done():
+		return 0, n.ctx.Err()
+	}
+
+	req, err := http.NewRequestWithContext(n.ctx, http.MethodPost, w.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	// Timestamp de la tentative (et non de la livraison) : le récepteur peut refuser les messages trop anciens
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "GoSentry-Webhook")
+	req.Header.Set(HeaderEvent, d.Event)
+	req.Header.Set(HeaderDelivery, d.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(HeaderSignature, Sign(w.Secret, now, d.Payload))
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("erreur d'envoi: %w", err)
+	}
+	defer resp.Body.Close()
+	// Vide le corps (limité à 64 Ko) pour que la connexion soit réutilisée
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("réponse HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// retryable indique si un échec mérite une nouvelle tentative
+// Pas de réponse, 5xx, 408 et 429 sont temporaires ; les autres 4xx ne changeront pas en réessayant
+func retryable(code int) bool {
+	return code == 0 || code >= 500 || code == http.StatusRequestTimeout || code == http.StatusTooManyRequests
+}
+
+// List retourne les webhooks configurés (sans leurs secrets)
+func (n *Notifier) List(ctx context.Context) ([]Webhook, error) {
+	webhooks, err := n.repo.ListWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	redacted := make([]Webhook, len(webhooks))
+	for i, w := range webhooks {
+		redacted[i] = w.Redacted()
+	}
+	return redacted, nil
+}
+
+// Get retourne le webhook id (sans son secret)
+func (n *Notifier) Get(ctx context.Context, id string) (Webhook, error) {
+	w, err := n.repo.GetWebhook(ctx, id)
+	if err != nil {
+		return Webhook{}, err
+	}
+	return w.Redacted(), nil
+}
+
+// Create valide et enregistre un webhook — la réponse contient le secret (seule occasion de le lire)
+func (n *Notifier) Create(ctx context.Context, w Webhook) (Webhook, error) {
+	if err := w.validate(); err != nil {
+		return Webhook{}, fmt.Errorf("%w : %v", ErrInvalid, err)
+	}
+	w.ID = newID()
+	w.CreatedAt = time.Now()
+	if w.Secret == "" {
+		w.Secret = newSecret()
+	}
+	if err := n.repo.SaveWebhook(ctx, w); err != nil {
+		return Webhook{}, err
+	}
+	return w, nil
+}
+
+// Update remplace la configuration du webhook id — secret vide = secret conservé
+func (n *Notifier) Update(ctx context.Context, id string, w Webhook) (Webhook, error) {
+	if err := w.validate(); err != nil {
+		return Webhook{}, fmt.Errorf("%w : %v", ErrInvalid, err)
+	}
+	existing, err := n.repo.GetWebhook(ctx, id)
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	w.ID = id
+	w.CreatedAt = existing.CreatedAt
+	if w.Secret == "" {
+		w.Secret = existing.Secret
+	}
+	if err := n.repo.SaveWebhook(ctx, w); err != nil {
+		return Webhook{}, err
+	}
+	return w.Redacted(), nil
+}
+
+// Delete supprime le webhook id et son journal de livraisons
+func (n *Notifier) Delete(ctx context.Context, id string) error {
+	if _, err := n.repo.GetWebhook(ctx, id); err != nil {
+		return err
+	}
+	return n.repo.DeleteWebhook(ctx, id)
+}
+
+// Deliveries retourne une page du journal de livraisons du webhook id
+func (n *Notifier) Deliveries(ctx context.Context, id string, limit, offset int) (DeliveryPage, error) {
+	if _, err := n.repo.GetWebhook(ctx, id); err != nil {
+		return DeliveryPage{}, err
+	}
+	return n.repo.ListDeliveries(ctx, id, limit, offset)
+}
+
+// Delivery retourne la livraison deliveryID du webhook id
+func (n *Notifier) Delivery(ctx context.Context, id, deliveryID string) (Delivery, error) {
+	d, err := n.repo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return Delivery{}, err
+	}
+	if d.WebhookID != id {
+		return Delivery{}, ErrDeliveryNotFound
+	}
+	return d, nil
+}
+
+// Replay renvoie le payload d'une livraison passée dans une nouvelle livraison
+// Le journal reste immuable : l'original est conservé, la copie pointe vers lui (replay_of)
+func (n *Notifier) Replay(ctx context.Context, id, deliveryID string) (Delivery, error) {
+	original, err := n.Delivery(ctx, id, deliveryID)
+	if err != nil {
+		return Delivery{}, err
+	}
+	return n.enqueue(ctx, Delivery{
+		WebhookID: original.WebhookID,
+		Event:     original.Event,
+		ScanID:    original.ScanID,
+		Payload:   original.Payload,
+		ReplayOf:  original.ID,
+	})
+}
@@ -0,0 +1,306 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/daviani/go__001/internal/diff"
+	"github.com/daviani/go__001/internal/jobs"
+	"github.com/daviani/go__001/internal/scanner"
+)
+
+// memRepo — Repository en mémoire pour les tests
+type memRepo struct {
+	mu         sync.Mutex
+	webhooks   map[string]Webhook
+	deliveries map[string]Delivery
+	saved      chan Delivery // Reçoit chaque livraison enregistrée
+}
+
+func newMemRepo() *memRepo {
+	return &memRepo{
+		webhooks:   make(map[string]Webhook),
+		deliveries: make(map[string]Delivery),
+		saved:      make(chan Delivery, 32),
+	}
+}
+
+func (r *memRepo) ListWebhooks(ctx context.Context) ([]Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var list []Webhook
+	for _, w := range r.webhooks {
+		list = append(list, w)
+	}
+	return list, nil
+}
+
+func (r *memRepo) GetWebhook(ctx context.Context, id string) (Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	w, ok := r.webhooks[id]
+	if !ok {
+		return Webhook{}, ErrNotFound
+	}
+	return w, nil
+}
+
+func (r *memRepo) SaveWebhook(ctx context.Context, w Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.webhooks[w.ID] = w
+	return nil
+}
+
+func (r *memRepo) DeleteWebhook(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.webhooks, id)
+	return nil
+}
+
+func (r *memRepo) SaveDelivery(ctx context.Context, d Delivery) error {
+	r.mu.Lock()
+	r.deliveries[d.ID] = d
+	r.mu.Unlock()
+	r.saved <- d
+	return nil
+}
+
+func (r *memRepo) GetDelivery(ctx context.Context, id string) (Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d, ok := r.deliveries[id]
+	if !ok {
+		return Delivery{}, ErrDeliveryNotFound
+	}
+	return d, nil
+}
+
+func (r *memRepo) ListDeliveries(ctx context.Context, webhookID string, limit, offset int) (DeliveryPage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	page := DeliveryPage{Limit: limit, Offset: offset}
+	for _, d := range r.deliveries {
+		if d.WebhookID == webhookID {
+			page.Deliveries = append(page.Deliveries, d)
+		}
+	}
+	sort.Slice(page.Deliveries, func(i, j int) bool { return page.Deliveries[i].CreatedAt.After(page.Deliveries[j].CreatedAt) })
+	page.Total = len(page.Deliveries)
+	return page, nil
+}
+
+func (r *memRepo) PendingDeliveries(ctx context.Context) ([]Delivery, error) {
+	return nil, nil
+}
+
+// finished attend qu'une livraison atteigne un état final (succeeded ou failed)
+func (r *memRepo) finished(t *testing.T) Delivery {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case d := <-r.saved:
+			if d.Status != DeliveryPending {
+				return d
+			}
+		case <-timeout:
+			t.Fatal("aucune livraison terminée après 5s")
+			return Delivery{}
+		}
+	}
+}
+
+// scan construit un scan terminé de example.com avec les findings donnés, rangés par scanner
+func scan(id string, findings map[string][]scanner.Finding) jobs.Snapshot {
+	snap := jobs.Snapshot{ID: id, Domain: "example.com", Status: jobs.StatusDone}
+	for name, f := range findings {
+		snap.Runs = append(snap.Runs, jobs.Run{Scanner: name, Status: jobs.StatusDone, Result: &scanner.Result{Findings: f}})
+	}
+	return snap
+}
+
+// TestNotifier_ScanFinished — seuls les findings nouveaux ou aggravés sont envoyés, avec une signature valide
+func TestNotifier_ScanFinished(t *testing.T) {
+	bodies := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		if r.Header.Get(HeaderSignature) != Sign("s3cr3t", time.Unix(ts, 0), body) {
+			t.Errorf("signature invalide : %s", r.Header.Get(HeaderSignature))
+		}
+		if r.Header.Get(HeaderEvent) != EventFindings {
+			t.Errorf("got event %q", r.Header.Get(HeaderEvent))
+		}
+		bodies <- body
+	}))
+	defer srv.Close()
+
+	repo := newMemRepo()
+	n := New(repo, Config{})
+	if _, err := n.Create(context.Background(), Webhook{URL: srv.URL, Secret: "s3cr3t"}); err != nil {
+		t.Fatal(err)
+	}
+
+	previous := scan("a", map[string][]scanner.Finding{
+		"header": {{ID: "header.hsts", Severity: scanner.SeverityLow, Asset: "example.com"}},
+	})
+	current := scan("b", map[string][]scanner.Finding{
+		"header": {
+			{ID: "header.hsts", Severity: scanner.SeverityHigh, Asset: "example.com"},   // Aggravé → envoyé
+			{ID: "header.csp", Severity: scanner.SeverityMedium, Asset: "example.com"},  // Nouveau → envoyé
+			{ID: "header.server", Severity: scanner.SeverityInfo, Asset: "example.com"}, // Info → filtré
+		},
+	})
+	if err := n.ScanFinished(context.Background(), previous, current); err != nil {
+		t.Fatal(err)
+	}
+
+	var payload Payload
+	if err := json.Unmarshal(<-bodies, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.ScanID != "b" || payload.PreviousScanID != "a" || len(payload.Changes) != 2 {
+		t.Fatalf("got %+v", payload)
+	}
+	if d := repo.finished(t); d.Status != DeliverySucceeded || d.Attempts != 1 || d.ResponseCode != 200 {
+		t.Errorf("got %+v", d)
+	}
+}
+
+// TestNotifier_ScanFinished_NewScanner — après un scan DNS seul, le scan complet suivant
+// notifie les findings des scanners jamais lancés au lieu de les ignorer
+func TestNotifier_ScanFinished_NewScanner(t *testing.T) {
+	bodies := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- body
+	}))
+	defer srv.Close()
+
+	repo := newMemRepo()
+	n := New(repo, Config{})
+	if _, err := n.Create(context.Background(), Webhook{URL: srv.URL}); err != nil {
+		t.Fatal(err)
+	}
+
+	dnsOnly := scan("a", map[string][]scanner.Finding{
+		"dns": {{ID: "dns.spf", Severity: scanner.SeverityLow, Asset: "example.com"}},
+	})
+	full := scan("b", map[string][]scanner.Finding{
+		"dns":       {{ID: "dns.spf", Severity: scanner.SeverityLow, Asset: "example.com"}},
+		"sensitive": {{ID: "sensitive.exposed", Severity: scanner.SeverityCritical, Asset: "/.env"}},
+	})
+	if err := n.ScanFinished(context.Background(), dnsOnly, full); err != nil {
+		t.Fatal(err)
+	}
+
+	var payload Payload
+	if err := json.Unmarshal(<-bodies, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if len(payload.Changes) != 1 || payload.Changes[0].Scanner != "sensitive" || payload.Changes[0].Asset != "/.env" {
+		t.Errorf("got %+v, want the exposed .env", payload.Changes)
+	}
+	repo.finished(t)
+}
+
+// TestNotifier_Retry — une réponse 5xx est retentée avec backoff, une 4xx échoue immédiatement
+func TestNotifier_Retry(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		switch {
+		case r.URL.Path == "/gone":
+			w.WriteHeader(http.StatusGone)
+		case calls < 3:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	repo := newMemRepo()
+	n := New(repo, Config{BaseDelay: time.Millisecond})
+	ctx := context.Background()
+	snap := scan("a", map[string][]scanner.Finding{
+		"ssl": {{ID: "ssl.expired", Severity: scanner.SeverityCritical, Asset: "example.com"}},
+	})
+
+	hook, _ := n.Create(ctx, Webhook{URL: srv.URL})
+	if err := n.ScanFinished(ctx, jobs.Snapshot{}, snap); err != nil {
+		t.Fatal(err)
+	}
+	d := repo.finished(t)
+	if d.Status != DeliverySucceeded || d.Attempts != 3 {
+		t.Fatalf("got %+v, want succeeded after 3 attempts", d)
+	}
+
+	// Le rejeu crée une nouvelle livraison du même payload
+	replay, err := n.Replay(ctx, hook.ID, d.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := repo.finished(t); r.ID != replay.ID || r.ReplayOf != d.ID || string(r.Payload) != string(d.Payload) {
+		t.Errorf("got %+v", r)
+	}
+	if err := n.Delete(ctx, hook.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := n.Create(ctx, Webhook{URL: srv.URL + "/gone"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.ScanFinished(ctx, jobs.Snapshot{}, snap); err != nil {
+		t.Fatal(err)
+	}
+	if d := repo.finished(t); d.Status != DeliveryFailed || d.Attempts != 1 || d.ResponseCode != http.StatusGone {
+		t.Errorf("got %+v, want failed after 1 attempt", d)
+	}
+}
+
+// TestWebhook_Matches — filtres de sévérité, de scanner et de domaine
+func TestWebhook_Matches(t *testing.T) {
+	snap := scan("a", map[string][]scanner.Finding{
+		"ssl":       {{ID: "ssl.weak", Severity: scanner.SeverityMedium, Asset: "example.com"}},
+		"sensitive": {{ID: "sensitive.env", Severity: scanner.SeverityCritical, Asset: "https://example.com/.env"}},
+	})
+
+	tests := []struct {
+		name    string
+		webhook Webhook
+		domain  string
+		want    int
+	}{
+		{"sans filtre", Webhook{}, "example.com", 2},
+		{"sévérité", Webhook{MinSeverity: scanner.SeverityHigh}, "example.com", 1},
+		{"scanner", Webhook{Scanners: []string{"ssl"}}, "example.com", 1},
+		{"sous-domaine", Webhook{Domains: []string{"example.com"}}, "www.example.com", 2},
+		{"autre domaine", Webhook{Domains: []string{"example.com"}}, "notexample.com", 0},
+		{"en pause", Webhook{Paused: true}, "example.com", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := tt.webhook
+			w.URL = "https://hooks.example.com"
+			if err := w.validate(); err != nil {
+				t.Fatal(err)
+			}
+			changes := w.matches(tt.domain, diff.Baseline(snap).Changes)
+			if len(changes) != tt.want {
+				t.Errorf("got %d changes, want %d", len(changes), tt.want)
+			}
+		})
+	}
+}
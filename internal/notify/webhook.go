@@ -0,0 +1,212 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/daviani/go__001/internal/diff"
+	"github.com/daviani/go__001/internal/scanner"
+)
+
+// ErrNotFound — aucun webhook ne correspond à l'identifiant demandé
+var ErrNotFound = errors.New("webhook introuvable")
+
+// ErrDeliveryNotFound — aucune livraison de ce webhook ne correspond à l'identifiant demandé
+var ErrDeliveryNotFound = errors.New("livraison introuvable")
+
+// ErrInvalid — configuration de webhook refusée (URL invalide, sévérité inconnue...)
+var ErrInvalid = errors.New("webhook invalide")
+
+// defaultMinSeverity — sans filtre explicite, les findings "info" (records DNS, sous-domaines...) ne sont pas notifiés
+const defaultMinSeverity = scanner.SeverityLow
+
+// Headers HTTP envoyés avec chaque livraison
+const (
+	HeaderEvent     = "X-GoSentry-Event"
+	HeaderDelivery  = "X-GoSentry-Delivery"
+	HeaderTimestamp = "X-GoSentry-Timestamp"
+	HeaderSignature = "X-GoSentry-Signature" // "sha256=" + HMAC-SHA256(secret, timestamp + "." + corps)
+)
+
+// EventFindings — événement envoyé quand un scan remonte des findings nouveaux ou aggravés
+const EventFindings = "scan.findings"
+
+// Webhook — destination HTTP des notifications
+type Webhook struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url"`
+	// Secret signe les livraisons (HMAC-SHA256) — généré s'il est absent, renvoyé uniquement à la création
+	Secret string `json:"secret,omitempty"`
+
+	// Filtres — vides = pas de filtre
+	MinSeverity scanner.Severity `json:"min_severity,omitempty"` // Sévérité minimale notifiée (défaut : low)
+	Scanners    []string         `json:"scanners,omitempty"`     // Scanners concernés (ex: ["sensitive", "ssl"])
+	Domains     []string         `json:"domains,omitempty"`      // Domaines concernés, sous-domaines compris
+	Paused      bool             `json:"paused"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Payload — corps JSON d'une livraison scan.findings
+type Payload struct {
+	Event          string        `json:"event"`
+	Domain         string        `json:"domain"`
+	ScanID         string        `json:"scan_id"`
+	PreviousScanID string        `json:"previous_scan_id,omitempty"` // Dernier scan ayant servi de référence, vide pour le premier scan du domaine
+	Changes        []diff.Change `json:"changes"`                    // Findings ajoutés ou aggravés depuis le scan précédent
+	Time           time.Time     `json:"time"`
+}
+
+// validate vérifie la configuration et applique les valeurs par défaut
+func (w *Webhook) validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("paramètre 'url' invalide (URL http ou https attendue)")
+	}
+	if w.MinSeverity == "" {
+		w.MinSeverity = defaultMinSeverity
+	}
+	if w.MinSeverity.Rank() == 0 {
+		return fmt.Errorf("paramètre 'min_severity' invalide : %s (info, low, medium, high, critical)", w.MinSeverity)
+	}
+	for i, d := range w.Domains {
+		w.Domains[i] = strings.ToLower(strings.TrimSuffix(d, "."))
+	}
+	return nil
+}
+
+// Redacted retourne le webhook sans son secret — pour toutes les réponses sauf la création
+func (w Webhook) Redacted() Webhook {
+	w.Secret = ""
+	return w
+}
+
+// matches filtre les changements d'un scan selon la configuration du webhook
+// Seuls les ajouts et les aggravations sont notifiés : un finding corrigé n'alerte personne
+func (w Webhook) matches(domain string, changes []diff.Change) []diff.Change {
+	if w.Paused || !w.matchesDomain(domain) {
+		return nil
+	}
+
+	var kept []diff.Change
+	for _, c := range changes {
+		if c.After == nil || (c.Kind == diff.KindChanged && !c.Worsened) {
+			continue
+		}
+		if c.After.Severity.Rank() < w.MinSeverity.Rank() {
+			continue
+		}
+		if len(w.Scanners) > 0 && !contains(w.Scanners, c.Scanner) {
+			continue
+		}
+		kept = append(kept, c)
+	}
+	return kept
+}
+
+// matchesDomain accepte le domaine lui-même et ses sous-domaines ("example.com" couvre "www.example.com")
+func (w Webhook) matchesDomain(domain string) bool {
+	if len(w.Domains) == 0 {
+		return true
+	}
+	domain = strings.ToLower(domain)
+	for _, d := range w.Domains {
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// Sign calcule la signature d'une livraison : HMAC-SHA256(secret, timestamp + "." + corps)
+// Le timestamp fait partie du message signé → un attaquant ne peut pas rejouer une vieille livraison avec une date récente
+// Côté récepteur : recalculer la signature, la comparer en temps constant et refuser les timestamps trop anciens
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newSecret génère un secret de signature aléatoire (32 octets)
+func newSecret() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// newID génère un identifiant aléatoire de 16 caractères hexadécimaux
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// contains indique si s est dans list
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// DeliveryStatus — état d'une livraison
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"   // En cours ou en attente d'une nouvelle tentative
+	DeliverySucceeded DeliveryStatus = "succeeded" // Réponse 2xx reçue
+	DeliveryFailed    DeliveryStatus = "failed"    // Tentatives épuisées ou erreur définitive (4xx)
+)
+
+// Delivery — envoi d'un payload à un webhook, avec l'historique de ses tentatives
+type Delivery struct {
+	ID            string          `json:"id"`
+	WebhookID     string          `json:"webhook_id"`
+	Event         string          `json:"event"`
+	ScanID        string          `json:"scan_id,omitempty"`
+	Payload       json.RawMessage `json:"payload" swaggertype:"object"` // Corps envoyé, identique à chaque tentative
+	Status        DeliveryStatus  `json:"status"`
+	Attempts      int             `json:"attempts"`
+	ResponseCode  int             `json:"response_code,omitempty"` // Status HTTP de la dernière tentative
+	Error         string          `json:"error,omitempty"`         // Erreur de la dernière tentative
+	ReplayOf      string          `json:"replay_of,omitempty"`     // Livraison rejouée (POST .../replay)
+	CreatedAt     time.Time       `json:"created_at"`
+	NextAttemptAt *time.Time      `json:"next_attempt_at,omitempty"`
+	DeliveredAt   *time.Time      `json:"delivered_at,omitempty"`
+}
+
+// DeliveryPage — page du journal de livraisons d'un webhook
+type DeliveryPage struct {
+	Deliveries []Delivery `json:"deliveries"`
+	Total      int        `json:"total"`
+	Limit      int        `json:"limit"`
+	Offset     int        `json:"offset"`
+}
+
+// Repository — persistance des webhooks et du journal de livraisons (implémentée par store.SQLite)
+type Repository interface {
+	ListWebhooks(ctx context.Context) ([]Webhook, error)
+	GetWebhook(ctx context.Context, id string) (Webhook, error) // ErrNotFound s'il n'existe pas
+	SaveWebhook(ctx context.Context, w Webhook) error
+	DeleteWebhook(ctx context.Context, id string) error // Supprime aussi ses livraisons
+
+	SaveDelivery(ctx context.Context, d Delivery) error
+	GetDelivery(ctx context.Context, id string) (Delivery, error) // ErrDeliveryNotFound s'il n'existe pas
+	ListDeliveries(ctx context.Context, webhookID string, limit, offset int) (DeliveryPage, error)
+	PendingDeliveries(ctx context.Context) ([]Delivery, error)
+}
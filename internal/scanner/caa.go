@@ -0,0 +1,430 @@
+package scanner
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/daviani/go__001/internal/dnsclient"
+)
+
+// caaKnownTags — tags CAA compris par les AC : un tag inconnu marqué critique bloque toute émission
+// issuemail (RFC 9495) et issuevmc (logos BIMI) concernent d'autres types de certificats
+var caaKnownTags = []string{"issue", "issuewild", "iodef", "issuemail", "issuevmc", "contactemail", "contactphone"}
+
+// caaIssuers — identifiants CAA des autorités de certification, indexés par un extrait de l'organisation émettrice
+// Une AC peut publier plusieurs identifiants (rachats, marques) : n'importe lequel l'autorise
+var caaIssuers = []struct {
+	org     string
+	domains []string
+}{
+	{"let's encrypt", []string{"letsencrypt.org"}},
+	{"google trust services", []string{"pki.goog"}},
+	{"digicert", []string{"digicert.com", "symantec.com", "geotrust.com", "rapidssl.com", "thawte.com"}},
+	{"zerossl", []string{"sectigo.com", "zerossl.com"}},
+	{"sectigo", []string{"sectigo.com", "comodoca.com", "comodo.com", "usertrust.com"}},
+	{"globalsign", []string{"globalsign.com"}},
+	{"amazon", []string{"amazon.com", "amazontrust.com", "awstrust.com", "amazonaws.com"}},
+	{"microsoft", []string{"microsoft.com"}},
+	{"entrust", []string{"entrust.net"}},
+	{"godaddy", []string{"godaddy.com", "starfieldtech.com"}},
+	{"starfield", []string{"starfieldtech.com", "godaddy.com"}},
+	{"buypass", []string{"buypass.com", "buypass.no"}},
+	{"ssl corporation", []string{"ssl.com"}},
+	{"certainly", []string{"certainly.com"}},
+	{"actalis", []string{"actalis.it"}},
+	{"identrust", []string{"identrust.com"}},
+	{"harica", []string{"harica.gr"}},
+}
+
+// CAAScanner - Scanner CAA : politique d'émission de certificats publiée dans le DNS (RFC 8659)
+// et cohérence avec l'émetteur du certificat réellement servi
+type CAAScanner struct {
+	DNS *dnsclient.Client // Client DNS (nil = résolveurs du système)
+
+	// DialContext ouvre les connexions TLS (nil = net.Dialer) — les tests y redirigent "domaine:443" vers un serveur local
+	DialContext func(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// CAAReport — données brutes du scanner CAA
+type CAAReport struct {
+	Owner        string           `json:"owner,omitempty"` // Nom portant le RRset CAA retenu : le domaine ou un parent (vide = aucun CAA)
+	Records      []dnsclient.CAA  `json:"records"`
+	Certificates []CAACertificate `json:"certificates,omitempty"`
+}
+
+// CAACertificate — émetteur du certificat servi sur un port, confronté à la politique CAA
+type CAACertificate struct {
+	Port     int      `json:"port"`
+	Issuer   string   `json:"issuer"`
+	Wildcard bool     `json:"wildcard"`        // Domaine couvert par un nom wildcard : la politique issuewild s'applique
+	CAA      []string `json:"caa,omitempty"`   // Identifiants CAA connus de l'émetteur (vide = émetteur non reconnu)
+	Allowed  bool     `json:"allowed"`         // Émetteur autorisé par la politique (ou aucune restriction)
+	Error    string   `json:"error,omitempty"` // Port injoignable, handshake en échec
+}
+
+// caaOptions — options typées de CAAScanner
+type caaOptions struct {
+	Ports       []int // Ports TLS dont le certificat est confronté à la politique
+	Certificate bool  // Vérifier l'émetteur du certificat servi
+}
+
+// Name retourne l'identifiant du scanner CAA
+func (c CAAScanner) Name() string { return "caa" }
+
+// Info retourne les métadonnées du scanner (GET /scanners)
+func (c CAAScanner) Info() Info {
+	return Info{
+		Title:       "CAA",
+		Description: "Vérifie la politique CAA du domaine (issue, issuewild, iodef, héritage des parents) et la cohérence avec l'émetteur du certificat servi",
+		Version:     "1.0.1",
+	}
+}
+
+// Schema publie les options acceptées par le scanner CAA
+func (c CAAScanner) Schema() Schema {
+	return Schema{
+		{
+			Name:        "ports",
+			Type:        OptionInts,
+			Description: "Ports TLS dont l'émetteur du certificat est confronté à la politique CAA",
+			Default:     []int{443},
+			Min:         intPtr(1),
+			Max:         intPtr(65535),
+		},
+		{
+			Name:        "certificate",
+			Type:        OptionBool,
+			Description: "Vérifier que l'émetteur du certificat servi est autorisé par la politique CAA",
+			Default:     true,
+		},
+	}
+}
+
+// options convertit les Options génériques en caaOptions
+func (c CAAScanner) options(opts Options) caaOptions {
+	opts = c.Schema().Apply(opts)
+	o := caaOptions{Ports: opts.Ints("ports"), Certificate: opts.Bool("certificate")}
+	// Liste vide (?ports=) → port HTTPS standard, comme le scanner ssl
+	if len(o.Ports) == 0 {
+		o.Ports = []int{443}
+	}
+	return o
+}
+
+// Scan recherche le RRset CAA applicable au domaine puis compare l'émetteur du certificat servi à la politique
+// Seule erreur fatale : la résolution CAA elle-même (une AC refuserait d'émettre dans ce cas)
+func (c CAAScanner) Scan(ctx context.Context, domain string, opts Options) (Result, error) {
+	o := c.options(opts)
+	client := c.DNS
+	if client == nil {
+		client = &dnsclient.Client{}
+	}
+
+	owner, records, err := client.RelevantCAA(ctx, domain)
+	if err != nil {
+		return Result{}, fmt.Errorf("erreur de CAA: %w", err)
+	}
+	report := CAAReport{Owner: owner, Records: records}
+	if report.Records == nil {
+		report.Records = []dnsclient.CAA{}
+	}
+	policy := caaPolicy{owner: owner, records: records}
+
+	result := newResult(ctx, nil)
+	addCAAPolicy(&result, domain, policy)
+	if len(records) > 0 {
+		addCAAWildcard(&result, domain, policy)
+		addCAAIodef(&result, domain, policy)
+		addCAACritical(&result, domain, policy)
+	}
+
+	if o.Certificate {
+		for _, port := range o.Ports {
+			cert := c.checkCertificate(ctx, domain, port, policy)
+			report.Certificates = append(report.Certificates, cert)
+			if cert.Error == "" {
+				addCAAIssuer(&result, domain, policy, cert)
+			}
+		}
+	}
+
+	result.Data = report
+	return result, ctx.Err()
+}
+
+// caaPolicy — RRset CAA applicable et le nom qui le porte
+type caaPolicy struct {
+	owner   string
+	records []dnsclient.CAA
+}
+
+// issuers retourne les identifiants d'AC autorisés pour tag ("issue" ou "issuewild")
+// present = faux si aucun record de ce tag : le tag n'impose alors aucune restriction
+// Un record `0 issue ";"` (identifiant vide) n'autorise aucune AC
+func (p caaPolicy) issuers(tag string) (domains []string, present bool) {
+	for _, r := range p.records {
+		if r.Tag != tag {
+			continue
+		}
+		present = true
+		// Paramètres après ";" (validationmethods, accounturi...) ignorés : on compare l'AC, pas le compte
+		name, _, _ := strings.Cut(r.Value, ";")
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			domains = append(domains, name)
+		}
+	}
+	return domains, present
+}
+
+// allowed retourne les AC autorisées pour un certificat (wildcard ou non)
+// Les wildcards suivent issuewild s'il existe, sinon issue (RFC 8659 §4.3)
+// restricted = faux si la politique ne restreint pas ce type de certificat
+func (p caaPolicy) allowed(wildcard bool) (domains []string, restricted bool) {
+	if wildcard {
+		if domains, ok := p.issuers("issuewild"); ok {
+			return domains, true
+		}
+	}
+	return p.issuers("issue")
+}
+
+// inherited indique si la politique vient d'un domaine parent
+func (p caaPolicy) inherited(domain string) bool {
+	return p.owner != "" && !strings.EqualFold(p.owner, dnsclient.Fqdn(domain))
+}
+
+// describeCAs formate une liste d'AC autorisées ("letsencrypt.org, pki.goog" ou "aucune AC")
+func describeCAs(domains []string) string {
+	if len(domains) == 0 {
+		return "aucune AC"
+	}
+	return strings.Join(domains, ", ")
+}
+
+// addCAAPolicy ajoute le finding de présence d'une politique CAA
+func addCAAPolicy(result *Result, domain string, p caaPolicy) {
+	finding := Finding{
+		ID:       "caa.policy",
+		Title:    "Politique CAA publiée",
+		Severity: SeverityInfo,
+		Category: "caa",
+		Asset:    domain,
+	}
+	if len(p.records) == 0 {
+		// Sans CAA, n'importe quelle AC peut émettre : une AC moins rigoureuse devient le maillon faible
+		finding.Title = "Aucun record CAA"
+		finding.Severity = SeverityLow
+		finding.Evidence = "ni " + domain + " ni ses domaines parents ne publient de record CAA"
+		finding.Remediation = `Publier un record CAA listant les AC utilisées, ex: example.com. CAA 0 issue "letsencrypt.org"`
+		result.add(finding)
+		return
+	}
+
+	var values []string
+	for _, r := range p.records {
+		values = append(values, r.String())
+	}
+	finding.Evidence = "CAA " + p.owner + " : " + strings.Join(values, " | ")
+	if p.inherited(domain) {
+		finding.Evidence += " (hérité du domaine parent)"
+	}
+	if _, ok := p.issuers("issue"); !ok {
+		// Seuls les wildcards (issuewild) ou les signalements (iodef) sont couverts : toute AC peut émettre un certificat classique
+		finding.Title = "Politique CAA sans record issue"
+		finding.Severity = SeverityLow
+		finding.Remediation = "Ajouter des records issue : issuewild ne restreint que les certificats wildcard"
+	}
+	result.add(finding)
+}
+
+// addCAAWildcard décrit la politique effective des certificats wildcard (issuewild, sinon issue)
+func addCAAWildcard(result *Result, domain string, p caaPolicy) {
+	finding := Finding{
+		ID:       "caa.issuewild",
+		Title:    "Politique CAA des certificats wildcard",
+		Severity: SeverityInfo,
+		Category: "caa",
+		Asset:    domain,
+	}
+	wild, hasWild := p.issuers("issuewild")
+	issue, hasIssue := p.issuers("issue")
+	switch {
+	case hasWild && len(wild) == 0:
+		finding.Evidence = `wildcards interdits (issuewild ";")`
+	case hasWild:
+		finding.Evidence = "wildcards autorisés pour " + describeCAs(wild) + " (issuewild)"
+	case hasIssue:
+		finding.Evidence = "wildcards autorisés pour " + describeCAs(issue) + " (hérité de issue, issuewild absent)"
+	default:
+		finding.Evidence = "wildcards non restreints (ni issue ni issuewild)"
+	}
+	// issuewild plus permissif que issue : un wildcard couvre pourtant tous les sous-domaines
+	if hasWild && hasIssue && slices.ContainsFunc(wild, func(ca string) bool { return !slices.Contains(issue, ca) }) {
+		finding.Title = "issuewild plus permissif que issue"
+		finding.Severity = SeverityLow
+		finding.Remediation = `Limiter issuewild aux AC de issue, ou interdire les wildcards avec issuewild ";"`
+	}
+	result.add(finding)
+}
+
+// addCAAIodef vérifie l'adresse de signalement iodef (demandes d'émission refusées)
+func addCAAIodef(result *Result, domain string, p caaPolicy) {
+	finding := Finding{
+		ID:       "caa.iodef",
+		Title:    "Signalement CAA (iodef)",
+		Severity: SeverityInfo,
+		Category: "caa",
+		Asset:    domain,
+	}
+	var targets, invalid []string
+	for _, r := range p.records {
+		if r.Tag != "iodef" {
+			continue
+		}
+		// RFC 8659 §4.4 : seuls mailto: et http(s): sont définis
+		if u, err := url.Parse(r.Value); err != nil || (u.Scheme != "mailto" && u.Scheme != "http" && u.Scheme != "https") {
+			invalid = append(invalid, r.Value)
+			continue
+		}
+		targets = append(targets, r.Value)
+	}
+	switch {
+	case len(invalid) > 0:
+		finding.Title = "Record iodef invalide"
+		finding.Severity = SeverityLow
+		finding.Evidence = "iodef " + strings.Join(invalid, ", ") + " (attendu mailto: ou https:)"
+		finding.Remediation = `Utiliser une URL mailto: ou https:, ex: 0 iodef "mailto:security@example.com"`
+	case len(targets) > 0:
+		finding.Evidence = "demandes refusées signalées à " + strings.Join(targets, ", ")
+	default:
+		finding.Evidence = "aucun iodef : les AC ne signalent pas les demandes refusées"
+	}
+	result.add(finding)
+}
+
+// addCAACritical signale un tag inconnu marqué critique (flag 128) : les AC doivent alors refuser toute émission
+func addCAACritical(result *Result, domain string, p caaPolicy) {
+	for _, r := range p.records {
+		if r.Flags&128 == 0 || slices.Contains(caaKnownTags, r.Tag) {
+			continue
+		}
+		// Le renouvellement du certificat échouera chez toutes les AC
+		result.add(Finding{
+			ID:          "caa.critical",
+			Title:       "Tag CAA critique inconnu",
+			Severity:    SeverityMedium,
+			Category:    "caa",
+			Evidence:    r.String() + " : aucune AC ne peut émettre tant que ce record existe",
+			Remediation: "Retirer le flag critique (128) ou le record lui-même",
+			Asset:       domain + " " + r.Tag,
+		})
+	}
+}
+
+// checkCertificate lit le certificat servi sur domain:port et le confronte à la politique
+// La chaîne n'est pas vérifiée : seul l'émetteur compte ici (la validité relève du scanner ssl)
+func (c CAAScanner) checkCertificate(ctx context.Context, domain string, port int, p caaPolicy) CAACertificate {
+	out := CAACertificate{Port: port}
+	cert, err := c.peerCertificate(ctx, domain, port)
+	if err != nil {
+		out.Error = err.Error()
+		return out
+	}
+
+	out.Issuer = "Inconnu"
+	if len(cert.Issuer.Organization) > 0 {
+		out.Issuer = cert.Issuer.Organization[0]
+	}
+	out.CAA = caaDomainsFor(out.Issuer)
+
+	// Nom exact absent mais wildcard du parent présent (*.example.com pour www.example.com) :
+	// le certificat a été émis pour ce nom sous la politique issuewild
+	// Un wildcard d'un autre domaine (*.cdn.example.net) ne couvre pas domain et ne compte pas
+	names := make([]string, len(cert.DNSNames))
+	for i, n := range cert.DNSNames {
+		names[i] = strings.ToLower(n)
+	}
+	name := strings.TrimSuffix(strings.ToLower(domain), ".")
+	if _, parent, ok := strings.Cut(name, "."); ok && !slices.Contains(names, name) {
+		out.Wildcard = slices.Contains(names, "*."+parent)
+	}
+
+	allowed, restricted := p.allowed(out.Wildcard)
+	out.Allowed = !restricted || slices.ContainsFunc(out.CAA, func(ca string) bool { return slices.Contains(allowed, ca) })
+	return out
+}
+
+// peerCertificate établit une connexion TLS et retourne le certificat feuille
+func (c CAAScanner) peerCertificate(ctx context.Context, domain string, port int) (*x509.Certificate, error) {
+	dial := c.DialContext
+	if dial == nil {
+		var dialer net.Dialer
+		dial = dialer.DialContext
+	}
+	raw, err := dial(ctx, "tcp", net.JoinHostPort(domain, strconv.Itoa(port)))
+	if err != nil {
+		return nil, fmt.Errorf("erreur SSL: %w", err)
+	}
+	conn := tls.Client(raw, &tls.Config{ServerName: domain, InsecureSkipVerify: true})
+	defer func() { _ = conn.Close() }()
+	if err := conn.HandshakeContext(ctx); err != nil {
+		return nil, fmt.Errorf("erreur SSL: %w", err)
+	}
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("erreur SSL: no peer certificate")
+	}
+	return certs[0], nil
+}
+
+// caaDomainsFor retourne les identifiants CAA d'une organisation émettrice (nil si non reconnue)
+func caaDomainsFor(issuer string) []string {
+	issuer = strings.ToLower(issuer)
+	for _, ca := range caaIssuers {
+		if strings.Contains(issuer, ca.org) {
+			return ca.domains
+		}
+	}
+	return nil
+}
+
+// addCAAIssuer ajoute le finding de cohérence entre l'émetteur du certificat et la politique CAA
+func addCAAIssuer(result *Result, domain string, p caaPolicy, cert CAACertificate) {
+	allowed, restricted := p.allowed(cert.Wildcard)
+	tag := "issue"
+	if cert.Wildcard {
+		if _, ok := p.issuers("issuewild"); ok {
+			tag = "issuewild"
+		}
+	}
+	finding := Finding{
+		ID:       "caa.issuer",
+		Title:    "Émetteur autorisé par CAA",
+		Severity: SeverityInfo,
+		Category: "caa",
+		Evidence: fmt.Sprintf("certificat émis par %s, %s autorise %s", cert.Issuer, tag, describeCAs(allowed)),
+		Asset:    net.JoinHostPort(domain, strconv.Itoa(cert.Port)),
+	}
+	switch {
+	case !restricted:
+		finding.Evidence = "certificat émis par " + cert.Issuer + ", aucune restriction CAA"
+	case cert.CAA == nil:
+		// Impossible de conclure : l'organisation n'est pas dans la table des AC connues
+		finding.Title = "Émetteur non reconnu"
+		finding.Evidence = fmt.Sprintf("certificat émis par %s (identifiant CAA inconnu), %s autorise %s", cert.Issuer, tag, describeCAs(allowed))
+	case !cert.Allowed:
+		// Certificat antérieur à la politique, émis via un CDN non déclaré... ou mal émis : le renouvellement échouera
+		finding.Title = "Émetteur non autorisé par CAA"
+		finding.Severity = SeverityMedium
+		finding.Evidence = fmt.Sprintf("certificat émis par %s (%s), %s autorise %s", cert.Issuer, strings.Join(cert.CAA, ", "), tag, describeCAs(allowed))
+		finding.Remediation = fmt.Sprintf(`Ajouter l'AC à la politique (ex: 0 %s "%s") ou remplacer le certificat par un certificat d'une AC autorisée`, tag, cert.CAA[0])
+	}
+	result.add(finding)
+}
@@ -0,0 +1,217 @@
+package scanner
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/daviani/go__001/internal/dnsclient"
+	"github.com/daviani/go__001/internal/dnsclient/dnstest"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// TestCAAScanner_Name vérifie que le scanner retourne le bon identifiant
+func TestCAAScanner_Name(t *testing.T) {
+	if got := (CAAScanner{}).Name(); got != "caa" {
+		t.Errorf("got %s, want caa", got)
+	}
+}
+
+// issuedBy démarre un serveur TLS local dont le certificat auto-signé porte l'organisation org
+// (auto-signé : émetteur = sujet) et couvre names
+func issuedBy(t *testing.T, org string, names ...string) *httptest.Server {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{Organization: []string{org}},
+		DNSNames:     names,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	// Le scanner ferme la connexion juste après le handshake : le serveur le journaliserait comme une erreur
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// caaRecord construit un record CAA (forme wire : flags, longueur du tag, tag, valeur)
+func caaRecord(name string, flags uint8, tag, value string) dnsmessage.Resource {
+	data := append([]byte{flags, byte(len(tag))}, tag...)
+	return dnstest.Raw(name, dnsclient.TypeCAA, append(data, value...))
+}
+
+// TestCAAScanner_Scan — héritage du parent, émetteur autorisé ou non, wildcards, iodef, tag critique
+func TestCAAScanner_Scan(t *testing.T) {
+	tests := []struct {
+		name   string
+		caa    []dnsmessage.Resource
+		org    string
+		names  []string
+		want   map[string]Severity // ID → sévérité attendue ("" = finding absent)
+		wantOK bool                // Valeur attendue de Allowed pour le certificat
+	}{
+		{
+			name:   "aucun CAA",
+			org:    "Let's Encrypt",
+			names:  []string{"www.example.com"},
+			want:   map[string]Severity{"caa.policy": SeverityLow, "caa.issuer": SeverityInfo, "caa.iodef": ""},
+			wantOK: true,
+		},
+		{
+			// CAA publié sur example.com, hérité par www.example.com
+			name:   "émetteur autorisé",
+			caa:    []dnsmessage.Resource{caaRecord("example.com.", 0, "issue", "letsencrypt.org; validationmethods=dns-01"), caaRecord("example.com.", 0, "iodef", "mailto:security@example.com")},
+			org:    "Let's Encrypt",
+			names:  []string{"www.example.com"},
+			want:   map[string]Severity{"caa.policy": SeverityInfo, "caa.issuer": SeverityInfo, "caa.iodef": SeverityInfo, "caa.issuewild": SeverityInfo},
+			wantOK: true,
+		},
+		{
+			name:  "émetteur non autorisé",
+			caa:   []dnsmessage.Resource{caaRecord("example.com.", 0, "issue", "digicert.com")},
+			org:   "Let's Encrypt",
+			names: []string{"www.example.com"},
+			want:  map[string]Severity{"caa.issuer": SeverityMedium},
+		},
+		{
+			// Certificat wildcard : issuewild ";" interdit les wildcards même si issue autorise l'AC
+			name:  "wildcard interdit",
+			caa:   []dnsmessage.Resource{caaRecord("example.com.", 0, "issue", "letsencrypt.org"), caaRecord("example.com.", 0, "issuewild", ";")},
+			org:   "Let's Encrypt",
+			names: []string{"*.example.com"},
+			want:  map[string]Severity{"caa.issuer": SeverityMedium, "caa.issuewild": SeverityInfo},
+		},
+		{
+			// Le wildcard d'un autre domaine ne couvre pas www.example.com : certificat émis sous issue
+			name:   "wildcard d'un autre domaine",
+			caa:    []dnsmessage.Resource{caaRecord("example.com.", 0, "issue", "letsencrypt.org"), caaRecord("example.com.", 0, "issuewild", ";")},
+			org:    "Let's Encrypt",
+			names:  []string{"*.cdn.example.net", "api.example.com"},
+			want:   map[string]Severity{"caa.issuer": SeverityInfo},
+			wantOK: true,
+		},
+		{
+			name:   "issuewild plus permissif",
+			caa:    []dnsmessage.Resource{caaRecord("example.com.", 0, "issue", "letsencrypt.org"), caaRecord("example.com.", 0, "issuewild", "pki.goog")},
+			org:    "Let's Encrypt",
+			names:  []string{"www.example.com"},
+			want:   map[string]Severity{"caa.issuewild": SeverityLow, "caa.issuer": SeverityInfo},
+			wantOK: true,
+		},
+		{
+			name:   "iodef invalide et tag critique inconnu",
+			caa:    []dnsmessage.Resource{caaRecord("example.com.", 0, "issue", "letsencrypt.org"), caaRecord("example.com.", 0, "iodef", "ftp://example.com"), caaRecord("example.com.", 128, "tbs", "x")},
+			org:    "Let's Encrypt",
+			names:  []string{"www.example.com"},
+			want:   map[string]Severity{"caa.iodef": SeverityLow, "caa.critical": SeverityMedium},
+			wantOK: true,
+		},
+		{
+			// Organisation absente de la table des AC : pas de conclusion possible
+			name:  "émetteur non reconnu",
+			caa:   []dnsmessage.Resource{caaRecord("example.com.", 0, "issue", "letsencrypt.org")},
+			org:   "Acme Co",
+			names: []string{"www.example.com"},
+			want:  map[string]Severity{"caa.issuer": SeverityInfo},
+		},
+		{
+			// Seul issuewild est publié : les certificats classiques ne sont pas restreints
+			name:   "sans issue",
+			caa:    []dnsmessage.Resource{caaRecord("example.com.", 0, "issuewild", "letsencrypt.org")},
+			org:    "DigiCert Inc",
+			names:  []string{"www.example.com"},
+			want:   map[string]Severity{"caa.policy": SeverityLow, "caa.issuer": SeverityInfo},
+			wantOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dns := dnstest.NewServer()
+			defer dns.Close()
+			dns.Add(dnstest.SOA("example.com."), dnstest.Record("www.example.com.", &dnsmessage.AResource{A: [4]byte{192, 0, 2, 10}}))
+			dns.Add(tt.caa...)
+			tlsSrv := issuedBy(t, tt.org, tt.names...)
+
+			scanner := CAAScanner{
+				DNS: dns.Client(),
+				DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+					var dialer net.Dialer
+					return dialer.DialContext(ctx, network, tlsSrv.Listener.Addr().String())
+				},
+			}
+			result, err := scanner.Scan(context.Background(), "www.example.com", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			byID := findingsByID(result)
+			for id, severity := range tt.want {
+				f, ok := byID[id]
+				if severity == "" {
+					if ok {
+						t.Errorf("%s : got %+v, want absent", id, f)
+					}
+					continue
+				}
+				if !ok || f.Severity != severity {
+					t.Errorf("%s : got %+v, want %s", id, f, severity)
+				}
+			}
+
+			report := result.Data.(CAAReport)
+			if len(report.Certificates) != 1 || report.Certificates[0].Allowed != tt.wantOK {
+				t.Errorf("got certificates %+v, want allowed=%v", report.Certificates, tt.wantOK)
+			}
+			if len(tt.caa) > 0 && report.Owner != "example.com." {
+				t.Errorf("got owner %q, want example.com.", report.Owner)
+			}
+		})
+	}
+}
+
+// TestCAAScanner_Scan_NoCertificate — option certificate=false : aucune connexion TLS
+func TestCAAScanner_Scan_NoCertificate(t *testing.T) {
+	dns := dnstest.NewServer()
+	defer dns.Close()
+	dns.Add(dnstest.SOA("example.com."), caaRecord("example.com.", 0, "issue", "letsencrypt.org"))
+
+	scanner := CAAScanner{
+		DNS: dns.Client(),
+		DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+			t.Errorf("unexpected TLS dial to %s", address)
+			return nil, net.ErrClosed
+		},
+	}
+	result, err := scanner.Scan(context.Background(), "example.com", Options{"certificate": false})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := findingsByID(result)["caa.issuer"]; ok {
+		t.Error("got caa.issuer finding, want none")
+	}
+}
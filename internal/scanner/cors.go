@@ -0,0 +1,289 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// CORSScanner - Scanner CORS : envoie des en-têtes Origin forgés et vérifie ce que le serveur autorise
+// Un site qui reflète n'importe quelle origine avec Access-Control-Allow-Credentials: true laisse
+// tout site tiers lire les réponses authentifiées de ses utilisateurs
+type CORSScanner struct {
+	Client *http.Client // Client HTTP utilisé (nil = client par défaut)
+}
+
+// CORSReport — réponses du serveur à chaque origine forgée, endpoint par endpoint
+type CORSReport struct {
+	Endpoints []CORSEndpoint `json:"endpoints"`
+}
+
+// CORSEndpoint — sondes envoyées à une URL
+type CORSEndpoint struct {
+	URL    string      `json:"url"`
+	Probes []CORSProbe `json:"probes"`
+}
+
+// CORSProbe — une requête forgée et les en-têtes CORS de la réponse
+type CORSProbe struct {
+	Name             string `json:"name"` // arbitrary, null, suffix, prefix, http, preflight
+	Method           string `json:"method"`
+	Origin           string `json:"origin"`
+	Status           int    `json:"status,omitempty"`
+	AllowOrigin      string `json:"allow_origin,omitempty"`
+	AllowCredentials bool   `json:"allow_credentials"`
+	AllowMethods     string `json:"allow_methods,omitempty"` // Preflight uniquement
+	AllowHeaders     string `json:"allow_headers,omitempty"` // Preflight uniquement
+	Error            string `json:"error,omitempty"`
+}
+
+// corsOptions — options typées de CORSScanner
+type corsOptions struct {
+	Paths []string // Endpoints testés (page d'accueil par défaut)
+}
+
+// corsOrigin — origine forgée envoyée par une sonde
+type corsOrigin struct {
+	name   string
+	origin func(host string) string
+	title  string   // Titre du finding quand l'origine est acceptée
+	risk   Severity // Sévérité si acceptée avec Access-Control-Allow-Credentials: true
+}
+
+// corsOrigins — origines forgées, de la plus grossière à la plus subtile
+// suffix / prefix visent les contrôles par expression régulière mal ancrée (startsWith, endsWith sans point)
+var corsOrigins = []corsOrigin{
+	{"arbitrary", func(string) string { return "https://gosentry-attacker.example" }, "Origine arbitraire acceptée", SeverityHigh},
+	{"null", func(string) string { return "null" }, "Origine null acceptée", SeverityHigh},
+	{"suffix", func(host string) string { return "https://" + host + ".gosentry-attacker.example" }, "Origine suffixée acceptée (contrôle par préfixe)", SeverityHigh},
+	{"prefix", func(host string) string { return "https://gosentryattacker" + host }, "Origine préfixée acceptée (contrôle par suffixe)", SeverityHigh},
+	{"http", func(host string) string { return "http://" + host }, "Origine HTTP acceptée", SeverityMedium},
+}
+
+// Preflight envoyé avec l'origine arbitraire : méthode destructrice et en-tête d'authentification
+const (
+	corsRequestMethod  = "DELETE"
+	corsRequestHeaders = "authorization, x-gosentry-test"
+)
+
+// corsDangerousMethods — méthodes qu'un preflight ne devrait accorder qu'aux origines de confiance
+var corsDangerousMethods = []string{"PUT", "PATCH", "DELETE"}
+
+// Name retourne l'identifiant du scanner CORS
+func (c CORSScanner) Name() string { return "cors" }
+
+// Info retourne les métadonnées du scanner (GET /scanners)
+func (c CORSScanner) Info() Info {
+	return Info{
+		Title:       "CORS",
+		Description: "Envoie des origines forgées (arbitraire, null, préfixe/suffixe, HTTP) et un preflight, puis détecte les origines reflétées, le joker combiné aux credentials et les preflights trop permissifs",
+		Version:     "1.0.0",
+	}
+}
+
+// Schema publie les options acceptées par le scanner CORS
+func (c CORSScanner) Schema() Schema {
+	return Schema{
+		{
+			Name:        "paths",
+			Type:        OptionStrings,
+			Description: "Endpoints testés (ex: /api/me, /graphql)",
+			Default:     []string{"/"},
+		},
+	}
+}
+
+// options convertit les Options génériques en corsOptions
+func (c CORSScanner) options(opts Options) corsOptions {
+	opts = c.Schema().Apply(opts)
+	var o corsOptions
+	for _, path := range opts.Strings("paths") {
+		if path = absolutePath(strings.TrimSpace(path)); !slices.Contains(o.Paths, path) {
+			o.Paths = append(o.Paths, path)
+		}
+	}
+	// Liste vide (?paths=) → page d'accueil
+	if len(o.Paths) == 0 {
+		o.Paths = []string{"/"}
+	}
+	return o
+}
+
+// Scan envoie chaque origine forgée puis un preflight à chaque endpoint
+// Un endpoint injoignable n'arrête pas le scan — erreur seulement si aucune requête n'aboutit
+func (c CORSScanner) Scan(ctx context.Context, domain string, opts Options) (Result, error) {
+	o := c.options(opts)
+	// Hôte sans port : les origines forgées imitent le nom, pas l'adresse de connexion
+	host := domain
+	if h, _, err := net.SplitHostPort(domain); err == nil {
+		host = h
+	}
+
+	report := &CORSReport{}
+	result := newResult(ctx, nil)
+	var lastErr error
+	answered := false
+	for _, path := range o.Paths {
+		url := "https://" + domain + path
+		endpoint := CORSEndpoint{URL: url}
+		for _, forged := range corsOrigins {
+			probe := c.probe(ctx, http.MethodGet, url, forged.origin(host), nil)
+			probe.Name = forged.name
+			endpoint.Probes = append(endpoint.Probes, probe)
+		}
+		preflight := c.probe(ctx, http.MethodOptions, url, corsOrigins[0].origin(host), http.Header{
+			"Access-Control-Request-Method":  {corsRequestMethod},
+			"Access-Control-Request-Headers": {corsRequestHeaders},
+		})
+		preflight.Name = "preflight"
+		endpoint.Probes = append(endpoint.Probes, preflight)
+		report.Endpoints = append(report.Endpoints, endpoint)
+
+		if ctx.Err() != nil {
+			result.Data = report
+			return result, ctx.Err()
+		}
+		for _, p := range endpoint.Probes {
+			if p.Error != "" {
+				lastErr = errors.New(p.Error)
+			} else {
+				answered = true
+			}
+		}
+		checkCORS(&result, endpoint)
+	}
+
+	if !answered {
+		return Result{}, fmt.Errorf("erreur de CORS: %w", lastErr)
+	}
+	result.Data = report
+	return result, nil
+}
+
+// probe envoie une requête avec l'en-tête Origin origin et relève les en-têtes CORS de la réponse
+func (c CORSScanner) probe(ctx context.Context, method, url, origin string, headers http.Header) CORSProbe {
+	p := CORSProbe{Method: method, Origin: origin}
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		p.Error = err.Error()
+		return p
+	}
+	for name, values := range headers {
+		req.Header[name] = values
+	}
+	req.Header.Set("Origin", origin)
+	resp, err := clientOrDefault(c.Client).Do(req)
+	if err != nil {
+		p.Error = err.Error()
+		return p
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	p.Status = resp.StatusCode
+	p.AllowOrigin = resp.Header.Get("Access-Control-Allow-Origin")
+	p.AllowCredentials = strings.EqualFold(strings.TrimSpace(resp.Header.Get("Access-Control-Allow-Credentials")), "true")
+	p.AllowMethods = resp.Header.Get("Access-Control-Allow-Methods")
+	p.AllowHeaders = resp.Header.Get("Access-Control-Allow-Headers")
+	return p
+}
+
+// evidence retranscrit la requête et les en-têtes CORS de la réponse
+func (p CORSProbe) evidence() string {
+	request := p.Method + " Origin: " + p.Origin
+	if p.Method == http.MethodOptions {
+		request += ", Access-Control-Request-Method: " + corsRequestMethod + ", Access-Control-Request-Headers: " + corsRequestHeaders
+	}
+	if p.Error != "" {
+		return request + " → " + p.Error
+	}
+	parts := []string{fmt.Sprintf("%s → %d", request, p.Status)}
+	if p.AllowOrigin != "" {
+		parts = append(parts, "Access-Control-Allow-Origin: "+p.AllowOrigin)
+	}
+	if p.AllowCredentials {
+		parts = append(parts, "Access-Control-Allow-Credentials: true")
+	}
+	if p.AllowMethods != "" {
+		parts = append(parts, "Access-Control-Allow-Methods: "+p.AllowMethods)
+	}
+	if p.AllowHeaders != "" {
+		parts = append(parts, "Access-Control-Allow-Headers: "+p.AllowHeaders)
+	}
+	return strings.Join(parts, " | ")
+}
+
+// checkCORS transforme les sondes d'un endpoint en findings — mêmes IDs que le contrôle passe ou échoue
+// Une sonde en erreur ne produit pas de finding : on ne sait pas ce que le serveur aurait répondu
+func checkCORS(result *Result, endpoint CORSEndpoint) {
+	add := func(id, title string, severity Severity, evidence, remediation string) {
+		if severity == SeverityInfo {
+			remediation = ""
+		}
+		result.add(Finding{ID: id, Title: title, Severity: severity, Category: "cors", Evidence: evidence, Asset: endpoint.URL, Remediation: remediation})
+	}
+	const fix = "Comparer l'Origin à une liste exacte d'origines autorisées au lieu de la refléter"
+
+	probes := make(map[string]CORSProbe)
+	for _, p := range endpoint.Probes {
+		probes[p.Name] = p
+	}
+
+	// Origines reflétées : sans credentials, seules les réponses publiques sont lisibles
+	for _, forged := range corsOrigins {
+		p := probes[forged.name]
+		if p.Error != "" {
+			continue
+		}
+		switch {
+		case p.AllowOrigin == p.Origin && p.AllowCredentials:
+			add("cors.origin."+forged.name, forged.title+" avec credentials", forged.risk, p.evidence(), fix)
+		case p.AllowOrigin == p.Origin:
+			add("cors.origin."+forged.name, forged.title, SeverityLow, p.evidence()+" — sans credentials : seules les réponses non authentifiées sont lisibles", fix)
+		default:
+			add("cors.origin."+forged.name, "Origine "+forged.name+" refusée", SeverityInfo, p.evidence(), "")
+		}
+	}
+
+	// Joker : légitime pour une API publique, jamais avec credentials (le navigateur bloque, mais la configuration
+	// révèle l'intention de partager des réponses authentifiées — souvent corrigée en reflétant l'origine)
+	if p := probes["arbitrary"]; p.Error == "" {
+		switch {
+		case p.AllowOrigin == "*" && p.AllowCredentials:
+			add("cors.wildcard", "Joker combiné aux credentials", SeverityMedium, p.evidence(),
+				"Access-Control-Allow-Origin: * ne doit jamais accompagner Access-Control-Allow-Credentials: true")
+		case p.AllowOrigin == "*":
+			add("cors.wildcard", "Joker Access-Control-Allow-Origin", SeverityInfo, p.evidence()+" — acceptable pour une API publique sans authentification", "")
+		default:
+			add("cors.wildcard", "Pas de joker", SeverityInfo, p.evidence(), "")
+		}
+	}
+
+	// Preflight : une origine étrangère ne doit obtenir ni DELETE ni l'en-tête Authorization
+	if p := probes["preflight"]; p.Error == "" {
+		allowed := p.AllowOrigin == "*" || p.AllowOrigin == p.Origin
+		methods := strings.ToUpper(p.AllowMethods)
+		var granted []string
+		for _, m := range corsDangerousMethods {
+			if strings.Contains(methods, m) || strings.TrimSpace(methods) == "*" {
+				granted = append(granted, m)
+			}
+		}
+		headers := strings.ToLower(p.AllowHeaders)
+		if strings.Contains(headers, "authorization") || strings.Contains(headers, "x-gosentry-test") || strings.TrimSpace(headers) == "*" {
+			granted = append(granted, "en-têtes arbitraires")
+		}
+		const preflightFix = "Ne répondre au preflight qu'aux origines autorisées, avec les seules méthodes et en-têtes utilisés"
+		switch {
+		case allowed && len(granted) > 0 && p.AllowCredentials:
+			add("cors.preflight", "Preflight permissif avec credentials", SeverityHigh, p.evidence()+" — accordé : "+strings.Join(granted, ", "), preflightFix)
+		case allowed && len(granted) > 0:
+			add("cors.preflight", "Preflight permissif", SeverityMedium, p.evidence()+" — accordé : "+strings.Join(granted, ", "), preflightFix)
+		default:
+			add("cors.preflight", "Preflight restreint", SeverityInfo, p.evidence(), "")
+		}
+	}
+}
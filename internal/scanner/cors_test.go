@@ -0,0 +1,156 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestCORSScanner_Name vérifie que le scanner retourne le bon identifiant
+func TestCORSScanner_Name(t *testing.T) {
+	if got := (CORSScanner{}).Name(); got != "cors" {
+		t.Errorf("got %s, want cors", got)
+	}
+}
+
+// corsServer démarre un serveur TLS dont la politique CORS est décidée par policy(origin)
+// policy retourne l'Access-Control-Allow-Origin à renvoyer ("" = aucun) et si les credentials sont autorisés
+func corsServer(t *testing.T, policy func(origin string) (string, bool), preflight http.Header) (CORSScanner, string) {
+	t.Helper()
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allow, credentials := policy(r.Header.Get("Origin"))
+		if allow != "" {
+			w.Header().Set("Access-Control-Allow-Origin", allow)
+		}
+		if credentials {
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+		if r.Method == http.MethodOptions {
+			for name, values := range preflight {
+				w.Header()[name] = values
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return CORSScanner{Client: srv.Client()}, srv.Listener.Addr().String()
+}
+
+// TestCORSScanner_Scan — origines reflétées, joker, contrôles mal ancrés et preflight
+func TestCORSScanner_Scan(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    func(origin string) (string, bool)
+		preflight http.Header
+		want      map[string]Severity
+	}{
+		{
+			name: "liste exacte",
+			policy: func(origin string) (string, bool) {
+				if origin == "https://app.127.0.0.1" {
+					return origin, true
+				}
+				return "", false
+			},
+			want: map[string]Severity{
+				"cors.origin.arbitrary": SeverityInfo, "cors.origin.null": SeverityInfo, "cors.origin.suffix": SeverityInfo,
+				"cors.origin.prefix": SeverityInfo, "cors.origin.http": SeverityInfo, "cors.wildcard": SeverityInfo,
+				"cors.preflight": SeverityInfo,
+			},
+		},
+		{
+			name:      "origine reflétée avec credentials",
+			policy:    func(origin string) (string, bool) { return origin, true },
+			preflight: http.Header{"Access-Control-Allow-Methods": {"GET, POST, DELETE"}},
+			want: map[string]Severity{
+				"cors.origin.arbitrary": SeverityHigh, "cors.origin.null": SeverityHigh, "cors.origin.http": SeverityMedium,
+				"cors.preflight": SeverityHigh,
+			},
+		},
+		{
+			// Contrôle strings.HasPrefix(origin, "https://127.0.0.1") : l'origine suffixée passe
+			name: "préfixe non ancré",
+			policy: func(origin string) (string, bool) {
+				if strings.HasPrefix(origin, "https://127.0.0.1") {
+					return origin, true
+				}
+				return "", false
+			},
+			want: map[string]Severity{
+				"cors.origin.arbitrary": SeverityInfo, "cors.origin.suffix": SeverityHigh, "cors.origin.prefix": SeverityInfo,
+			},
+		},
+		{
+			// Contrôle strings.HasSuffix(origin, "127.0.0.1") sans le point : l'origine préfixée passe
+			name: "suffixe sans point",
+			policy: func(origin string) (string, bool) {
+				if strings.HasSuffix(origin, "127.0.0.1") {
+					return origin, false
+				}
+				return "", false
+			},
+			want: map[string]Severity{
+				"cors.origin.prefix": SeverityLow, "cors.origin.http": SeverityLow, "cors.origin.suffix": SeverityInfo,
+			},
+		},
+		{
+			name:      "joker avec credentials",
+			policy:    func(string) (string, bool) { return "*", true },
+			preflight: http.Header{"Access-Control-Allow-Headers": {"*"}},
+			want: map[string]Severity{
+				"cors.wildcard": SeverityMedium, "cors.origin.arbitrary": SeverityInfo, "cors.preflight": SeverityHigh,
+			},
+		},
+		{
+			name:   "API publique",
+			policy: func(string) (string, bool) { return "*", false },
+			want:   map[string]Severity{"cors.wildcard": SeverityInfo, "cors.preflight": SeverityInfo},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner, domain := corsServer(t, tt.policy, tt.preflight)
+			result, err := scanner.Scan(context.Background(), domain, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			byID := findingsByID(result)
+			for id, severity := range tt.want {
+				if f, ok := byID[id]; !ok || f.Severity != severity {
+					t.Errorf("%s : got %+v, want %s", id, f, severity)
+				}
+			}
+		})
+	}
+}
+
+// TestCORSScanner_Scan_Evidence — la preuve reprend la requête forgée et les en-têtes de la réponse
+func TestCORSScanner_Scan_Evidence(t *testing.T) {
+	scanner, domain := corsServer(t, func(origin string) (string, bool) { return origin, true }, nil)
+	result, err := scanner.Scan(context.Background(), domain, Options{"paths": []string{"/api/me", "api/me"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := result.Data.(*CORSReport)
+	if len(report.Endpoints) != 1 || !strings.HasSuffix(report.Endpoints[0].URL, "/api/me") {
+		t.Fatalf("got endpoints %+v, want a single /api/me", report.Endpoints)
+	}
+	f := findingsByID(result)["cors.origin.arbitrary"]
+	want := "GET Origin: https://gosentry-attacker.example → 200 | Access-Control-Allow-Origin: https://gosentry-attacker.example | Access-Control-Allow-Credentials: true"
+	if f.Evidence != want {
+		t.Errorf("got evidence %q, want %q", f.Evidence, want)
+	}
+}
+
+// TestCORSScanner_Scan_Unreachable — aucune requête n'aboutit : erreur, aucun finding
+func TestCORSScanner_Scan_Unreachable(t *testing.T) {
+	result, err := CORSScanner{}.Scan(context.Background(), "127.0.0.1:1", nil)
+	if err == nil {
+		t.Error("expected error for unreachable target")
+	}
+	if len(result.Findings) != 0 {
+		t.Errorf("got %d findings, want none", len(result.Findings))
+	}
+}
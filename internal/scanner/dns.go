@@ -1,73 +1,780 @@
 package scanner
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/daviani/go__001/internal/dnsclient"
+	"golang.org/x/net/dns/dnsmessage"
 )
 
-// DNSScanner - Scanner pour la résolution DNS (records A et AAAA)
-type DNSScanner struct{}
+// dnssecExpiryWarning — délai avant expiration des signatures à partir duquel on alerte
+// Une zone re-signée automatiquement garde toujours plusieurs semaines de marge : moins d'une semaine = re-signature en panne
+const dnssecExpiryWarning = 7 * 24 * time.Hour
+
+// DNSScanner - Scanner DNS : records de la zone, serveurs faisant autorité, cohérence entre résolveurs et DNSSEC
+// Les requêtes passent par dnsclient (format wire) : choix des résolveurs, types CAA/SOA/SRV, requêtes sans récursion
+type DNSScanner struct {
+	DNS     *dnsclient.Client // Client DNS (nil = résolveurs du système) — l'option "resolvers" remplace ses serveurs
+	Anchors []dnsclient.DS    // Ancres de confiance DNSSEC (nil = clés de la racine publiées par l'IANA)
+}
+
+// DNSRecords — données brutes du scanner DNS, un champ par type de record
+type DNSRecords struct {
+	A     []string `json:"a"`
+	AAAA  []string `json:"aaaa"`
+	CNAME []string `json:"cname,omitempty"`
+	MX    []string `json:"mx"`
+	NS    []string `json:"ns"`
+	TXT   []string `json:"txt"`
+	SOA   []string `json:"soa,omitempty"`
+	CAA   []string `json:"caa,omitempty"`
+	SRV   []string `json:"srv,omitempty"` // "<service>.<domaine>. priorité poids port cible"
+
+	Resolvers   []string        `json:"resolvers"`             // Résolveurs interrogés
+	Zone        string          `json:"zone,omitempty"`        // Apex de la zone contenant le domaine
+	Nameservers []DNSNameserver `json:"nameservers,omitempty"` // Serveurs faisant autorité interrogés directement
+	Comparison  []DNSComparison `json:"comparison,omitempty"`  // Réponses de chaque serveur, par type
+	Transfers   []DNSTransfer   `json:"transfers,omitempty"`   // Tentatives de transfert de zone (AXFR)
+	Hostnames   []string        `json:"hostnames,omitempty"`   // Noms d'hôte obtenus par transfert de zone
+
+	DNSSEC *dnsclient.Chain `json:"dnssec,omitempty"` // Chaîne de confiance validée (nil si option dnssec désactivée ou validation impossible)
+}
+
+// DNSNameserver — serveur faisant autorité et résultat de la requête SOA directe
+type DNSNameserver struct {
+	dnsclient.Nameserver
+	Authoritative bool   `json:"authoritative"`   // Réponse avec le bit AA : le serveur sert bien la zone
+	Error         string `json:"error,omitempty"` // Erreur réseau ou RCODE (REFUSED, SERVFAIL...)
+}
+
+// DNSTransfer — tentative de transfert de zone (AXFR) auprès d'un serveur faisant autorité
+type DNSTransfer struct {
+	dnsclient.Nameserver
+	Allowed   bool   `json:"allowed"`             // Zone transférée : n'importe qui peut en lister tous les noms
+	Truncated bool   `json:"truncated,omitempty"` // Transfert accepté mais interrompu avant la fin : zone partielle
+	Records   int    `json:"records,omitempty"`   // Nombre de records transférés
+	Error     string `json:"error,omitempty"`     // Motif du refus (REFUSED, NOTAUTH, connexion fermée...) ou de l'interruption
+}
+
+// DNSComparison — réponses d'un même type selon le serveur interrogé
+type DNSComparison struct {
+	Type       string              `json:"type"`
+	Consistent bool                `json:"consistent"`
+	Answers    map[string][]string `json:"answers"` // Serveur → valeurs triées (serveur en erreur absent)
+}
+
+// dnsRecordTypes — types de records interrogeables par DNSScanner, dans l'ordre des findings
+var dnsRecordTypes = []string{"A", "AAAA", "CNAME", "MX", "NS", "TXT", "SOA", "CAA", "SRV"}
+
+// dnsDefaultTypes — types interrogés par défaut (SRV coûte une requête par service testé)
+var dnsDefaultTypes = []string{"A", "AAAA", "CNAME", "MX", "NS", "TXT", "SOA", "CAA"}
+
+// dnsCompareTypes — types comparés entre serveurs
+// SOA exclu : le numéro de série change à chaque modification, un cache légèrement en retard n'est pas une anomalie
+var dnsCompareTypes = []string{"A", "AAAA", "CNAME", "MX", "NS", "TXT", "CAA"}
+
+// dnsSRVServices — services SRV courants testés quand le type SRV est demandé (non découvrables autrement)
+var dnsSRVServices = []string{
+	"_sip._tcp", "_sip._udp", "_sips._tcp", "_xmpp-client._tcp", "_xmpp-server._tcp",
+	"_autodiscover._tcp", "_caldavs._tcp", "_carddavs._tcp", "_imaps._tcp", "_submission._tcp",
+	"_ldap._tcp", "_kerberos._udp", "_matrix._tcp",
+}
+
+// dnsOptions — options typées de DNSScanner
+type dnsOptions struct {
+	Types         map[string]bool // Types de records à interroger (Set)
+	Resolvers     []string        // Résolveurs "ip:port" (vide = ceux du client)
+	Authoritative bool            // Interroger directement les serveurs faisant autorité
+	Compare       bool            // Comparer les réponses entre serveurs
+	DNSSEC        bool            // Valider la chaîne de confiance DNSSEC
+	AXFR          bool            // Tenter un transfert de zone auprès des serveurs faisant autorité
+}
 
 // Name retourne l'identifiant du scanner DNS
 func (d DNSScanner) Name() string { return "dns" }
 
-// Scan effectue une résolution DNS complète du domaine
-// Résout les records A/AAAA (IPs), MX (serveurs mail), NS (nameservers) et TXT (SPF, DMARC...)
-func (d DNSScanner) Scan(domain string) (string, error) {
+// Info retourne les métadonnées du scanner (GET /scanners)
+func (d DNSScanner) Info() Info {
+	return Info{
+		Title:       "DNS",
+		Description: "Analyse les records DNS du domaine (A, AAAA, CNAME, MX, NS, TXT, SOA, CAA, SRV), ses serveurs faisant autorité (transfert de zone ouvert), la cohérence des réponses entre résolveurs et sa chaîne DNSSEC",
+		Version:     "1.3.1",
+	}
+}
 
-	// --- Records A et AAAA (adresses IP) ---
-	// LookupIP retourne une slice de net.IP (IPv4 + IPv6)
-	ips, err := net.LookupIP(domain)
+// Schema publie les options acceptées par le scanner DNS
+func (d DNSScanner) Schema() Schema {
+	return Schema{
+		{
+			Name:        "types",
+			Type:        OptionStrings,
+			Description: "Types de records à interroger",
+			Default:     dnsDefaultTypes,
+			Enum:        dnsRecordTypes,
+		},
+		{
+			Name:        "resolvers",
+			Type:        OptionStrings,
+			Description: "Résolveurs à interroger (ip ou ip:port) — vide = DNS_RESOLVERS, sinon ceux du système",
+			Default:     []string{},
+			Validate: func(value any) error {
+				servers, _ := value.([]string)
+				_, err := dnsclient.ParseServers(servers)
+				return err
+			},
+		},
+		{
+			Name:        "authoritative",
+			Type:        OptionBool,
+			Description: "Interroger directement les serveurs faisant autorité de la zone (délégation défaillante)",
+			Default:     true,
+		},
+		{
+			Name:        "compare",
+			Type:        OptionBool,
+			Description: "Comparer les réponses des résolveurs et des serveurs faisant autorité (split-horizon, cache empoisonné)",
+			Default:     true,
+		},
+		{
+			Name:        "axfr",
+			Type:        OptionBool,
+			Description: "Tenter un transfert de zone (AXFR) auprès de chaque serveur faisant autorité",
+			Default:     true,
+		},
+		{
+			Name:        "dnssec",
+			Type:        OptionBool,
+			Description: "Valider la chaîne de confiance DNSSEC (DS, DNSKEY, RRSIG) depuis la racine",
+			Default:     true,
+		},
+	}
+}
+
+// options convertit les Options génériques en dnsOptions
+func (d DNSScanner) options(opts Options) (dnsOptions, error) {
+	opts = d.Schema().Apply(opts)
+	resolvers, err := dnsclient.ParseServers(opts.Strings("resolvers"))
 	if err != nil {
-		return "", fmt.Errorf("erreur de DNS: %w", err)
+		return dnsOptions{}, err
+	}
+	o := dnsOptions{
+		Types:         make(map[string]bool),
+		Resolvers:     resolvers,
+		Authoritative: opts.Bool("authoritative"),
+		Compare:       opts.Bool("compare"),
+		DNSSEC:        opts.Bool("dnssec"),
+		AXFR:          opts.Bool("axfr"),
 	}
+	for _, t := range opts.Strings("types") {
+		o.Types[t] = true
+	}
+	return o, nil
+}
 
-	// Construction des résultats par type de record
-	resultIP := "IPs pour " + domain + ": \n"
-	resultMX := "MXs pour " + domain + ": \n"
-	resultNS := "NSs pour " + domain + ": \n"
-	resultTXT := "TXTs pour " + domain + ": \n"
+// client retourne le client DNS du scan : celui du scanner, avec les résolveurs de l'option "resolvers" s'il y en a
+func (d DNSScanner) client(o dnsOptions) *dnsclient.Client {
+	var c dnsclient.Client
+	if d.DNS != nil {
+		c = *d.DNS
+	}
+	if len(o.Resolvers) > 0 {
+		c.Servers = o.Resolvers
+	}
+	return &c
+}
 
-	// --- Records MX (serveurs mail) ---
-	// LookupMX retourne []*net.MX — chaque MX a un champ .Host (string) et .Pref (priorité)
-	mxs, err := net.LookupMX(domain)
+// Scan effectue une résolution DNS complète du domaine
+// Résout les records A/AAAA (IPs), MX (serveurs mail), NS (nameservers), TXT (SPF, DMARC...), SOA, CAA...
+// L'option "types" restreint les records interrogés (ex: ?types=MX,TXT)
+// Chaque requête reçoit ctx : elle est abandonnée dès que le contexte expire
+func (d DNSScanner) Scan(ctx context.Context, domain string, opts Options) (Result, error) {
+	o, err := d.options(opts)
 	if err != nil {
-		resultMX = "MX : erreur de résolution"
+		return Result{}, fmt.Errorf("erreur de DNS: %w", err)
+	}
+	client := d.client(o)
+
+	result := newResult(ctx, nil)
+	records := DNSRecords{Resolvers: client.Resolvers()}
+
+	// Une erreur sur un type n'est pas fatale : elle devient un finding dns.error et les autres types sont interrogés
+	var addrErrs []error
+	for _, t := range dnsRecordTypes {
+		if !o.Types[t] {
+			continue
+		}
+		values, err := lookupRecords(ctx, client, domain, t)
+		if errors.Is(err, dnsclient.ErrNXDomain) {
+			// Domaine inexistant : rien d'autre à analyser
+			return Result{}, fmt.Errorf("erreur de DNS: %s : %w", domain, err)
+		}
+		if err != nil {
+			if t == "A" || t == "AAAA" {
+				addrErrs = append(addrErrs, err)
+			}
+			dnsLookupFailed(&result, domain, t, err)
+			continue
+		}
+		records.set(t, values)
+	}
+
+	// Seule erreur fatale : un domaine sans IP n'a pas de surface d'attaque web
+	if (o.Types["A"] || o.Types["AAAA"]) && len(records.A)+len(records.AAAA) == 0 {
+		err := errors.Join(addrErrs...)
+		if err == nil {
+			err = fmt.Errorf("aucune adresse IP pour %s", domain)
+		}
+		return Result{}, fmt.Errorf("erreur de DNS: %w", err)
 	}
 
-	// --- Records NS (nameservers) ---
-	// LookupNS retourne []*net.NS — chaque NS a un champ .Host (string)
-	nss, err := net.LookupNS(domain)
+	// Un finding "info" par record — le client peut filtrer par ID (dns.record.mx...)
+	for _, t := range dnsRecordTypes {
+		addRecords(&result, domain, t, records.get(t))
+	}
+
+	// --- Serveurs faisant autorité et cohérence des réponses ---
+	if o.Authoritative {
+		records.Zone, records.Nameservers = checkNameservers(ctx, &result, client, domain)
+	}
+	if o.Compare {
+		records.Comparison = compareResolvers(ctx, &result, client, domain, o, records.Nameservers)
+	}
+	// Zone introuvable lors de la vérification des NS : déjà signalé, inutile de la rechercher à nouveau
+	if o.AXFR && (records.Zone != "" || !o.Authoritative) {
+		records.Transfers, records.Hostnames = checkTransfers(ctx, &result, client, domain, records.Zone, records.Nameservers)
+	}
+
+	// --- DNSSEC (DS chez le parent, DNSKEY, RRSIG) ---
+	if o.DNSSEC {
+		records.DNSSEC = d.checkDNSSEC(ctx, &result, client, domain)
+	}
+	result.Data = records
+
+	// Contexte expiré en cours de route → on retourne ce qui a été résolu avec l'erreur
+	return result, ctx.Err()
+}
+
+// lookupRecords interroge un type de record et retourne ses valeurs au format zone
+// MX : seul l'hôte est gardé (format historique du scanner), SRV : nom du service + valeur
+func lookupRecords(ctx context.Context, client *dnsclient.Client, domain, recordType string) ([]string, error) {
+	if recordType == "SRV" {
+		var values []string
+		for _, service := range dnsSRVServices {
+			srv, err := client.Lookup(ctx, service+"."+domain, dnsmessage.TypeSRV)
+			if err != nil {
+				// Service absent (NXDOMAIN) ou en erreur : on teste les suivants
+				continue
+			}
+			for _, r := range srv {
+				values = append(values, r.Name+" "+r.Value)
+			}
+		}
+		return values, ctx.Err()
+	}
+
+	qtype, _ := dnsclient.ParseType(recordType)
+	found, err := client.Lookup(ctx, domain, qtype)
 	if err != nil {
-		resultNS = "NS : erreur de résolution"
+		return nil, err
+	}
+	values := make([]string, 0, len(found))
+	for _, r := range found {
+		value := r.Value
+		if recordType == "MX" {
+			_, value, _ = strings.Cut(value, " ")
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// set range les valeurs d'un type de record dans le champ correspondant
+func (r *DNSRecords) set(recordType string, values []string) {
+	switch recordType {
+	case "A":
+		r.A = values
+	case "AAAA":
+		r.AAAA = values
+	case "CNAME":
+		r.CNAME = values
+	case "MX":
+		r.MX = values
+	case "NS":
+		r.NS = values
+	case "TXT":
+		r.TXT = values
+	case "SOA":
+		r.SOA = values
+	case "CAA":
+		r.CAA = values
+	case "SRV":
+		r.SRV = values
 	}
+}
 
-	// --- Records TXT (SPF, vérification domaine...) ---
-	// LookupTXT retourne directement []string — pas besoin de .Host ou .String()
-	txts, err := net.LookupTXT(domain)
+// get retourne les valeurs d'un type de record
+func (r DNSRecords) get(recordType string) []string {
+	switch recordType {
+	case "A":
+		return r.A
+	case "AAAA":
+		return r.AAAA
+	case "CNAME":
+		return r.CNAME
+	case "MX":
+		return r.MX
+	case "NS":
+		return r.NS
+	case "TXT":
+		return r.TXT
+	case "SOA":
+		return r.SOA
+	case "CAA":
+		return r.CAA
+	case "SRV":
+		return r.SRV
+	}
+	return nil
+}
+
+// dnsLookupFailed signale une requête en échec (timeout, SERVFAIL...) sans interrompre le scan
+// ID distinct des records : une panne passagère ne doit pas apparaître comme un record supprimé
+func dnsLookupFailed(result *Result, domain, recordType string, err error) {
+	result.add(Finding{
+		ID:       "dns.error",
+		Title:    "Requête DNS en échec",
+		Severity: SeverityInfo,
+		Category: "dns",
+		Evidence: recordType + " " + domain + " : " + err.Error(),
+		Asset:    recordType + " " + domain,
+	})
+}
+
+// checkNameservers interroge chaque serveur faisant autorité sans récursion (SOA de la zone)
+// Un serveur qui ne répond pas avec autorité (REFUSED, timeout, bit AA absent) = délégation défaillante (lame delegation)
+func checkNameservers(ctx context.Context, result *Result, client *dnsclient.Client, domain string) (string, []DNSNameserver) {
+	zone, servers, err := client.Authoritative(ctx, domain)
+	if err != nil {
+		dnsLookupFailed(result, domain, "NS", err)
+		return zone, nil
+	}
+
+	auth := *client
+	auth.NoRecursion = true
+	nameservers := make([]DNSNameserver, len(servers))
+	var wg sync.WaitGroup
+	for i, ns := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nameservers[i] = DNSNameserver{Nameserver: ns}
+			resp, err := auth.Exchange(ctx, ns.Addr, zone, dnsmessage.TypeSOA, false)
+			switch {
+			case err != nil:
+				nameservers[i].Error = err.Error()
+			case resp.Header.RCode != dnsmessage.RCodeSuccess:
+				nameservers[i].Error = (&dnsclient.RCodeError{RCode: resp.Header.RCode}).Error()
+			case !resp.Header.Authoritative:
+				nameservers[i].Error = "réponse sans autorité (bit AA absent)"
+			default:
+				nameservers[i].Authoritative = true
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Un finding par nom de serveur : défaillant si l'une de ses adresses l'est
+	var names []string
+	failures := make(map[string][]string)
+	for _, ns := range nameservers {
+		if !slices.Contains(names, ns.Name) {
+			names = append(names, ns.Name)
+		}
+		if !ns.Authoritative {
+			failures[ns.Name] = append(failures[ns.Name], strings.TrimSuffix(ns.Addr, ":53")+" : "+ns.Error)
+		}
+	}
+	for _, name := range names {
+		finding := Finding{
+			ID:       "dns.ns.authoritative",
+			Title:    "Serveur faisant autorité",
+			Severity: SeverityInfo,
+			Category: "dns",
+			Evidence: name + " répond avec autorité pour " + zone,
+			Asset:    name,
+		}
+		if errs := failures[name]; len(errs) > 0 {
+			// Les résolveurs qui tombent sur ce serveur échouent ou ralentissent ; s'il est repris par un tiers, il peut servir de fausses réponses
+			finding.Title = "Délégation défaillante (lame delegation)"
+			finding.Severity = SeverityMedium
+			finding.Evidence = name + " ne sert pas " + zone + " : " + strings.Join(errs, ", ")
+			finding.Remediation = "Corriger la configuration du serveur ou retirer ce NS de la délégation (chez le registrar et dans la zone)"
+		}
+		result.add(finding)
+	}
+	return zone, nameservers
+}
+
+// checkTransfers tente un transfert de zone (AXFR) auprès des serveurs faisant autorité
+// Serveurs déjà vérifiés par checkNameservers : seuls ceux qui servent la zone sont sollicités ; sinon, recherche de la zone et de ses NS
+// Un finding par nom de serveur : high si l'une de ses adresses accepte le transfert
+func checkTransfers(ctx context.Context, result *Result, client *dnsclient.Client, domain, zone string, nameservers []DNSNameserver) ([]DNSTransfer, []string) {
+	var servers []dnsclient.Nameserver
+	if zone == "" {
+		var err error
+		zone, servers, err = client.Authoritative(ctx, domain)
+		if err != nil {
+			dnsLookupFailed(result, domain, "NS", err)
+			return nil, nil
+		}
+	}
+	for _, ns := range nameservers {
+		if ns.Authoritative {
+			servers = append(servers, ns.Nameserver)
+		}
+	}
+
+	transfers, hostnames := transferZone(ctx, client, zone, servers)
+
+	var names []string
+	byName := make(map[string][]DNSTransfer)
+	for _, t := range transfers {
+		if _, ok := byName[t.Name]; !ok {
+			names = append(names, t.Name)
+		}
+		byName[t.Name] = append(byName[t.Name], t)
+	}
+	for _, name := range names {
+		finding := Finding{
+			ID:       "dns.axfr",
+			Title:    "Transfert de zone refusé",
+			Severity: SeverityInfo,
+			Category: "dns",
+			Asset:    name,
+		}
+		var refusals []string
+		for _, t := range byName[name] {
+			addr := strings.TrimSuffix(t.Addr, ":53")
+			if t.Allowed {
+				// Le transfert livre toute la zone : hôtes internes, préproduction, VPN... la cartographie est offerte à l'attaquant
+				finding.Title = "Transfert de zone (AXFR) ouvert"
+				finding.Severity = SeverityHigh
+				finding.Evidence = fmt.Sprintf("%s (%s) a transféré la zone %s : %d records, %d noms d'hôte", name, addr, zone, t.Records, len(hostnames))
+				if t.Truncated {
+					// Le serveur a accepté : l'interruption ne rend la zone que partielle, pas le transfert fermé
+					finding.Evidence += " (transfert interrompu : " + t.Error + ")"
+				}
+				finding.Remediation = "Restreindre les transferts de zone aux serveurs secondaires (allow-transfer par adresse IP ou clé TSIG)"
+				break
+			}
+			refusals = append(refusals, addr+" : "+t.Error)
+		}
+		if finding.Evidence == "" {
+			finding.Evidence = name + " refuse le transfert de " + zone + " (" + strings.Join(refusals, ", ") + ")"
+		}
+		result.add(finding)
+	}
+	return transfers, hostnames
+}
+
+// transferZone tente un AXFR de la zone auprès de chaque serveur, en parallèle
+// Retourne les tentatives et les noms d'hôte (triés, sans point final) obtenus par les transferts réussis
+// Partagée avec SubdomainScanner, pour qui un transfert ouvert est une source de sous-domaines
+func transferZone(ctx context.Context, client *dnsclient.Client, zone string, servers []dnsclient.Nameserver) ([]DNSTransfer, []string) {
+	transfers := make([]DNSTransfer, len(servers))
+	found := make([][]string, len(servers))
+	var wg sync.WaitGroup
+	for i, ns := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			transfers[i] = DNSTransfer{Nameserver: ns}
+			z, err := client.Transfer(ctx, ns.Addr, zone)
+			if err != nil {
+				transfers[i].Error = err.Error()
+				return
+			}
+			transfers[i].Allowed = true
+			transfers[i].Truncated = z.Truncated
+			transfers[i].Error = z.Reason
+			transfers[i].Records = len(z.Records)
+			found[i] = zoneHostnames(zone, z.Records)
+		}()
+	}
+	wg.Wait()
+
+	var hostnames []string
+	for _, names := range found {
+		hostnames = append(hostnames, names...)
+	}
+	slices.Sort(hostnames)
+	return transfers, slices.Compact(hostnames)
+}
+
+// zoneHostnames extrait les noms d'hôte d'une zone transférée : propriétaires des records sous l'apex, sans point final
+// Les noms de service ("_dmarc", "_sip._tcp"...) ne désignent pas des hôtes et sont ignorés
+func zoneHostnames(zone string, records []dnsmessage.Resource) []string {
+	apex := strings.ToLower(strings.TrimSuffix(zone, "."))
+	var names []string
+	for _, rr := range records {
+		name := strings.ToLower(strings.TrimSuffix(rr.Header.Name.String(), "."))
+		if name == apex || !strings.HasSuffix(name, "."+apex) || strings.HasPrefix(name, "_") || strings.Contains(name, "._") {
+			continue
+		}
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// dnsTarget — serveur interrogé pour la comparaison (résolveur ou serveur faisant autorité)
+type dnsTarget struct {
+	label  string
+	addr   string
+	client *dnsclient.Client
+}
+
+// compareResolvers interroge chaque résolveur et chaque serveur faisant autorité, puis compare les réponses par type
+// Une divergence signale un split-horizon exposé, une zone désynchronisée entre NS ou un cache empoisonné
+func compareResolvers(ctx context.Context, result *Result, client *dnsclient.Client, domain string, o dnsOptions, nameservers []DNSNameserver) []DNSComparison {
+	var targets []dnsTarget
+	for _, addr := range client.Resolvers() {
+		targets = append(targets, dnsTarget{label: addr, addr: addr, client: client})
+	}
+	auth := *client
+	auth.NoRecursion = true
+	for _, ns := range nameservers {
+		if ns.Authoritative {
+			targets = append(targets, dnsTarget{label: ns.Name + " (" + ns.Addr + ")", addr: ns.Addr, client: &auth})
+		}
+	}
+	if len(targets) < 2 {
+		return nil
+	}
+
+	var types []string
+	for _, t := range dnsCompareTypes {
+		if o.Types[t] {
+			types = append(types, t)
+		}
+	}
+
+	// answers[type][serveur] — chaque serveur est interrogé dans sa propre goroutine
+	answers := make(map[string]map[string][]string)
+	for _, t := range types {
+		answers[t] = make(map[string][]string)
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, target := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, t := range types {
+				qtype, _ := dnsclient.ParseType(t)
+				found, err := target.client.LookupServer(ctx, target.addr, domain, qtype)
+				if err != nil && !errors.Is(err, dnsclient.ErrNXDomain) {
+					continue
+				}
+				values := make([]string, 0, len(found))
+				for _, r := range found {
+					values = append(values, r.Value)
+				}
+				slices.Sort(values)
+				mu.Lock()
+				answers[t][target.label] = values
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	var comparisons []DNSComparison
+	for _, t := range types {
+		byServer := answers[t]
+		if len(byServer) < 2 {
+			continue
+		}
+		comparison := DNSComparison{Type: t, Consistent: true, Answers: byServer}
+		var reference []string
+		first := true
+		for _, values := range byServer {
+			if first {
+				reference, first = values, false
+			} else if !slices.Equal(values, reference) {
+				comparison.Consistent = false
+			}
+		}
+		comparisons = append(comparisons, comparison)
+		addComparison(result, domain, comparison, targets)
+	}
+	return comparisons
+}
+
+// addComparison ajoute le finding de cohérence d'un type : info si tous les serveurs s'accordent, low sinon
+func addComparison(result *Result, domain string, c DNSComparison, targets []dnsTarget) {
+	finding := Finding{
+		ID:       "dns.consistency",
+		Title:    "Réponses DNS cohérentes (" + c.Type + ")",
+		Severity: SeverityInfo,
+		Category: "dns",
+		Evidence: fmt.Sprintf("%d serveurs, mêmes réponses", len(c.Answers)),
+		Asset:    c.Type + " " + domain,
+	}
+	if !c.Consistent {
+		// Evidence dans l'ordre des serveurs (résolveurs puis NS) plutôt que dans l'ordre aléatoire de la map
+		var parts []string
+		for _, target := range targets {
+			if values, ok := c.Answers[target.label]; ok {
+				parts = append(parts, target.label+" → "+strings.Join(values, ", "))
+			}
+		}
+		finding.Title = "Réponses DNS divergentes (" + c.Type + ")"
+		finding.Severity = SeverityLow
+		finding.Evidence = strings.Join(parts, " | ")
+		finding.Remediation = "Vérifier que tous les serveurs faisant autorité servent la même zone et qu'aucun résolveur ne renvoie de réponse falsifiée (les CDN à géolocalisation DNS divergent aussi légitimement)"
+	}
+	result.add(finding)
+}
+
+// addRecords ajoute un finding info par valeur de record DNS
+// Asset = la valeur elle-même (IP, hôte MX...) pour que chaque record reste identifiable
+func addRecords(result *Result, domain, recordType string, values []string) {
+	for _, value := range values {
+		result.add(Finding{
+			ID:       "dns.record." + strings.ToLower(recordType),
+			Title:    "Record " + recordType,
+			Severity: SeverityInfo,
+			Category: "dns",
+			Evidence: recordType + " " + domain + " → " + value,
+			Asset:    value,
+		})
+	}
+}
+
+// checkDNSSEC valide la chaîne de confiance et ajoute les findings dns.dnssec*
+// Une erreur réseau n'est pas fatale : le reste du scan DNS reste exploitable
+func (d DNSScanner) checkDNSSEC(ctx context.Context, result *Result, client *dnsclient.Client, domain string) *dnsclient.Chain {
+	now := time.Now()
+
+	chain, err := client.ValidateChain(ctx, domain, d.Anchors, now)
 	if err != nil {
-		resultTXT = "TXT : erreur de résolution"
+		result.add(Finding{
+			ID:       "dns.dnssec.error",
+			Title:    "Validation DNSSEC impossible",
+			Severity: SeverityInfo,
+			Category: "dns",
+			Evidence: err.Error(),
+			Asset:    domain,
+		})
+		return nil
+	}
+
+	zone := chain.Zone()
+	finding := Finding{ID: "dns.dnssec", Category: "dns", Asset: domain, Evidence: chain.Reason}
+	switch chain.Status {
+	case dnsclient.StatusSecure:
+		finding.Title = "DNSSEC validé"
+		finding.Severity = SeverityInfo
+		finding.Evidence = "chaîne de confiance validée jusqu'à la zone " + zone.Name
+	case dnsclient.StatusInsecure:
+		// Sans DNSSEC, un résolveur empoisonné (cache poisoning) peut rediriger le domaine sans que personne ne le détecte
+		finding.Title = "Zone non signée (DNSSEC absent)"
+		finding.Severity = SeverityLow
+		finding.Remediation = "Activer DNSSEC chez l'hébergeur DNS puis publier le DS chez le registrar"
+	case dnsclient.StatusIsland:
+		finding.Title = "Zone signée sans DS chez le parent"
+		finding.Severity = SeverityMedium
+		finding.Remediation = "Publier chez le registrar le DS de la KSK : sans lui, les signatures ne sont vérifiées par aucun résolveur"
+	case dnsclient.StatusBogus:
+		// Les résolveurs validants (1.1.1.1, 8.8.8.8, la plupart des FAI) répondent SERVFAIL : le domaine devient injoignable
+		finding.Title = "Chaîne DNSSEC invalide"
+		finding.Severity = SeverityHigh
+		finding.Remediation = "Re-signer la zone ou corriger le DS chez le registrar (clé retirée, signatures expirées...)"
+	}
+	result.add(finding)
+
+	if chain.Status == dnsclient.StatusSecure {
+		checkDNSSECExpiry(result, domain, zone, now)
+	}
+	if len(zone.Keys) > 0 {
+		checkDNSSECAlgorithms(result, domain, zone)
 	}
+	return &chain
+}
 
-	// ip.String() convertit net.IP en string lisible (ex: "188.114.96.2")
-	for _, ip := range ips {
-		resultIP += ip.String() + "\n"
+// checkDNSSECExpiry signale des signatures proches de l'expiration (expirées = chaîne bogus)
+func checkDNSSECExpiry(result *Result, domain string, zone dnsclient.Zone, now time.Time) {
+	remaining := zone.Expiration.Sub(now)
+	finding := Finding{
+		ID:       "dns.dnssec.expiry",
+		Title:    "Expiration des signatures DNSSEC",
+		Severity: SeverityInfo,
+		Category: "dns",
+		Evidence: fmt.Sprintf("signatures de %s valides jusqu'au %s (%d jours)", zone.Name, zone.Expiration.Format(time.DateOnly), int(remaining.Hours()/24)),
+		Asset:    domain,
+	}
+	if remaining < dnssecExpiryWarning {
+		finding.Title = "Signatures DNSSEC bientôt expirées"
+		finding.Severity = SeverityMedium
+		finding.Remediation = "Vérifier la re-signature automatique de la zone : à expiration, le domaine ne se résout plus chez les résolveurs validants"
 	}
+	result.add(finding)
+}
 
-	// mx.Host est un champ string de la struct net.MX (ex: "mx01.mail.icloud.com.")
-	for _, mx := range mxs {
-		resultMX += mx.Host + "\n"
+// checkDNSSECAlgorithms évalue les algorithmes des clés et des DS (RFC 8624)
+// Un seul finding, à la sévérité du pire problème trouvé
+func checkDNSSECAlgorithms(result *Result, domain string, zone dnsclient.Zone) {
+	finding := Finding{
+		ID:       "dns.dnssec.algorithm",
+		Title:    "Algorithmes DNSSEC",
+		Severity: SeverityInfo,
+		Category: "dns",
+		Asset:    domain,
+	}
+	worse := func(s Severity, title, remediation string) {
+		if s.Rank() > finding.Severity.Rank() {
+			finding.Severity, finding.Title, finding.Remediation = s, title, remediation
+		}
 	}
 
-	// ns.Host est un champ string de la struct net.NS (ex: "fish.ns.cloudflare.com.")
-	for _, ns := range nss {
-		resultNS += ns.Host + "\n"
+	var evidence []string
+	for _, key := range zone.Keys {
+		role := "ZSK"
+		if key.IsKSK() {
+			role = "KSK"
+		}
+		desc := fmt.Sprintf("%s %d %s", role, key.KeyTag(), dnsclient.AlgorithmName(key.Algorithm))
+		if bits := key.Bits(); bits > 0 {
+			desc += fmt.Sprintf(" %d bits", bits)
+		}
+		evidence = append(evidence, desc)
+
+		switch key.Algorithm {
+		case dnsclient.AlgRSAMD5, dnsclient.AlgDSA, dnsclient.AlgDSANSEC3SHA1, dnsclient.AlgECCGOST:
+			// Interdits pour signer (RFC 8624) : les résolveurs récents traitent la zone comme non signée
+			worse(SeverityHigh, "Algorithme DNSSEC obsolète", "Migrer vers ECDSAP256SHA256 (13) ou ED25519 (15) par un roulement d'algorithme")
+		case dnsclient.AlgRSASHA1, dnsclient.AlgRSASHA1NSEC3:
+			// SHA-1 : collisions pratiques, plus accepté par certaines distributions (RHEL 9)
+			worse(SeverityMedium, "Algorithme DNSSEC faible (SHA-1)", "Migrer vers ECDSAP256SHA256 (13) ou RSASHA256 (8) par un roulement d'algorithme")
+		}
+		if bits := key.Bits(); bits > 0 && bits < 2048 {
+			worse(SeverityLow, "Clé DNSSEC RSA courte", "Utiliser des clés RSA d'au moins 2048 bits, ou passer à ECDSAP256SHA256 (13)")
+		}
 	}
 
-	// txt est déjà une string, pas besoin de conversion
-	for _, txt := range txts {
-		resultTXT += txt + "\n"
+	// DS uniquement en SHA-1 : le lien parent → zone repose sur un condensat déprécié
+	if len(zone.DS) > 0 && !slices.ContainsFunc(zone.DS, func(ds dnsclient.DS) bool { return ds.DigestType != dnsclient.DigestSHA1 }) {
+		evidence = append(evidence, "DS SHA-1 uniquement")
+		worse(SeverityLow, "DS en SHA-1 uniquement", "Publier un DS SHA-256 (type 2) chez le registrar et retirer le DS SHA-1")
 	}
 
-	return resultIP + resultMX + resultNS + resultTXT, nil
+	finding.Evidence = zone.Name + " : " + strings.Join(evidence, ", ")
+	result.add(finding)
 }
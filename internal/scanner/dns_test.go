@@ -1,6 +1,15 @@
 package scanner
 
-import "testing"
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/daviani/go__001/internal/dnsclient"
+	"github.com/daviani/go__001/internal/dnsclient/dnstest"
+	"golang.org/x/net/dns/dnsmessage"
+)
 
 // TestDNSScanner_Name vérifie que le scanner retourne le bon identifiant
 // Convention Go : TestNomStruct_Methode — permet de cibler un test avec -run
@@ -19,26 +28,26 @@ func TestDNSScanner_Name(t *testing.T) {
 // TestDNSScanner_Scan — Happy path : un domaine valide doit retourner des records DNS
 // Note : ce test fait un VRAI appel réseau → dépend de la connexion internet
 func TestDNSScanner_Scan(t *testing.T) {
-	// Scan retourne (string, error) — on capte les deux valeurs
-	result, err := DNSScanner{}.Scan("google.com")
+	// Scan retourne (Result, error) — on capte les deux valeurs
+	result, err := DNSScanner{}.Scan(context.Background(), "google.com", nil)
 
 	// Fatal = arrête le test immédiatement, pas la peine de vérifier result
-	// On vérifie err EN PREMIER : si le scan a échoué, result est "" (inutile à tester)
+	// On vérifie err EN PREMIER : si le scan a échoué, result est vide (inutile à tester)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	// Si pas d'erreur, on vérifie que le résultat contient quelque chose
 	// Errorf (pas Fatal) : non bloquant, le test continue après
-	if result == "" {
-		t.Errorf("got empty result, want DNS records")
+	if len(result.Findings) == 0 {
+		t.Errorf("got no findings, want DNS records")
 	}
 }
 
 // TestDNSScanner_Scan_InvalidDomain — Error path : un domaine invalide doit retourner une erreur
 // "false_url" n'existe pas → net.LookupIP échoue → err != nil attendu
 func TestDNSScanner_Scan_InvalidDomain(t *testing.T) {
-	result, err := DNSScanner{}.Scan("false_url")
+	result, err := DNSScanner{}.Scan(context.Background(), "false_url", nil)
 
 	// Ici on VEUT une erreur — si err est nil, le test a un problème
 	// Errorf (pas Fatal) : on veut aussi vérifier result ensuite
@@ -46,9 +55,264 @@ func TestDNSScanner_Scan_InvalidDomain(t *testing.T) {
 		t.Errorf("expected error for invalid domain, got nil")
 	}
 
-	// Quand le scan échoue, result doit être "" (string vide)
+	// Quand le scan échoue, result ne doit contenir aucun finding
 	// C'est le contrat de notre interface : erreur → pas de résultat
-	if result != "" {
-		t.Errorf("expected empty result, got %s", result)
+	if len(result.Findings) != 0 {
+		t.Errorf("expected no findings, got %v", result.Findings)
+	}
+}
+
+// signedDNS démarre un serveur DNS de test avec une racine et "com." signés
+// zone est la zone example.com. servie (nil = non signée), delegated celle dont le DS est publié dans com. (nil = aucun DS)
+func signedDNS(t *testing.T, zone, delegated *dnstest.Zone) DNSScanner {
+	t.Helper()
+	srv := dnstest.NewServer()
+	t.Cleanup(srv.Close)
+
+	root, com := dnstest.NewZone("."), dnstest.NewZone("com.")
+	srv.Add(root.Records()...)
+	srv.Add(com.Records()...)
+	ds := com.DS(dnsclient.DigestSHA256)
+	srv.Add(ds, root.Sign(ds))
+
+	if zone == nil {
+		srv.Add(dnstest.SOA("example.com."))
+	} else {
+		srv.Add(zone.Records()...)
+	}
+	if delegated != nil {
+		ds := delegated.DS(dnsclient.DigestSHA256)
+		srv.Add(ds, com.Sign(ds))
+	}
+	return DNSScanner{DNS: srv.Client(), Anchors: []dnsclient.DS{root.Anchor()}}
+}
+
+// TestDNSScanner_Scan_DNSSEC — statut de la chaîne, expiration et algorithmes (serveur DNS local, sans réseau)
+func TestDNSScanner_Scan_DNSSEC(t *testing.T) {
+	example := dnstest.NewZone("example.com.")
+	expiring := dnstest.NewZone("example.com.")
+	expiring.Expiration = time.Now().Add(2 * 24 * time.Hour)
+	weak := dnstest.NewRSAZone("example.com.", dnsclient.AlgRSASHA1NSEC3, 1024)
+
+	tests := []struct {
+		name    string
+		scanner DNSScanner
+		want    map[string]Severity
+	}{
+		{
+			name:    "secure",
+			scanner: signedDNS(t, example, example),
+			want:    map[string]Severity{"dns.dnssec": SeverityInfo, "dns.dnssec.expiry": SeverityInfo, "dns.dnssec.algorithm": SeverityInfo},
+		},
+		{
+			name:    "unsigned",
+			scanner: signedDNS(t, nil, nil),
+			want:    map[string]Severity{"dns.dnssec": SeverityLow},
+		},
+		{
+			name:    "island",
+			scanner: signedDNS(t, example, nil),
+			want:    map[string]Severity{"dns.dnssec": SeverityMedium, "dns.dnssec.algorithm": SeverityInfo},
+		},
+		{
+			name:    "expiring",
+			scanner: signedDNS(t, expiring, expiring),
+			want:    map[string]Severity{"dns.dnssec": SeverityInfo, "dns.dnssec.expiry": SeverityMedium},
+		},
+		{
+			name:    "sha1 short keys",
+			scanner: signedDNS(t, weak, weak),
+			want:    map[string]Severity{"dns.dnssec": SeverityInfo, "dns.dnssec.algorithm": SeverityMedium},
+		},
+		{
+			// Le parent publie le DS d'une autre clé (rotation de KSK ratée)
+			name:    "bogus",
+			scanner: signedDNS(t, example, dnstest.NewZone("example.com.")),
+			want:    map[string]Severity{"dns.dnssec": SeverityHigh},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// types vide : seuls les findings DNSSEC sont produits, sans requête vers les vrais résolveurs
+			result, err := tt.scanner.Scan(context.Background(), "example.com", Options{"types": []string{}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			byID := findingsByID(result)
+			for id, severity := range tt.want {
+				if f, ok := byID[id]; !ok || f.Severity != severity {
+					t.Errorf("%s : got %+v, want severity %s", id, f, severity)
+				}
+			}
+			if result.Data.(DNSRecords).DNSSEC == nil {
+				t.Error("got no DNSSEC data")
+			}
+		})
+	}
+}
+
+// exampleZone publie une zone example.com. non signée dont l'apex résout vers ip
+// NS : ns1 (192.0.2.53) et ns2 (192.0.2.54), à rediriger avec dnstest.Dialer
+func exampleZone(srv *dnstest.Server, ip [4]byte) {
+	srv.Add(
+		dnstest.SOA("example.com."),
+		dnstest.Record("example.com.", &dnsmessage.AResource{A: ip}),
+		dnstest.Record("example.com.", &dnsmessage.MXResource{Pref: 10, MX: dnsmessage.MustNewName("mx.example.com.")}),
+		dnstest.Record("example.com.", &dnsmessage.NSResource{NS: dnsmessage.MustNewName("ns1.example.com.")}),
+		dnstest.Record("example.com.", &dnsmessage.NSResource{NS: dnsmessage.MustNewName("ns2.example.com.")}),
+		dnstest.Record("example.com.", &dnsmessage.TXTResource{TXT: []string{"v=spf1 ", "-all"}}),
+		dnstest.Raw("example.com.", dnsclient.TypeCAA, append([]byte{0, 5}, "issueletsencrypt.org"...)),
+		dnstest.Record("_sip._tcp.example.com.", &dnsmessage.SRVResource{Priority: 10, Weight: 5, Port: 5060, Target: dnsmessage.MustNewName("sip.example.com.")}),
+		dnstest.Record("ns1.example.com.", &dnsmessage.AResource{A: [4]byte{192, 0, 2, 53}}),
+		dnstest.Record("ns2.example.com.", &dnsmessage.AResource{A: [4]byte{192, 0, 2, 54}}),
+	)
+}
+
+// findingFor retourne le finding (ID, Asset) — plusieurs findings peuvent partager un ID
+func findingFor(result Result, id, asset string) (Finding, bool) {
+	for _, f := range result.Findings {
+		if f.ID == id && f.Asset == asset {
+			return f, true
+		}
+	}
+	return Finding{}, false
+}
+
+// TestDNSScanner_Scan_Records — tous les types de records via le client DNS, sans réseau
+func TestDNSScanner_Scan_Records(t *testing.T) {
+	srv := dnstest.NewServer()
+	defer srv.Close()
+	exampleZone(srv, [4]byte{192, 0, 2, 10})
+
+	scanner := DNSScanner{DNS: &dnsclient.Client{
+		Servers:     []string{"192.0.2.1:53"},
+		DialContext: dnstest.Dialer(map[string]*dnstest.Server{"192.0.2.1": srv}),
+	}}
+	opts := Options{"types": dnsRecordTypes, "authoritative": false, "compare": false, "dnssec": false}
+	result, err := scanner.Scan(context.Background(), "example.com", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records := result.Data.(DNSRecords)
+	want := map[string][]string{
+		"A":   {"192.0.2.10"},
+		"MX":  {"mx.example.com."},
+		"TXT": {"v=spf1 -all"},
+		"CAA": {`0 issue "letsencrypt.org"`},
+		"SOA": {"ns1.example.com. hostmaster.example.com. 1 7200 3600 1209600 300"},
+		"SRV": {"_sip._tcp.example.com. 10 5 5060 sip.example.com."},
+	}
+	for recordType, values := range want {
+		if got := records.get(recordType); !slices.Equal(got, values) {
+			t.Errorf("%s : got %q, want %q", recordType, got, values)
+		}
+	}
+	if len(records.NS) != 2 || len(records.AAAA) != 0 {
+		t.Errorf("got NS %q, AAAA %q", records.NS, records.AAAA)
+	}
+	if _, ok := findingFor(result, "dns.record.caa", `0 issue "letsencrypt.org"`); !ok {
+		t.Error("missing dns.record.caa finding")
+	}
+}
+
+// TestDNSScanner_Scan_Resolvers — résolveur divergent et serveur faisant autorité défaillant
+func TestDNSScanner_Scan_Resolvers(t *testing.T) {
+	honest, poisoned, lame := dnstest.NewServer(), dnstest.NewServer(), dnstest.NewServer()
+	for _, srv := range []*dnstest.Server{honest, poisoned, lame} {
+		defer srv.Close()
+	}
+	exampleZone(honest, [4]byte{192, 0, 2, 10})
+	// Même zone, mais l'apex pointe vers une autre adresse (cache empoisonné, split-horizon)
+	exampleZone(poisoned, [4]byte{203, 0, 113, 66})
+
+	scanner := DNSScanner{DNS: &dnsclient.Client{
+		Timeout: time.Second,
+		DialContext: dnstest.Dialer(map[string]*dnstest.Server{
+			"192.0.2.1":  honest,
+			"192.0.2.2":  poisoned,
+			"192.0.2.53": honest, // ns1 : sert la zone
+			"192.0.2.54": lame,   // ns2 : ne connaît pas la zone (NXDOMAIN)
+		}),
+	}}
+	opts := Options{"resolvers": []string{"192.0.2.1", "192.0.2.2"}, "dnssec": false}
+	result, err := scanner.Scan(context.Background(), "example.com", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		id, asset string
+		severity  Severity
+	}{
+		{"dns.ns.authoritative", "ns1.example.com.", SeverityInfo},
+		{"dns.ns.authoritative", "ns2.example.com.", SeverityMedium},
+		{"dns.consistency", "A example.com", SeverityLow},
+		{"dns.consistency", "MX example.com", SeverityInfo},
+	}
+	for _, w := range want {
+		if f, ok := findingFor(result, w.id, w.asset); !ok || f.Severity != w.severity {
+			t.Errorf("%s %s : got %+v, want severity %s", w.id, w.asset, f, w.severity)
+		}
+	}
+
+	records := result.Data.(DNSRecords)
+	if records.Zone != "example.com." || !slices.Equal(records.Resolvers, []string{"192.0.2.1:53", "192.0.2.2:53"}) {
+		t.Errorf("got zone %s, resolvers %q", records.Zone, records.Resolvers)
+	}
+	// Comparaison A : 2 résolveurs + ns1 (ns2, défaillant, est exclu)
+	for _, c := range records.Comparison {
+		if c.Type == "A" && len(c.Answers) != 3 {
+			t.Errorf("got %d answers for A, want 3", len(c.Answers))
+		}
+	}
+}
+
+// TestDNSScanner_Scan_InvalidResolver — option resolvers invalide → erreur avant toute requête
+func TestDNSScanner_Scan_InvalidResolver(t *testing.T) {
+	_, err := DNSScanner{}.Scan(context.Background(), "example.com", Options{"resolvers": []string{"not-an-ip"}})
+	if err == nil {
+		t.Error("expected error for invalid resolver, got nil")
+	}
+}
+
+// TestDNSScanner_Scan_AXFR — ns1 accepte le transfert de zone, ns2 le refuse
+func TestDNSScanner_Scan_AXFR(t *testing.T) {
+	open, closed := dnstest.NewServer(), dnstest.NewServer()
+	defer open.Close()
+	defer closed.Close()
+	for _, srv := range []*dnstest.Server{open, closed} {
+		exampleZone(srv, [4]byte{192, 0, 2, 10})
+		srv.Add(dnstest.Record("intranet.example.com.", &dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}}))
+	}
+	open.AllowTransfer("example.com")
+
+	scanner := DNSScanner{DNS: &dnsclient.Client{
+		Servers: []string{"192.0.2.1:53"},
+		DialContext: dnstest.Dialer(map[string]*dnstest.Server{
+			"192.0.2.1":  closed,
+			"192.0.2.53": open,   // ns1
+			"192.0.2.54": closed, // ns2
+		}),
+	}}
+	opts := Options{"types": []string{"A", "NS"}, "compare": false, "dnssec": false}
+	result, err := scanner.Scan(context.Background(), "example.com", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f, ok := findingFor(result, "dns.axfr", "ns1.example.com."); !ok || f.Severity != SeverityHigh {
+		t.Errorf("ns1 : got %+v, want high", f)
+	}
+	if f, ok := findingFor(result, "dns.axfr", "ns2.example.com."); !ok || f.Severity != SeverityInfo {
+		t.Errorf("ns2 : got %+v, want info", f)
+	}
+
+	// _sip._tcp est un nom de service, pas un hôte
+	records := result.Data.(DNSRecords)
+	want := []string{"intranet.example.com", "ns1.example.com", "ns2.example.com"}
+	if !slices.Equal(records.Hostnames, want) {
+		t.Errorf("got hostnames %q, want %q", records.Hostnames, want)
 	}
 }
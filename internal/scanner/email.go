@@ -0,0 +1,782 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// TXTResolver — résolution des records TXT (implémentée par *net.Resolver)
+// Interface pour que les tests puissent injecter des records sans réseau
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// spfMaxLookups — la RFC 7208 limite à 10 les mécanismes déclenchant une requête DNS
+// Au-delà, l'évaluation échoue (permerror) et les serveurs de réception traitent le SPF comme invalide
+const spfMaxLookups = 10
+
+// Limites de la recherche DKIM : une requête DNS par sélecteur, la liste vient de l'appelant
+const (
+	dkimMaxSelectors = 64 // Nombre maximal de sélecteurs par scan (option dkim_selectors)
+	dkimConcurrency  = 8  // Requêtes DNS simultanées
+)
+
+// dkimSelectors — sélecteurs DKIM courants (Google Workspace, Microsoft 365, Mailchimp, Zoho, Proton...)
+// Le sélecteur n'est pas découvrable par DNS : on ne peut que tester des noms connus
+var dkimSelectors = []string{
+	"default", "google", "selector1", "selector2", "k1", "k2", "k3", "mail", "dkim",
+	"s1", "s2", "smtp", "mandrill", "mxvault", "zoho", "pm", "sig1",
+	"fm1", "fm2", "fm3", "protonmail", "protonmail2", "protonmail3",
+}
+
+// EmailScanner - Scanner de l'authentification email (SPF, DMARC, DKIM, MTA-STS, TLS-RPT)
+type EmailScanner struct {
+	Resolver TXTResolver  // Résolveur DNS utilisé, ex: *dnsclient.Client (nil = net.DefaultResolver)
+	Client   *http.Client // Client HTTP de la politique MTA-STS (nil = client par défaut)
+}
+
+// EmailReport — données brutes du scanner email (nil = record absent)
+type EmailReport struct {
+	SPF    *SPFRecord    `json:"spf,omitempty"`
+	DMARC  *DMARCRecord  `json:"dmarc,omitempty"`
+	DKIM   []DKIMKey     `json:"dkim"`
+	MTASTS *MTASTSPolicy `json:"mta_sts,omitempty"`
+	TLSRPT string        `json:"tls_rpt,omitempty"` // Record TXT _smtp._tls
+}
+
+// SPFRecord — record SPF et résultat de son évaluation
+type SPFRecord struct {
+	Record   string   `json:"record"`
+	All      string   `json:"all,omitempty"` // Mécanisme final avec son qualificateur (-all, ~all...)
+	Redirect string   `json:"redirect,omitempty"`
+	Lookups  int      `json:"lookups"`  // Requêtes DNS nécessaires, includes récursifs compris
+	Includes []string `json:"includes"` // Domaines inclus, récursivement
+}
+
+// DMARCRecord — tags principaux du record _dmarc
+type DMARCRecord struct {
+	Record          string   `json:"record"`
+	Policy          string   `json:"policy"`                     // p= : none, quarantine, reject
+	SubdomainPolicy string   `json:"subdomain_policy,omitempty"` // sp= (hérite de p si absent)
+	Pct             int      `json:"pct"`                        // Pourcentage de messages soumis à la politique
+	RUA             []string `json:"rua,omitempty"`              // Destinataires des rapports agrégés
+	RUF             []string `json:"ruf,omitempty"`              // Destinataires des rapports d'échec
+}
+
+// DKIMKey — clé publique DKIM trouvée pour un sélecteur
+type DKIMKey struct {
+	Selector string `json:"selector"`
+	Type     string `json:"type"`           // k= : rsa (défaut) ou ed25519
+	Bits     int    `json:"bits,omitempty"` // Taille de la clé RSA
+	Revoked  bool   `json:"revoked"`        // p= vide : clé révoquée
+}
+
+// MTASTSPolicy — record _mta-sts et politique publiée en HTTPS
+type MTASTSPolicy struct {
+	Record string   `json:"record"`
+	Mode   string   `json:"mode,omitempty"` // enforce, testing, none
+	MX     []string `json:"mx,omitempty"`
+	MaxAge int      `json:"max_age,omitempty"` // Durée de cache de la politique (secondes)
+}
+
+// emailOptions — options typées de EmailScanner
+type emailOptions struct {
+	Selectors []string // Sélecteurs DKIM testés
+	MTASTS    bool     // Télécharger et analyser la politique MTA-STS
+}
+
+// Name retourne l'identifiant du scanner Email
+func (e EmailScanner) Name() string { return "email" }
+
+// Info retourne les métadonnées du scanner (GET /scanners)
+func (e EmailScanner) Info() Info {
+	return Info{
+		Title:       "Email",
+		Description: "Analyse l'authentification email : SPF, DMARC, DKIM, MTA-STS et TLS-RPT",
+		Version:     "1.0.1",
+	}
+}
+
+// Schema publie les options acceptées par le scanner Email
+func (e EmailScanner) Schema() Schema {
+	return Schema{
+		{
+			Name:        "dkim_selectors",
+			Type:        OptionStrings,
+			Description: fmt.Sprintf("Sélecteurs DKIM testés (<sélecteur>._domainkey.<domaine>), %d au maximum", dkimMaxSelectors),
+			Default:     dkimSelectors,
+			Validate: func(value any) error {
+				if selectors, _ := value.([]string); len(selectors) > dkimMaxSelectors {
+					return fmt.Errorf("%d sélecteurs, maximum %d", len(selectors), dkimMaxSelectors)
+				}
+				return nil
+			},
+		},
+		{
+			Name:        "mta_sts",
+			Type:        OptionBool,
+			Description: "Télécharger la politique MTA-STS (https://mta-sts.<domaine>/.well-known/mta-sts.txt)",
+			Default:     true,
+		},
+	}
+}
+
+// options convertit les Options génériques en emailOptions
+func (e EmailScanner) options(opts Options) emailOptions {
+	opts = e.Schema().Apply(opts)
+	return emailOptions{Selectors: opts.Strings("dkim_selectors"), MTASTS: opts.Bool("mta_sts")}
+}
+
+// resolver retourne le résolveur configuré, sinon celui du système
+func (e EmailScanner) resolver() TXTResolver {
+	if e.Resolver != nil {
+		return e.Resolver
+	}
+	return net.DefaultResolver
+}
+
+// Scan analyse les records d'authentification email du domaine
+// Les vérifications sont indépendantes : un record absent n'empêche pas d'analyser les autres
+// Présent ou absent, une vérification garde le même ID : seule la sévérité change
+func (e EmailScanner) Scan(ctx context.Context, domain string, opts Options) (Result, error) {
+	o := e.options(opts)
+
+	// Seule erreur fatale : le domaine lui-même ne répond pas (hors "pas de record")
+	records, err := e.lookup(ctx, domain)
+	if err != nil {
+		return Result{}, fmt.Errorf("erreur de email: %w", err)
+	}
+
+	result := newResult(ctx, nil)
+	result.Data = EmailReport{
+		SPF:    e.checkSPF(ctx, &result, domain, records),
+		DMARC:  e.checkDMARC(ctx, &result, domain),
+		DKIM:   e.checkDKIM(ctx, &result, domain, o.Selectors),
+		MTASTS: e.checkMTASTS(ctx, &result, domain, o.MTASTS),
+		TLSRPT: e.checkTLSRPT(ctx, &result, domain),
+	}
+
+	// Contexte expiré en cours de route → on retourne ce qui a été analysé avec l'erreur
+	return result, ctx.Err()
+}
+
+// lookup retourne les records TXT de name — un nom sans record TXT n'est pas une erreur
+func (e EmailScanner) lookup(ctx context.Context, name string) ([]string, error) {
+	records, err := e.resolver().LookupTXT(ctx, name)
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return nil, nil
+	}
+	return records, err
+}
+
+// lookupFailed signale une requête DNS en échec (timeout, SERVFAIL...)
+// ID distinct des vérifications : une panne passagère ne doit pas apparaître comme un record supprimé
+func lookupFailed(result *Result, name string, err error) {
+	result.add(Finding{
+		ID:       "email.dns.error",
+		Title:    "Requête DNS en échec",
+		Severity: SeverityInfo,
+		Category: "email",
+		Evidence: err.Error(),
+		Asset:    name,
+	})
+}
+
+// withPrefix retourne les records qui commencent par prefix (insensible à la casse)
+// Un même nom porte souvent plusieurs TXT (vérification Google, SPF...) : on ne garde que les bons
+func withPrefix(records []string, prefix string) []string {
+	var matched []string
+	for _, r := range records {
+		r = strings.TrimSpace(r)
+		if len(r) >= len(prefix) && strings.EqualFold(r[:len(prefix)], prefix) {
+			// "v=spf1" ne doit pas matcher "v=spf10" : le préfixe est suivi d'un espace, d'un ";" ou de rien
+			if rest := r[len(prefix):]; rest == "" || rest[0] == ' ' || rest[0] == ';' {
+				matched = append(matched, r)
+			}
+		}
+	}
+	return matched
+}
+
+// parseTags découpe un record "clé=valeur; clé=valeur" (DMARC, DKIM, MTA-STS, TLS-RPT)
+func parseTags(record string) map[string]string {
+	tags := make(map[string]string)
+	for _, part := range strings.Split(record, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		tags[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+	return tags
+}
+
+// --- SPF ---
+
+// spfWalk — état de l'évaluation récursive d'un SPF (includes et redirect)
+type spfWalk struct {
+	lookups  int
+	includes []string
+	visited  map[string]bool // Protège des boucles (a inclut b qui inclut a)
+	broken   []string        // Includes sans record SPF → permerror
+	ptr      bool            // Mécanisme ptr utilisé (déprécié par la RFC 7208)
+}
+
+// checkSPF analyse le record SPF du domaine
+func (e EmailScanner) checkSPF(ctx context.Context, result *Result, domain string, records []string) *SPFRecord {
+	finding := Finding{ID: "email.spf", Category: "email", Asset: domain}
+
+	spf := withPrefix(records, "v=spf1")
+	switch len(spf) {
+	case 0:
+		finding.Title = "SPF absent"
+		finding.Severity = SeverityMedium
+		finding.Evidence = "aucun record TXT v=spf1 sur " + domain
+		finding.Remediation = "Publier un record SPF listant les serveurs d'envoi, terminé par -all (ou \"v=spf1 -all\" si le domaine n'envoie pas d'emails)"
+		result.add(finding)
+		return nil
+	case 1:
+		finding.Title = "SPF présent"
+		finding.Severity = SeverityInfo
+		finding.Evidence = spf[0]
+		result.add(finding)
+	default:
+		// Plusieurs records SPF → permerror : le domaine est traité comme sans SPF
+		finding.Title = "Plusieurs records SPF"
+		finding.Severity = SeverityHigh
+		finding.Evidence = strings.Join(spf, " | ")
+		finding.Remediation = "Fusionner les records en un seul record v=spf1"
+		result.add(finding)
+	}
+
+	record := &SPFRecord{Record: spf[0], Includes: []string{}}
+	walk := &spfWalk{visited: map[string]bool{domain: true}}
+	record.All, record.Redirect = e.walkSPF(ctx, walk, spf[0])
+	record.Lookups = walk.lookups
+	record.Includes = append(record.Includes, walk.includes...)
+
+	e.reportSPFAll(result, domain, record)
+
+	lookups := Finding{
+		ID:       "email.spf.lookups",
+		Title:    "Requêtes DNS du SPF",
+		Severity: SeverityInfo,
+		Category: "email",
+		Evidence: fmt.Sprintf("%d requêtes DNS (limite : %d)", walk.lookups, spfMaxLookups),
+		Asset:    domain,
+	}
+	if walk.lookups > spfMaxLookups {
+		lookups.Title = "SPF dépasse la limite de requêtes DNS"
+		lookups.Severity = SeverityHigh
+		lookups.Remediation = "Réduire les include (aplatir les plages IP en ip4:/ip6:) : au-delà de 10 requêtes, le SPF est invalide (permerror)"
+	}
+	result.add(lookups)
+
+	for _, target := range walk.broken {
+		result.add(Finding{
+			ID:          "email.spf.include",
+			Title:       "Include SPF sans record SPF",
+			Severity:    SeverityMedium,
+			Category:    "email",
+			Evidence:    "include:" + target + " ne publie pas de record v=spf1",
+			Asset:       target,
+			Remediation: "Retirer ou corriger l'include : un include sans SPF rend tout le record invalide (permerror)",
+		})
+	}
+	if walk.ptr {
+		result.add(Finding{
+			ID:          "email.spf.ptr",
+			Title:       "Mécanisme SPF ptr déprécié",
+			Severity:    SeverityLow,
+			Category:    "email",
+			Evidence:    record.Record,
+			Asset:       domain,
+			Remediation: "Remplacer ptr par des mécanismes ip4:/ip6: ou a: (RFC 7208 §5.5)",
+		})
+	}
+	return record
+}
+
+// walkSPF compte les requêtes DNS d'un record SPF, includes et redirect compris
+// Retourne le mécanisme all et la cible du redirect du record de premier niveau
+func (e EmailScanner) walkSPF(ctx context.Context, walk *spfWalk, record string) (all, redirect string) {
+	for _, term := range strings.Fields(record)[1:] {
+		// Modificateurs (redirect=, exp=) : "nom=valeur"
+		if name, value, ok := strings.Cut(term, "="); ok && !strings.ContainsAny(name, ":/") {
+			if strings.EqualFold(name, "redirect") {
+				walk.lookups++
+				redirect = value
+			}
+			continue
+		}
+
+		// Mécanismes : [qualificateur]nom[:domaine][/cidr]
+		mechanism := strings.ToLower(strings.TrimLeft(term, "+-~?"))
+		name, target, _ := strings.Cut(mechanism, ":")
+		name, _, _ = strings.Cut(name, "/")
+		switch name {
+		case "all":
+			all = term
+			if !strings.ContainsAny(term[:1], "+-~?") {
+				all = "+" + term
+			}
+		case "include":
+			walk.lookups++
+			e.followSPF(ctx, walk, target)
+		case "a", "mx", "exists":
+			walk.lookups++
+		case "ptr":
+			walk.lookups++
+			walk.ptr = true
+		}
+	}
+
+	// redirect n'est appliqué que si le record n'a pas de mécanisme all
+	if redirect != "" && all == "" {
+		e.followSPF(ctx, walk, redirect)
+	}
+	return all, redirect
+}
+
+// followSPF évalue récursivement le SPF d'un include ou d'un redirect
+func (e EmailScanner) followSPF(ctx context.Context, walk *spfWalk, target string) {
+	// Macros (%{i}...) : la cible dépend de l'expéditeur, impossible à résoudre ici
+	// Au-delà de la limite, inutile de continuer : le SPF est déjà invalide
+	if target == "" || strings.Contains(target, "%") || walk.visited[target] || walk.lookups > spfMaxLookups || ctx.Err() != nil {
+		return
+	}
+	walk.visited[target] = true
+	walk.includes = append(walk.includes, target)
+
+	records, err := e.lookup(ctx, target)
+	if err != nil {
+		return
+	}
+	spf := withPrefix(records, "v=spf1")
+	if len(spf) != 1 {
+		walk.broken = append(walk.broken, target)
+		return
+	}
+	e.walkSPF(ctx, walk, spf[0])
+}
+
+// reportSPFAll évalue la politique appliquée aux serveurs non listés (mécanisme all)
+func (e EmailScanner) reportSPFAll(result *Result, domain string, spf *SPFRecord) {
+	finding := Finding{ID: "email.spf.all", Category: "email", Asset: domain, Evidence: spf.Record}
+
+	switch {
+	case spf.All == "" && spf.Redirect != "":
+		// La politique finale est celle du domaine cible du redirect
+		finding.Title = "SPF délégué par redirect"
+		finding.Severity = SeverityInfo
+	case spf.All == "":
+		finding.Title = "SPF sans mécanisme all"
+		finding.Severity = SeverityLow
+		finding.Remediation = "Terminer le record par -all (ou ~all avec DMARC en reject)"
+	case spf.All[0] == '+':
+		finding.Title = "SPF +all : tout serveur est autorisé"
+		finding.Severity = SeverityHigh
+		finding.Remediation = "Remplacer +all par -all : +all autorise n'importe qui à envoyer au nom du domaine"
+	case spf.All[0] == '?':
+		finding.Title = "SPF ?all : politique neutre"
+		finding.Severity = SeverityMedium
+		finding.Remediation = "Remplacer ?all par -all (ou ~all avec DMARC en reject)"
+	case spf.All[0] == '~':
+		finding.Title = "SPF ~all (softfail)"
+		finding.Severity = SeverityInfo
+	default:
+		finding.Title = "SPF -all (fail)"
+		finding.Severity = SeverityInfo
+	}
+	result.add(finding)
+}
+
+// --- DMARC ---
+
+// checkDMARC analyse le record _dmarc du domaine
+func (e EmailScanner) checkDMARC(ctx context.Context, result *Result, domain string) *DMARCRecord {
+	name := "_dmarc." + domain
+	records, err := e.lookup(ctx, name)
+	if err != nil {
+		lookupFailed(result, name, err)
+		return nil
+	}
+
+	finding := Finding{ID: "email.dmarc", Category: "email", Asset: domain}
+	dmarc := withPrefix(records, "v=DMARC1")
+	if len(dmarc) != 1 {
+		finding.Title = "DMARC absent"
+		finding.Severity = SeverityMedium
+		finding.Evidence = "aucun record TXT v=DMARC1 sur " + name
+		finding.Remediation = "Publier un record DMARC, par exemple \"v=DMARC1; p=quarantine; rua=mailto:dmarc@" + domain + "\""
+		if len(dmarc) > 1 {
+			finding.Title = "Plusieurs records DMARC"
+			finding.Evidence = strings.Join(dmarc, " | ")
+			finding.Remediation = "Ne garder qu'un seul record v=DMARC1 : plusieurs records invalident DMARC"
+		}
+		result.add(finding)
+		return nil
+	}
+
+	tags := parseTags(dmarc[0])
+	record := &DMARCRecord{
+		Record:          dmarc[0],
+		Policy:          strings.ToLower(tags["p"]),
+		SubdomainPolicy: strings.ToLower(tags["sp"]),
+		Pct:             100,
+		RUA:             splitURIs(tags["rua"]),
+		RUF:             splitURIs(tags["ruf"]),
+	}
+	if pct, err := strconv.Atoi(tags["pct"]); err == nil {
+		record.Pct = pct
+	}
+
+	finding.Title = "DMARC présent"
+	finding.Severity = SeverityInfo
+	finding.Evidence = dmarc[0]
+	result.add(finding)
+
+	policy := Finding{ID: "email.dmarc.policy", Category: "email", Asset: domain, Evidence: "p=" + record.Policy}
+	switch record.Policy {
+	case "reject", "quarantine":
+		policy.Title = "DMARC p=" + record.Policy
+		policy.Severity = SeverityInfo
+	case "none":
+		policy.Title = "DMARC p=none"
+		policy.Severity = SeverityMedium
+		policy.Remediation = "Passer à p=quarantine puis p=reject une fois les rapports analysés : p=none ne bloque aucun message usurpé"
+	default:
+		policy.Title = "Politique DMARC invalide"
+		policy.Severity = SeverityMedium
+		policy.Remediation = "Le tag p= (none, quarantine ou reject) est obligatoire"
+	}
+	result.add(policy)
+
+	if record.Pct < 100 && record.Policy != "none" {
+		result.add(Finding{
+			ID:          "email.dmarc.pct",
+			Title:       "DMARC appliqué partiellement",
+			Severity:    SeverityLow,
+			Category:    "email",
+			Evidence:    fmt.Sprintf("pct=%d", record.Pct),
+			Asset:       domain,
+			Remediation: "Retirer pct= (100 par défaut) une fois le déploiement validé",
+		})
+	}
+	if record.SubdomainPolicy == "none" && record.Policy != "none" {
+		result.add(Finding{
+			ID:          "email.dmarc.subdomain_policy",
+			Title:       "DMARC sp=none : sous-domaines non protégés",
+			Severity:    SeverityLow,
+			Category:    "email",
+			Evidence:    "sp=none",
+			Asset:       domain,
+			Remediation: "Retirer sp=none pour que les sous-domaines héritent de p=" + record.Policy,
+		})
+	}
+
+	reporting := Finding{
+		ID:       "email.dmarc.reporting",
+		Title:    "Rapports DMARC configurés",
+		Severity: SeverityInfo,
+		Category: "email",
+		Evidence: "rua=" + tags["rua"],
+		Asset:    domain,
+	}
+	if len(record.RUA) == 0 {
+		reporting.Title = "Rapports DMARC non configurés"
+		reporting.Severity = SeverityLow
+		reporting.Evidence = "pas de tag rua="
+		reporting.Remediation = "Ajouter rua=mailto:... pour recevoir les rapports agrégés et détecter les usurpations"
+	}
+	result.add(reporting)
+
+	return record
+}
+
+// splitURIs découpe une liste d'URI DMARC ("mailto:a@x,mailto:b@y")
+func splitURIs(raw string) []string {
+	var uris []string
+	for _, uri := range strings.Split(raw, ",") {
+		if uri = strings.TrimSpace(uri); uri != "" {
+			uris = append(uris, uri)
+		}
+	}
+	return uris
+}
+
+// --- DKIM ---
+
+// checkDKIM cherche une clé publique DKIM pour chaque sélecteur
+// Les sélecteurs sont interrogés en parallèle : la plupart n'existent pas et chaque réponse négative coûte un aller-retour
+// Au plus dkimConcurrency requêtes à la fois, pour ne pas inonder le résolveur
+func (e EmailScanner) checkDKIM(ctx context.Context, result *Result, domain string, selectors []string) []DKIMKey {
+	type answer struct {
+		records []string
+		err     error
+	}
+	answers := make([]answer, len(selectors))
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, dkimConcurrency) // Sémaphore : un jeton par requête en cours
+	for i, selector := range selectors {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			records, err := e.lookup(ctx, selector+"._domainkey."+domain)
+			answers[i] = answer{records, err}
+		}()
+	}
+	wg.Wait()
+
+	keys := []DKIMKey{}
+	for i, selector := range selectors {
+		name := selector + "._domainkey." + domain
+		if answers[i].err != nil {
+			lookupFailed(result, name, answers[i].err)
+			continue
+		}
+		for _, record := range answers[i].records {
+			tags := parseTags(record)
+			p, ok := tags["p"]
+			if !ok {
+				continue
+			}
+			key := dkimKey(selector, tags["k"], p)
+			keys = append(keys, key)
+			result.add(dkimFinding(name, key, record))
+		}
+	}
+
+	if len(keys) == 0 {
+		result.add(Finding{
+			ID:          "email.dkim.none",
+			Title:       "Aucune clé DKIM trouvée",
+			Severity:    SeverityLow,
+			Category:    "email",
+			Evidence:    fmt.Sprintf("%d sélecteurs courants testés", len(selectors)),
+			Asset:       domain,
+			Remediation: "Signer les emails sortants avec DKIM ; si un sélecteur spécifique est utilisé, le passer dans l'option dkim_selectors",
+		})
+	}
+	return keys
+}
+
+// dkimKey décode la clé publique d'un record DKIM (p= en base64)
+func dkimKey(selector, keyType, p string) DKIMKey {
+	key := DKIMKey{Selector: selector, Type: strings.ToLower(keyType)}
+	if key.Type == "" {
+		key.Type = "rsa"
+	}
+	// Les longues clés sont découpées en plusieurs chaînes TXT, parfois avec des espaces
+	p = strings.Join(strings.Fields(p), "")
+	if p == "" {
+		key.Revoked = true
+		return key
+	}
+
+	der, err := base64.StdEncoding.DecodeString(p)
+	if err != nil {
+		return key
+	}
+	// DKIM publie normalement une SubjectPublicKeyInfo, certains outils une clé PKCS#1 brute
+	if pub, err := x509.ParsePKIXPublicKey(der); err == nil {
+		switch k := pub.(type) {
+		case *rsa.PublicKey:
+			key.Bits = k.N.BitLen()
+		case ed25519.PublicKey:
+			key.Type = "ed25519"
+		}
+	} else if k, err := x509.ParsePKCS1PublicKey(der); err == nil {
+		key.Bits = k.N.BitLen()
+	}
+	return key
+}
+
+// dkimFinding évalue une clé DKIM : révoquée, trop courte ou correcte
+func dkimFinding(name string, key DKIMKey, record string) Finding {
+	finding := Finding{
+		ID:       "email.dkim",
+		Title:    "Clé DKIM " + key.Selector,
+		Severity: SeverityInfo,
+		Category: "email",
+		Evidence: record,
+		Asset:    name,
+	}
+	switch {
+	case key.Revoked:
+		finding.Title = "Clé DKIM " + key.Selector + " révoquée"
+	case key.Type == "rsa" && key.Bits > 0 && key.Bits < 1024:
+		finding.Title = fmt.Sprintf("Clé DKIM RSA de %d bits", key.Bits)
+		finding.Severity = SeverityHigh
+		finding.Remediation = "Générer une nouvelle clé RSA de 2048 bits : une clé de moins de 1024 bits est factorisable"
+	case key.Type == "rsa" && key.Bits > 0 && key.Bits < 2048:
+		finding.Title = fmt.Sprintf("Clé DKIM RSA de %d bits", key.Bits)
+		finding.Severity = SeverityLow
+		finding.Remediation = "Passer à une clé RSA de 2048 bits (RFC 8301)"
+	}
+	return finding
+}
+
+// --- MTA-STS et TLS-RPT ---
+
+// checkMTASTS analyse le record _mta-sts et, si fetch, la politique publiée en HTTPS
+// MTA-STS impose TLS aux serveurs qui envoient des emails au domaine (RFC 8461)
+func (e EmailScanner) checkMTASTS(ctx context.Context, result *Result, domain string, fetch bool) *MTASTSPolicy {
+	name := "_mta-sts." + domain
+	records, err := e.lookup(ctx, name)
+	if err != nil {
+		lookupFailed(result, name, err)
+		return nil
+	}
+
+	finding := Finding{ID: "email.mta_sts", Category: "email", Asset: domain}
+	sts := withPrefix(records, "v=STSv1")
+	if len(sts) != 1 || parseTags(sts[0])["id"] == "" {
+		finding.Title = "MTA-STS absent"
+		finding.Severity = SeverityLow
+		finding.Evidence = "aucun record TXT v=STSv1 valide sur " + name
+		finding.Remediation = "Publier _mta-sts (v=STSv1; id=...) et la politique sur https://mta-sts." + domain + "/.well-known/mta-sts.txt"
+		result.add(finding)
+		return nil
+	}
+	finding.Title = "MTA-STS présent"
+	finding.Severity = SeverityInfo
+	finding.Evidence = sts[0]
+	result.add(finding)
+
+	policy := &MTASTSPolicy{Record: sts[0]}
+	if !fetch {
+		return policy
+	}
+
+	url := "https://mta-sts." + domain + "/.well-known/mta-sts.txt"
+	pf := Finding{ID: "email.mta_sts.policy", Category: "email", Asset: url}
+	if err := e.fetchPolicy(ctx, url, policy); err != nil {
+		pf.Title = "Politique MTA-STS inaccessible"
+		pf.Severity = SeverityMedium
+		pf.Evidence = err.Error()
+		pf.Remediation = "Servir la politique en HTTPS (certificat valide, status 200, sans redirection)"
+		result.add(pf)
+		return policy
+	}
+
+	pf.Evidence = fmt.Sprintf("mode: %s, max_age: %d, mx: %s", policy.Mode, policy.MaxAge, strings.Join(policy.MX, ", "))
+	switch policy.Mode {
+	case "enforce":
+		pf.Title = "MTA-STS en mode enforce"
+		pf.Severity = SeverityInfo
+	case "testing":
+		pf.Title = "MTA-STS en mode testing"
+		pf.Severity = SeverityLow
+		pf.Remediation = "Passer en mode enforce une fois les rapports TLS-RPT vérifiés"
+	case "none":
+		pf.Title = "MTA-STS désactivé (mode none)"
+		pf.Severity = SeverityLow
+		pf.Remediation = "Passer en mode enforce"
+	default:
+		pf.Title = "Politique MTA-STS invalide"
+		pf.Severity = SeverityMedium
+		pf.Remediation = "La politique doit contenir version: STSv1, mode (enforce, testing ou none), mx et max_age"
+	}
+	result.add(pf)
+
+	// Politique trop courte : un attaquant en position d'interception peut attendre son expiration
+	if policy.Mode == "enforce" && policy.MaxAge < 86400 {
+		result.add(Finding{
+			ID:          "email.mta_sts.max_age",
+			Title:       "Durée de cache MTA-STS courte",
+			Severity:    SeverityLow,
+			Category:    "email",
+			Evidence:    fmt.Sprintf("max_age: %d", policy.MaxAge),
+			Asset:       url,
+			Remediation: "Utiliser un max_age d'au moins une semaine (604800) en production",
+		})
+	}
+	return policy
+}
+
+// fetchPolicy télécharge et parse la politique MTA-STS ("clé: valeur", une par ligne)
+func (e EmailScanner) fetchPolicy(ctx context.Context, url string, policy *MTASTSPolicy) error {
+	// La RFC 8461 interdit de suivre les redirections : copie du client sans redirection
+	client := *clientOrDefault(e.Client)
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	resp, err := get(ctx, &client, url)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("réponse HTTP %d", resp.StatusCode)
+	}
+
+	// Politique limitée à 64 Ko (RFC 8461 §3.3)
+	fields := bufio.NewScanner(io.LimitReader(resp.Body, 64<<10))
+	version := ""
+	for fields.Scan() {
+		key, value, ok := strings.Cut(fields.Text(), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "version":
+			version = value
+		case "mode":
+			policy.Mode = value
+		case "mx":
+			policy.MX = append(policy.MX, value)
+		case "max_age":
+			policy.MaxAge, _ = strconv.Atoi(value)
+		}
+	}
+	if err := fields.Err(); err != nil {
+		return err
+	}
+	if version != "STSv1" {
+		policy.Mode = ""
+	}
+	return nil
+}
+
+// checkTLSRPT analyse le record _smtp._tls (rapports d'échec TLS, RFC 8460)
+func (e EmailScanner) checkTLSRPT(ctx context.Context, result *Result, domain string) string {
+	name := "_smtp._tls." + domain
+	records, err := e.lookup(ctx, name)
+	if err != nil {
+		lookupFailed(result, name, err)
+		return ""
+	}
+
+	finding := Finding{ID: "email.tls_rpt", Category: "email", Asset: domain}
+	rpt := withPrefix(records, "v=TLSRPTv1")
+	if len(rpt) != 1 || parseTags(rpt[0])["rua"] == "" {
+		finding.Title = "TLS-RPT absent"
+		finding.Severity = SeverityLow
+		finding.Evidence = "aucun record TXT v=TLSRPTv1 avec rua= sur " + name
+		finding.Remediation = "Publier \"v=TLSRPTv1; rua=mailto:tls-rpt@" + domain + "\" pour être alerté des échecs TLS entrants"
+		result.add(finding)
+		return ""
+	}
+	finding.Title = "TLS-RPT présent"
+	finding.Severity = SeverityInfo
+	finding.Evidence = rpt[0]
+	result.add(finding)
+	return rpt[0]
+}
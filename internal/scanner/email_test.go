@@ -0,0 +1,220 @@
+package scanner
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeTXT — résolveur TXT en mémoire : un nom absent répond "pas de record" comme un vrai DNS
+type fakeTXT map[string][]string
+
+func (f fakeTXT) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	records, ok := f[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return records, nil
+}
+
+// staticPolicy — client HTTP qui sert la même politique MTA-STS pour toute URL, sans réseau
+func staticPolicy(body string) *http.Client {
+	return &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Request: r}, nil
+	})}
+}
+
+// roundTripFunc — adaptateur fonction → http.RoundTripper (comme http.HandlerFunc)
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+// findingsByID indexe les findings par ID (le dernier l'emporte en cas de doublon)
+func findingsByID(result Result) map[string]Finding {
+	byID := make(map[string]Finding)
+	for _, f := range result.Findings {
+		byID[f.ID] = f
+	}
+	return byID
+}
+
+// dkimRecord génère un record DKIM avec une clé RSA de bits bits
+func dkimRecord(t *testing.T, bits int) string {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "v=DKIM1; k=rsa; p=" + base64.StdEncoding.EncodeToString(der)
+}
+
+// TestEmailScanner_Name vérifie que le scanner retourne le bon identifiant
+func TestEmailScanner_Name(t *testing.T) {
+	if got := (EmailScanner{}).Name(); got != "email" {
+		t.Errorf("got %s, want email", got)
+	}
+}
+
+// TestEmailScanner_Scan_Secure — configuration complète : tous les findings sont informatifs
+func TestEmailScanner_Scan_Secure(t *testing.T) {
+	scanner := EmailScanner{
+		Resolver: fakeTXT{
+			"example.com":                   {"google-site-verification=abc", "v=spf1 include:_spf.example.net -all"},
+			"_spf.example.net":              {"v=spf1 ip4:192.0.2.0/24 ~all"},
+			"_dmarc.example.com":            {"v=DMARC1; p=reject; rua=mailto:dmarc@example.com"},
+			"google._domainkey.example.com": {dkimRecord(t, 2048)},
+			"_mta-sts.example.com":          {"v=STSv1; id=20250101"},
+			"_smtp._tls.example.com":        {"v=TLSRPTv1; rua=mailto:tls@example.com"},
+		},
+		Client: staticPolicy("version: STSv1\nmode: enforce\nmx: mx.example.com\nmax_age: 604800\n"),
+	}
+
+	result, err := scanner.Scan(context.Background(), "example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range result.Findings {
+		if f.Severity != SeverityInfo {
+			t.Errorf("%s : got severity %s, want info (%s)", f.ID, f.Severity, f.Title)
+		}
+	}
+
+	report := result.Data.(EmailReport)
+	if report.SPF == nil || report.SPF.Lookups != 1 || report.SPF.All != "-all" {
+		t.Errorf("got SPF %+v", report.SPF)
+	}
+	if len(report.DKIM) != 1 || report.DKIM[0].Selector != "google" || report.DKIM[0].Bits != 2048 {
+		t.Errorf("got DKIM %+v", report.DKIM)
+	}
+	if report.MTASTS == nil || report.MTASTS.Mode != "enforce" || report.MTASTS.MaxAge != 604800 {
+		t.Errorf("got MTA-STS %+v", report.MTASTS)
+	}
+}
+
+// TestEmailScanner_Scan_Weak — chaque faiblesse produit le finding et la sévérité attendus
+func TestEmailScanner_Scan_Weak(t *testing.T) {
+	records := fakeTXT{
+		"example.com":                      {"v=spf1 ptr include:broken.example.net ?all"},
+		"broken.example.net":               {"not an spf record"},
+		"_dmarc.example.com":               {"v=DMARC1; p=none"},
+		"selector1._domainkey.example.com": {dkimRecord(t, 1024)},
+	}
+
+	result, err := EmailScanner{Resolver: records}.Scan(context.Background(), "example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byID := findingsByID(result)
+	want := map[string]Severity{
+		"email.spf":             SeverityInfo,
+		"email.spf.all":         SeverityMedium,
+		"email.spf.ptr":         SeverityLow,
+		"email.spf.include":     SeverityMedium,
+		"email.dmarc.policy":    SeverityMedium,
+		"email.dmarc.reporting": SeverityLow,
+		"email.dkim":            SeverityLow,
+		"email.mta_sts":         SeverityLow,
+		"email.tls_rpt":         SeverityLow,
+	}
+	for id, severity := range want {
+		if f, ok := byID[id]; !ok || f.Severity != severity {
+			t.Errorf("%s : got %+v, want severity %s", id, f, severity)
+		}
+	}
+}
+
+// TestEmailScanner_Scan_Missing — aucun record : SPF, DMARC et DKIM signalés absents
+func TestEmailScanner_Scan_Missing(t *testing.T) {
+	result, err := EmailScanner{Resolver: fakeTXT{}}.Scan(context.Background(), "example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byID := findingsByID(result)
+	for _, id := range []string{"email.spf", "email.dmarc", "email.dkim.none"} {
+		if _, ok := byID[id]; !ok {
+			t.Errorf("missing finding %s", id)
+		}
+	}
+	if byID["email.spf"].Severity != SeverityMedium {
+		t.Errorf("got SPF severity %s, want medium", byID["email.spf"].Severity)
+	}
+}
+
+// TestEmailScanner_SPFLookups — includes récursifs au-delà de 10 requêtes DNS → permerror
+func TestEmailScanner_SPFLookups(t *testing.T) {
+	records := fakeTXT{"example.com": {"v=spf1 include:a0.example.net include:loop.example.net -all"}}
+	// Chaîne de 12 includes : a0 → a1 → ... → a11
+	for i := range 12 {
+		records[fmt.Sprintf("a%d.example.net", i)] = []string{fmt.Sprintf("v=spf1 include:a%d.example.net mx -all", i+1)}
+	}
+	// Boucle : ne doit pas faire tourner le scanner indéfiniment
+	records["loop.example.net"] = []string{"v=spf1 include:loop.example.net -all"}
+
+	result, err := EmailScanner{Resolver: records}.Scan(context.Background(), "example.com", Options{"dkim_selectors": []string{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := findingsByID(result)["email.spf.lookups"]; f.Severity != SeverityHigh {
+		t.Errorf("got %+v, want high severity", f)
+	}
+	if spf := result.Data.(EmailReport).SPF; spf.Lookups <= spfMaxLookups {
+		t.Errorf("got %d lookups, want > %d", spf.Lookups, spfMaxLookups)
+	}
+}
+
+// countingTXT — résolveur qui relève le nombre maximal de requêtes simultanées
+type countingTXT struct {
+	mu            sync.Mutex
+	running, peak int
+}
+
+func (c *countingTXT) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	c.mu.Lock()
+	c.running++
+	c.peak = max(c.peak, c.running)
+	c.mu.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+
+	c.mu.Lock()
+	c.running--
+	c.mu.Unlock()
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+// TestEmailScanner_DKIMLimits — liste de sélecteurs bornée par le schéma, requêtes DNS bornées pendant le scan
+func TestEmailScanner_DKIMLimits(t *testing.T) {
+	selectors := make([]string, dkimMaxSelectors+1)
+	for i := range selectors {
+		selectors[i] = fmt.Sprintf("s%d", i)
+	}
+	schema := EmailScanner{}.Schema()
+	if _, err := schema.ParseQuery(url.Values{"dkim_selectors": selectors}); err == nil {
+		t.Errorf("%d selectors : expected error", len(selectors))
+	}
+
+	resolver := &countingTXT{}
+	opts := Options{"dkim_selectors": selectors[:dkimMaxSelectors], "mta_sts": false}
+	if _, err := (EmailScanner{Resolver: resolver}).Scan(context.Background(), "example.com", opts); err != nil {
+		t.Fatal(err)
+	}
+	if resolver.peak > dkimConcurrency {
+		t.Errorf("got %d concurrent lookups, want at most %d", resolver.peak, dkimConcurrency)
+	}
+}
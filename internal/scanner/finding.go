@@ -0,0 +1,82 @@
+package scanner
+
+import "context"
+
+// Severity — niveau de gravité d'un finding, du plus faible au plus grave
+// Type string (et pas int) pour que le JSON reste lisible côté client : "high" plutôt que 3
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"     // Information neutre (record DNS, émetteur du certificat...)
+	SeverityLow      Severity = "low"      // Bonne pratique manquante sans impact direct
+	SeverityMedium   Severity = "medium"   // Faiblesse exploitable dans certaines conditions
+	SeverityHigh     Severity = "high"     // Exposition réelle à corriger rapidement
+	SeverityCritical Severity = "critical" // Fuite de secrets ou compromission probable
+)
+
+// Rank retourne un entier comparable pour trier les findings par gravité
+// Une sévérité inconnue vaut 0 (en dessous de "info")
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityInfo:
+		return 1
+	case SeverityLow:
+		return 2
+	case SeverityMedium:
+		return 3
+	case SeverityHigh:
+		return 4
+	case SeverityCritical:
+		return 5
+	}
+	return 0
+}
+
+// Finding — un constat unitaire remonté par un scanner
+// ID identifie la vérification (ex: "header.hsts.missing"), Asset l'élément concerné
+// Le couple (ID, Asset) est stable d'un scan à l'autre → permet de filtrer et comparer
+type Finding struct {
+	ID          string   `json:"id"`                    // Identifiant de la vérification (ex: "dns.record.mx")
+	Title       string   `json:"title"`                 // Libellé court lisible par un humain
+	Severity    Severity `json:"severity"`              // info, low, medium, high, critical
+	Category    string   `json:"category"`              // Famille du constat (dns, tls, headers, exposure...)
+	Evidence    string   `json:"evidence"`              // Preuve brute (valeur du header, record, status HTTP...)
+	Asset       string   `json:"asset"`                 // Élément concerné (domaine, IP, URL, sous-domaine...)
+	Remediation string   `json:"remediation,omitempty"` // Action corrective suggérée (vide pour les findings info)
+}
+
+// Result — résultat structuré retourné par Scanner.Scan
+// Findings est la partie exploitable (filtre, tri, agrégation), Data les données brutes du scanner
+type Result struct {
+	Findings []Finding `json:"findings"`
+	Data     any       `json:"data,omitempty"`    // Struct propre à chaque scanner (DNSRecords, CertificateInfo...)
+	Partial  bool      `json:"partial,omitempty"` // true si le scan a été interrompu (timeout, annulation)
+
+	report FindingFunc // Reçoit chaque finding dès son ajout (voir WithFindingFunc) — nil hors job
+}
+
+// newResult prépare le résultat d'un scan : les findings ajoutés seront aussi remontés au FindingFunc de ctx
+func newResult(ctx context.Context, data any) Result {
+	report, _ := ctx.Value(findingFuncKey{}).(FindingFunc)
+	return Result{Data: data, report: report}
+}
+
+// add ajoute un finding au résultat — raccourci pour éviter append(r.Findings, ...) partout
+// Le finding est aussi remonté tout de suite à l'appelant du scan, sans attendre la fin du scanner
+func (r *Result) add(f Finding) {
+	r.Findings = append(r.Findings, f)
+	if r.report != nil {
+		r.report(f)
+	}
+}
+
+// MaxSeverity retourne la sévérité la plus haute parmi les findings ("" si aucun finding)
+func (r Result) MaxSeverity() Severity {
+	var highest Severity
+	for _, f := range r.Findings {
+		if f.Severity.Rank() > highest.Rank() {
+			highest = f.Severity
+		}
+	}
+	return highest
+}
@@ -0,0 +1,39 @@
+package scanner
+
+import "testing"
+
+// TestSeverity_Rank vérifie l'ordre des sévérités (info < low < ... < critical)
+func TestSeverity_Rank(t *testing.T) {
+	order := []Severity{SeverityInfo, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+
+	// Chaque sévérité doit être strictement supérieure à la précédente
+	for i := 1; i < len(order); i++ {
+		if order[i].Rank() <= order[i-1].Rank() {
+			t.Errorf("%s (rank %d) should be above %s (rank %d)",
+				order[i], order[i].Rank(), order[i-1], order[i-1].Rank())
+		}
+	}
+
+	// Une sévérité inconnue est classée sous "info"
+	if Severity("unknown").Rank() != 0 {
+		t.Errorf("got rank %d for unknown severity, want 0", Severity("unknown").Rank())
+	}
+}
+
+// TestResult_MaxSeverity vérifie que la sévérité la plus haute est retournée
+func TestResult_MaxSeverity(t *testing.T) {
+	var result Result
+
+	// Sans finding → sévérité vide
+	if got := result.MaxSeverity(); got != "" {
+		t.Errorf("got %s, want empty severity", got)
+	}
+
+	result.add(Finding{ID: "a", Severity: SeverityLow})
+	result.add(Finding{ID: "b", Severity: SeverityHigh})
+	result.add(Finding{ID: "c", Severity: SeverityInfo})
+
+	if got := result.MaxSeverity(); got != SeverityHigh {
+		t.Errorf("got %s, want high", got)
+	}
+}
@@ -1,37 +1,192 @@
 package scanner
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"slices"
+	"strings"
+	"time"
 )
 
+// headerChecks — vérifications appliquées à chaque réponse, dans l'ordre du rapport
+// Cache-Control n'en fait pas partie : il n'est jugé que sur les pages sensibles (voir sensitivePage)
+var headerChecks = []func(http.Header) HeaderVerdict{
+	checkHSTS, checkCSP, checkXFO, checkXCTO, checkReferrer, checkPermissions,
+	checkCOOP, checkCOEP, checkCORP, checkServer, checkPoweredBy,
+}
+
 // HeaderScanner - Scanner pour les headers HTTP de sécurité
-type HeaderScanner struct{}
+type HeaderScanner struct {
+	Client *http.Client // Client HTTP utilisé (nil = client par défaut)
+}
+
+// headerOptions — options typées de HeaderScanner
+type headerOptions struct {
+	Path        string   // Chemin de la page analysée (ex: "/login")
+	CookiePaths []string // Pages supplémentaires dont seuls les cookies sont audités
+}
 
 // Name retourne l'identifiant du scanner Headers
 func (h HeaderScanner) Name() string { return "header" }
 
-// Scan effectue une requête HTTP et récupère les headers de sécurité
-// Vérifie HSTS, CSP et X-Frame-Options (protection contre le clickjacking)
-func (h HeaderScanner) Scan(domain string) (string, error) {
-	// http.Get effectue une requête GET - on ajoute https:// car domain = "daviani.dev"
-	resp, err := http.Get("https://" + domain)
+// Info retourne les métadonnées du scanner (GET /scanners)
+func (h HeaderScanner) Info() Info {
+	return Info{
+		Title:       "Headers HTTP",
+		Description: "Évalue les headers de sécurité (HSTS et preload, CSP analysée directive par directive, X-Frame-Options, X-Content-Type-Options, Referrer-Policy, Permissions-Policy, COOP/COEP/CORP, Cache-Control des pages sensibles, fuites Server/X-Powered-By) et note la réponse de A à F ; audite les cookies (Secure, HttpOnly, SameSite, préfixes, Domain, durée)",
+		Version:     "2.2.0",
+	}
+}
+
+// Schema publie les options acceptées par le scanner Headers
+func (h HeaderScanner) Schema() Schema {
+	return Schema{
+		{
+			Name:        "path",
+			Type:        OptionString,
+			Description: "Chemin de la page dont on analyse les headers",
+			Default:     "/",
+		},
+		{
+			Name:        "cookie_paths",
+			Type:        OptionStrings,
+			Description: "Pages supplémentaires dont on audite les cookies (ex: /login, /account)",
+		},
+	}
+}
+
+// options convertit les Options génériques en headerOptions
+// Les chemins sont toujours préfixés par "/" pour ne pas modifier l'hôte de l'URL
+func (h HeaderScanner) options(opts Options) headerOptions {
+	opts = h.Schema().Apply(opts)
+	o := headerOptions{Path: absolutePath(opts.String("path"))}
+	for _, path := range opts.Strings("cookie_paths") {
+		if path = absolutePath(strings.TrimSpace(path)); path != o.Path && !slices.Contains(o.CookiePaths, path) {
+			o.CookiePaths = append(o.CookiePaths, path)
+		}
+	}
+	return o
+}
+
+// absolutePath préfixe path par "/" s'il ne l'est pas déjà
+func absolutePath(path string) string {
+	if !strings.HasPrefix(path, "/") {
+		return "/" + path
+	}
+	return path
+}
+
+// Scan effectue une requête HTTP et évalue chaque header de sécurité de la réponse
+// Chaque header reçoit un verdict (ok, weak, missing, exposed), la réponse une note sur 100 et de A à F
+// Les cookies de la page et des chemins cookie_paths sont audités attribut par attribut
+// Correct ou non, un header garde le même ID : seule la sévérité change
+func (h HeaderScanner) Scan(ctx context.Context, domain string, opts Options) (Result, error) {
+	o := h.options(opts)
+
+	// get effectue une requête GET annulable - on ajoute https:// car domain = "daviani.dev"
+	url := "https://" + domain + o.Path
+	resp, err := get(ctx, h.Client, url)
 
 	if err != nil {
-		return "", fmt.Errorf("erreur de header: %w", err)
+		return Result{}, fmt.Errorf("erreur de header: %w", err)
 	}
 
 	// defer ferme le body à la fin de la fonction (libère les ressources)
 	defer func() { _ = resp.Body.Close() }()
 
 	// resp.Header est une map[string][]string contenant tous les headers HTTP
-	headers := resp.Header
-
-	// headers.Get("key") retourne la valeur du header ou "" si absent
-	// HSTS : force HTTPS | CSP : politique de sécurité | X-Frame-Options : anti-clickjacking
-	return fmt.Sprintf("HSTS: %s | CSP: %s | X-Frame-Options: %s",
-		headers.Get("Strict-Transport-Security"),
-		headers.Get("Content-Security-Policy"),
-		headers.Get("X-Frame-Options"),
-	), nil
+	report := &HeaderReport{URL: url, Status: resp.StatusCode}
+	for _, check := range headerChecks {
+		report.Headers = append(report.Headers, check(resp.Header))
+	}
+	if reason := sensitivePage(o.Path, resp); reason != "" {
+		report.Headers = append(report.Headers, checkCache(resp.Header, reason))
+	}
+	report.Score, report.Grade = scoreHeaders(report.Headers)
+
+	result := newResult(ctx, report)
+	for _, v := range report.Headers {
+		evidence := v.Detail
+		if v.Value != "" && v.Value != v.Detail {
+			evidence = v.Header + ": " + v.Value + " | " + v.Detail
+		}
+		f := Finding{
+			ID:       "header." + v.id,
+			Title:    v.title,
+			Severity: v.Severity,
+			Category: "headers",
+			Evidence: evidence,
+			Asset:    url,
+		}
+		if v.Verdict != verdictOK {
+			f.Remediation = v.remediation
+		}
+		result.add(f)
+		for _, extra := range v.extra {
+			extra.Asset = url
+			result.add(extra)
+		}
+	}
+
+	// Note globale — une note basse vient de headers absents, jamais d'une faille directe : medium au plus
+	severity := SeverityMedium
+	switch report.Grade {
+	case "A":
+		severity = SeverityInfo
+	case "B":
+		severity = SeverityLow
+	}
+	result.add(Finding{
+		ID:       "header.grade",
+		Title:    "Note des headers de sécurité : " + report.Grade,
+		Severity: severity,
+		Category: "headers",
+		Evidence: fmt.Sprintf("Note %s (score %d/100)", report.Grade, report.Score),
+		Asset:    url,
+	})
+
+	// Cookies de la page, puis des pages supplémentaires — hors note : un cookie dépend de la page qui le dépose
+	// Un même nom n'est audité qu'une fois, sur la première page qui le dépose
+	now := time.Now()
+	seen := make(map[string]bool)
+	auditCookies := func(url string, resp *http.Response) {
+		for _, c := range resp.Cookies() {
+			a := auditCookie(url, c, now)
+			if a == nil || seen[a.Name] {
+				continue
+			}
+			seen[a.Name] = true
+			for _, check := range checkCookie(a, now) {
+				result.add(Finding{
+					ID:          "header.cookie." + check.id,
+					Title:       check.title,
+					Severity:    check.severity,
+					Category:    "headers",
+					Evidence:    a.Name + " : " + check.evidence,
+					Asset:       url + " [cookie " + a.Name + "]",
+					Remediation: check.remediation,
+				})
+			}
+			report.Cookies = append(report.Cookies, *a)
+		}
+	}
+	auditCookies(url, resp)
+
+	// Une page supplémentaire injoignable n'arrête pas le scan : elle est notée dans le rapport
+	for _, path := range o.CookiePaths {
+		pageURL := "https://" + domain + path
+		page, err := get(ctx, h.Client, pageURL)
+		if err != nil {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			report.Errors = append(report.Errors, fmt.Sprintf("%s : %v", path, err))
+			continue
+		}
+		auditCookies(pageURL, page)
+		_ = page.Body.Close()
+	}
+
+	return result, nil
 }
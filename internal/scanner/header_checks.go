@@ -0,0 +1,573 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/daviani/go__001/internal/csp"
+)
+
+// HeaderReport — verdict de chaque header de sécurité de la page et note globale de la réponse
+type HeaderReport struct {
+	URL     string          `json:"url"`
+	Status  int             `json:"status"`
+	Headers []HeaderVerdict `json:"headers"`
+	Score   int             `json:"score"` // Sur 100, pénalités de fuite d'information déduites
+	Grade   string          `json:"grade"` // A à F
+	Cookies []CookieAudit   `json:"cookies,omitempty"`
+	Errors  []string        `json:"errors,omitempty"` // Pages de cookie_paths injoignables
+}
+
+// HeaderVerdict — évaluation d'un header de la réponse
+type HeaderVerdict struct {
+	Header   string   `json:"header"`
+	Value    string   `json:"value,omitempty"`
+	Verdict  string   `json:"verdict"` // ok, weak, missing, exposed
+	Severity Severity `json:"severity"`
+	Detail   string   `json:"detail"`
+	Issues   []string `json:"issues,omitempty"` // Faiblesses détaillées (directives CSP)
+
+	extra       []Finding // Findings rattachés au header (une vérification CSP chacun), Asset complété par Scan
+	id          string    // Suffixe de l'ID du finding (header.<id>)
+	title       string    // Titre du finding
+	remediation string    // Conseil quand le verdict n'est pas ok
+	weight      int       // Points dans la note (0 = informatif)
+	penalty     int       // Points retirés quand le verdict est exposed
+}
+
+// Verdicts d'un header — weak compte pour moitié dans la note
+const (
+	verdictOK      = "ok"
+	verdictWeak    = "weak"
+	verdictMissing = "missing"
+	verdictExposed = "exposed" // Le header divulgue une information (version, framework)
+)
+
+// Durées HSTS : 180 jours minimum recommandé, un an exigé par la preload list (hstspreload.org)
+const (
+	hstsMinMaxAge     = 180 * 24 * 3600
+	hstsPreloadMaxAge = 365 * 24 * 3600
+)
+
+// hstsPolicy — directives de Strict-Transport-Security (RFC 6797 §6.1)
+type hstsPolicy struct {
+	MaxAge            int64
+	IncludeSubDomains bool
+	Preload           bool
+}
+
+// parseHSTS lit la valeur d'un header Strict-Transport-Security
+// Directives insensibles à la casse, valeur de max-age éventuellement entre guillemets ;
+// une directive répétée ou un max-age absent rendent le header invalide (le navigateur l'ignore)
+func parseHSTS(value string) (hstsPolicy, error) {
+	var p hstsPolicy
+	seen := make(map[string]bool)
+	for _, directive := range strings.Split(value, ";") {
+		name, val, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if seen[name] {
+			return p, fmt.Errorf("directive %s répétée", name)
+		}
+		seen[name] = true
+		switch name {
+		case "max-age":
+			n, err := strconv.ParseInt(strings.Trim(strings.TrimSpace(val), `"`), 10, 64)
+			if err != nil || n < 0 {
+				return p, fmt.Errorf("max-age invalide : %q", val)
+			}
+			p.MaxAge = n
+		case "includesubdomains":
+			p.IncludeSubDomains = true
+		case "preload":
+			p.Preload = true
+		}
+	}
+	if !seen["max-age"] {
+		return p, errors.New("directive max-age absente")
+	}
+	return p, nil
+}
+
+// preloadIssues liste ce qui manque pour l'inscription sur la preload list — vide = éligible
+func (p hstsPolicy) preloadIssues() []string {
+	var issues []string
+	if p.MaxAge < hstsPreloadMaxAge {
+		issues = append(issues, "max-age inférieur à un an")
+	}
+	if !p.IncludeSubDomains {
+		issues = append(issues, "includeSubDomains absent")
+	}
+	if !p.Preload {
+		issues = append(issues, "directive preload absente")
+	}
+	return issues
+}
+
+// yesNo — booléen affiché dans les preuves
+func yesNo(b bool) string {
+	if b {
+		return "oui"
+	}
+	return "non"
+}
+
+// checkHSTS — présence, durée et portée de Strict-Transport-Security, éligibilité à la preload list
+func checkHSTS(h http.Header) HeaderVerdict {
+	v := HeaderVerdict{
+		Header: "Strict-Transport-Security", id: "hsts", weight: 20,
+		remediation: "Ajouter Strict-Transport-Security: max-age=31536000; includeSubDomains",
+	}
+	values := h.Values(v.Header)
+	if len(values) == 0 {
+		return v.missing(SeverityMedium, "Le navigateur accepte une première connexion en HTTP (SSL stripping)")
+	}
+	// Plusieurs headers : le navigateur n'applique que le premier (RFC 6797 §8.1)
+	v.Value = values[0]
+	p, err := parseHSTS(v.Value)
+	if err != nil {
+		return v.weak(SeverityMedium, "Header invalide, ignoré par le navigateur : "+err.Error())
+	}
+
+	days := p.MaxAge / (24 * 3600)
+	preload := "éligible"
+	if issues := p.preloadIssues(); len(issues) > 0 {
+		preload = "non éligible (" + strings.Join(issues, ", ") + ")"
+	}
+	detail := fmt.Sprintf("max-age %d jour(s) | includeSubDomains : %s | preload list : %s", days, yesNo(p.IncludeSubDomains), preload)
+	if len(values) > 1 {
+		detail += fmt.Sprintf(" | %d headers, seul le premier compte", len(values))
+	}
+	switch {
+	case p.MaxAge == 0:
+		return v.weak(SeverityMedium, "max-age=0 : HSTS désactivé pour ce domaine")
+	case p.MaxAge < hstsMinMaxAge:
+		return v.weak(SeverityLow, "max-age trop court — "+detail)
+	}
+	return v.ok(detail)
+}
+
+// cspChecks — finding de chaque vérification CSP : titres (faiblesse / conforme), sévérité et correction
+var cspChecks = map[string]struct {
+	weak, ok, fix string
+	severity      Severity
+}{
+	csp.CheckScriptSrc: {"Scripts non restreints par la CSP", "Scripts restreints par la CSP",
+		"Ajouter script-src (ou default-src) : 'self', ou des nonces avec 'strict-dynamic'", SeverityMedium},
+	csp.CheckUnsafeInline: {"CSP : 'unsafe-inline' autorisé", "CSP : pas de 'unsafe-inline' actif",
+		"Remplacer 'unsafe-inline' par des nonces ('nonce-...') ou des hashes ('sha256-...')", SeverityMedium},
+	csp.CheckUnsafeEval: {"CSP : 'unsafe-eval' autorisé", "CSP : pas de 'unsafe-eval'",
+		"Retirer 'unsafe-eval' et les appels à eval() / new Function()", SeverityLow},
+	csp.CheckWildcard: {"CSP : sources trop larges", "CSP : pas de source joker",
+		"Lister les hôtes autorisés au lieu de *, https: ou data:", SeverityMedium},
+	csp.CheckBypassHost: {"CSP : hôte de contournement autorisé", "CSP : aucun hôte de contournement connu",
+		"Retirer ces hôtes de script-src, ou passer à une politique à nonces avec 'strict-dynamic'", SeverityMedium},
+	csp.CheckObjectSrc: {"CSP : object-src non restreint", "CSP : object-src restreint",
+		"Ajouter object-src 'none'", SeverityLow},
+	csp.CheckBaseURI: {"CSP : base-uri absent", "CSP : base-uri défini",
+		"Ajouter base-uri 'none' (ou 'self')", SeverityLow},
+	csp.CheckFrameAncestors: {"CSP : frame-ancestors absent", "CSP : frame-ancestors défini",
+		"Ajouter frame-ancestors 'none' (ou 'self')", SeverityLow},
+	csp.CheckReporting: {"CSP : violations non remontées", "CSP : remontée des violations",
+		"Déclarer report-to et le groupe correspondant dans Reporting-Endpoints", SeverityInfo},
+}
+
+// checkCSP — analyse Content-Security-Policy et Content-Security-Policy-Report-Only
+// Plusieurs politiques appliquées se cumulent : une faiblesse ne compte que si toutes la partagent
+// Sans politique appliquée, la politique Report-Only est évaluée — elle ne bloque rien mais montre l'intention
+func checkCSP(h http.Header) HeaderVerdict {
+	v := HeaderVerdict{
+		Header: "Content-Security-Policy", id: "csp", weight: 20,
+		remediation: "Définir une Content-Security-Policy restrictive (default-src 'self'; object-src 'none'; base-uri 'none'; frame-ancestors 'none')",
+	}
+	enforced := h.Values(v.Header)
+	reportOnly := h.Values("Content-Security-Policy-Report-Only")
+	policies := csp.ParseHeaders(enforced, nil)
+	if len(policies) == 0 {
+		policies = csp.ParseHeaders(nil, reportOnly)
+	}
+	v.Value = strings.Join(enforced, ", ")
+	if len(policies) == 0 {
+		return v.missing(SeverityMedium, "Aucune restriction sur les scripts et ressources chargés par la page (XSS)")
+	}
+	if v.Value == "" {
+		v.Value = strings.Join(reportOnly, ", ")
+	}
+
+	// Groupes report-to déclarés par la réponse (Reporting-Endpoints, ou l'ancien Report-To)
+	endpoints := csp.ParseReportTo(strings.Join(h.Values("Report-To"), ","))
+	for name, url := range csp.ParseReportingEndpoints(strings.Join(h.Values("Reporting-Endpoints"), ",")) {
+		endpoints[name] = url
+	}
+
+	// Intersection : une vérification échoue si elle échoue dans chacune des politiques
+	failed := make(map[string]csp.Issue)
+	counts := make(map[string]int)
+	for _, p := range policies {
+		for _, issue := range csp.Evaluate(p, endpoints) {
+			if counts[issue.Check]++; counts[issue.Check] == 1 {
+				failed[issue.Check] = issue
+			}
+		}
+	}
+	xfo := strings.ToUpper(strings.TrimSpace(h.Get("X-Frame-Options")))
+	worst := SeverityInfo
+	for _, check := range csp.Checks {
+		c := cspChecks[check]
+		issue, ok := failed[check]
+		if !ok || counts[check] < len(policies) {
+			v.extra = append(v.extra, Finding{ID: "header.csp." + check, Title: c.ok, Severity: SeverityInfo, Category: "headers", Evidence: "Aucune faiblesse relevée"})
+			continue
+		}
+		severity := c.severity
+		switch {
+		// X-Frame-Options prend le relais de frame-ancestors
+		case check == csp.CheckFrameAncestors && (xfo == "DENY" || xfo == "SAMEORIGIN"):
+			severity = SeverityInfo
+		// Une politique Report-Only sans remontée ne sert à rien ; un groupe report-to inconnu est une erreur de config
+		case check == csp.CheckReporting && (policies[0].ReportOnly || issue.Directive != ""):
+			severity = SeverityLow
+		}
+		if severity.Rank() > worst.Rank() {
+			worst = severity
+		}
+		v.Issues = append(v.Issues, issue.Detail)
+		v.extra = append(v.extra, Finding{ID: "header.csp." + check, Title: c.weak, Severity: severity, Category: "headers", Evidence: issue.Detail, Remediation: c.fix})
+	}
+
+	summary := fmt.Sprintf("%d politique(s), %d faiblesse(s)", len(policies), len(v.Issues))
+	if dup := policies[0].Duplicates; len(dup) > 0 {
+		summary += " | directives répétées ignorées : " + strings.Join(dup, ", ")
+	}
+	switch {
+	case policies[0].ReportOnly:
+		return v.weak(SeverityMedium, "Politique en Report-Only uniquement : les violations sont signalées, rien n'est bloqué — "+summary)
+	case worst.Rank() >= SeverityMedium.Rank():
+		return v.weak(worst, summary)
+	}
+	return v.ok(summary)
+}
+
+// checkXFO — protection anti-clickjacking, par X-Frame-Options ou la directive frame-ancestors de la CSP
+// frame-ancestors prime sur X-Frame-Options dans les navigateurs qui gèrent les deux
+func checkXFO(h http.Header) HeaderVerdict {
+	v := HeaderVerdict{
+		Header: "X-Frame-Options", id: "xfo", weight: 10,
+		remediation: "Ajouter X-Frame-Options: DENY (ou frame-ancestors dans la CSP)",
+	}
+	v.Value = h.Get(v.Header)
+	// Seule une politique appliquée protège : frame-ancestors est sans effet en Report-Only
+	frameAncestors := slices.ContainsFunc(csp.ParseHeaders(h.Values("Content-Security-Policy"), nil), func(p csp.Policy) bool {
+		_, ok := p.Directive("frame-ancestors")
+		return ok
+	})
+
+	switch value := strings.ToUpper(strings.TrimSpace(v.Value)); {
+	case value == "DENY" || value == "SAMEORIGIN":
+		return v.ok(v.Value)
+	case frameAncestors:
+		return v.ok("Protection assurée par la directive frame-ancestors de la CSP")
+	case value == "":
+		return v.missing(SeverityLow, "La page peut être intégrée dans une iframe par n'importe quel site (clickjacking)")
+	case strings.HasPrefix(value, "ALLOW-FROM"):
+		return v.weak(SeverityLow, "ALLOW-FROM est ignoré par les navigateurs actuels : aucune protection")
+	}
+	return v.weak(SeverityLow, "Valeur invalide, ignorée par le navigateur")
+}
+
+// checkXCTO — X-Content-Type-Options: nosniff empêche le navigateur de deviner le type MIME
+func checkXCTO(h http.Header) HeaderVerdict {
+	v := HeaderVerdict{
+		Header: "X-Content-Type-Options", id: "xcto", weight: 10,
+		remediation: "Ajouter X-Content-Type-Options: nosniff",
+	}
+	v.Value = h.Get(v.Header)
+	switch {
+	case strings.EqualFold(strings.TrimSpace(v.Value), "nosniff"):
+		return v.ok(v.Value)
+	case v.Value == "":
+		return v.missing(SeverityLow, "Le navigateur peut interpréter un fichier téléversé comme script ou HTML (MIME sniffing)")
+	}
+	return v.weak(SeverityLow, "Seule la valeur nosniff est reconnue")
+}
+
+// referrerPolicies — valeurs de Referrer-Policy et fuite associée ("" = sûre)
+var referrerPolicies = map[string]string{
+	"no-referrer":                     "",
+	"same-origin":                     "",
+	"strict-origin":                   "",
+	"strict-origin-when-cross-origin": "",
+	"origin":                          "origine envoyée à tous les sites, même en HTTP",
+	"origin-when-cross-origin":        "origine envoyée aux autres sites, même en HTTP",
+	"no-referrer-when-downgrade":      "URL complète envoyée aux autres sites en HTTPS",
+	"unsafe-url":                      "URL complète (chemin et paramètres) envoyée à tous les sites, même en HTTP",
+}
+
+// checkReferrer — Referrer-Policy : quelle part de l'URL part vers les sites tiers
+// Une liste est permise ("no-referrer, strict-origin") : le navigateur retient la dernière valeur qu'il connaît
+func checkReferrer(h http.Header) HeaderVerdict {
+	v := HeaderVerdict{
+		Header: "Referrer-Policy", id: "referrer", weight: 10,
+		remediation: "Ajouter Referrer-Policy: strict-origin-when-cross-origin (ou no-referrer)",
+	}
+	v.Value = strings.Join(h.Values(v.Header), ", ")
+	if v.Value == "" {
+		return v.missing(SeverityLow, "Comportement laissé au navigateur (strict-origin-when-cross-origin dans les versions récentes)")
+	}
+	policy := ""
+	for _, token := range strings.Split(v.Value, ",") {
+		token = strings.ToLower(strings.TrimSpace(token))
+		if _, known := referrerPolicies[token]; known {
+			policy = token
+		}
+	}
+	switch leak := referrerPolicies[policy]; {
+	case policy == "":
+		return v.weak(SeverityLow, "Aucune valeur reconnue, ignorée par le navigateur")
+	case policy == "unsafe-url":
+		return v.weak(SeverityMedium, policy+" : "+leak)
+	case leak != "":
+		return v.weak(SeverityLow, policy+" : "+leak)
+	}
+	return v.ok("Politique appliquée : " + policy)
+}
+
+// sensitiveFeatures — fonctionnalités qui ne doivent pas être ouvertes à toutes les origines
+var sensitiveFeatures = []string{
+	"camera", "microphone", "geolocation", "payment", "usb", "serial", "hid", "bluetooth", "display-capture",
+}
+
+// checkPermissions — Permissions-Policy : accès des iframes et scripts tiers aux API du navigateur
+// Format « structured field » : camera=(), geolocation=(self "https://maps.example"), microphone=*
+func checkPermissions(h http.Header) HeaderVerdict {
+	v := HeaderVerdict{
+		Header: "Permissions-Policy", id: "permissions", weight: 10,
+		remediation: "Ajouter Permissions-Policy en désactivant les API inutilisées : camera=(), microphone=(), geolocation=()",
+	}
+	v.Value = strings.Join(h.Values(v.Header), ", ")
+	if v.Value == "" {
+		if legacy := h.Get("Feature-Policy"); legacy != "" {
+			v.Value = legacy
+			return v.weak(SeverityLow, "Seul Feature-Policy, obsolète et ignoré par les navigateurs actuels, est présent")
+		}
+		return v.missing(SeverityLow, "Les API sensibles (caméra, micro, géolocalisation) restent ouvertes aux iframes autorisées")
+	}
+
+	var open []string
+	restricted := 0
+	for _, member := range strings.Split(v.Value, ",") {
+		feature, allow, ok := strings.Cut(strings.TrimSpace(member), "=")
+		if !ok {
+			return v.weak(SeverityLow, fmt.Sprintf("Syntaxe invalide (%q) : la politique est ignorée", strings.TrimSpace(member)))
+		}
+		feature = strings.ToLower(strings.TrimSpace(feature))
+		allow = strings.TrimSpace(allow)
+		if allow == "()" {
+			restricted++
+		}
+		if slices.Contains(sensitiveFeatures, feature) && (allow == "*" || slices.Contains(strings.Fields(strings.Trim(allow, "()")), "*")) {
+			open = append(open, feature)
+		}
+	}
+	if len(open) > 0 {
+		return v.weak(SeverityLow, "Ouvert à toutes les origines : "+strings.Join(open, ", "))
+	}
+	return v.ok(fmt.Sprintf("%d fonctionnalité(s) désactivée(s)", restricted))
+}
+
+// isolationToken — valeur d'un header d'isolation, sans ses paramètres (same-origin; report-to="x")
+func isolationToken(value string) string {
+	token, _, _ := strings.Cut(value, ";")
+	return strings.ToLower(strings.TrimSpace(token))
+}
+
+// checkCOOP — Cross-Origin-Opener-Policy : isole la fenêtre des popups et pages ouvertes (XS-Leaks, Spectre)
+func checkCOOP(h http.Header) HeaderVerdict {
+	v := HeaderVerdict{
+		Header: "Cross-Origin-Opener-Policy", id: "coop", weight: 5,
+		remediation: "Ajouter Cross-Origin-Opener-Policy: same-origin (same-origin-allow-popups pour les connexions OAuth en popup)",
+	}
+	v.Value = h.Get(v.Header)
+	switch isolationToken(v.Value) {
+	case "same-origin", "same-origin-allow-popups", "noopener-allow-popups":
+		return v.ok(v.Value)
+	case "", "unsafe-none":
+		return v.missing(SeverityLow, "Une page ouverte depuis un autre site garde une référence à cette fenêtre (window.opener)")
+	}
+	return v.weak(SeverityLow, "Valeur invalide, ignorée par le navigateur")
+}
+
+// checkCOEP — Cross-Origin-Embedder-Policy : condition de l'isolation cross-origin (SharedArrayBuffer)
+// Informatif : utile seulement aux applications qui ont besoin de cette isolation
+func checkCOEP(h http.Header) HeaderVerdict {
+	v := HeaderVerdict{
+		Header: "Cross-Origin-Embedder-Policy", id: "coep",
+		remediation: "Si l'application a besoin de l'isolation cross-origin : Cross-Origin-Embedder-Policy: require-corp",
+	}
+	v.Value = h.Get(v.Header)
+	switch isolationToken(v.Value) {
+	case "require-corp", "credentialless":
+		return v.ok(v.Value)
+	case "", "unsafe-none":
+		return v.missing(SeverityInfo, "Isolation cross-origin non activée")
+	}
+	return v.weak(SeverityInfo, "Valeur invalide, ignorée par le navigateur")
+}
+
+// checkCORP — Cross-Origin-Resource-Policy : quels sites peuvent intégrer la réponse (Spectre)
+func checkCORP(h http.Header) HeaderVerdict {
+	v := HeaderVerdict{
+		Header: "Cross-Origin-Resource-Policy", id: "corp",
+		remediation: "Ajouter Cross-Origin-Resource-Policy: same-origin (ou same-site)",
+	}
+	v.Value = h.Get(v.Header)
+	switch isolationToken(v.Value) {
+	case "same-origin", "same-site":
+		return v.ok(v.Value)
+	case "cross-origin":
+		return v.weak(SeverityInfo, "Réponse intégrable par n'importe quel site")
+	case "":
+		return v.missing(SeverityInfo, "Réponse intégrable par n'importe quel site")
+	}
+	return v.weak(SeverityInfo, "Valeur invalide, ignorée par le navigateur")
+}
+
+// authPath — chemins de pages authentifiées ou de formulaires d'identifiants
+var authPath = regexp.MustCompile(`(?i)(login|logout|signin|signup|auth|account|admin|dashboard|profile|session|password|checkout|billing)`)
+
+// sensitivePage indique pourquoi la page ne doit pas être mise en cache ("" = page publique)
+func sensitivePage(path string, resp *http.Response) string {
+	if authPath.MatchString(path) {
+		return "chemin d'authentification ou de compte"
+	}
+	if len(resp.Cookies()) > 0 {
+		return "la réponse dépose un cookie"
+	}
+	return ""
+}
+
+// checkCache — Cache-Control d'une page sensible : no-store empêche toute copie (proxy, cache partagé, disque)
+// reason explique pourquoi la page est jugée sensible
+func checkCache(h http.Header, reason string) HeaderVerdict {
+	v := HeaderVerdict{
+		Header: "Cache-Control", id: "cache", weight: 10,
+		remediation: "Ajouter Cache-Control: no-store sur les pages authentifiées",
+	}
+	v.Value = strings.Join(h.Values(v.Header), ", ")
+	directives := make(map[string]string)
+	for _, d := range strings.Split(v.Value, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(d), "=")
+		directives[strings.ToLower(name)] = strings.Trim(value, `"`)
+	}
+	_, noStore := directives["no-store"]
+	_, private := directives["private"]
+	_, noCache := directives["no-cache"]
+	switch {
+	case noStore:
+		return v.ok("Page sensible (" + reason + ") : " + v.Value)
+	case v.Value == "":
+		return v.missing(SeverityMedium, "Page sensible ("+reason+") sans Cache-Control : un proxy ou le navigateur peut la conserver")
+	case private && (noCache || directives["max-age"] == "0"):
+		return v.weak(SeverityLow, "Page sensible ("+reason+") : pas de cache partagé, mais une copie reste sur le disque du navigateur")
+	}
+	return v.weak(SeverityMedium, "Page sensible ("+reason+") qui peut être conservée par un cache partagé")
+}
+
+// checkServer — le header Server ne doit pas exposer la version du logiciel
+func checkServer(h http.Header) HeaderVerdict {
+	v := HeaderVerdict{
+		Header: "Server", id: "server", penalty: 5,
+		remediation: "Retirer le numéro de version du header Server (server_tokens off, ServerTokens Prod)",
+	}
+	v.Value = h.Get(v.Header)
+	switch {
+	case v.Value == "":
+		return v.ok("Non exposé")
+	case strings.ContainsAny(v.Value, "0123456789"):
+		return v.exposed(SeverityLow, "Version exposée : facilite la recherche de vulnérabilités connues")
+	}
+	return v.ok("Produit exposé sans version")
+}
+
+// poweredByHeaders — headers qui révèlent le framework ou le langage de l'application
+var poweredByHeaders = []string{"X-Powered-By", "X-AspNet-Version", "X-AspNetMvc-Version", "X-Generator"}
+
+// checkPoweredBy — X-Powered-By et apparentés n'ont aucune utilité pour le client
+func checkPoweredBy(h http.Header) HeaderVerdict {
+	v := HeaderVerdict{
+		Header: "X-Powered-By", id: "powered_by", penalty: 5,
+		remediation: "Retirer X-Powered-By et les headers de version du framework",
+	}
+	var exposed []string
+	for _, name := range poweredByHeaders {
+		if value := h.Get(name); value != "" {
+			exposed = append(exposed, name+": "+value)
+		}
+	}
+	if len(exposed) == 0 {
+		return v.ok("Non exposé")
+	}
+	v.Value = strings.Join(exposed, " | ")
+	return v.exposed(SeverityLow, "Framework ou langage exposé")
+}
+
+// ok, weak, missing, exposed fixent le verdict, la sévérité et le titre du finding
+func (v HeaderVerdict) ok(detail string) HeaderVerdict {
+	v.Verdict, v.Severity, v.Detail, v.title = verdictOK, SeverityInfo, detail, v.Header+" correct"
+	return v
+}
+
+func (v HeaderVerdict) weak(severity Severity, detail string) HeaderVerdict {
+	v.Verdict, v.Severity, v.Detail, v.title = verdictWeak, severity, detail, v.Header+" insuffisant"
+	return v
+}
+
+func (v HeaderVerdict) missing(severity Severity, detail string) HeaderVerdict {
+	v.Verdict, v.Severity, v.Detail, v.title = verdictMissing, severity, detail, v.Header+" absent"
+	return v
+}
+
+func (v HeaderVerdict) exposed(severity Severity, detail string) HeaderVerdict {
+	v.Verdict, v.Severity, v.Detail, v.title = verdictExposed, severity, detail, v.Header+" divulgue une information"
+	return v
+}
+
+// scoreHeaders note la réponse : points des headers pondérés (weak = moitié), moins les pénalités de fuite
+func scoreHeaders(verdicts []HeaderVerdict) (int, string) {
+	earned, possible, penalty := 0, 0, 0
+	for _, v := range verdicts {
+		possible += v.weight
+		switch v.Verdict {
+		case verdictOK:
+			earned += v.weight
+		case verdictWeak:
+			earned += v.weight / 2
+		case verdictExposed:
+			penalty += v.penalty
+		}
+	}
+	score := 100
+	if possible > 0 {
+		score = earned * 100 / possible
+	}
+	score = max(score-penalty, 0)
+
+	switch {
+	case score >= 90:
+		return score, "A"
+	case score >= 75:
+		return score, "B"
+	case score >= 60:
+		return score, "C"
+	case score >= 40:
+		return score, "D"
+	}
+	return score, "F"
+}
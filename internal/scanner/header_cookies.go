@@ -0,0 +1,168 @@
+package scanner
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// CookieAudit — attributs d'un cookie déposé par le site et faiblesses relevées
+type CookieAudit struct {
+	URL      string    `json:"url"` // Page qui a déposé le cookie
+	Name     string    `json:"name"`
+	Secure   bool      `json:"secure"`
+	HttpOnly bool      `json:"http_only"`
+	SameSite string    `json:"same_site,omitempty"` // Strict, Lax, None — vide = non précisé
+	Domain   string    `json:"domain,omitempty"`    // Vide = cookie limité à l'hôte qui l'a déposé
+	Path     string    `json:"path,omitempty"`
+	Expires  time.Time `json:"expires,omitzero"` // Zéro = cookie de session (supprimé à la fermeture du navigateur)
+	Session  bool      `json:"session_like"`     // Le nom évoque un identifiant de session ou un jeton
+	Issues   []string  `json:"issues,omitempty"`
+}
+
+// Durées de vie : au-delà de 30 jours un jeton de session survit trop longtemps à un vol,
+// au-delà de 400 jours Chrome tronque de toute façon l'expiration
+const (
+	sessionCookieMaxAge = 30 * 24 * time.Hour
+	cookieMaxAge        = 400 * 24 * time.Hour
+)
+
+// sessionCookieName — noms d'identifiants de session et de jetons (PHPSESSID, JSESSIONID, connect.sid, auth_token...)
+var sessionCookieName = regexp.MustCompile(`(?i)(sess|^sid$|[._-]sid$|auth|token|jwt|login|remember)`)
+
+// auditCookie lit les attributs d'un cookie — nil pour un cookie en cours de suppression (Max-Age=0, date passée)
+func auditCookie(url string, c *http.Cookie, now time.Time) *CookieAudit {
+	a := &CookieAudit{
+		URL: url, Name: c.Name, Secure: c.Secure, HttpOnly: c.HttpOnly,
+		Domain: strings.TrimPrefix(c.Domain, "."), Path: c.Path,
+		Session: sessionCookieName.MatchString(c.Name),
+	}
+	// Max-Age prime sur Expires (RFC 6265 §5.3)
+	switch {
+	case c.MaxAge < 0:
+		return nil
+	case c.MaxAge > 0:
+		a.Expires = now.Add(time.Duration(c.MaxAge) * time.Second)
+	case !c.Expires.IsZero():
+		if c.Expires.Before(now) {
+			return nil
+		}
+		a.Expires = c.Expires
+	}
+	switch c.SameSite {
+	case http.SameSiteStrictMode:
+		a.SameSite = "Strict"
+	case http.SameSiteLaxMode:
+		a.SameSite = "Lax"
+	case http.SameSiteNoneMode:
+		a.SameSite = "None"
+	}
+	return a
+}
+
+// cookieFinding — une vérification d'un cookie : suffixe d'ID, titre et sévérité (Info = conforme)
+type cookieFinding struct {
+	id, title   string
+	severity    Severity
+	evidence    string
+	remediation string
+}
+
+// checkCookie juge les attributs du cookie — une vérification par attribut, conforme ou non
+// Un cookie qui ressemble à un identifiant de session aggrave chaque absence de protection
+func checkCookie(a *CookieAudit, now time.Time) []cookieFinding {
+	var checks []cookieFinding
+	add := func(id, title string, severity Severity, evidence, remediation string) {
+		if severity != SeverityInfo {
+			a.Issues = append(a.Issues, evidence)
+		} else {
+			remediation = ""
+		}
+		checks = append(checks, cookieFinding{id, title, severity, evidence, remediation})
+	}
+	raise := func(normal, session Severity) Severity {
+		if a.Session {
+			return session
+		}
+		return normal
+	}
+
+	// Secure : sans lui, le cookie part aussi sur une requête HTTP en clair
+	if a.Secure {
+		add("secure", "Cookie Secure", SeverityInfo, "Envoyé uniquement en HTTPS", "")
+	} else {
+		add("secure", "Cookie sans Secure", raise(SeverityMedium, SeverityHigh),
+			"Envoyé aussi en HTTP : interceptable sur un réseau non chiffré", "Ajouter l'attribut Secure")
+	}
+
+	// HttpOnly : sans lui, une XSS lit le cookie — légitime pour un cookie lu par le front (préférences, jeton CSRF)
+	if a.HttpOnly {
+		add("httponly", "Cookie HttpOnly", SeverityInfo, "Illisible depuis JavaScript", "")
+	} else {
+		add("httponly", "Cookie sans HttpOnly", raise(SeverityInfo, SeverityMedium),
+			"Lisible par JavaScript (document.cookie) : volé par une XSS", "Ajouter l'attribut HttpOnly")
+	}
+
+	// SameSite : None sans Secure est refusé par les navigateurs ; absent = Lax dans Chrome, pas partout
+	switch {
+	case a.SameSite == "None" && !a.Secure:
+		add("samesite", "SameSite=None sans Secure", SeverityMedium,
+			"Cookie refusé par les navigateurs actuels", "Ajouter Secure, ou passer à SameSite=Lax")
+	case a.SameSite == "None":
+		add("samesite", "Cookie envoyé en cross-site", raise(SeverityInfo, SeverityLow),
+			"SameSite=None : joint aux requêtes venues d'autres sites (CSRF)", "Utiliser SameSite=Lax ou Strict si le cookie n'a pas à être partagé")
+	case a.SameSite == "":
+		add("samesite", "Cookie sans SameSite", raise(SeverityInfo, SeverityLow),
+			"Comportement laissé au navigateur : Lax dans Chrome, envoyé en cross-site ailleurs", "Ajouter SameSite=Lax (ou Strict)")
+	default:
+		add("samesite", "Cookie SameSite", SeverityInfo, "SameSite="+a.SameSite, "")
+	}
+
+	// Préfixes : __Host- impose Secure, Path=/ et aucun Domain ; __Secure- impose Secure
+	// Un préfixe mal respecté fait rejeter le cookie par le navigateur
+	switch {
+	case strings.HasPrefix(a.Name, "__Host-"):
+		if a.Secure && a.Path == "/" && a.Domain == "" {
+			add("prefix", "Préfixe __Host- respecté", SeverityInfo, "Secure, Path=/, sans Domain", "")
+		} else {
+			add("prefix", "Préfixe __Host- non respecté", SeverityMedium,
+				"__Host- exige Secure, Path=/ et aucun Domain : cookie rejeté par le navigateur", "Ajouter Secure et Path=/, retirer Domain")
+		}
+	case strings.HasPrefix(a.Name, "__Secure-"):
+		if a.Secure {
+			add("prefix", "Préfixe __Secure- respecté", SeverityInfo, "Secure", "")
+		} else {
+			add("prefix", "Préfixe __Secure- non respecté", SeverityMedium,
+				"__Secure- exige Secure : cookie rejeté par le navigateur", "Ajouter l'attribut Secure")
+		}
+	default:
+		add("prefix", "Cookie sans préfixe", SeverityInfo, "Ni __Host- ni __Secure-", "")
+	}
+
+	// Domain : présent, il étend le cookie à tous les sous-domaines (un sous-domaine compromis le lit)
+	if a.Domain != "" {
+		add("domain", "Cookie partagé avec les sous-domaines", raise(SeverityLow, SeverityMedium),
+			"Domain="+a.Domain+" : envoyé à tous les sous-domaines", "Retirer Domain pour limiter le cookie à l'hôte (ou utiliser __Host-)")
+	} else {
+		add("domain", "Cookie limité à l'hôte", SeverityInfo, "Pas d'attribut Domain", "")
+	}
+
+	// Persistance
+	lifetime := a.Expires.Sub(now)
+	days := int(lifetime.Hours() / 24)
+	switch {
+	case a.Expires.IsZero():
+		add("persistence", "Cookie de session", SeverityInfo, "Supprimé à la fermeture du navigateur", "")
+	case a.Session && lifetime > sessionCookieMaxAge:
+		add("persistence", "Jeton de session persistant", SeverityMedium,
+			fmt.Sprintf("Valide %d jours : un jeton volé reste utilisable longtemps", days), "Limiter la durée à quelques heures ou jours, renouveler côté serveur")
+	case lifetime > cookieMaxAge:
+		add("persistence", "Cookie à très longue durée de vie", SeverityLow,
+			fmt.Sprintf("Valide %d jours (tronqué à 400 par Chrome)", days), "Réduire Max-Age / Expires")
+	default:
+		add("persistence", "Cookie persistant", SeverityInfo, fmt.Sprintf("Valide %d jours", days), "")
+	}
+	return checks
+}
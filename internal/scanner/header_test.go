@@ -1,6 +1,12 @@
 package scanner
 
-import "testing"
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
 
 // TestHeaderScanner_Name vérifie que le scanner retourne le bon identifiant
 func TestHeaderScanner_Name(t *testing.T) {
@@ -15,32 +21,328 @@ func TestHeaderScanner_Name(t *testing.T) {
 // google.com doit répondre avec au moins HSTS ou X-Frame-Options
 func TestHeaderScanner_Scan(t *testing.T) {
 	// HeaderScanner fait un http.Get("https://" + domain) en interne
-	result, err := HeaderScanner{}.Scan("google.com")
+	result, err := HeaderScanner{}.Scan(context.Background(), "google.com", nil)
 
 	// Pas d'erreur attendue — le serveur Google répond toujours
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	// Le résultat contient les headers formatés : "HSTS: ... | CSP: ... | X-Frame: ..."
-	// Même si certains headers sont vides, la string formatée n'est jamais ""
-	if result == "" {
-		t.Errorf("got empty result, want security headers")
+	// Un finding par header vérifié (présent → info, absent → medium/low)
+	// Même si certains headers sont absents, la liste de findings n'est jamais vide
+	if len(result.Findings) == 0 {
+		t.Errorf("got no findings, want security headers")
 	}
 }
 
 // TestHeaderScanner_Scan_InvalidDomain — Error path : un domaine invalide fait échouer http.Get
 // "false_url" → résolution DNS échoue → http.Get retourne une erreur
 func TestHeaderScanner_Scan_InvalidDomain(t *testing.T) {
-	result, err := HeaderScanner{}.Scan("false_url")
+	result, err := HeaderScanner{}.Scan(context.Background(), "false_url", nil)
 
 	// http.Get échoue car "false_url" n'est pas un domaine valide
 	if err == nil {
 		t.Errorf("expected error for invalid domain, got nil")
 	}
 
-	// Contrat : en cas d'erreur, result doit être "" (string vide)
-	if result != "" {
-		t.Errorf("expected empty result, got %s", result)
+	// Contrat : en cas d'erreur, result ne contient aucun finding
+	if len(result.Findings) != 0 {
+		t.Errorf("expected no findings, got %v", result.Findings)
+	}
+}
+
+// TestHeaderScanner_Scan_Local — serveur TLS local (httptest) : pas de dépendance réseau
+// HSTS présent → finding info, CSP et X-Frame-Options absents → findings medium/low
+func TestHeaderScanner_Scan_Local(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Strict-Transport-Security", "max-age=31536000")
+	}))
+	defer srv.Close()
+
+	// srv.Client() fait confiance au certificat auto-signé du serveur de test
+	// srv.Listener.Addr() = "127.0.0.1:port" → utilisé comme "domaine"
+	scanner := HeaderScanner{Client: srv.Client()}
+	result, err := scanner.Scan(context.Background(), srv.Listener.Addr().String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// On indexe les findings par ID pour vérifier chaque header
+	severities := make(map[string]Severity)
+	for _, f := range result.Findings {
+		severities[f.ID] = f.Severity
+	}
+
+	if severities["header.hsts"] != SeverityInfo {
+		t.Errorf("got %s for header.hsts, want info", severities["header.hsts"])
+	}
+	if severities["header.csp"] != SeverityMedium {
+		t.Errorf("got %s for header.csp, want medium", severities["header.csp"])
+	}
+	if severities["header.xfo"] != SeverityLow {
+		t.Errorf("got %s for header.xfo, want low", severities["header.xfo"])
+	}
+}
+
+// headerServer démarre un serveur TLS qui répond avec headers et retourne le scanner qui lui fait confiance
+func headerServer(t *testing.T, headers http.Header) (HeaderScanner, string) {
+	t.Helper()
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for name, values := range headers {
+			w.Header()[name] = values
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return HeaderScanner{Client: srv.Client()}, srv.Listener.Addr().String()
+}
+
+// TestHeaderScanner_Scan_Verdicts — verdict de chaque header selon sa valeur, et note globale
+func TestHeaderScanner_Scan_Verdicts(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers http.Header
+		path    string
+		want    map[string]Severity // ID → sévérité attendue ("" = finding absent)
+		grade   string
+	}{
+		{
+			name: "configuration complète",
+			headers: http.Header{
+				"Strict-Transport-Security":    {"max-age=63072000; includeSubDomains; preload"},
+				"Content-Security-Policy":      {"default-src 'self'; frame-ancestors 'none'"},
+				"X-Content-Type-Options":       {"nosniff"},
+				"Referrer-Policy":              {"strict-origin-when-cross-origin"},
+				"Permissions-Policy":           {"camera=(), microphone=(), geolocation=(self)"},
+				"Cross-Origin-Opener-Policy":   {"same-origin"},
+				"Cross-Origin-Embedder-Policy": {"require-corp"},
+				"Cross-Origin-Resource-Policy": {"same-origin"},
+				"Server":                       {"nginx"},
+			},
+			want: map[string]Severity{
+				"header.hsts": SeverityInfo, "header.csp": SeverityInfo, "header.xfo": SeverityInfo,
+				"header.xcto": SeverityInfo, "header.referrer": SeverityInfo, "header.permissions": SeverityInfo,
+				"header.coop": SeverityInfo, "header.coep": SeverityInfo, "header.corp": SeverityInfo,
+				"header.server": SeverityInfo, "header.powered_by": SeverityInfo, "header.cache": "",
+				"header.grade": SeverityInfo,
+			},
+			grade: "A",
+		},
+		{
+			name: "valeurs faibles et fuites",
+			headers: http.Header{
+				"Strict-Transport-Security": {"max-age=0"},
+				"X-Frame-Options":           {"ALLOW-FROM https://example.com"},
+				"X-Content-Type-Options":    {"sniff"},
+				"Referrer-Policy":           {"unsafe-url"},
+				"Permissions-Policy":        {"camera=*, fullscreen=*"},
+				"Server":                    {"Apache/2.4.41 (Ubuntu)"},
+				"X-Powered-By":              {"PHP/7.4.3"},
+			},
+			want: map[string]Severity{
+				"header.hsts": SeverityMedium, "header.xfo": SeverityLow, "header.xcto": SeverityLow,
+				"header.referrer": SeverityMedium, "header.permissions": SeverityLow,
+				"header.server": SeverityLow, "header.powered_by": SeverityLow, "header.grade": SeverityMedium,
+			},
+			grade: "F",
+		},
+		{
+			name:    "page de connexion sans no-store",
+			headers: http.Header{"Cache-Control": {"public, max-age=3600"}},
+			path:    "/login",
+			want:    map[string]Severity{"header.cache": SeverityMedium},
+			grade:   "F",
+		},
+		{
+			name:    "cookie avec no-store",
+			headers: http.Header{"Cache-Control": {"no-store"}, "Set-Cookie": {"sid=1; Secure; HttpOnly"}},
+			want:    map[string]Severity{"header.cache": SeverityInfo},
+			grade:   "F",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner, domain := headerServer(t, tt.headers)
+			opts := Options{}
+			if tt.path != "" {
+				opts["path"] = tt.path
+			}
+			result, err := scanner.Scan(context.Background(), domain, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			byID := findingsByID(result)
+			for id, severity := range tt.want {
+				f, ok := byID[id]
+				if severity == "" {
+					if ok {
+						t.Errorf("%s : got %+v, want absent", id, f)
+					}
+					continue
+				}
+				if !ok || f.Severity != severity {
+					t.Errorf("%s : got %+v, want %s", id, f, severity)
+				}
+			}
+			if report := result.Data.(*HeaderReport); report.Grade != tt.grade {
+				t.Errorf("got grade %s (score %d), want %s", report.Grade, report.Score, tt.grade)
+			}
+		})
+	}
+}
+
+// TestParseHSTS — directives, guillemets, répétition et éligibilité à la preload list
+func TestParseHSTS(t *testing.T) {
+	p, err := parseHSTS(`max-age="31536000"; includeSubDomains; PRELOAD`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.MaxAge != 31536000 || !p.IncludeSubDomains || !p.Preload || len(p.preloadIssues()) != 0 {
+		t.Errorf("got %+v, want preload-eligible policy", p)
+	}
+
+	p, err = parseHSTS("max-age=86400")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issues := p.preloadIssues(); len(issues) != 3 {
+		t.Errorf("got %v, want 3 preload issues", issues)
+	}
+
+	for _, raw := range []string{"includeSubDomains", "max-age=abc", "max-age=1; max-age=2"} {
+		if _, err := parseHSTS(raw); err == nil {
+			t.Errorf("%q : expected error", raw)
+		}
+	}
+}
+
+// TestHeaderScanner_Scan_CSP — une finding par vérification CSP, politiques cumulées et Report-Only
+func TestHeaderScanner_Scan_CSP(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers http.Header
+		want    map[string]Severity
+	}{
+		{
+			name: "politique à nonce",
+			headers: http.Header{
+				"Content-Security-Policy": {"script-src 'nonce-r4nd0m' 'strict-dynamic'; object-src 'none'; base-uri 'none'; frame-ancestors 'none'; report-to csp"},
+				"Reporting-Endpoints":     {`csp="https://r.example/csp"`},
+			},
+			want: map[string]Severity{
+				"header.csp": SeverityInfo, "header.csp.unsafe_inline": SeverityInfo, "header.csp.wildcard": SeverityInfo,
+				"header.csp.object_src": SeverityInfo, "header.csp.reporting": SeverityInfo,
+			},
+		},
+		{
+			name: "unsafe-inline et hôte JSONP",
+			headers: http.Header{
+				"Content-Security-Policy": {"default-src 'self'; script-src 'self' 'unsafe-inline' https://www.google.com"},
+				"X-Frame-Options":         {"DENY"},
+			},
+			want: map[string]Severity{
+				"header.csp": SeverityMedium, "header.csp.unsafe_inline": SeverityMedium, "header.csp.bypass_host": SeverityMedium,
+				"header.csp.object_src": SeverityInfo, "header.csp.base_uri": SeverityLow, "header.csp.frame_ancestors": SeverityInfo,
+			},
+		},
+		{
+			// La seconde politique bloque l'inline : la faiblesse de la première ne compte pas
+			name: "politiques cumulées",
+			headers: http.Header{
+				"Content-Security-Policy": {"script-src 'self' 'unsafe-inline'", "script-src 'self'"},
+			},
+			want: map[string]Severity{"header.csp.unsafe_inline": SeverityInfo, "header.csp.base_uri": SeverityLow},
+		},
+		{
+			name:    "Report-Only seul",
+			headers: http.Header{"Content-Security-Policy-Report-Only": {"default-src 'self'"}},
+			want:    map[string]Severity{"header.csp": SeverityMedium, "header.csp.reporting": SeverityLow},
+		},
+		{
+			name:    "aucune politique",
+			headers: http.Header{},
+			want:    map[string]Severity{"header.csp": SeverityMedium, "header.csp.unsafe_inline": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner, domain := headerServer(t, tt.headers)
+			result, err := scanner.Scan(context.Background(), domain, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			byID := findingsByID(result)
+			for id, severity := range tt.want {
+				f, ok := byID[id]
+				if severity == "" {
+					if ok {
+						t.Errorf("%s : got %+v, want absent", id, f)
+					}
+					continue
+				}
+				if !ok || f.Severity != severity {
+					t.Errorf("%s : got %+v, want %s", id, f, severity)
+				}
+			}
+		})
+	}
+}
+
+// TestHeaderScanner_Scan_Cookies — attributs de chaque cookie, page d'accueil et cookie_paths
+func TestHeaderScanner_Scan_Cookies(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Add("Set-Cookie", "__Host-sid=1; Secure; HttpOnly; SameSite=Lax; Path=/")
+			w.Header().Add("Set-Cookie", "theme=dark; Secure; SameSite=Strict")
+			w.Header().Add("Set-Cookie", "old=1; Max-Age=0")
+		case "/login":
+			w.Header().Add("Set-Cookie", "PHPSESSID=abc; Domain=.example.com; Max-Age=7776000")
+			w.Header().Add("Set-Cookie", "__Secure-pref=1; SameSite=None")
+			w.Header().Add("Set-Cookie", "theme=light")
+		}
+	}))
+	defer srv.Close()
+	domain := srv.Listener.Addr().String()
+
+	result, err := HeaderScanner{Client: srv.Client()}.Scan(context.Background(), domain, Options{"cookie_paths": []string{"login", "/absent"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Index (cookie, vérification) → sévérité
+	got := make(map[string]Severity)
+	for _, f := range result.Findings {
+		check, ok := strings.CutPrefix(f.ID, "header.cookie.")
+		_, name, _ := strings.Cut(f.Asset, "[cookie ")
+		if ok {
+			got[strings.TrimSuffix(name, "]")+" "+check] = f.Severity
+		}
+	}
+	want := map[string]Severity{
+		"__Host-sid secure": SeverityInfo, "__Host-sid httponly": SeverityInfo, "__Host-sid prefix": SeverityInfo,
+		"__Host-sid samesite": SeverityInfo, "__Host-sid persistence": SeverityInfo,
+		"theme httponly": SeverityInfo, // Cookie du front : HttpOnly facultatif
+		"PHPSESSID secure": SeverityHigh, "PHPSESSID httponly": SeverityMedium, "PHPSESSID samesite": SeverityLow,
+		"PHPSESSID domain": SeverityMedium, "PHPSESSID persistence": SeverityMedium,
+		"__Secure-pref prefix": SeverityMedium, "__Secure-pref samesite": SeverityMedium,
+	}
+	for key, severity := range want {
+		if got[key] != severity {
+			t.Errorf("%s : got %q, want %s", key, got[key], severity)
+		}
+	}
+	// old est en cours de suppression ; theme n'est audité qu'une fois (page d'accueil)
+	if _, ok := got["old secure"]; ok {
+		t.Error("got finding for deleted cookie old")
+	}
+	report := result.Data.(*HeaderReport)
+	if len(report.Cookies) != 4 {
+		t.Errorf("got %d cookies, want 4", len(report.Cookies))
+	}
+	if len(report.Errors) != 0 {
+		t.Errorf("got errors %v, want none (404 is still a response)", report.Errors)
 	}
 }
@@ -0,0 +1,41 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"time"
+)
+
+// defaultClient — client HTTP partagé par les scanners qui n'ont pas de Client configuré
+// Le Timeout est un filet de sécurité : la vraie deadline vient du contexte de la requête
+var defaultClient = &http.Client{Timeout: 30 * time.Second}
+
+// clientOrDefault retourne c s'il est défini, sinon le client partagé
+// Permet aux tests d'injecter le client d'un httptest.Server (certificat auto-signé)
+func clientOrDefault(c *http.Client) *http.Client {
+	if c != nil {
+		return c
+	}
+	return defaultClient
+}
+
+// get effectue un GET lié au contexte — la requête est abandonnée si ctx expire
+// Équivalent de http.Get(url), mais annulable
+func get(ctx context.Context, client *http.Client, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return clientOrDefault(client).Do(req)
+}
+
+// post envoie body en POST lié au contexte, avec l'en-tête Content-Type contentType
+func post(ctx context.Context, client *http.Client, url, contentType string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return clientOrDefault(client).Do(req)
+}
@@ -0,0 +1,278 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OptionType — type d'une option de scanner (détermine le parsing et la validation)
+type OptionType string
+
+const (
+	OptionString  OptionType = "string"   // Ex: "/admin"
+	OptionInt     OptionType = "int"      // Ex: 443
+	OptionBool    OptionType = "bool"     // Ex: true
+	OptionStrings OptionType = "[]string" // Ex: ["A", "MX"] ou "A,MX" en query param
+	OptionInts    OptionType = "[]int"    // Ex: [443, 8443] ou "443,8443" en query param
+)
+
+// Option décrit un paramètre accepté par un scanner
+// La liste des Option d'un scanner forme son Schema, publié aux clients pour la validation
+type Option struct {
+	Name        string     `json:"name"`
+	Type        OptionType `json:"type"`
+	Description string     `json:"description"`
+	Default     any        `json:"default,omitempty"` // Valeur utilisée si le client ne fournit rien
+	Enum        []string   `json:"enum,omitempty"`    // Valeurs autorisées (string et []string)
+	Min         *int       `json:"min,omitempty"`     // Borne basse incluse (int et []int)
+	Max         *int       `json:"max,omitempty"`     // Borne haute incluse (int et []int)
+
+	// Validate — contrôle propre à l'option (format d'adresse...), appelé après Enum / Min / Max
+	Validate func(value any) error `json:"-"`
+}
+
+// Schema — ensemble des options acceptées par un scanner
+type Schema []Option
+
+// Options — valeurs d'options validées, indexées par nom
+// Les valeurs ont le type Go correspondant à OptionType (string, int, bool, []string, []int)
+type Options map[string]any
+
+// intPtr retourne un pointeur vers n — pratique pour Option.Min / Option.Max
+func intPtr(n int) *int { return &n }
+
+// lookup retourne la définition de l'option name (ok = false si inconnue)
+func (s Schema) lookup(name string) (Option, bool) {
+	for _, o := range s {
+		if o.Name == name {
+			return o, true
+		}
+	}
+	return Option{}, false
+}
+
+// Apply retourne une copie de opts complétée par les valeurs par défaut du schéma
+// Permet d'appeler Scan avec nil (tests, appels internes) sans valeurs manquantes
+func (s Schema) Apply(opts Options) Options {
+	out := make(Options, len(s))
+	for _, o := range s {
+		if o.Default != nil {
+			out[o.Name] = o.Default
+		}
+	}
+	for name, value := range opts {
+		out[name] = value
+	}
+	return out
+}
+
+// ParseQuery lit les options depuis des query params (?ports=443,8443&types=A&types=MX)
+// Les listes acceptent les valeurs séparées par des virgules et/ou les paramètres répétés
+// Toute clé inconnue du schéma est refusée pour éviter les fautes de frappe silencieuses
+func (s Schema) ParseQuery(values url.Values) (Options, error) {
+	opts := make(Options)
+
+	// Clés triées → message d'erreur déterministe si plusieurs options sont invalides
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		o, ok := s.lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("option inconnue : %s", name)
+		}
+
+		// Découpe "a,b" et fusionne les paramètres répétés (?types=A&types=MX)
+		var raw []string
+		for _, v := range values[name] {
+			for _, part := range strings.Split(v, ",") {
+				if part = strings.TrimSpace(part); part != "" {
+					raw = append(raw, part)
+				}
+			}
+		}
+
+		value, err := o.parseStrings(raw)
+		if err != nil {
+			return nil, err
+		}
+		opts[name] = value
+	}
+
+	return s.validate(opts)
+}
+
+// parseStrings convertit des valeurs texte (query params) vers le type Go de l'option
+func (o Option) parseStrings(raw []string) (any, error) {
+	switch o.Type {
+	case OptionStrings:
+		return raw, nil
+	case OptionInts:
+		ints := make([]int, 0, len(raw))
+		for _, r := range raw {
+			n, err := strconv.Atoi(r)
+			if err != nil {
+				return nil, fmt.Errorf("option %s : %q n'est pas un entier", o.Name, r)
+			}
+			ints = append(ints, n)
+		}
+		return ints, nil
+	}
+
+	// Types scalaires : une seule valeur attendue
+	if len(raw) != 1 {
+		return nil, fmt.Errorf("option %s : une seule valeur attendue", o.Name)
+	}
+
+	switch o.Type {
+	case OptionInt:
+		n, err := strconv.Atoi(raw[0])
+		if err != nil {
+			return nil, fmt.Errorf("option %s : %q n'est pas un entier", o.Name, raw[0])
+		}
+		return n, nil
+	case OptionBool:
+		b, err := strconv.ParseBool(raw[0])
+		if err != nil {
+			return nil, fmt.Errorf("option %s : %q n'est pas un booléen", o.Name, raw[0])
+		}
+		return b, nil
+	}
+	return raw[0], nil
+}
+
+// ParseJSON lit les options depuis un objet JSON ({"ports": [443, 8443]})
+// raw vide ou "null" → aucune option (les défauts s'appliquent)
+func (s Schema) ParseJSON(raw json.RawMessage) (Options, error) {
+	opts := make(Options)
+	if len(raw) == 0 || string(raw) == "null" {
+		return s.validate(opts)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("options : objet JSON attendu")
+	}
+
+	for name, field := range fields {
+		o, ok := s.lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("option inconnue : %s", name)
+		}
+
+		// On désérialise directement dans le type Go attendu → json fait la vérification de type
+		var value any
+		var err error
+		switch o.Type {
+		case OptionInt:
+			var n int
+			err = json.Unmarshal(field, &n)
+			value = n
+		case OptionBool:
+			var b bool
+			err = json.Unmarshal(field, &b)
+			value = b
+		case OptionStrings:
+			var list []string
+			err = json.Unmarshal(field, &list)
+			value = list
+		case OptionInts:
+			var list []int
+			err = json.Unmarshal(field, &list)
+			value = list
+		default:
+			var str string
+			err = json.Unmarshal(field, &str)
+			value = str
+		}
+		if err != nil {
+			return nil, fmt.Errorf("option %s : type %s attendu", name, o.Type)
+		}
+		opts[name] = value
+	}
+
+	return s.validate(opts)
+}
+
+// validate vérifie les contraintes Enum / Min / Max / Validate puis applique les valeurs par défaut
+func (s Schema) validate(opts Options) (Options, error) {
+	for name, value := range opts {
+		o, _ := s.lookup(name)
+
+		var strs []string
+		var ints []int
+		switch v := value.(type) {
+		case string:
+			strs = []string{v}
+		case []string:
+			strs = v
+		case int:
+			ints = []int{v}
+		case []int:
+			ints = v
+		}
+
+		if len(o.Enum) > 0 {
+			for _, str := range strs {
+				if !slices.Contains(o.Enum, str) {
+					return nil, fmt.Errorf("option %s : %q non autorisé (valeurs possibles : %s)",
+						name, str, strings.Join(o.Enum, ", "))
+				}
+			}
+		}
+
+		for _, n := range ints {
+			if o.Min != nil && n < *o.Min {
+				return nil, fmt.Errorf("option %s : %d inférieur au minimum %d", name, n, *o.Min)
+			}
+			if o.Max != nil && n > *o.Max {
+				return nil, fmt.Errorf("option %s : %d supérieur au maximum %d", name, n, *o.Max)
+			}
+		}
+
+		if o.Validate != nil {
+			if err := o.Validate(value); err != nil {
+				return nil, fmt.Errorf("option %s : %w", name, err)
+			}
+		}
+	}
+	return s.Apply(opts), nil
+}
+
+// String retourne l'option name en string ("" si absente ou d'un autre type)
+func (o Options) String(name string) string {
+	v, _ := o[name].(string)
+	return v
+}
+
+// Int retourne l'option name en int (0 si absente ou d'un autre type)
+func (o Options) Int(name string) int {
+	v, _ := o[name].(int)
+	return v
+}
+
+// Bool retourne l'option name en bool (false si absente ou d'un autre type)
+func (o Options) Bool(name string) bool {
+	v, _ := o[name].(bool)
+	return v
+}
+
+// Strings retourne l'option name en []string (nil si absente ou d'un autre type)
+func (o Options) Strings(name string) []string {
+	v, _ := o[name].([]string)
+	return v
+}
+
+// Ints retourne l'option name en []int (nil si absente ou d'un autre type)
+func (o Options) Ints(name string) []int {
+	v, _ := o[name].([]int)
+	return v
+}
@@ -0,0 +1,86 @@
+package scanner
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testSchema — schéma couvrant chaque type d'option
+var testSchema = Schema{
+	{Name: "ports", Type: OptionInts, Default: []int{443}, Min: intPtr(1), Max: intPtr(65535)},
+	{Name: "types", Type: OptionStrings, Default: []string{"A"}, Enum: []string{"A", "MX"}},
+	{Name: "deep", Type: OptionBool, Default: false},
+	{Name: "path", Type: OptionString, Default: "/", Validate: func(v any) error {
+		if !strings.HasPrefix(v.(string), "/") {
+			return errors.New("doit commencer par /")
+		}
+		return nil
+	}},
+}
+
+// TestSchema_ParseQuery — listes séparées par virgules et paramètres répétés
+func TestSchema_ParseQuery(t *testing.T) {
+	values := url.Values{"ports": {"443,8443"}, "types": {"A", "MX"}, "deep": {"true"}}
+
+	opts, err := testSchema.ParseQuery(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := opts.Ints("ports"); !slices.Equal(got, []int{443, 8443}) {
+		t.Errorf("got ports %v, want [443 8443]", got)
+	}
+	if got := opts.Strings("types"); !slices.Equal(got, []string{"A", "MX"}) {
+		t.Errorf("got types %v, want [A MX]", got)
+	}
+	if !opts.Bool("deep") {
+		t.Errorf("got deep false, want true")
+	}
+	// path non fourni → valeur par défaut appliquée
+	if got := opts.String("path"); got != "/" {
+		t.Errorf("got path %q, want /", got)
+	}
+}
+
+// TestSchema_ParseQuery_Invalid — chaque entrée invalide doit être refusée
+// Table-driven test : un cas par ligne, même assertion pour tous
+func TestSchema_ParseQuery_Invalid(t *testing.T) {
+	cases := map[string]url.Values{
+		"option inconnue":     {"unknown": {"1"}},
+		"entier invalide":     {"ports": {"abc"}},
+		"sous le minimum":     {"ports": {"0"}},
+		"hors enum":           {"types": {"CNAME"}},
+		"booléen invalide":    {"deep": {"peut-être"}},
+		"plusieurs scalaires": {"path": {"/a", "/b"}},
+		"refusé par Validate": {"path": {"admin"}},
+	}
+
+	for name, values := range cases {
+		if _, err := testSchema.ParseQuery(values); err == nil {
+			t.Errorf("%s : expected error, got nil", name)
+		}
+	}
+}
+
+// TestSchema_ParseJSON — le type JSON doit correspondre au type de l'option
+func TestSchema_ParseJSON(t *testing.T) {
+	opts, err := testSchema.ParseJSON(json.RawMessage(`{"ports": [8443], "path": "/login"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := opts.Ints("ports"); !slices.Equal(got, []int{8443}) {
+		t.Errorf("got ports %v, want [8443]", got)
+	}
+	if got := opts.String("path"); got != "/login" {
+		t.Errorf("got path %q, want /login", got)
+	}
+
+	// "443" (string) au lieu de [443] → erreur de type
+	if _, err := testSchema.ParseJSON(json.RawMessage(`{"ports": "443"}`)); err == nil {
+		t.Errorf("expected type error, got nil")
+	}
+}
@@ -0,0 +1,97 @@
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// validName — un nom de scanner sert de segment d'URL (/scan/<name>) : minuscules, chiffres, "-" et "_"
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// reservedNames — noms déjà utilisés par des routes /scan/* qui ne sont pas des scanners
+var reservedNames = map[string]bool{"all": true}
+
+// Descriptor — description complète d'un scanner enregistré (réponse de GET /scanners)
+type Descriptor struct {
+	Name string `json:"name"`
+	Info
+	Options Schema `json:"options"`
+}
+
+// Registry — catalogue des scanners disponibles
+// Les routes HTTP, la doc Swagger et le menu du front sont générés à partir de lui :
+// ajouter un scanner = l'enregistrer ici, sans toucher à l'API
+type Registry struct {
+	mu       sync.RWMutex
+	scanners []Scanner          // Ordre d'enregistrement (ordre d'affichage)
+	byName   map[string]Scanner // Index pour Get
+}
+
+// NewRegistry crée un registre contenant les scanners fournis
+// Panic si deux scanners ont le même nom — erreur de programmation détectée au démarrage
+func NewRegistry(scanners ...Scanner) *Registry {
+	r := &Registry{byName: make(map[string]Scanner)}
+	for _, s := range scanners {
+		if err := r.Register(s); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// Register ajoute un scanner au registre
+// Erreur si le nom est invalide, réservé ou déjà pris
+func (r *Registry) Register(s Scanner) error {
+	name := s.Name()
+	if !validName.MatchString(name) {
+		return fmt.Errorf("nom de scanner invalide : %q", name)
+	}
+	if reservedNames[name] {
+		return fmt.Errorf("nom de scanner réservé : %q", name)
+	}
+
+	// Lock en écriture : Register peut être appelé pendant que des handlers lisent le registre
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.byName[name]; exists {
+		return fmt.Errorf("scanner déjà enregistré : %q", name)
+	}
+	r.scanners = append(r.scanners, s)
+	r.byName[name] = s
+	return nil
+}
+
+// Get retourne le scanner nommé name (ok = false s'il n'existe pas)
+func (r *Registry) Get(name string) (Scanner, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.byName[name]
+	return s, ok
+}
+
+// All retourne les scanners dans l'ordre d'enregistrement
+// Copie de la slice → l'appelant peut itérer sans tenir le lock
+func (r *Registry) All() []Scanner {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]Scanner(nil), r.scanners...)
+}
+
+// Len retourne le nombre de scanners enregistrés
+func (r *Registry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.scanners)
+}
+
+// Descriptors retourne nom, métadonnées et schéma d'options de chaque scanner
+func (r *Registry) Descriptors() []Descriptor {
+	scanners := r.All()
+	descriptors := make([]Descriptor, 0, len(scanners))
+	for _, s := range scanners {
+		descriptors = append(descriptors, Descriptor{Name: s.Name(), Info: s.Info(), Options: s.Schema()})
+	}
+	return descriptors
+}
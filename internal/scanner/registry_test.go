@@ -0,0 +1,54 @@
+package scanner
+
+import "testing"
+
+// namedScanner — faux scanner dont le nom est configurable (tests du registre)
+type namedScanner struct {
+	slowScanner
+	name string
+}
+
+func (n namedScanner) Name() string { return n.name }
+
+// TestRegistry_Register — ordre conservé, doublons et noms invalides refusés
+func TestRegistry_Register(t *testing.T) {
+	registry := NewRegistry(DNSScanner{}, SSLScanner{})
+
+	// Doublon → erreur
+	if err := registry.Register(DNSScanner{}); err == nil {
+		t.Errorf("expected error for duplicate scanner, got nil")
+	}
+
+	// "all" est réservé à la route /scan/all, "Bad Name" n'est pas un segment d'URL valide
+	for _, name := range []string{"all", "Bad Name", ""} {
+		if err := registry.Register(namedScanner{name: name}); err == nil {
+			t.Errorf("expected error for scanner name %q, got nil", name)
+		}
+	}
+
+	// L'ordre d'enregistrement est l'ordre d'affichage
+	all := registry.All()
+	if len(all) != 2 || all[0].Name() != "dns" || all[1].Name() != "ssl" {
+		t.Errorf("got %v, want [dns ssl]", all)
+	}
+
+	if _, ok := registry.Get("ssl"); !ok {
+		t.Errorf("expected ssl scanner to be registered")
+	}
+	if _, ok := registry.Get("unknown"); ok {
+		t.Errorf("expected unknown scanner to be missing")
+	}
+}
+
+// TestRegistry_Descriptors — chaque descripteur expose nom, version et options
+func TestRegistry_Descriptors(t *testing.T) {
+	descriptors := NewRegistry(SSLScanner{}).Descriptors()
+
+	if len(descriptors) != 1 {
+		t.Fatalf("got %d descriptors, want 1", len(descriptors))
+	}
+	d := descriptors[0]
+	if d.Name != "ssl" || d.Version == "" || len(d.Options) == 0 {
+		t.Errorf("got %+v, want ssl descriptor with version and options", d)
+	}
+}
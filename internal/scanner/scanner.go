@@ -1,8 +1,71 @@
 package scanner
 
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
 // Scanner définit le contrat que tous les scanners doivent implémenter
 // Toute struct ayant ces méthodes implémente automatiquement l'interface
+// ctx porte l'annulation (client déconnecté) et la deadline du scan :
+// un scanner doit s'arrêter dès que ctx.Done() est fermé et retourner ce qu'il a déjà trouvé
+// opts contient les options validées par Schema() — nil = valeurs par défaut
 type Scanner interface {
-	Scan(domain string) (string, error)
+	Scan(ctx context.Context, domain string, opts Options) (Result, error)
 	Name() string
+	Info() Info
+	Schema() Schema
+}
+
+// Info — métadonnées publiées par un scanner (GET /scanners, doc Swagger, menu du front)
+type Info struct {
+	Title       string `json:"title"`       // Libellé court (ex: "SSL/TLS")
+	Description string `json:"description"` // Ce que le scanner analyse
+	Version     string `json:"version"`     // Version du scanner — incrémentée quand ses findings changent
+}
+
+// FindingFunc reçoit les findings d'un scan au fur et à mesure (événements SSE "finding" des jobs)
+// Appelée depuis la goroutine du scanner : elle doit être rapide et sûre en concurrence
+// Un scanner qui échoue après coup a pu remonter des findings absents de son résultat : le résultat final fait foi
+type FindingFunc func(Finding)
+
+// findingFuncKey — clé de contexte du FindingFunc (type non exporté : pas de collision possible)
+type findingFuncKey struct{}
+
+// WithFindingFunc retourne un contexte dont les scanners remontent chaque finding à fn dès qu'ils le trouvent
+// Équivalent JS : un callback onFinding passé au scan, plutôt qu'une Promise résolue à la fin
+func WithFindingFunc(ctx context.Context, fn FindingFunc) context.Context {
+	return context.WithValue(ctx, findingFuncKey{}, fn)
+}
+
+// Run exécute un scanner avec un timeout optionnel (0 = pas de timeout propre)
+// - Deadline atteinte ou requête annulée → le résultat partiel est conservé et marqué Partial
+// - Panic dans le scanner → convertie en erreur au lieu de faire tomber le serveur
+func Run(ctx context.Context, s Scanner, domain string, opts Options, timeout time.Duration) (result Result, err error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		// cancel libère le timer même si le scan finit avant la deadline
+		defer cancel()
+	}
+
+	// recover() ne fonctionne que dans un defer — capte la panic et la transforme en erreur
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic dans le scanner %s: %v", s.Name(), r)
+		}
+	}()
+
+	result, err = s.Scan(ctx, domain, opts)
+
+	// ctx.Err() != nil → le scan a été coupé (timeout ou annulation) : ce qui a été trouvé reste valable
+	// On garde l'erreur du scanner si elle existe, sinon on signale l'interruption
+	if ctx.Err() != nil {
+		result.Partial = true
+		if err == nil {
+			err = fmt.Errorf("scan %s interrompu: %w", s.Name(), ctx.Err())
+		}
+	}
+	return result, err
 }
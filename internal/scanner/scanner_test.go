@@ -0,0 +1,85 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// slowScanner — faux scanner qui trouve un finding puis attend la fin du contexte
+// Simule une cible qui ne répond plus (ex: crt.sh surchargé)
+type slowScanner struct{}
+
+func (slowScanner) Name() string { return "slow" }
+
+func (slowScanner) Info() Info { return Info{Title: "Slow"} }
+
+func (slowScanner) Schema() Schema { return nil }
+
+func (slowScanner) Scan(ctx context.Context, domain string, opts Options) (Result, error) {
+	result := newResult(ctx, nil)
+	result.add(Finding{ID: "slow.first", Severity: SeverityInfo, Asset: domain})
+	<-ctx.Done()
+	return result, ctx.Err()
+}
+
+// panicScanner — faux scanner qui panic pour vérifier le recover de Run
+type panicScanner struct{}
+
+func (panicScanner) Name() string { return "panic" }
+
+func (panicScanner) Info() Info { return Info{Title: "Panic"} }
+
+func (panicScanner) Schema() Schema { return nil }
+
+func (panicScanner) Scan(ctx context.Context, domain string, opts Options) (Result, error) {
+	panic("boom")
+}
+
+// TestRun_Timeout — le timeout coupe le scan et le résultat partiel est conservé
+func TestRun_Timeout(t *testing.T) {
+	result, err := Run(context.Background(), slowScanner{}, "example.com", nil, 20*time.Millisecond)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want context.DeadlineExceeded", err)
+	}
+	if !result.Partial {
+		t.Errorf("expected partial result")
+	}
+	// Le finding trouvé avant la deadline doit être retourné
+	if len(result.Findings) != 1 {
+		t.Errorf("got %d findings, want 1", len(result.Findings))
+	}
+}
+
+// TestRun_FindingFunc — le finding est remonté pendant le scan, avant que le scanner ne rende la main
+func TestRun_FindingFunc(t *testing.T) {
+	found := make(chan Finding, 1)
+	ctx, cancel := context.WithCancel(WithFindingFunc(context.Background(), func(f Finding) { found <- f }))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = Run(ctx, slowScanner{}, "example.com", nil, 0)
+	}()
+
+	select {
+	case f := <-found:
+		if f.ID != "slow.first" {
+			t.Errorf("got %s, want slow.first", f.ID)
+		}
+	case <-time.After(time.Second):
+		t.Error("finding not reported while the scanner was running")
+	}
+	cancel()
+	<-done
+}
+
+// TestRun_Panic — une panic du scanner devient une erreur
+func TestRun_Panic(t *testing.T) {
+	_, err := Run(context.Background(), panicScanner{}, "example.com", nil, 0)
+
+	if err == nil {
+		t.Errorf("expected error from panicking scanner, got nil")
+	}
+}
@@ -1,50 +1,390 @@
 package scanner
 
 import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 )
 
+// sensitiveMaxBody — octets lus par réponse : assez pour les signatures et l'empreinte des pages d'erreur
+const sensitiveMaxBody = 64 << 10
+
+// soft404Probes — chemins aléatoires demandés avant le scan, un par forme de chemin
+// Un serveur peut traiter différemment fichiers, fichiers cachés, dossiers et scripts : chaque forme a sa page d'erreur
+var soft404Probes = []string{"%s", ".%s", "%s/", "%s.php"}
+
+// sensitivePath — chemin testé, sa liste d'origine et ce qui prouve son exposition
+type sensitivePath struct {
+	path        string
+	category    string
+	title       string // Titre de la liste (ex: "Secrets et fichiers d'environnement")
+	severity    Severity
+	remediation string
+	sig         *signature // nil = une réponse 200 distincte de la page d'erreur suffit
+}
+
+// SensitiveReport — données brutes du scanner Sensitive
+type SensitiveReport struct {
+	Exposed   []ExposedFile  `json:"exposed"`
+	Tested    int            `json:"tested"`    // Nombre de chemins demandés
+	Baselines []PageBaseline `json:"baselines"` // Réponses aux chemins aléatoires (pages d'erreur)
+	Soft404   bool           `json:"soft_404"`  // Le serveur répond 200 à un chemin inexistant
+	Errors    []string       `json:"errors,omitempty"`
+}
+
+// ExposedFile — fichier accessible publiquement
+type ExposedFile struct {
+	Path      string `json:"path"`
+	Category  string `json:"category"`
+	Status    string `json:"status"`
+	Size      int    `json:"size"`      // Octets lus (tronqué à 64 Kio)
+	Signature string `json:"signature"` // Contenu reconnu — vide si la seule preuve est une réponse distincte de la page d'erreur
+}
+
+// PageBaseline — empreinte de la réponse à un chemin qui n'existe pas
+type PageBaseline struct {
+	Path        string `json:"path"`
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int    `json:"size"`
+	Error       string `json:"error,omitempty"`
+	hash        [32]byte
+}
+
 // SensitiveScanner - Scanner pour la détection de fichiers sensibles exposés publiquement
-type SensitiveScanner struct{}
+type SensitiveScanner struct {
+	Client *http.Client // Client HTTP utilisé (nil = client par défaut)
+}
+
+// sensitiveOptions — options typées de SensitiveScanner
+type sensitiveOptions struct {
+	Paths       []sensitivePath // Chemins à tester : listes choisies puis chemins personnalisés
+	Concurrency int             // Requêtes simultanées
+	Rate        int             // Requêtes par seconde (0 = sans limite)
+}
 
 // Name retourne l'identifiant du scanner Sensitive
 func (d SensitiveScanner) Name() string { return "sensitive" }
 
-// Scan teste une liste de chemins sensibles via HTTP GET
-// Un status 200 signifie que le fichier est accessible publiquement → alerte de sécurité
-func (d SensitiveScanner) Scan(domain string) (string, error) {
+// Info retourne les métadonnées du scanner (GET /scanners)
+func (d SensitiveScanner) Info() Info {
+	return Info{
+		Title:       "Fichiers sensibles",
+		Description: "Teste des listes de chemins par catégorie (métadonnées Git/SVN, fichiers .env et secrets, configurations, sauvegardes, dumps SQL, interfaces d'administration, endpoints de debug) ; confirme chaque fichier par une signature de contenu et écarte les fausses pages 404 grâce à l'empreinte d'un chemin aléatoire",
+		Version:     "2.0.0",
+	}
+}
 
-	// Liste des chemins sensibles à tester
-	// .git/config → exposition du repo Git
-	// .env → secrets (clés API, mots de passe)
-	// .htaccess → configuration Apache
-	// robots.txt / sitemap.xml → fichiers normaux mais informatifs
-	// wp-config.php → configuration WordPress (accès BDD)
-	paths := []string{".git/config", ".env", ".htaccess", "robots.txt", "sitemap.xml", "wp-config.php"}
+// Schema publie les options acceptées par le scanner Sensitive
+func (d SensitiveScanner) Schema() Schema {
+	categories := sensitiveCategories()
+	return Schema{
+		{
+			Name:        "categories",
+			Type:        OptionStrings,
+			Description: "Listes de chemins à tester",
+			Default:     categories,
+			Enum:        categories,
+		},
+		{
+			Name:        "paths",
+			Type:        OptionStrings,
+			Description: "Chemins supplémentaires (relatifs à la racine du site)",
+		},
+		{
+			Name:        "concurrency",
+			Type:        OptionInt,
+			Description: "Nombre de requêtes simultanées",
+			Default:     10,
+			Min:         intPtr(1),
+			Max:         intPtr(50),
+		},
+		{
+			Name:        "rate",
+			Type:        OptionInt,
+			Description: "Requêtes par seconde au maximum (0 = sans limite)",
+			Default:     20,
+			Min:         intPtr(0),
+			Max:         intPtr(500),
+		},
+	}
+}
 
-	var result = ""
+// options convertit les Options génériques en sensitiveOptions
+// host remplace {domain} / {name} dans les listes ; un chemin personnalisé déjà présent dans une liste garde sa signature,
+// un chemin inconnu est classé "custom", de gravité medium
+func (d SensitiveScanner) options(opts Options, host string) sensitiveOptions {
+	opts = d.Schema().Apply(opts)
+	o := sensitiveOptions{Concurrency: max(opts.Int("concurrency"), 1), Rate: opts.Int("rate")}
 
-	for _, path := range paths {
-		resp, err := http.Get("https://" + domain + "/" + path)
-		if err != nil {
-			return "", fmt.Errorf("erreur https: %w", err)
+	seen := make(map[string]bool)
+	add := func(sp sensitivePath) {
+		if sp.path == "" || seen[sp.path] {
+			return
 		}
-
-		// StatusCode == 200 → fichier accessible publiquement
-		// resp.Status contient le code + texte (ex: "200 OK", "404 Not Found")
-		if resp.StatusCode == 200 {
-			result += path + " → " + resp.Status + "\n"
+		seen[sp.path] = true
+		o.Paths = append(o.Paths, sp)
+	}
+	categories := opts.Strings("categories")
+	var catalog []sensitivePath
+	for _, w := range sensitiveWordlists {
+		for _, e := range w.entries {
+			sp := sensitivePath{
+				path: expandPath(e.path, host), category: w.category, title: w.title,
+				severity: cmp.Or(e.severity, w.severity), remediation: w.remediation, sig: e.sig,
+			}
+			catalog = append(catalog, sp)
+			if slices.Contains(categories, w.category) {
+				add(sp)
+			}
+		}
+	}
+	for _, path := range opts.Strings("paths") {
+		path = strings.TrimPrefix(strings.TrimSpace(path), "/")
+		sp := sensitivePath{
+			path: path, category: "custom", title: "Chemin personnalisé",
+			severity: SeverityMedium, remediation: "Restreindre l'accès à ce fichier",
+		}
+		for _, known := range catalog {
+			if known.path == path {
+				sp = known
+			}
 		}
-		// Ferme le body à chaque itération (pas de defer dans une boucle)
-		// defer s'exécute à la fin de la FONCTION, pas de l'itération → fuite de ressources
-		_ = resp.Body.Close()
+		add(sp)
 	}
+	return o
+}
+
+// sensitiveResponse — réponse lue pour un chemin
+type sensitiveResponse struct {
+	status      int
+	statusText  string
+	contentType string
+	finalPath   string // Chemin après redirections
+	body        []byte
+	hash        [32]byte // Empreinte du contenu, chemin demandé retiré (les pages d'erreur le recopient souvent)
+	err         error
+}
 
-	// Si aucun fichier sensible trouvé, retourner un message explicite
-	if result == "" {
-		return "Aucun fichier sensible trouvé", nil
+// Scan teste chaque chemin des listes choisies via HTTP GET
+// Un fichier n'est signalé que si la réponse est un 200, qu'elle diffère de la page d'erreur du site (soft-404)
+// et, quand la liste en fournit une, qu'elle porte la signature du fichier (ex: [core] dans .git/config)
+// Une requête en échec est notée dans le rapport sans arrêter le scan — erreur seulement si rien ne répond
+// Si le contexte expire en cours de route, les chemins déjà testés sont retournés (résultat partiel)
+func (d SensitiveScanner) Scan(ctx context.Context, domain string, opts Options) (Result, error) {
+	host := domain
+	if h, _, err := net.SplitHostPort(domain); err == nil {
+		host = h
 	}
+	o := d.options(opts, strings.ToLower(host))
+	base := "https://" + domain + "/"
+
+	// Slice vide (pas nil) → sérialisée en [] et non null si rien n'est exposé
+	report := &SensitiveReport{Exposed: []ExposedFile{}, Tested: len(o.Paths)}
+	result := newResult(ctx, report)
 
+	// Empreinte des pages d'erreur : un chemin aléatoire n'existe pas, sa réponse est celle d'un fichier absent
+	token := strings.ToLower(rand.Text())
+	answered := false
+	var lastErr error
+	for _, probe := range soft404Probes {
+		path := fmt.Sprintf(probe, token)
+		r := d.fetch(ctx, base, path)
+		b := PageBaseline{Path: "/" + path, Status: r.status, ContentType: r.contentType, Size: len(r.body), hash: r.hash}
+		if r.err != nil {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			b.Error = r.err.Error()
+			lastErr = r.err
+		} else {
+			answered = true
+			report.Soft404 = report.Soft404 || r.status == http.StatusOK
+		}
+		report.Baselines = append(report.Baselines, b)
+	}
+	if !answered {
+		return Result{}, fmt.Errorf("erreur de sensitive: %w", lastErr)
+	}
+
+	responses := d.fetchAll(ctx, base, o)
+	if ctx.Err() != nil {
+		// Les chemins déjà testés sont conservés
+		for i, sp := range o.Paths {
+			if responses[i].err == nil && responses[i].status != 0 {
+				judgeSensitive(&result, report, base, sp, responses[i])
+			}
+		}
+		return result, ctx.Err()
+	}
+	for i, sp := range o.Paths {
+		if r := responses[i]; r.err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("/%s : %v", sp.path, r.err))
+			continue
+		}
+		judgeSensitive(&result, report, base, sp, responses[i])
+	}
 	return result, nil
 }
+
+// fetchAll demande chaque chemin avec au plus o.Concurrency requêtes simultanées et o.Rate requêtes par seconde
+// responses[i] correspond à o.Paths[i] — l'ordre du rapport ne dépend pas de l'ordre d'arrivée
+func (d SensitiveScanner) fetchAll(ctx context.Context, base string, o sensitiveOptions) []sensitiveResponse {
+	responses := make([]sensitiveResponse, len(o.Paths))
+
+	// Limiteur de débit : chaque requête attend un top du ticker
+	var tick <-chan time.Time
+	if o.Rate > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(o.Rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	// File de travail partagée par o.Concurrency workers
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(o.Concurrency, len(o.Paths)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if tick != nil {
+					select {
+					case <-tick:
+					case <-ctx.Done():
+						continue
+					}
+				}
+				responses[i] = d.fetch(ctx, base, o.Paths[i].path)
+			}
+		}()
+	}
+	for i := range o.Paths {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return responses
+}
+
+// fetch demande base + path et lit le début de la réponse
+func (d SensitiveScanner) fetch(ctx context.Context, base, path string) sensitiveResponse {
+	resp, err := get(ctx, d.Client, base+path)
+	if err != nil {
+		return sensitiveResponse{err: err}
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, sensitiveMaxBody))
+	if err != nil {
+		return sensitiveResponse{err: err}
+	}
+	// Le chemin demandé, recopié par la page d'erreur (« /xyz introuvable »), est retiré de l'empreinte
+	return sensitiveResponse{
+		status:      resp.StatusCode,
+		statusText:  resp.Status,
+		contentType: resp.Header.Get("Content-Type"),
+		finalPath:   strings.TrimPrefix(resp.Request.URL.Path, "/"),
+		body:        body,
+		hash:        sha256.Sum256(stripPath(body, path)),
+	}
+}
+
+// stripPath retire de body chaque occurrence de path, casse ignorée, avec le "/" qui la précède
+// Comparaison octet par octet plutôt qu'une regexp : appelée pour chaque chemin testé, sur jusqu'à 64 Kio
+func stripPath(body []byte, path string) []byte {
+	p := []byte(path)
+	if len(p) == 0 {
+		return body
+	}
+	out := make([]byte, 0, len(body))
+	kept := false // body[i-1] a été recopié dans out (et n'appartient donc pas à une occurrence retirée)
+	for i := 0; i < len(body); {
+		if i+len(p) <= len(body) && bytes.EqualFold(body[i:i+len(p)], p) {
+			if kept && body[i-1] == '/' {
+				out = out[:len(out)-1]
+			}
+			i += len(p)
+			kept = false
+			continue
+		}
+		out = append(out, body[i])
+		i++
+		kept = true
+	}
+	return out
+}
+
+// judgeSensitive décide si la réponse prouve l'exposition du fichier et ajoute le finding
+func judgeSensitive(result *Result, report *SensitiveReport, base string, sp sensitivePath, r sensitiveResponse) {
+	if r.status != http.StatusOK {
+		return
+	}
+	url := base + sp.path
+	evidence := "GET " + url + " → " + r.statusText
+	file := ExposedFile{Path: sp.path, Category: sp.category, Status: r.statusText, Size: len(r.body)}
+	switch {
+	case sp.sig != nil:
+		// Un fichier signé se reconnaît à son contenu : seule une copie exacte de la page d'erreur est écartée
+		if errorPage(report.Baselines, r, true) || !sp.sig.match(r.body) {
+			return
+		}
+		file.Signature = sp.sig.String()
+		evidence += " | signature : " + file.Signature
+	case r.finalPath != sp.path:
+		// Redirigé ailleurs (page de connexion, accueil) : le fichier lui-même n'est pas servi
+		return
+	case errorPage(report.Baselines, r, false):
+		return
+	default:
+		evidence += fmt.Sprintf(" | %d octets, distinct de la page d'erreur", len(r.body))
+	}
+
+	report.Exposed = append(report.Exposed, file)
+	result.add(Finding{
+		ID:          "sensitive.exposed",
+		Title:       "Fichier " + sp.path + " accessible",
+		Severity:    sp.severity,
+		Category:    "exposure",
+		Evidence:    evidence + " | " + sp.title,
+		Asset:       url,
+		Remediation: sp.remediation,
+	})
+}
+
+// errorPage indique si la réponse ressemble à la page d'erreur d'un chemin aléatoire (soft-404)
+// Même contenu une fois le chemin retiré ; sauf exact, aussi même type et taille à 5 % près
+func errorPage(baselines []PageBaseline, r sensitiveResponse, exact bool) bool {
+	for _, b := range baselines {
+		if b.Error != "" || b.Status != r.status {
+			continue
+		}
+		if b.hash == r.hash {
+			return true
+		}
+		diff := b.Size - len(r.body)
+		if diff < 0 {
+			diff = -diff
+		}
+		if !exact && b.ContentType == r.contentType && diff <= max(32, b.Size/20) {
+			return true
+		}
+	}
+	return false
+}
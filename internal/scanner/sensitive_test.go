@@ -1,6 +1,15 @@
 package scanner
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
 
 // TestSensitiveScanner_Name vérifie que le scanner retourne le bon identifiant
 func TestSensitiveScanner_Name(t *testing.T) {
@@ -14,34 +23,225 @@ func TestSensitiveScanner_Name(t *testing.T) {
 // TestSensitiveScanner_Scan — Happy path : vérifie que le scan de fichiers sensibles fonctionne
 // google.com ne devrait pas exposer de fichiers sensibles → résultat = "Aucun fichier sensible trouvé"
 func TestSensitiveScanner_Scan(t *testing.T) {
-	// SensitiveScanner teste 6 chemins (.git/config, .env, .htaccess, robots.txt, sitemap.xml, wp-config.php)
-	// via http.Get sur chaque chemin, vérifie si status == 200
-	result, err := SensitiveScanner{}.Scan("google.com")
+	// SensitiveScanner demande des chemins aléatoires (pages d'erreur), puis chaque chemin des listes intégrées
+	result, err := SensitiveScanner{}.Scan(context.Background(), "google.com", nil)
 
 	// Les requêtes HTTP doivent aboutir (même si le fichier n'existe pas → 404)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	// google.com retourne "Aucun fichier sensible trouvé" ou la liste des fichiers exposés
-	// Dans les deux cas, result n'est jamais vide
-	if result == "" {
-		t.Errorf("got empty result, want scan report")
+	// google.com peut n'exposer aucun fichier (0 finding) — mais Data contient toujours
+	// la liste des fichiers exposés, éventuellement vide
+	if result.Data == nil {
+		t.Errorf("got nil data, want scan report")
 	}
 }
 
 // TestSensitiveScanner_Scan_InvalidDomain — Error path : un domaine invalide fait échouer http.Get
-// "false_url" → résolution DNS échoue dès le premier http.Get → err != nil
+// "false_url" → résolution DNS échoue pour chaque requête → aucune réponse → err != nil
 func TestSensitiveScanner_Scan_InvalidDomain(t *testing.T) {
-	result, err := SensitiveScanner{}.Scan("false_url")
+	result, err := SensitiveScanner{}.Scan(context.Background(), "false_url", nil)
 
 	// http.Get échoue car "false_url" ne résout pas en IP
 	if err == nil {
 		t.Errorf("expected error for invalid domain, got nil")
 	}
 
-	// Contrat : en cas d'erreur, result doit être "" (string vide)
-	if result != "" {
-		t.Errorf("expected empty result, got %s", result)
+	// Contrat : en cas d'erreur, result ne contient aucun finding
+	if len(result.Findings) != 0 {
+		t.Errorf("expected no findings, got %v", result.Findings)
+	}
+}
+
+// TestSensitiveScanner_Scan_Local — seul .env répond 200 sur le serveur de test
+func TestSensitiveScanner_Scan_Local(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.env" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("DB_PASSWORD=secret"))
+	}))
+	defer srv.Close()
+
+	scanner := SensitiveScanner{Client: srv.Client()}
+	result, err := scanner.Scan(context.Background(), srv.Listener.Addr().String(), Options{"rate": 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Un seul fichier exposé → un seul finding, de sévérité critical
+	if len(result.Findings) != 1 {
+		t.Fatalf("got %d findings, want 1", len(result.Findings))
+	}
+	if result.Findings[0].Severity != SeverityCritical {
+		t.Errorf("got %s, want critical", result.Findings[0].Severity)
+	}
+}
+
+// TestSensitiveScanner_Scan_Canceled — contexte annulé : le scan s'arrête et retourne ctx.Err()
+func TestSensitiveScanner_Scan_Canceled(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	// cancel() avant le scan → la première requête échoue immédiatement
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := SensitiveScanner{Client: srv.Client()}.Scan(ctx, srv.Listener.Addr().String(), nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+}
+
+// TestSensitiveScanner_Scan_Soft404 — le serveur répond 200 partout : seuls les fichiers signés sont retenus
+func TestSensitiveScanner_Scan_Soft404(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.git/config":
+			_, _ = w.Write([]byte("[core]\n\trepositoryformatversion = 0\n"))
+		case "/admin/":
+			_, _ = w.Write([]byte("<html><h1>Tableau de bord</h1><form>" + strings.Repeat("<input>", 200) + "</form></html>"))
+		default:
+			// Page d'erreur servie en 200, qui recopie le chemin demandé
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<html>Page " + r.URL.Path + " introuvable</html>"))
+		}
+	}))
+	defer srv.Close()
+
+	result, err := SensitiveScanner{Client: srv.Client()}.Scan(context.Background(), srv.Listener.Addr().String(), Options{"rate": 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := result.Data.(*SensitiveReport)
+	if !report.Soft404 {
+		t.Error("got soft_404 false, want true")
+	}
+	var paths []string
+	for _, f := range report.Exposed {
+		paths = append(paths, f.Path)
+	}
+	if strings.Join(paths, ",") != ".git/config,admin/" {
+		t.Fatalf("got exposed %v, want [.git/config admin/]", paths)
+	}
+	if report.Exposed[0].Signature == "" || report.Exposed[1].Signature != "" {
+		t.Errorf("got %+v, want .git/config confirmed by its signature, admin/ by its content only", report.Exposed)
+	}
+	if result.Findings[0].Severity != SeverityHigh || result.Findings[1].Severity != SeverityLow {
+		t.Errorf("got severities %s / %s, want high / low", result.Findings[0].Severity, result.Findings[1].Severity)
+	}
+}
+
+// TestStripPath — le chemin recopié par une page d'erreur est retiré, casse ignorée, avec son "/" initial
+func TestStripPath(t *testing.T) {
+	tests := []struct {
+		body, path, want string
+	}{
+		{"<p>Page /.env introuvable</p>", ".env", "<p>Page  introuvable</p>"},
+		{"<p>/Admin/ et admin/</p>", "admin/", "<p> et </p>"},
+		{"a.b, aXb", "a.b", ", aXb"}, // Le "." est littéral
+		{"//x", "x", "/"},
+		{"sans rapport", ".git/config", "sans rapport"},
+	}
+	for _, tt := range tests {
+		if got := string(stripPath([]byte(tt.body), tt.path)); got != tt.want {
+			t.Errorf("stripPath(%q, %q) = %q, want %q", tt.body, tt.path, got, tt.want)
+		}
+	}
+}
+
+// TestSensitiveScanner_Scan_Signature — un 200 sans la signature attendue n'est pas une exposition
+// .env servi par une application qui renvoie sa page d'accueil HTML, wp-config.php interprété par PHP (page vide)
+func TestSensitiveScanner_Scan_Signature(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.env":
+			_, _ = w.Write([]byte("<!doctype html><title>Accueil</title>"))
+		case "/wp-config.php":
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	result, err := SensitiveScanner{Client: srv.Client()}.Scan(context.Background(), srv.Listener.Addr().String(), Options{"rate": 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Findings) != 0 {
+		t.Errorf("got %v, want no findings", result.Findings)
+	}
+}
+
+// TestSensitiveScanner_Scan_PathErrors — une connexion coupée sur un chemin n'arrête pas le scan
+func TestSensitiveScanner_Scan_PathErrors(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.git/HEAD":
+			// Connexion fermée sans réponse
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			_ = conn.Close()
+		case "/dump.sql":
+			_, _ = w.Write([]byte("-- MySQL dump 10.13\nCREATE TABLE users (id int);"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	opts := Options{"categories": []string{"vcs", "database"}, "rate": 0}
+	result, err := SensitiveScanner{Client: srv.Client()}.Scan(context.Background(), srv.Listener.Addr().String(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := result.Data.(*SensitiveReport)
+	if len(report.Errors) != 1 || !strings.HasPrefix(report.Errors[0], "/.git/HEAD") {
+		t.Errorf("got errors %v, want /.git/HEAD only", report.Errors)
+	}
+	if len(result.Findings) != 1 || result.Findings[0].Severity != SeverityCritical {
+		t.Errorf("got %v, want dump.sql critical", result.Findings)
+	}
+}
+
+// TestSensitiveScanner_Scan_Concurrency — jamais plus de concurrency requêtes en vol
+func TestSensitiveScanner_Scan_Concurrency(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	opts := Options{"categories": []string{"env"}, "concurrency": 3, "rate": 0}
+	if _, err := (SensitiveScanner{Client: srv.Client()}).Scan(context.Background(), srv.Listener.Addr().String(), opts); err != nil {
+		t.Fatal(err)
+	}
+	if got := peak.Load(); got > 3 || got < 2 {
+		t.Errorf("got %d concurrent requests, want 2 or 3", got)
+	}
+}
+
+// TestSensitiveScanner_Scan_Rate — rate requêtes par seconde au plus : 13 chemins à 100/s prennent au moins 120 ms
+func TestSensitiveScanner_Scan_Rate(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	start := time.Now()
+	opts := Options{"categories": []string{"vcs"}, "rate": 100}
+	if _, err := (SensitiveScanner{Client: srv.Client()}).Scan(context.Background(), srv.Listener.Addr().String(), opts); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 120*time.Millisecond {
+		t.Errorf("got %s, want at least 120ms", elapsed)
 	}
 }
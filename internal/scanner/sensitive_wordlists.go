@@ -0,0 +1,254 @@
+package scanner
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// signature — contenu qui prouve que la réponse est bien le fichier recherché, et pas une page d'erreur servie en 200
+type signature struct {
+	prefix  string         // Octets de début de fichier (formats binaires : zip, gzip, SQLite...)
+	pattern *regexp.Regexp // Motif recherché dans le contenu (formats texte)
+}
+
+// textSignature — motif recherché n'importe où dans le contenu
+func textSignature(expr string) *signature {
+	return &signature{pattern: regexp.MustCompile(expr)}
+}
+
+// magicSignature — octets par lesquels commence le fichier (« nombre magique »)
+func magicSignature(prefix string) *signature {
+	return &signature{prefix: prefix}
+}
+
+// match indique si body correspond à la signature
+func (s *signature) match(body []byte) bool {
+	if s.prefix != "" {
+		return bytes.HasPrefix(body, []byte(s.prefix))
+	}
+	return s.pattern.Match(body)
+}
+
+// String retourne la signature telle qu'affichée dans les preuves
+func (s *signature) String() string {
+	if s.prefix != "" {
+		return "début " + strconv.Quote(s.prefix)
+	}
+	return s.pattern.String()
+}
+
+// Signatures partagées par plusieurs entrées
+var (
+	sigEnv      = textSignature(`(?m)^\s*(export\s+)?[A-Z][A-Z0-9_]*\s*=`)
+	sigPHP      = textSignature(`<\?php`)
+	sigWPConfig = textSignature(`DB_PASSWORD|DB_NAME`)
+	sigSQL      = textSignature(`(?i)(CREATE TABLE|INSERT INTO|-- MySQL dump|PostgreSQL database dump)`)
+	sigSQLite   = magicSignature("SQLite format 3\x00")
+	sigZip      = magicSignature("PK\x03\x04")
+	sigGzip     = magicSignature("\x1f\x8b")
+	sigRar      = magicSignature("Rar!")
+	sigVimSwap  = magicSignature("b0VIM")
+	sigPHPInfo  = textSignature(`(?i)<title>phpinfo\(\)|PHP Version \d`)
+	sigPMA      = textSignature(`pma_username|(?i)<title>phpMyAdmin`)
+)
+
+// sensitiveEntry — chemin d'une liste ; sans signature, une réponse 200 distincte de la page d'erreur suffit
+// {domain} et {name} sont remplacés par le domaine (example.com) et son premier label (example)
+type sensitiveEntry struct {
+	path     string
+	sig      *signature
+	severity Severity // Vide = gravité de la liste
+}
+
+// sensitiveWordlist — famille de fichiers sensibles : gravité et correction communes
+type sensitiveWordlist struct {
+	category    string
+	title       string
+	severity    Severity
+	remediation string
+	entries     []sensitiveEntry
+}
+
+// sensitiveWordlists — listes intégrées, dans l'ordre du rapport
+var sensitiveWordlists = []sensitiveWordlist{
+	{
+		category: "vcs", title: "Métadonnées de gestion de versions", severity: SeverityHigh,
+		remediation: "Bloquer l'accès aux dossiers .git, .svn, .hg côté serveur web — l'historique complet du code se reconstruit depuis ces fichiers",
+		entries: []sensitiveEntry{
+			{".git/config", textSignature(`(?m)^\[core\]`), ""},
+			{".git/HEAD", textSignature(`\Aref: refs/`), ""},
+			{".git/index", magicSignature("DIRC"), ""},
+			{".git/logs/HEAD", textSignature(`(?m)^[0-9a-f]{40} [0-9a-f]{40} `), ""},
+			{".git/packed-refs", textSignature(`# pack-refs with:`), ""},
+			{".git/FETCH_HEAD", textSignature(`(?m)^[0-9a-f]{40}\s`), ""},
+			{".svn/entries", textSignature(`\A(\d+\s|<\?xml)`), ""},
+			{".svn/wc.db", sigSQLite, ""},
+			{".hg/hgrc", textSignature(`(?m)^\[(paths|ui)\]`), ""},
+			{".hg/requires", textSignature(`(?m)^(revlogv1|store|fncache)$`), ""},
+			{".bzr/branch-format", textSignature(`Bazaar`), ""},
+			{"CVS/Root", textSignature(`\A(:\w+:|/)`), ""},
+			{"CVS/Entries", textSignature(`(?m)^(D?/[^/]+/)`), ""},
+		},
+	},
+	{
+		category: "env", title: "Secrets et fichiers d'environnement", severity: SeverityCritical,
+		remediation: "Retirer le fichier du webroot et changer les secrets exposés",
+		entries: []sensitiveEntry{
+			{".env", sigEnv, ""},
+			{".env.local", sigEnv, ""},
+			{".env.production", sigEnv, ""},
+			{".env.prod", sigEnv, ""},
+			{".env.staging", sigEnv, ""},
+			{".env.development", sigEnv, ""},
+			{".env.dev", sigEnv, ""},
+			{".env.test", sigEnv, ""},
+			{".env.backup", sigEnv, ""},
+			{".env.bak", sigEnv, ""},
+			{".env.old", sigEnv, ""},
+			{".env.save", sigEnv, ""},
+			{".env~", sigEnv, ""},
+			{"api/.env", sigEnv, ""},
+			{"app/.env", sigEnv, ""},
+			{"backend/.env", sigEnv, ""},
+			{".npmrc", textSignature(`_authToken|_auth\s*=|//registry`), ""},
+			{".pypirc", textSignature(`(?m)^\[(pypi|distutils)\]`), ""},
+			{".aws/credentials", textSignature(`aws_access_key_id`), ""},
+			{".docker/config.json", textSignature(`"auths"`), ""},
+			{".netrc", textSignature(`(?m)^\s*machine\s+\S+`), ""},
+			{".ssh/id_rsa", textSignature(`-----BEGIN (RSA |OPENSSH )?PRIVATE KEY-----`), ""},
+			{"id_rsa", textSignature(`-----BEGIN (RSA |OPENSSH )?PRIVATE KEY-----`), ""},
+			{".vscode/sftp.json", textSignature(`"(password|privateKeyPath)"`), ""},
+		},
+	},
+	{
+		category: "config", title: "Fichiers de configuration", severity: SeverityHigh,
+		remediation: "Sortir le fichier du webroot, ou interdire sa lecture (et vérifier que PHP l'interprète au lieu de le servir)",
+		entries: []sensitiveEntry{
+			// Interprété par PHP, wp-config.php répond une page vide : seule une source servie brute porte la signature
+			{"wp-config.php", sigWPConfig, SeverityCritical},
+			{"config.php", sigPHP, SeverityCritical},
+			{"configuration.php", textSignature(`class JConfig`), SeverityCritical},
+			{"app/etc/env.php", textSignature(`'crypt'|'connection'`), SeverityCritical},
+			{"config/database.yml", textSignature(`(?m)^\s*adapter:`), ""},
+			{"config/secrets.yml", textSignature(`secret_key_base`), SeverityCritical},
+			{"appsettings.json", textSignature(`"ConnectionStrings"`), ""},
+			{"web.config", textSignature(`<configuration`), SeverityMedium},
+			{".htaccess", textSignature(`(?i)(RewriteEngine|RewriteRule|Require (all|valid)|Deny from|AuthType|<IfModule)`), SeverityMedium},
+			{".htpasswd", textSignature(`(?m)^[^:\s]+:(\$apr1\$|\$2[aby]\$|\{SHA\})`), ""},
+			{"docker-compose.yml", textSignature(`(?m)^services:`), SeverityMedium},
+			{"Dockerfile", textSignature(`(?m)^FROM\s+\S+`), SeverityLow},
+			{"composer.json", textSignature(`"require"`), SeverityLow},
+			{"package.json", textSignature(`"dependencies"`), SeverityLow},
+		},
+	},
+	{
+		category: "backup", title: "Sauvegardes et archives", severity: SeverityHigh,
+		remediation: "Supprimer les sauvegardes du webroot et les stocker hors du serveur web",
+		entries: []sensitiveEntry{
+			{"backup.zip", sigZip, ""},
+			{"backup.tar.gz", sigGzip, ""},
+			{"backup.tgz", sigGzip, ""},
+			{"backup.rar", sigRar, ""},
+			{"site.zip", sigZip, ""},
+			{"www.zip", sigZip, ""},
+			{"web.zip", sigZip, ""},
+			{"html.zip", sigZip, ""},
+			{"public_html.zip", sigZip, ""},
+			{"archive.zip", sigZip, ""},
+			{"{domain}.zip", sigZip, ""},
+			{"{domain}.tar.gz", sigGzip, ""},
+			{"{name}.zip", sigZip, ""},
+			{"{name}.tar.gz", sigGzip, ""},
+			{"wp-config.php.bak", sigWPConfig, SeverityCritical},
+			{"wp-config.php.old", sigWPConfig, SeverityCritical},
+			{"wp-config.php.save", sigWPConfig, SeverityCritical},
+			{"wp-config.php~", sigWPConfig, SeverityCritical},
+			{".wp-config.php.swp", sigVimSwap, SeverityCritical},
+			{"config.php.bak", sigPHP, SeverityCritical},
+			{"config.php~", sigPHP, SeverityCritical},
+			{"index.php.bak", sigPHP, ""},
+			{"index.php~", sigPHP, ""},
+			{".index.php.swp", sigVimSwap, ""},
+		},
+	},
+	{
+		category: "database", title: "Dumps de base de données", severity: SeverityCritical,
+		remediation: "Supprimer le dump du webroot et considérer les données (comptes, mots de passe) comme divulguées",
+		entries: []sensitiveEntry{
+			{"dump.sql", sigSQL, ""},
+			{"db.sql", sigSQL, ""},
+			{"database.sql", sigSQL, ""},
+			{"backup.sql", sigSQL, ""},
+			{"mysql.sql", sigSQL, ""},
+			{"data.sql", sigSQL, ""},
+			{"users.sql", sigSQL, ""},
+			{"{name}.sql", sigSQL, ""},
+			{"{domain}.sql", sigSQL, ""},
+			{"dump.sql.gz", sigGzip, ""},
+			{"db.sql.gz", sigGzip, ""},
+			{"backup.sql.gz", sigGzip, ""},
+			{"database.sqlite", sigSQLite, ""},
+			{"db.sqlite", sigSQLite, ""},
+			{"db.sqlite3", sigSQLite, ""},
+			{"data.db", sigSQLite, ""},
+		},
+	},
+	{
+		category: "admin", title: "Interfaces d'administration", severity: SeverityMedium,
+		remediation: "Restreindre l'interface d'administration (VPN, liste d'IP, authentification forte) ou la retirer",
+		entries: []sensitiveEntry{
+			{"phpmyadmin/", sigPMA, ""},
+			{"phpMyAdmin/", sigPMA, ""},
+			{"pma/", sigPMA, ""},
+			{"adminer.php", textSignature(`(?i)<title>[^<]*Adminer|adminer\.org`), SeverityHigh},
+			{"manager/html", textSignature(`(?i)Tomcat Web Application Manager`), SeverityHigh},
+			{"solr/", textSignature(`(?i)<title>Solr Admin`), ""},
+			{"_plugin/kibana/", textSignature(`kbn-injected-metadata|(?i)<title>Kibana`), ""},
+			{"jenkins/", textSignature(`(?i)Dashboard \[Jenkins\]`), ""},
+			{"administrator/", textSignature(`(?i)content="Joomla`), SeverityLow},
+			{"wp-login.php", textSignature(`user_login`), SeverityLow},
+			// Panneaux génériques : pas de signature possible, une réponse distincte de la page d'erreur suffit
+			{"admin/", nil, SeverityLow},
+			{"admin.php", nil, SeverityLow},
+			{"backend/", nil, SeverityLow},
+		},
+	},
+	{
+		category: "debug", title: "Endpoints de debug", severity: SeverityHigh,
+		remediation: "Désactiver l'endpoint en production ou le réserver au réseau interne",
+		entries: []sensitiveEntry{
+			{"phpinfo.php", sigPHPInfo, ""},
+			{"info.php", sigPHPInfo, ""},
+			{"server-status", textSignature(`Apache Server Status`), SeverityMedium},
+			{"server-info", textSignature(`Apache Server Information`), ""},
+			{"nginx_status", textSignature(`Active connections:`), SeverityLow},
+			{"actuator", textSignature(`"_links"`), SeverityMedium},
+			{"actuator/env", textSignature(`"propertySources"|"activeProfiles"`), ""},
+			{"actuator/heapdump", magicSignature("JAVA PROFILE"), SeverityCritical},
+			{"debug/pprof/", textSignature(`Types of profiles available`), ""},
+			{"debug/vars", textSignature(`"memstats"`), SeverityMedium},
+			{"_profiler/", textSignature(`(?i)Symfony Profiler`), ""},
+			{"telescope", textSignature(`window\.Telescope`), ""},
+			{"console", textSignature(`__debugger__|Interactive Console`), SeverityCritical},
+			{"elmah.axd", textSignature(`(?i)Error Log for`), ""},
+			{"trace.axd", textSignature(`(?i)Application Trace`), ""},
+		},
+	},
+}
+
+// sensitiveCategories — noms des listes, pour l'option categories
+func sensitiveCategories() []string {
+	names := make([]string, len(sensitiveWordlists))
+	for i, w := range sensitiveWordlists {
+		names[i] = w.category
+	}
+	return names
+}
+
+// expandPath remplace {domain} et {name} par le domaine scanné et son premier label
+func expandPath(path, host string) string {
+	name, _, _ := strings.Cut(host, ".")
+	return strings.NewReplacer("{domain}", host, "{name}", name).Replace(path)
+}
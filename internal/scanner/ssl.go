@@ -1,46 +1,350 @@
 package scanner
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/daviani/go__001/internal/starttls"
+	"github.com/daviani/go__001/internal/tlsprobe"
 )
 
 // SSLScanner - Scanner pour les certificats SSL/TLS
-type SSLScanner struct{}
+type SSLScanner struct {
+	// RootCAs — autorités de confiance pour vérifier la chaîne (nil = magasin du système)
+	// main.go y ajoute les racines de TLS_ROOT_CAS ; les tests, le certificat de leur serveur httptest
+	RootCAs *x509.CertPool
+
+	Client *http.Client // Client HTTP des requêtes AIA, OCSP et CRL (nil = client par défaut)
+}
+
+// CertificateInfo — données brutes du certificat présenté par le serveur sur un port
+type CertificateInfo struct {
+	Port       int       `json:"port"`
+	Protocol   string    `json:"protocol,omitempty"` // Protocole STARTTLS négocié (smtp, imap...) — vide = TLS direct
+	CommonName string    `json:"common_name"`
+	Issuer     string    `json:"issuer"`
+	NotBefore  time.Time `json:"not_before"`
+	NotAfter   time.Time `json:"not_after"`
+	DaysLeft   int       `json:"days_left"` // Jours avant expiration (négatif = expiré)
+	SANs       []string  `json:"sans,omitempty"`
+	KeyType    string    `json:"key_type"` // RSA, ECDSA, Ed25519
+	KeyBits    int       `json:"key_bits"`
+
+	SignatureAlgorithm string `json:"signature_algorithm"`
+	SCTs               int    `json:"scts"` // Preuves Certificate Transparency (extension du certificat + handshake)
+
+	// Vérification de la chaîne — les dates sont jugées à part (DaysLeft, NotBefore)
+	Trusted         bool               `json:"trusted"`          // Chaîne reliée à une racine de confiance
+	IncompleteChain bool               `json:"incomplete_chain"` // Intermédiaire absent, retrouvé via l'URL AIA
+	SelfSigned      bool               `json:"self_signed"`
+	HostnameMatch   bool               `json:"hostname_match"` // Domaine couvert par les SAN (le CommonName est ignoré)
+	ChainError      string             `json:"chain_error,omitempty"`
+	Chain           []ChainCertificate `json:"chain"` // Certificats tels que servis, feuille en tête
+
+	Revocation *Revocation `json:"revocation,omitempty"` // OCSP et CRL (option revocation)
+
+	Audit *TLSAudit `json:"audit,omitempty"` // Configuration TLS du port (option audit)
+}
+
+// sslOptions — options typées de SSLScanner
+type sslOptions struct {
+	Ports    []int               // Ports TLS à interroger (443 par défaut)
+	StartTLS []starttls.Endpoint // Services en clair à faire passer en TLS (SMTP, IMAP, POP3, FTP)
+	Audit    bool                // Énumérer protocoles et suites, et noter la configuration
+
+	Revocation bool // Vérifier la révocation (OCSP agrafé, répondeurs OCSP, CRL)
+
+	ExpiryWarning  int // Jours avant expiration à partir desquels l'alerte est moyenne
+	ExpiryCritical int // Jours avant expiration à partir desquels l'alerte est haute
+}
 
 // Name retourne l'identifiant du scanner SSL
 func (s SSLScanner) Name() string { return "ssl" }
 
-// Scan établit une connexion TLS et récupère les infos du certificat
+// Info retourne les métadonnées du scanner (GET /scanners)
+func (s SSLScanner) Info() Info {
+	return Info{
+		Title:       "SSL/TLS",
+		Description: "Vérifie le certificat TLS de chaque port, STARTTLS compris (chaîne de confiance, nom, expiration, révocation OCSP/CRL, clé, signature, Certificate Transparency) et audite la configuration : protocoles, suites, confidentialité persistante, note A à F",
+		Version:     "1.4.0",
+	}
+}
+
+// Schema publie les options acceptées par le scanner SSL
+func (s SSLScanner) Schema() Schema {
+	return Schema{
+		{
+			Name:        "ports",
+			Type:        OptionInts,
+			Description: "Ports TLS à interroger",
+			Default:     []int{443},
+			Min:         intPtr(1),
+			Max:         intPtr(65535),
+		},
+		{
+			Name:        "starttls",
+			Type:        OptionStrings,
+			Description: "Services STARTTLS à analyser : smtp, imap, pop3, ftp, port standard ou précisé (ex: smtp:587)",
+			Validate: func(value any) error {
+				services, _ := value.([]string)
+				_, err := starttls.ParseEndpoints(services)
+				return err
+			},
+		},
+		{
+			Name:        "audit",
+			Type:        OptionBool,
+			Description: "Énumérer les protocoles (SSLv3 à TLS 1.3) et suites acceptés, puis noter la configuration",
+			Default:     true,
+		},
+		{
+			Name:        "revocation",
+			Type:        OptionBool,
+			Description: "Vérifier la révocation : réponse OCSP agrafée, répondeurs OCSP et CRL du certificat",
+			Default:     true,
+		},
+		{
+			Name:        "expiry_warning_days",
+			Type:        OptionInt,
+			Description: "Expiration dans moins de N jours : alerte moyenne",
+			Default:     30,
+			Min:         intPtr(0),
+		},
+		{
+			Name:        "expiry_critical_days",
+			Type:        OptionInt,
+			Description: "Expiration dans moins de N jours : alerte haute",
+			Default:     7,
+			Min:         intPtr(0),
+		},
+	}
+}
+
+// options convertit les Options génériques en sslOptions
+func (s SSLScanner) options(opts Options) sslOptions {
+	opts = s.Schema().Apply(opts)
+	o := sslOptions{
+		Ports:          opts.Ints("ports"),
+		Audit:          opts.Bool("audit"),
+		Revocation:     opts.Bool("revocation"),
+		ExpiryWarning:  opts.Int("expiry_warning_days"),
+		ExpiryCritical: opts.Int("expiry_critical_days"),
+	}
+	// Déjà validé par Schema.Validate : l'erreur ne peut venir que d'un appel direct, on ignore l'entrée
+	o.StartTLS, _ = starttls.ParseEndpoints(opts.Strings("starttls"))
+	// Liste vide (?ports=) → on retombe sur le port HTTPS standard, sauf si seuls des services STARTTLS sont demandés
+	if len(o.Ports) == 0 && len(o.StartTLS) == 0 {
+		o.Ports = []int{443}
+	}
+	return o
+}
+
+// endpoints retourne les points d'accès à analyser : ports TLS directs (Protocol vide), puis services STARTTLS
+func (o sslOptions) endpoints() []starttls.Endpoint {
+	endpoints := make([]starttls.Endpoint, 0, len(o.Ports)+len(o.StartTLS))
+	for _, port := range o.Ports {
+		endpoints = append(endpoints, starttls.Endpoint{Port: port})
+	}
+	return append(endpoints, o.StartTLS...)
+}
+
+// Scan établit une connexion TLS sur chaque port configuré et récupère les infos du certificat
 // Utilise crypto/tls pour une connexion sécurisée native (pas de curl/openssl)
-func (s SSLScanner) Scan(domain string) (string, error) {
-	// tls.Dial ouvre une connexion TLS sur le port 443
-	conn, err := tls.Dial("tcp", domain+":443", nil)
+// Les services STARTTLS passent par le même chemin : seule l'ouverture de connexion change (voir dial)
+// Un port injoignable n'arrête pas le scan — erreur seulement si aucun port ne répond
+// Option audit : chaque port joignable est ensuite sondé version par version (voir auditTLS)
+func (s SSLScanner) Scan(ctx context.Context, domain string, opts Options) (Result, error) {
+	o := s.options(opts)
+
+	var certs []CertificateInfo
+	result := newResult(ctx, nil)
+	var lastErr error
+
+	for _, ep := range o.endpoints() {
+		info, err := s.fetchCertificate(ctx, domain, ep, o.Revocation)
+		if err != nil {
+			// Contexte expiré → inutile de tenter les ports suivants
+			if ctx.Err() != nil {
+				result.Data = certs
+				return result, ctx.Err()
+			}
+			lastErr = err
+			// Service joignable qui refuse le passage en TLS : les échanges (identifiants compris) restent en clair
+			if errors.Is(err, starttls.ErrNotOffered) {
+				result.add(Finding{
+					ID:       "ssl.starttls",
+					Title:    "STARTTLS non proposé",
+					Severity: SeverityHigh,
+					Category: "tls",
+					Evidence: err.Error(),
+					Asset:    net.JoinHostPort(domain, strconv.Itoa(ep.Port)),
+				})
+			}
+			continue
+		}
+		certs = append(certs, info)
+
+		// Format date : "02/01/2006" = jour/mois/année (format Go spécifique)
+		asset := net.JoinHostPort(domain, strconv.Itoa(ep.Port))
+		if ep.Protocol != "" {
+			result.add(Finding{
+				ID:       "ssl.starttls",
+				Title:    "STARTTLS disponible",
+				Severity: SeverityInfo,
+				Category: "tls",
+				Evidence: strings.ToUpper(ep.Protocol) + " : passage en TLS accepté",
+				Asset:    asset,
+			})
+		}
+		result.add(Finding{
+			ID:       "ssl.certificate",
+			Title:    "Certificat TLS",
+			Severity: SeverityInfo,
+			Category: "tls",
+			Evidence: fmt.Sprintf("Domaine: %s | Expire: %s", info.CommonName, info.NotAfter.Format("02/01/2006")),
+			Asset:    asset,
+		})
+		result.add(Finding{
+			ID:       "ssl.issuer",
+			Title:    "Émetteur du certificat",
+			Severity: SeverityInfo,
+			Category: "tls",
+			Evidence: info.Issuer,
+			Asset:    asset,
+		})
+		checkCertificate(&result, asset, domain, info, o)
+		if info.Revocation != nil {
+			checkRevocationStatus(&result, asset, info.Revocation)
+		}
+
+		if o.Audit {
+			prober := &tlsprobe.Prober{DialContext: s.dial(domain, ep.Protocol)}
+			audit, err := auditTLS(ctx, prober, asset, domain, info)
+			if err != nil {
+				result.Data = certs
+				return result, err
+			}
+			certs[len(certs)-1].Audit = audit
+			checkTLSAudit(&result, asset, audit)
+		}
+	}
+
+	// Aucun certificat : les seuls findings possibles sont des refus STARTTLS, qui restent un résultat
+	if len(certs) == 0 && len(result.Findings) == 0 {
+		return Result{}, lastErr
+	}
+
+	result.Data = certs
+	return result, nil
+}
+
+// dial retourne une fonction d'ouverture de connexion pour un point d'accès : TCP simple en TLS direct,
+// TCP puis négociation STARTTLS sinon — la connexion retournée attend le ClientHello dans les deux cas
+// Partagée par la lecture du certificat et par la sonde de l'audit (une négociation par connexion)
+func (s SSLScanner) dial(domain, protocol string) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, network, address)
+		if err != nil || protocol == "" {
+			return conn, err
+		}
+		if err := starttls.Negotiate(ctx, conn, protocol, domain); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		return conn, nil
+	}
+}
+
+// fetchCertificate ouvre une connexion TLS sur le point d'accès ep, lit le certificat du serveur et vérifie sa chaîne
+// revocation : interroge aussi OCSP et les CRL pendant que la réponse agrafée du handshake est disponible
+func (s SSLScanner) fetchCertificate(ctx context.Context, domain string, ep starttls.Endpoint, revocation bool) (CertificateInfo, error) {
+	// net.JoinHostPort gère les IPv6 ("[::1]:443")
+	// InsecureSkipVerify : un certificat invalide doit être analysé, pas faire échouer la connexion —
+	// la vérification est refaite juste après (inspectChain) pour en détailler chaque défaut
+	// Versions et suites obsolètes acceptées : on veut lire le certificat, juger la configuration
+	// est le rôle de l'audit
+	rawConn, err := s.dial(domain, ep.Protocol)(ctx, "tcp", net.JoinHostPort(domain, strconv.Itoa(ep.Port)))
 	if err != nil {
-		return "", fmt.Errorf("erreur SSL: %w", err)
+		return CertificateInfo{}, fmt.Errorf("erreur SSL: %w", err)
 	}
+	conn := tls.Client(rawConn, &tls.Config{
+		ServerName:         domain,
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS10,
+		CipherSuites:       allCipherSuites(),
+	})
 
 	// defer garantit que la connexion sera fermée à la fin de la fonction
 	// _ = ignore l'erreur de Close() volontairement
 	defer func() { _ = conn.Close() }()
 
-	certs := conn.ConnectionState().PeerCertificates
+	// HandshakeContext abandonne le handshake si ctx expire
+	if err := conn.HandshakeContext(ctx); err != nil {
+		return CertificateInfo{}, fmt.Errorf("erreur SSL: %w", err)
+	}
+
+	state := conn.ConnectionState()
+	peerCerts := state.PeerCertificates
 
-	if len(certs) == 0 {
-		return "", fmt.Errorf("erreur SSL: no client certificate")
+	if len(peerCerts) == 0 {
+		return CertificateInfo{}, fmt.Errorf("erreur SSL: no peer certificate")
 	}
 	// Récupère le premier certificat de la chaîne (celui du domaine)
-	cert := certs[0]
+	cert := peerCerts[0]
 
 	issuer := "Inconnu"
 	if len(cert.Issuer.Organization) > 0 {
 		issuer = cert.Issuer.Organization[0]
 	}
 
-	// Sprintf formate les infos du certificat en une string lisible
-	// Format date : "02/01/2006" = jour/mois/année (format Go spécifique)
-	return fmt.Sprintf("Domaine: %s | Émetteur: %s | Expire: %s",
-		cert.Subject.CommonName,
-		issuer,
-		cert.NotAfter.Format("02/01/2006")), nil
+	keyType, keyBits := publicKeyInfo(cert)
+	info := CertificateInfo{
+		Port:       ep.Port,
+		Protocol:   ep.Protocol,
+		CommonName: cert.Subject.CommonName,
+		Issuer:     issuer,
+		NotAfter:   cert.NotAfter,
+		KeyType:    keyType,
+		KeyBits:    keyBits,
+	}
+	issuerCert := s.inspectChain(ctx, &info, domain, state)
+	if revocation {
+		info.Revocation = s.checkRevocation(ctx, cert, issuerCert, state.OCSPResponse)
+	}
+	return info, nil
+}
+
+// allCipherSuites retourne toutes les suites TLS ≤ 1.2 que crypto/tls implémente, obsolètes comprises
+func allCipherSuites() []uint16 {
+	var ids []uint16
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		ids = append(ids, suite.ID)
+	}
+	return ids
+}
+
+// publicKeyInfo retourne l'algorithme et la taille de la clé publique du certificat
+func publicKeyInfo(cert *x509.Certificate) (string, int) {
+	// Type switch : équivalent d'un instanceof enchaîné en JS
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return "RSA", key.N.BitLen()
+	case *ecdsa.PublicKey:
+		return "ECDSA", key.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return "Ed25519", 256
+	}
+	return "Inconnu", 0
 }
@@ -0,0 +1,382 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/daviani/go__001/internal/tlsprobe"
+)
+
+// TLSAudit — configuration TLS d'un port : protocoles, suites, échange de clés et note globale
+type TLSAudit struct {
+	Protocols []TLSProtocol `json:"protocols"`
+	DHBits    int           `json:"dh_bits,omitempty"` // Plus petit module DHE accepté
+	Curves    []string      `json:"curves,omitempty"`  // Groupes ECDHE/FFDHE vus pendant l'énumération
+	Score     int           `json:"score"`             // Score sur 100 avant plafonnement
+	Grade     string        `json:"grade"`             // A à F, façon SSL Labs
+	Caps      []string      `json:"caps,omitempty"`    // Raisons qui plafonnent la note
+}
+
+// TLSProtocol — une version du protocole et les suites que le serveur y accepte
+type TLSProtocol struct {
+	Version          string      `json:"version"`
+	Supported        bool        `json:"supported"`
+	ServerPreference bool        `json:"server_preference,omitempty"` // Ordre des suites imposé par le serveur
+	Ciphers          []TLSCipher `json:"ciphers,omitempty"`
+	Error            string      `json:"error,omitempty"` // Sonde interrompue (timeout...) : version traitée comme absente
+}
+
+// TLSCipher — suite acceptée, dans l'ordre de préférence du serveur
+type TLSCipher struct {
+	Name           string `json:"name"`
+	Bits           int    `json:"bits"`
+	ForwardSecrecy bool   `json:"forward_secrecy"`
+	Weak           bool   `json:"weak"`
+}
+
+// auditTLS sonde chaque version de SSLv3 à TLS 1.3 en parallèle et note la configuration
+// Un échec de sonde sur une version n'arrête pas l'audit ; seul un contexte expiré le fait
+// prober ouvre les connexions : TCP direct ou après négociation STARTTLS (voir SSLScanner.dial)
+func auditTLS(ctx context.Context, prober *tlsprobe.Prober, address, domain string, cert CertificateInfo) (*TLSAudit, error) {
+	protocols := make([]tlsprobe.Protocol, len(tlsprobe.Versions))
+	errs := make([]error, len(tlsprobe.Versions))
+	var wg sync.WaitGroup
+	for i, version := range tlsprobe.Versions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			protocols[i], errs[i] = prober.Enumerate(ctx, address, domain, version)
+		}()
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	audit := &TLSAudit{}
+	for i, proto := range protocols {
+		p := TLSProtocol{
+			Version:          tlsprobe.VersionName(proto.Version),
+			Supported:        proto.Supported && errs[i] == nil,
+			ServerPreference: proto.ServerPreference,
+		}
+		if errs[i] != nil {
+			p.Error = errs[i].Error()
+		}
+		if p.Supported {
+			for _, suite := range proto.Suites {
+				p.Ciphers = append(p.Ciphers, TLSCipher{
+					Name:           suite.Name,
+					Bits:           suite.Bits,
+					ForwardSecrecy: suite.ForwardSecrecy(),
+					Weak:           weakSuite(proto.Version, suite),
+				})
+			}
+			for _, group := range proto.Groups {
+				if name := tlsprobe.GroupName(group); !slices.Contains(audit.Curves, name) {
+					audit.Curves = append(audit.Curves, name)
+				}
+			}
+			if proto.DHBits > 0 && (audit.DHBits == 0 || proto.DHBits < audit.DHBits) {
+				audit.DHBits = proto.DHBits
+			}
+		}
+		audit.Protocols = append(audit.Protocols, p)
+	}
+
+	audit.Score, audit.Grade, audit.Caps = gradeTLS(protocols, errs, cert, audit.DHBits)
+	return audit, nil
+}
+
+// insecureSuite — suite cassée : aucun chiffrement, clé de 40 à 56 bits, RC4 ou serveur non authentifié
+func insecureSuite(s tlsprobe.Suite) bool {
+	return s.Null() || s.Export() || s.Anonymous() || s.DES() || s.RC4()
+}
+
+// weakSuite — suite à retirer : cassée, 3DES (Sweet32), ou CBC en TLS 1.0 / SSLv3 (BEAST)
+func weakSuite(version uint16, s tlsprobe.Suite) bool {
+	return insecureSuite(s) || s.TripleDES() || (s.CBC() && version <= tlsprobe.VersionTLS10)
+}
+
+// keyStrength ramène une clé de certificat à sa taille RSA équivalente (NIST SP 800-57 : 256 bits EC ≈ 3072 bits RSA)
+func keyStrength(cert CertificateInfo) int {
+	if cert.KeyType == "RSA" {
+		return cert.KeyBits
+	}
+	return cert.KeyBits * 12
+}
+
+// weakKey — clé trop courte pour être considérée comme sûre aujourd'hui
+func weakKey(cert CertificateInfo) bool {
+	if cert.KeyType == "RSA" {
+		return cert.KeyBits < 2048
+	}
+	return cert.KeyBits < 256
+}
+
+// gradeTLS calcule le score et la note, selon la méthode publiée par SSL Labs :
+// 30 % protocole, 30 % échange de clés, 40 % chiffrement, puis plafonds pour les faiblesses connues
+func gradeTLS(protocols []tlsprobe.Protocol, errs []error, cert CertificateInfo, dhBits int) (int, string, []string) {
+	var versions []uint16
+	var accepted []tlsprobe.Suite
+	for i, proto := range protocols {
+		if proto.Supported && errs[i] == nil {
+			versions = append(versions, proto.Version)
+			accepted = append(accepted, proto.Suites...)
+		}
+	}
+	if len(versions) == 0 {
+		return 0, "F", []string{"aucun protocole accepté"}
+	}
+
+	// Protocole : moyenne de la meilleure et de la pire version acceptée
+	protocolScore := func(v uint16) int {
+		switch v {
+		case tlsprobe.VersionSSL30:
+			return 80
+		case tlsprobe.VersionTLS10:
+			return 90
+		case tlsprobe.VersionTLS11:
+			return 95
+		}
+		return 100
+	}
+	best, worst := slices.Max(versions), slices.Min(versions)
+	protocol := (protocolScore(best) + protocolScore(worst)) / 2
+
+	// Échange de clés : la plus faible entre la clé du certificat et le module DHE
+	// Clé de type inconnu (KeyBits = 0) : seul le module DHE compte, 2048 bits à défaut
+	kxBits := keyStrength(cert)
+	if kxBits == 0 {
+		kxBits = 2048
+	}
+	if dhBits > 0 {
+		kxBits = min(kxBits, dhBits)
+	}
+	var exchange int
+	switch {
+	case slices.ContainsFunc(accepted, tlsprobe.Suite.Anonymous):
+		exchange = 0
+	case kxBits < 512:
+		exchange = 20
+	case kxBits < 1024:
+		exchange = 40
+	case kxBits < 2048:
+		exchange = 80
+	case kxBits < 4096:
+		exchange = 90
+	default:
+		exchange = 100
+	}
+
+	// Chiffrement : moyenne de la suite la plus forte et de la plus faible (taille de clé effective)
+	cipherScore := func(bits int) int {
+		switch {
+		case bits == 0:
+			return 0
+		case bits < 128:
+			return 20
+		case bits < 256:
+			return 80
+		}
+		return 100
+	}
+	var strongest, weakest int
+	for i, s := range accepted {
+		if i == 0 || s.Bits > strongest {
+			strongest = s.Bits
+		}
+		if s.Bits >= 0 && (i == 0 || s.Bits < weakest) {
+			weakest = s.Bits
+		}
+	}
+	cipher := (cipherScore(strongest) + cipherScore(weakest)) / 2
+
+	// Arrondi à l'entier le plus proche : 0.3 × 95 + 0.3 × 90 + 0.4 × 50 = 75.5 → 76
+	score := (3*protocol + 3*exchange + 4*cipher + 5) / 10
+	grade := "F"
+	for _, step := range []struct {
+		min   int
+		grade string
+	}{{80, "A"}, {65, "B"}, {50, "C"}, {35, "D"}, {20, "E"}} {
+		if score >= step.min {
+			grade = step.grade
+			break
+		}
+	}
+
+	// Plafonds : une seule faiblesse grave suffit à compromettre la connexion, quel que soit le score
+	var caps []string
+	// Les lettres se comparent comme des chaînes : "A" < "C", donc une note inférieure au plafond est relevée
+	limit := func(ceiling, reason string) {
+		caps = append(caps, fmt.Sprintf("%s (%s max)", reason, ceiling))
+		if grade < ceiling {
+			grade = ceiling
+		}
+	}
+	for _, s := range accepted {
+		if s.Null() || s.Export() || s.Anonymous() || s.DES() {
+			limit("F", "suite "+s.Name)
+		}
+	}
+	// KeyBits = 0 : type de clé inconnu, pas de conclusion
+	strength := keyStrength(cert)
+	if cert.KeyBits > 0 && strength < 1024 {
+		limit("F", fmt.Sprintf("clé %s de %d bits", cert.KeyType, cert.KeyBits))
+	}
+	if dhBits > 0 && dhBits < 1024 {
+		limit("F", fmt.Sprintf("module DH de %d bits", dhBits))
+	}
+	if slices.Contains(versions, tlsprobe.VersionSSL30) {
+		limit("C", "SSLv3 accepté")
+	}
+	if slices.ContainsFunc(accepted, tlsprobe.Suite.RC4) {
+		limit("C", "RC4 accepté")
+	}
+	if slices.ContainsFunc(accepted, tlsprobe.Suite.TripleDES) {
+		limit("C", "3DES accepté")
+	}
+	if best < tlsprobe.VersionTLS12 {
+		limit("C", "ni TLS 1.2 ni TLS 1.3")
+	}
+	if slices.Contains(versions, tlsprobe.VersionTLS10) || slices.Contains(versions, tlsprobe.VersionTLS11) {
+		limit("B", "TLS 1.0 ou 1.1 accepté")
+	}
+	if !slices.ContainsFunc(accepted, tlsprobe.Suite.ForwardSecrecy) {
+		limit("B", "aucune suite à confidentialité persistante")
+	}
+	if strength >= 1024 && strength < 2048 {
+		limit("B", fmt.Sprintf("clé %s de %d bits", cert.KeyType, cert.KeyBits))
+	}
+	if dhBits >= 1024 && dhBits < 2048 {
+		limit("B", fmt.Sprintf("module DH de %d bits", dhBits))
+	}
+	return score, grade, caps
+}
+
+// checkTLSAudit transforme l'audit d'un port en findings — mêmes IDs que le contrôle passe ou échoue
+func checkTLSAudit(result *Result, asset string, audit *TLSAudit) {
+	supported := make(map[string]bool)
+	for _, p := range audit.Protocols {
+		supported[p.Version] = p.Supported
+	}
+	add := func(id, title string, severity Severity, evidence string) {
+		result.add(Finding{ID: id, Title: title, Severity: severity, Category: "tls", Evidence: evidence, Asset: asset})
+	}
+
+	// Protocoles : SSLv3 (POODLE), TLS 1.0 et 1.1 (abandonnés par la RFC 8996)
+	for _, legacy := range []struct {
+		id, version string
+		severity    Severity
+		why         string
+	}{
+		{"ssl.protocol.ssl3", "SSLv3", SeverityHigh, "vulnérable à POODLE"},
+		{"ssl.protocol.tls1_0", "TLS 1.0", SeverityMedium, "obsolète (RFC 8996)"},
+		{"ssl.protocol.tls1_1", "TLS 1.1", SeverityMedium, "obsolète (RFC 8996)"},
+	} {
+		if supported[legacy.version] {
+			add(legacy.id, legacy.version+" accepté", legacy.severity, legacy.version+" "+legacy.why)
+		} else {
+			add(legacy.id, legacy.version+" désactivé", SeverityInfo, legacy.version+" refusé par le serveur")
+		}
+	}
+	switch {
+	case supported["TLS 1.2"]:
+		add("ssl.protocol.tls1_2", "TLS 1.2 accepté", SeverityInfo, "TLS 1.2 accepté")
+	case supported["TLS 1.3"]:
+		add("ssl.protocol.tls1_2", "TLS 1.2 absent", SeverityInfo, "TLS 1.3 seul : les clients anciens ne pourront pas se connecter")
+	default:
+		add("ssl.protocol.tls1_2", "Ni TLS 1.2 ni TLS 1.3", SeverityMedium, "Le serveur ne propose que des versions obsolètes")
+	}
+	if supported["TLS 1.3"] {
+		add("ssl.protocol.tls1_3", "TLS 1.3 accepté", SeverityInfo, "TLS 1.3 accepté")
+	} else {
+		add("ssl.protocol.tls1_3", "TLS 1.3 absent", SeverityLow, "TLS 1.3 non proposé (handshake plus lent, suites plus anciennes)")
+	}
+
+	// Suites : on garde le nom de chaque suite concernée, toutes versions confondues
+	var insecure, tripleDES, cbc10, withoutFS []string
+	var withFS bool
+	for _, p := range audit.Protocols {
+		for _, c := range p.Ciphers {
+			suite, _ := tlsprobe.SuiteByName(c.Name)
+			switch {
+			case insecureSuite(suite):
+				insecure = appendUnique(insecure, c.Name)
+			case suite.TripleDES():
+				tripleDES = appendUnique(tripleDES, c.Name)
+			}
+			if suite.CBC() && (p.Version == "TLS 1.0" || p.Version == "SSLv3") {
+				cbc10 = appendUnique(cbc10, c.Name)
+			}
+			if c.ForwardSecrecy {
+				withFS = true
+			} else {
+				withoutFS = appendUnique(withoutFS, c.Name)
+			}
+		}
+	}
+	if len(insecure) > 0 {
+		add("ssl.cipher.insecure", "Suites de chiffrement non sécurisées", SeverityHigh, strings.Join(insecure, ", "))
+	} else {
+		add("ssl.cipher.insecure", "Aucune suite non sécurisée", SeverityInfo, "Ni NULL, EXPORT, anonyme, DES ni RC4")
+	}
+	if len(tripleDES) > 0 {
+		add("ssl.cipher.3des", "Suites 3DES acceptées (Sweet32)", SeverityMedium, strings.Join(tripleDES, ", "))
+	} else {
+		add("ssl.cipher.3des", "Aucune suite 3DES", SeverityInfo, "3DES refusé")
+	}
+	if len(cbc10) > 0 {
+		add("ssl.cipher.cbc_tls10", "Suites CBC en TLS 1.0 (BEAST)", SeverityLow, strings.Join(cbc10, ", "))
+	} else {
+		add("ssl.cipher.cbc_tls10", "Pas de CBC en TLS 1.0", SeverityInfo, "Aucune suite CBC négociable en TLS 1.0 ou SSLv3")
+	}
+	switch {
+	case !withFS:
+		add("ssl.forward_secrecy", "Pas de confidentialité persistante", SeverityMedium, "Aucune suite ECDHE/DHE : la clé privée du certificat déchiffre tout le trafic enregistré")
+	case len(withoutFS) > 0:
+		add("ssl.forward_secrecy", "Confidentialité persistante partielle", SeverityLow, "Suites sans ECDHE/DHE : "+strings.Join(withoutFS, ", "))
+	default:
+		add("ssl.forward_secrecy", "Confidentialité persistante", SeverityInfo, "Toutes les suites utilisent un échange de clés éphémère")
+	}
+
+	// Module DHE (la clé du certificat est jugée par checkCertificate)
+	if audit.DHBits > 0 {
+		dhEvidence := fmt.Sprintf("Module DHE de %d bits", audit.DHBits)
+		switch {
+		case audit.DHBits < 1024:
+			add("ssl.dh", "Paramètres DH faibles (Logjam)", SeverityHigh, dhEvidence)
+		case audit.DHBits < 2048:
+			add("ssl.dh", "Paramètres DH inférieurs à 2048 bits", SeverityMedium, dhEvidence)
+		default:
+			add("ssl.dh", "Paramètres DH", SeverityInfo, dhEvidence)
+		}
+	}
+
+	// Note globale
+	severity := SeverityHigh
+	switch audit.Grade {
+	case "A":
+		severity = SeverityInfo
+	case "B":
+		severity = SeverityLow
+	case "C":
+		severity = SeverityMedium
+	}
+	evidence := fmt.Sprintf("Note %s (score %d/100)", audit.Grade, audit.Score)
+	if len(audit.Caps) > 0 {
+		evidence += " — plafonnée : " + strings.Join(audit.Caps, ", ")
+	}
+	add("ssl.grade", "Note de la configuration TLS : "+audit.Grade, severity, evidence)
+}
+
+// appendUnique ajoute s à list s'il n'y figure pas déjà
+func appendUnique(list []string, s string) []string {
+	if slices.Contains(list, s) {
+		return list
+	}
+	return append(list, s)
+}
@@ -0,0 +1,289 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ChainCertificate — un certificat de la chaîne telle que servie par le serveur
+type ChainCertificate struct {
+	Subject            string    `json:"subject"`
+	Issuer             string    `json:"issuer"`
+	NotAfter           time.Time `json:"not_after"`
+	SignatureAlgorithm string    `json:"signature_algorithm"`
+	KeyType            string    `json:"key_type"`
+	KeyBits            int       `json:"key_bits"`
+	WeakSignature      bool      `json:"weak_signature,omitempty"` // MD5 ou SHA-1, hors racine auto-signée
+}
+
+// maxAIAFetches — intermédiaires téléchargés au plus pour compléter une chaîne
+const maxAIAFetches = 3
+
+// oidSCTList — extension SignedCertificateTimestampList (RFC 6962 §3.3) : SCT intégrés par l'AC
+var oidSCTList = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 2}
+
+// inspectChain vérifie la chaîne servie et complète info : confiance, nom couvert, signatures, SCT
+// Retourne l'émetteur du certificat (nil si introuvable) — nécessaire pour interroger OCSP et la CRL
+func (s SSLScanner) inspectChain(ctx context.Context, info *CertificateInfo, domain string, state tls.ConnectionState) *x509.Certificate {
+	chain := state.PeerCertificates
+	leaf := chain[0]
+	now := time.Now()
+
+	info.NotBefore = leaf.NotBefore
+	info.DaysLeft = int(math.Floor(leaf.NotAfter.Sub(now).Hours() / 24))
+	info.SANs = slices.Clone(leaf.DNSNames)
+	for _, ip := range leaf.IPAddresses {
+		info.SANs = append(info.SANs, ip.String())
+	}
+	info.SignatureAlgorithm = leaf.SignatureAlgorithm.String()
+	info.SCTs = countSCTs(leaf) + len(state.SignedCertificateTimestamps)
+	info.SelfSigned = selfSigned(leaf)
+	// VerifyHostname ne regarde que les SAN : le CommonName n'est plus accepté par les navigateurs
+	info.HostnameMatch = leaf.VerifyHostname(domain) == nil
+	for _, cert := range chain {
+		keyType, keyBits := publicKeyInfo(cert)
+		info.Chain = append(info.Chain, ChainCertificate{
+			Subject:            certificateName(cert.Subject),
+			Issuer:             certificateName(cert.Issuer),
+			NotAfter:           cert.NotAfter,
+			SignatureAlgorithm: cert.SignatureAlgorithm.String(),
+			KeyType:            keyType,
+			KeyBits:            keyBits,
+			// La signature d'une racine (émetteur = sujet) n'est jamais vérifiée : seule celle des certificats émis compte
+			WeakSignature: weakSignature(cert.SignatureAlgorithm) && !bytes.Equal(cert.RawSubject, cert.RawIssuer),
+		})
+	}
+
+	// Les dates sont jugées à part (ssl.expiry) : hors validité, on vérifie la chaîne à la borne la plus proche
+	// de la période du certificat pour distinguer « expiré » de « non reconnu »
+	at := now
+	switch {
+	case now.Before(leaf.NotBefore):
+		at = leaf.NotBefore
+	case now.After(leaf.NotAfter):
+		at = leaf.NotAfter
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	opts := x509.VerifyOptions{Roots: s.RootCAs, Intermediates: intermediates, CurrentTime: at}
+	chains, err := leaf.Verify(opts)
+
+	// Autorité inconnue : le serveur a peut-être oublié un intermédiaire — les navigateurs le téléchargent
+	// via l'URL AIA du dernier certificat servi, beaucoup de clients (API, curl, mobiles) non
+	var unknown x509.UnknownAuthorityError
+	if last := chain[len(chain)-1]; errors.As(err, &unknown) && !selfSigned(last) {
+		if s.fetchIssuers(ctx, last, intermediates) > 0 {
+			if retried, retry := leaf.Verify(opts); retry == nil {
+				info.IncompleteChain = true
+				chains, err = retried, nil
+			}
+		}
+	}
+	info.Trusted = err == nil
+	if err != nil {
+		info.ChainError = err.Error()
+	}
+
+	// Émetteur : celui de la chaîne vérifiée, à défaut le certificat servi juste après la feuille s'il l'a signée
+	switch {
+	case err == nil && len(chains[0]) > 1:
+		return chains[0][1]
+	case len(chain) > 1 && leaf.CheckSignatureFrom(chain[1]) == nil:
+		return chain[1]
+	}
+	return nil
+}
+
+// fetchIssuers suit les URL AIA (Authority Information Access) à partir de cert et ajoute les émetteurs
+// téléchargés à pool — retourne le nombre de certificats ajoutés
+func (s SSLScanner) fetchIssuers(ctx context.Context, cert *x509.Certificate, pool *x509.CertPool) int {
+	added := 0
+	for range maxAIAFetches {
+		var issuer *x509.Certificate
+		for _, url := range cert.IssuingCertificateURL {
+			if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+				continue
+			}
+			if issuer = s.fetchIssuer(ctx, url); issuer != nil {
+				break
+			}
+		}
+		if issuer == nil {
+			break
+		}
+		pool.AddCert(issuer)
+		added++
+		if selfSigned(issuer) {
+			break
+		}
+		cert = issuer
+	}
+	return added
+}
+
+// fetchIssuer télécharge un certificat émetteur (DER, parfois PEM) — nil si indisponible ou illisible
+func (s SSLScanner) fetchIssuer(ctx context.Context, url string) *x509.Certificate {
+	resp, err := get(ctx, s.Client, url)
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return nil
+	}
+	if block, _ := pem.Decode(data); block != nil {
+		data = block.Bytes
+	}
+	cert, err := x509.ParseCertificate(data)
+	if err != nil {
+		return nil
+	}
+	return cert
+}
+
+// selfSigned — émetteur identique au sujet et signature vérifiée par la propre clé du certificat
+// CheckSignature plutôt que CheckSignatureFrom : ce dernier refuse un parent qui n'est pas une AC
+func selfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawSubject, cert.RawIssuer) &&
+		cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+}
+
+// countSCTs compte les SCT intégrés au certificat
+// L'extension contient une OCTET STRING qui enveloppe une liste au format TLS :
+// longueur totale sur 2 octets, puis chaque SCT précédé de sa longueur sur 2 octets
+func countSCTs(cert *x509.Certificate) int {
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(oidSCTList) {
+			continue
+		}
+		var list []byte
+		if _, err := asn1.Unmarshal(ext.Value, &list); err != nil || len(list) < 2 {
+			return 0
+		}
+		n := 0
+		for data := list[2:]; len(data) >= 2; n++ {
+			size := int(binary.BigEndian.Uint16(data))
+			if len(data) < 2+size {
+				break
+			}
+			data = data[2+size:]
+		}
+		return n
+	}
+	return 0
+}
+
+// weakSignature — algorithmes de signature cassés (collisions MD5 et SHA-1)
+func weakSignature(alg x509.SignatureAlgorithm) bool {
+	switch alg {
+	case x509.MD2WithRSA, x509.MD5WithRSA, x509.SHA1WithRSA, x509.DSAWithSHA1, x509.ECDSAWithSHA1:
+		return true
+	}
+	return false
+}
+
+// certificateName retourne le CommonName, ou le nom distinctif complet s'il est vide
+func certificateName(name pkix.Name) string {
+	if name.CommonName != "" {
+		return name.CommonName
+	}
+	return name.String()
+}
+
+// checkCertificate transforme le certificat d'un port en findings — mêmes IDs que le contrôle passe ou échoue
+func checkCertificate(result *Result, asset, domain string, info CertificateInfo, o sslOptions) {
+	add := func(id, title string, severity Severity, evidence string) {
+		result.add(Finding{ID: id, Title: title, Severity: severity, Category: "tls", Evidence: evidence, Asset: asset})
+	}
+
+	// Chaîne de confiance
+	var path []string
+	for _, cert := range info.Chain {
+		path = append(path, cert.Subject)
+	}
+	served := fmt.Sprintf("%d certificat(s) servi(s) : %s", len(info.Chain), strings.Join(path, " → "))
+	switch {
+	case info.Trusted && info.IncompleteChain:
+		add("ssl.chain", "Chaîne de certificats incomplète", SeverityMedium,
+			served+" — intermédiaire manquant, retrouvé via AIA : les clients qui ne le téléchargent pas échoueront")
+	case info.Trusted:
+		add("ssl.chain", "Chaîne de confiance valide", SeverityInfo, served)
+	case info.SelfSigned:
+		add("ssl.chain", "Certificat auto-signé", SeverityHigh, served)
+	default:
+		add("ssl.chain", "Chaîne de confiance invalide", SeverityHigh, info.ChainError)
+	}
+
+	// Dates de validité — seuils configurables
+	switch {
+	case time.Now().Before(info.NotBefore):
+		add("ssl.expiry", "Certificat pas encore valide", SeverityHigh, "Valide à partir du "+info.NotBefore.Format("02/01/2006"))
+	case info.DaysLeft < 0:
+		add("ssl.expiry", "Certificat expiré", SeverityHigh, "Expiré depuis le "+info.NotAfter.Format("02/01/2006"))
+	case info.DaysLeft < o.ExpiryCritical:
+		add("ssl.expiry", "Certificat bientôt expiré", SeverityHigh, fmt.Sprintf("Expire dans %d jour(s)", info.DaysLeft))
+	case info.DaysLeft < o.ExpiryWarning:
+		add("ssl.expiry", "Certificat bientôt expiré", SeverityMedium, fmt.Sprintf("Expire dans %d jour(s)", info.DaysLeft))
+	default:
+		add("ssl.expiry", "Certificat valide", SeverityInfo, fmt.Sprintf("Expire dans %d jour(s)", info.DaysLeft))
+	}
+
+	// Nom couvert : SAN uniquement
+	sans := "SAN : " + strings.Join(info.SANs, ", ")
+	switch {
+	case info.HostnameMatch:
+		add("ssl.hostname", "Nom couvert par le certificat", SeverityInfo, sans)
+	case info.CommonName == domain:
+		add("ssl.hostname", "Nom absent des SAN", SeverityHigh, domain+" figure seulement dans le CommonName, ignoré par les navigateurs — "+sans)
+	default:
+		add("ssl.hostname", "Nom non couvert par le certificat", SeverityHigh, domain+" absent — "+sans)
+	}
+
+	// Clé du certificat
+	keyEvidence := fmt.Sprintf("%s %d bits", info.KeyType, info.KeyBits)
+	if info.KeyBits > 0 && weakKey(info) {
+		add("ssl.key", "Clé du certificat trop courte", SeverityHigh, keyEvidence)
+	} else {
+		add("ssl.key", "Clé du certificat", SeverityInfo, keyEvidence)
+	}
+
+	// Signatures : la feuille et les intermédiaires servis
+	var weak []string
+	for _, cert := range info.Chain {
+		if cert.WeakSignature {
+			weak = append(weak, cert.Subject+" ("+cert.SignatureAlgorithm+")")
+		}
+	}
+	if len(weak) > 0 {
+		add("ssl.signature", "Signature MD5 ou SHA-1", SeverityHigh, strings.Join(weak, ", "))
+	} else {
+		add("ssl.signature", "Algorithme de signature", SeverityInfo, info.SignatureAlgorithm)
+	}
+
+	// Certificate Transparency : exigée par Chrome et Safari pour les certificats publics
+	if info.SCTs > 0 {
+		add("ssl.sct", "Certificate Transparency", SeverityInfo, fmt.Sprintf("%d SCT", info.SCTs))
+	} else {
+		add("ssl.sct", "Aucun SCT (Certificate Transparency)", SeverityLow, "Ni dans le certificat ni dans le handshake : refusé par Chrome et Safari s'il est émis par une AC publique")
+	}
+}
@@ -0,0 +1,251 @@
+package scanner
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// Revocation — statut de révocation du certificat, source par source
+type Revocation struct {
+	MustStaple bool         `json:"must_staple"`       // Extension TLS Feature status_request (RFC 7633)
+	Stapled    *OCSPStatus  `json:"stapled,omitempty"` // Réponse OCSP agrafée dans le handshake (nil = absente)
+	OCSP       []OCSPStatus `json:"ocsp,omitempty"`    // Réponses des répondeurs listés dans l'AIA
+	CRL        []CRLStatus  `json:"crl,omitempty"`     // Listes de révocation (CRL Distribution Points)
+	Error      string       `json:"error,omitempty"`   // Vérification impossible (émetteur introuvable)
+}
+
+// OCSPStatus — réponse OCSP, agrafée ou obtenue auprès d'un répondeur
+type OCSPStatus struct {
+	URL        string     `json:"url,omitempty"`
+	Status     string     `json:"status,omitempty"` // good, revoked, unknown
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	NextUpdate time.Time  `json:"next_update,omitzero"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// CRLStatus — résultat de la recherche du numéro de série dans une CRL
+type CRLStatus struct {
+	URL        string     `json:"url"`
+	Revoked    bool       `json:"revoked"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	NextUpdate time.Time  `json:"next_update,omitzero"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// Limites de téléchargement : une réponse OCSP tient en quelques Ko, une CRL peut peser plusieurs Mo
+const (
+	maxOCSPResponse = 64 << 10
+	maxCRL          = 20 << 20
+)
+
+// oidTLSFeature — extension TLS Feature (RFC 7633) : la valeur 5 (status_request) signifie Must-Staple
+var oidTLSFeature = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 24}
+
+// mustStaple indique un certificat qui exige une réponse OCSP agrafée
+func mustStaple(cert *x509.Certificate) bool {
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(oidTLSFeature) {
+			continue
+		}
+		var features []int
+		if _, err := asn1.Unmarshal(ext.Value, &features); err != nil {
+			return false
+		}
+		return slices.Contains(features, 5)
+	}
+	return false
+}
+
+// checkRevocation interroge les trois sources de révocation : réponse agrafée, répondeurs OCSP, CRL
+// Une source injoignable est notée dans son Error ; les autres sont quand même consultées
+func (s SSLScanner) checkRevocation(ctx context.Context, leaf, issuer *x509.Certificate, stapled []byte) *Revocation {
+	rev := &Revocation{MustStaple: mustStaple(leaf)}
+	// Sans émetteur, ni la requête OCSP (hash de sa clé) ni la signature de la CRL ne sont vérifiables
+	if issuer == nil {
+		rev.Error = "émetteur du certificat introuvable"
+		return rev
+	}
+
+	if len(stapled) > 0 {
+		status := parseOCSP(stapled, leaf, issuer)
+		rev.Stapled = &status
+	}
+	for _, url := range leaf.OCSPServer {
+		if httpURL(url) {
+			rev.OCSP = append(rev.OCSP, s.queryOCSP(ctx, url, leaf, issuer))
+		}
+	}
+	for _, url := range leaf.CRLDistributionPoints {
+		if httpURL(url) {
+			rev.CRL = append(rev.CRL, s.checkCRL(ctx, url, leaf, issuer))
+		}
+	}
+	return rev
+}
+
+// httpURL — seules les URL HTTP(S) sont suivies (les CRL LDAP existent encore chez certaines AC internes)
+func httpURL(url string) bool {
+	return strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")
+}
+
+// queryOCSP envoie une requête OCSP (RFC 6960) au répondeur url
+// POST plutôt que GET : le GET encode la requête dans l'URL, en base64, ce que certains répondeurs gèrent mal
+func (s SSLScanner) queryOCSP(ctx context.Context, url string, leaf, issuer *x509.Certificate) OCSPStatus {
+	// SHA-1 : seul algorithme de hachage de CertID accepté par tous les répondeurs (RFC 5019)
+	req, err := ocsp.CreateRequest(leaf, issuer, &ocsp.RequestOptions{Hash: crypto.SHA1})
+	if err != nil {
+		return OCSPStatus{URL: url, Error: err.Error()}
+	}
+	resp, err := post(ctx, s.Client, url, "application/ocsp-request", req)
+	if err != nil {
+		return OCSPStatus{URL: url, Error: err.Error()}
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return OCSPStatus{URL: url, Error: fmt.Sprintf("statut HTTP %d", resp.StatusCode)}
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxOCSPResponse))
+	if err != nil {
+		return OCSPStatus{URL: url, Error: err.Error()}
+	}
+	status := parseOCSP(body, leaf, issuer)
+	status.URL = url
+	return status
+}
+
+// parseOCSP vérifie la signature d'une réponse OCSP (émetteur ou répondeur délégué) et en extrait le statut
+// Une réponse périmée (NextUpdate dépassé) ne prouve plus rien : elle est traitée comme une erreur
+func parseOCSP(der []byte, leaf, issuer *x509.Certificate) OCSPStatus {
+	resp, err := ocsp.ParseResponseForCert(der, leaf, issuer)
+	if err != nil {
+		return OCSPStatus{Error: err.Error()}
+	}
+	status := OCSPStatus{NextUpdate: resp.NextUpdate}
+	switch resp.Status {
+	case ocsp.Good:
+		status.Status = "good"
+	case ocsp.Revoked:
+		status.Status = "revoked"
+		status.RevokedAt = &resp.RevokedAt
+	default:
+		status.Status = "unknown"
+	}
+	if !resp.NextUpdate.IsZero() && time.Now().After(resp.NextUpdate) {
+		status.Error = "réponse périmée depuis le " + resp.NextUpdate.Format("02/01/2006")
+	}
+	return status
+}
+
+// checkCRL télécharge la CRL url, vérifie qu'elle est signée par l'émetteur et y cherche le numéro de série
+func (s SSLScanner) checkCRL(ctx context.Context, url string, leaf, issuer *x509.Certificate) CRLStatus {
+	status := CRLStatus{URL: url}
+	resp, err := get(ctx, s.Client, url)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		status.Error = fmt.Sprintf("statut HTTP %d", resp.StatusCode)
+		return status
+	}
+	der, err := io.ReadAll(io.LimitReader(resp.Body, maxCRL))
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+
+	crl, err := x509.ParseRevocationList(der)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	if err := crl.CheckSignatureFrom(issuer); err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.NextUpdate = crl.NextUpdate
+	for _, entry := range crl.RevokedCertificateEntries {
+		if entry.SerialNumber.Cmp(leaf.SerialNumber) == 0 {
+			status.Revoked = true
+			status.RevokedAt = &entry.RevocationTime
+			break
+		}
+	}
+	return status
+}
+
+// checkRevocationStatus transforme le statut de révocation en findings — mêmes IDs que le contrôle passe ou échoue
+func checkRevocationStatus(result *Result, asset string, rev *Revocation) {
+	add := func(id, title string, severity Severity, evidence string) {
+		result.add(Finding{ID: id, Title: title, Severity: severity, Category: "tls", Evidence: evidence, Asset: asset})
+	}
+
+	// Agrafage OCSP : sans lui, le navigateur interroge lui-même le répondeur (latence, fuite de la navigation)
+	switch {
+	case rev.Stapled != nil && rev.Stapled.Error != "":
+		add("ssl.ocsp_stapling", "Réponse OCSP agrafée invalide", SeverityMedium, rev.Stapled.Error)
+	case rev.Stapled != nil:
+		add("ssl.ocsp_stapling", "Agrafage OCSP actif", SeverityInfo, fmt.Sprintf("Réponse agrafée : %s | Must-Staple : %t", rev.Stapled.Status, rev.MustStaple))
+	case rev.MustStaple:
+		add("ssl.ocsp_stapling", "Must-Staple sans réponse agrafée", SeverityHigh, "Le certificat exige l'agrafage OCSP mais le serveur n'en envoie pas : Firefox refuse la connexion")
+	default:
+		add("ssl.ocsp_stapling", "Pas d'agrafage OCSP", SeverityLow, "Aucune réponse OCSP dans le handshake | Must-Staple : false")
+	}
+
+	if rev.Error != "" {
+		add("ssl.revocation", "Révocation non vérifiable", SeverityLow, rev.Error)
+		return
+	}
+
+	// Une seule source qui signale la révocation suffit
+	var checked, revoked, failed []string
+	note := func(source string, isRevoked bool, revokedAt *time.Time, err string) {
+		switch {
+		case isRevoked:
+			revoked = append(revoked, fmt.Sprintf("%s (le %s)", source, revokedAt.Format("02/01/2006")))
+		case err != "":
+			failed = append(failed, source+" : "+err)
+		default:
+			checked = append(checked, source)
+		}
+	}
+	if st := rev.Stapled; st != nil {
+		note("OCSP agrafé", st.Status == "revoked", st.RevokedAt, ocspError(*st))
+	}
+	for _, st := range rev.OCSP {
+		note("OCSP "+st.URL, st.Status == "revoked", st.RevokedAt, ocspError(st))
+	}
+	for _, crl := range rev.CRL {
+		note("CRL "+crl.URL, crl.Revoked, crl.RevokedAt, crl.Error)
+	}
+
+	switch {
+	case len(revoked) > 0:
+		add("ssl.revocation", "Certificat révoqué", SeverityCritical, "Révoqué selon "+strings.Join(revoked, ", "))
+	case len(checked) > 0:
+		add("ssl.revocation", "Certificat non révoqué", SeverityInfo, "Vérifié via "+strings.Join(checked, ", "))
+	case len(failed) > 0:
+		add("ssl.revocation", "Révocation non vérifiable", SeverityLow, strings.Join(failed, " | "))
+	default:
+		add("ssl.revocation", "Révocation non vérifiable", SeverityLow, "Ni répondeur OCSP ni CRL dans le certificat")
+	}
+}
+
+// ocspError retourne l'erreur d'une réponse OCSP — le statut unknown (certificat inconnu du répondeur) en est une
+func ocspError(st OCSPStatus) string {
+	if st.Error == "" && st.Status == "unknown" {
+		return "certificat inconnu du répondeur"
+	}
+	return st.Error
+}
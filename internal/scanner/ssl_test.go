@@ -1,6 +1,31 @@
 package scanner
 
-import "testing"
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daviani/go__001/internal/tlsprobe"
+	"golang.org/x/crypto/ocsp"
+)
 
 // TestSSLScanner_Name vérifie que le scanner retourne le bon identifiant
 func TestSSLScanner_Name(t *testing.T) {
@@ -15,31 +40,776 @@ func TestSSLScanner_Name(t *testing.T) {
 // SSLScanner utilise tls.Dial sur le port 443 pour récupérer le certificat x509
 func TestSSLScanner_Scan(t *testing.T) {
 	// tls.Dial("tcp", "google.com:443", nil) en interne
-	result, err := SSLScanner{}.Scan("google.com")
+	result, err := SSLScanner{}.Scan(context.Background(), "google.com", nil)
 
 	// La connexion TLS doit réussir — Google a un certificat valide
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	// Le résultat contient "Domaine: ... | Émetteur: ... | Expire: ..."
-	if result == "" {
-		t.Errorf("got empty result, want certificate info")
+	// Le résultat contient les findings ssl.certificate et ssl.issuer
+	if len(result.Findings) == 0 {
+		t.Errorf("got no findings, want certificate info")
 	}
 }
 
 // TestSSLScanner_Scan_InvalidDomain — Error path : un domaine invalide fait échouer tls.Dial
 // "false_url" → résolution DNS échoue → tls.Dial retourne une erreur immédiatement
 func TestSSLScanner_Scan_InvalidDomain(t *testing.T) {
-	result, err := SSLScanner{}.Scan("false_url")
+	result, err := SSLScanner{}.Scan(context.Background(), "false_url", nil)
 
 	// tls.Dial échoue car le domaine n'existe pas (pas de handshake TLS possible)
 	if err == nil {
 		t.Errorf("expected error for invalid domain, got nil")
 	}
 
-	// Contrat : en cas d'erreur, result doit être "" (string vide)
-	if result != "" {
-		t.Errorf("expected empty result, got %s", result)
+	// Contrat : en cas d'erreur, result ne contient aucun finding
+	if len(result.Findings) != 0 {
+		t.Errorf("expected no findings, got %v", result.Findings)
+	}
+}
+
+// constrainedTLS démarre un serveur TLS local restreint par config et retourne un scanner qui lui fait confiance
+// ainsi que son port (le certificat httptest couvre 127.0.0.1)
+func constrainedTLS(t *testing.T, config *tls.Config) (SSLScanner, int) {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = config
+	// La sonde abandonne chaque handshake après ServerHello : le serveur le journaliserait comme une erreur
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+	port, _ := strconv.Atoi(srv.URL[len("https://127.0.0.1:"):])
+	return SSLScanner{RootCAs: roots}, port
+}
+
+// rsaCertificate génère un certificat auto-signé pour 127.0.0.1 avec une clé RSA de bits bits
+func rsaCertificate(t *testing.T, bits int) tls.Certificate {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// TestSSLScanner_Scan_Audit — protocoles, suites et note sur des serveurs locaux aux configurations restreintes
+func TestSSLScanner_Scan_Audit(t *testing.T) {
+	tests := []struct {
+		name   string
+		config func(t *testing.T) *tls.Config
+		want   map[string]Severity // ID → sévérité attendue ("" = finding absent)
+		grade  string
+	}{
+		{
+			// Configuration par défaut de crypto/tls : TLS 1.2 et 1.3, ECDHE uniquement
+			name:   "moderne",
+			config: func(t *testing.T) *tls.Config { return &tls.Config{} },
+			want: map[string]Severity{
+				"ssl.protocol.ssl3": SeverityInfo, "ssl.protocol.tls1_0": SeverityInfo, "ssl.protocol.tls1_2": SeverityInfo,
+				"ssl.protocol.tls1_3": SeverityInfo, "ssl.cipher.insecure": SeverityInfo, "ssl.forward_secrecy": SeverityInfo,
+				"ssl.key": SeverityInfo, "ssl.dh": "", "ssl.grade": SeverityInfo,
+			},
+			grade: "A",
+		},
+		{
+			// TLS 1.0 à 1.2 avec RC4, 3DES et échange de clés RSA
+			name: "legacy",
+			config: func(t *testing.T) *tls.Config {
+				return &tls.Config{
+					MinVersion: tls.VersionTLS10,
+					MaxVersion: tls.VersionTLS12,
+					CipherSuites: []uint16{
+						tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+						tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+						tls.TLS_RSA_WITH_3DES_EDE_CBC_SHA,
+						tls.TLS_RSA_WITH_RC4_128_SHA,
+					},
+				}
+			},
+			want: map[string]Severity{
+				"ssl.protocol.tls1_0": SeverityMedium, "ssl.protocol.tls1_1": SeverityMedium, "ssl.protocol.tls1_3": SeverityLow,
+				"ssl.cipher.insecure": SeverityHigh, "ssl.cipher.3des": SeverityMedium, "ssl.cipher.cbc_tls10": SeverityLow,
+				"ssl.forward_secrecy": SeverityLow, "ssl.grade": SeverityMedium,
+			},
+			grade: "C",
+		},
+		{
+			// TLS 1.3 seul : TLS 1.2 absent n'est pas une faiblesse
+			name:   "TLS 1.3 seul",
+			config: func(t *testing.T) *tls.Config { return &tls.Config{MinVersion: tls.VersionTLS13} },
+			want: map[string]Severity{
+				"ssl.protocol.tls1_2": SeverityInfo, "ssl.protocol.tls1_3": SeverityInfo, "ssl.cipher.cbc_tls10": SeverityInfo,
+				"ssl.grade": SeverityInfo,
+			},
+			grade: "A",
+		},
+		{
+			// Échange de clés RSA uniquement, clé de 1024 bits
+			name: "clé RSA 1024 sans confidentialité persistante",
+			config: func(t *testing.T) *tls.Config {
+				return &tls.Config{
+					MaxVersion:   tls.VersionTLS12,
+					CipherSuites: []uint16{tls.TLS_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_RSA_WITH_AES_256_GCM_SHA384},
+					Certificates: []tls.Certificate{rsaCertificate(t, 1024)},
+				}
+			},
+			want: map[string]Severity{
+				"ssl.key": SeverityHigh, "ssl.forward_secrecy": SeverityMedium, "ssl.protocol.tls1_3": SeverityLow,
+				"ssl.grade": SeverityLow,
+			},
+			grade: "B",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner, port := constrainedTLS(t, tt.config(t))
+			result, err := scanner.Scan(context.Background(), "127.0.0.1", Options{"ports": []int{port}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			byID := findingsByID(result)
+			for id, severity := range tt.want {
+				f, ok := byID[id]
+				if severity == "" {
+					if ok {
+						t.Errorf("%s : got %+v, want absent", id, f)
+					}
+					continue
+				}
+				if !ok || f.Severity != severity {
+					t.Errorf("%s : got %+v, want %s", id, f, severity)
+				}
+			}
+
+			audit := result.Data.([]CertificateInfo)[0].Audit
+			if audit == nil || audit.Grade != tt.grade {
+				t.Fatalf("got audit %+v, want grade %s", audit, tt.grade)
+			}
+			for _, p := range audit.Protocols {
+				if p.Error != "" {
+					t.Errorf("%s : unexpected probe error %s", p.Version, p.Error)
+				}
+			}
+		})
+	}
+}
+
+// TestSSLScanner_Scan_NoAudit — option audit=false : seul le certificat est lu
+func TestSSLScanner_Scan_NoAudit(t *testing.T) {
+	scanner, port := constrainedTLS(t, &tls.Config{})
+	result, err := scanner.Scan(context.Background(), "127.0.0.1", Options{"ports": []int{port}, "audit": false})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info := result.Data.([]CertificateInfo)[0]; info.Audit != nil || info.KeyType != "RSA" {
+		t.Errorf("got %+v, want RSA key and no audit", info)
+	}
+	if _, ok := findingsByID(result)["ssl.grade"]; ok {
+		t.Error("got ssl.grade finding, want none")
+	}
+}
+
+// TestGradeTLS — plafonds pour les faiblesses qu'un serveur crypto/tls ne peut pas reproduire (SSLv3, EXPORT, DH)
+func TestGradeTLS(t *testing.T) {
+	suite := func(name string) tlsprobe.Suite {
+		s, ok := tlsprobe.SuiteByName(name)
+		if !ok {
+			t.Fatalf("unknown suite %s", name)
+		}
+		return s
+	}
+	modern := tlsprobe.Protocol{Version: tlsprobe.VersionTLS12, Supported: true, Suites: []tlsprobe.Suite{suite("TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384")}}
+	rsa2048 := CertificateInfo{KeyType: "RSA", KeyBits: 2048}
+
+	tests := []struct {
+		name      string
+		protocols []tlsprobe.Protocol
+		cert      CertificateInfo
+		dhBits    int
+		want      string
+	}{
+		{"TLS 1.2 ECDHE", []tlsprobe.Protocol{modern}, rsa2048, 0, "A"},
+		{"ECDSA P-256", []tlsprobe.Protocol{modern}, CertificateInfo{KeyType: "ECDSA", KeyBits: 256}, 0, "A"},
+		{"SSLv3", []tlsprobe.Protocol{modern, {Version: tlsprobe.VersionSSL30, Supported: true, Suites: []tlsprobe.Suite{suite("TLS_RSA_WITH_AES_128_CBC_SHA")}}}, rsa2048, 0, "C"},
+		{"EXPORT", []tlsprobe.Protocol{{Version: tlsprobe.VersionTLS10, Supported: true, Suites: []tlsprobe.Suite{suite("TLS_RSA_WITH_AES_128_CBC_SHA"), suite("TLS_RSA_EXPORT_WITH_RC4_40_MD5")}}}, rsa2048, 0, "F"},
+		{"DH 1024", []tlsprobe.Protocol{{Version: tlsprobe.VersionTLS12, Supported: true, Suites: []tlsprobe.Suite{suite("TLS_DHE_RSA_WITH_AES_128_GCM_SHA256")}}}, rsa2048, 1024, "B"},
+		{"DH 512", []tlsprobe.Protocol{{Version: tlsprobe.VersionTLS12, Supported: true, Suites: []tlsprobe.Suite{suite("TLS_DHE_RSA_WITH_AES_128_GCM_SHA256")}}}, rsa2048, 512, "F"},
+		{"aucun protocole", []tlsprobe.Protocol{{Version: tlsprobe.VersionTLS12}}, rsa2048, 0, "F"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, grade, caps := gradeTLS(tt.protocols, make([]error, len(tt.protocols)), tt.cert, tt.dhBits)
+			if grade != tt.want {
+				t.Errorf("got %s (caps %q), want %s", grade, caps, tt.want)
+			}
+		})
+	}
+}
+
+// testPKI — racine et intermédiaire locaux, pour émettre des certificats de serveur aux défauts choisis
+type testPKI struct {
+	root, intermediate *x509.Certificate
+	intermediateKey    *ecdsa.PrivateKey
+	roots              *x509.CertPool
+}
+
+// newTestPKI crée une racine et un intermédiaire signé par elle
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+	rootKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	intermediateKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ca := func(serial int64, name string) *x509.Certificate {
+		return &x509.Certificate{
+			SerialNumber:          big.NewInt(serial),
+			Subject:               pkix.Name{CommonName: name},
+			NotBefore:             time.Now().Add(-time.Hour),
+			NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		}
+	}
+	rootTemplate := ca(1, "Test Root")
+	rootDER, err := x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, &rootKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, _ := x509.ParseCertificate(rootDER)
+	intermediateDER, err := x509.CreateCertificate(rand.Reader, ca(2, "Test Intermediate"), root, &intermediateKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intermediate, _ := x509.ParseCertificate(intermediateDER)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	return &testPKI{root: root, intermediate: intermediate, intermediateKey: intermediateKey, roots: roots}
+}
+
+// issue émet un certificat de serveur pour 127.0.0.1 signé par l'intermédiaire ; edit ajuste le modèle
+// Le certificat servi contient la feuille, suivie de l'intermédiaire si withIntermediate
+func (p *testPKI) issue(t *testing.T, withIntermediate bool, edit func(*x509.Certificate)) tls.Certificate {
+	t.Helper()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "leaf"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(90 * 24 * time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if edit != nil {
+		edit(template)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, p.intermediate, &key.PublicKey, p.intermediateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+	if withIntermediate {
+		cert.Certificate = append(cert.Certificate, p.intermediate.Raw)
+	}
+	return cert
+}
+
+// sctExtension construit une extension SignedCertificateTimestampList contenant n SCT factices
+func sctExtension(t *testing.T, n int) pkix.Extension {
+	t.Helper()
+	var list []byte
+	for range n {
+		list = append(list, 0, 4, 0, 1, 2, 3) // longueur 4, puis 4 octets
+	}
+	value, err := asn1.Marshal(append([]byte{byte(len(list) >> 8), byte(len(list))}, list...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pkix.Extension{Id: oidSCTList, Value: value}
+}
+
+// TestSSLScanner_Scan_Chain — chaîne complète, incomplète, auto-signée, dates, nom, signature et SCT
+func TestSSLScanner_Scan_Chain(t *testing.T) {
+	pki := newTestPKI(t)
+	// Serveur AIA : publie l'intermédiaire que le serveur TLS « oublie » d'envoyer
+	aia := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(pki.intermediate.Raw)
+	}))
+	defer aia.Close()
+
+	tests := []struct {
+		name  string
+		cert  func(t *testing.T) tls.Certificate
+		roots *x509.CertPool
+		opts  Options
+		want  map[string]Severity
+		check func(t *testing.T, info CertificateInfo)
+	}{
+		{
+			name: "chaîne complète",
+			cert: func(t *testing.T) tls.Certificate { return pki.issue(t, true, nil) },
+			want: map[string]Severity{
+				"ssl.chain": SeverityInfo, "ssl.expiry": SeverityInfo, "ssl.hostname": SeverityInfo,
+				"ssl.key": SeverityInfo, "ssl.signature": SeverityInfo, "ssl.sct": SeverityLow,
+			},
+			check: func(t *testing.T, info CertificateInfo) {
+				if !info.Trusted || len(info.Chain) != 2 || info.KeyType != "ECDSA" || info.DaysLeft < 88 {
+					t.Errorf("got %+v", info)
+				}
+			},
+		},
+		{
+			name: "chaîne incomplète",
+			cert: func(t *testing.T) tls.Certificate {
+				return pki.issue(t, false, func(c *x509.Certificate) { c.IssuingCertificateURL = []string{aia.URL} })
+			},
+			want: map[string]Severity{"ssl.chain": SeverityMedium},
+			check: func(t *testing.T, info CertificateInfo) {
+				if !info.Trusted || !info.IncompleteChain {
+					t.Errorf("got trusted=%v incomplete=%v, want both", info.Trusted, info.IncompleteChain)
+				}
+			},
+		},
+		{
+			// Sans URL AIA, l'intermédiaire manquant ne peut pas être retrouvé
+			name: "intermédiaire manquant sans AIA",
+			cert: func(t *testing.T) tls.Certificate { return pki.issue(t, false, nil) },
+			want: map[string]Severity{"ssl.chain": SeverityHigh},
+		},
+		{
+			name:  "autorité inconnue",
+			cert:  func(t *testing.T) tls.Certificate { return pki.issue(t, true, nil) },
+			roots: x509.NewCertPool(),
+			want:  map[string]Severity{"ssl.chain": SeverityHigh},
+			check: func(t *testing.T, info CertificateInfo) {
+				if info.Trusted || info.SelfSigned || info.ChainError == "" {
+					t.Errorf("got %+v", info)
+				}
+			},
+		},
+		{
+			name: "auto-signé",
+			cert: func(t *testing.T) tls.Certificate { return rsaCertificate(t, 2048) },
+			want: map[string]Severity{"ssl.chain": SeverityHigh, "ssl.hostname": SeverityInfo},
+			check: func(t *testing.T, info CertificateInfo) {
+				if !info.SelfSigned || info.Trusted {
+					t.Errorf("got self_signed=%v trusted=%v", info.SelfSigned, info.Trusted)
+				}
+			},
+		},
+		{
+			// Chaîne valide mais certificat expiré : les deux défauts sont rapportés séparément
+			name: "expiré",
+			cert: func(t *testing.T) tls.Certificate {
+				return pki.issue(t, true, func(c *x509.Certificate) {
+					c.NotBefore = time.Now().Add(-2 * time.Hour)
+					c.NotAfter = time.Now().Add(-30 * time.Minute)
+				})
+			},
+			want: map[string]Severity{"ssl.expiry": SeverityHigh, "ssl.chain": SeverityInfo},
+		},
+		{
+			name: "pas encore valide",
+			cert: func(t *testing.T) tls.Certificate {
+				return pki.issue(t, true, func(c *x509.Certificate) { c.NotBefore = time.Now().Add(24 * time.Hour) })
+			},
+			want: map[string]Severity{"ssl.expiry": SeverityHigh},
+		},
+		{
+			name: "expire dans 20 jours",
+			cert: func(t *testing.T) tls.Certificate {
+				return pki.issue(t, true, func(c *x509.Certificate) { c.NotAfter = time.Now().Add(20 * 24 * time.Hour) })
+			},
+			want: map[string]Severity{"ssl.expiry": SeverityMedium},
+		},
+		{
+			// Seuils configurables : alerte à 10 jours au lieu de 30
+			name: "expire dans 20 jours, seuil à 10",
+			cert: func(t *testing.T) tls.Certificate {
+				return pki.issue(t, true, func(c *x509.Certificate) { c.NotAfter = time.Now().Add(20 * 24 * time.Hour) })
+			},
+			opts: Options{"expiry_warning_days": 10},
+			want: map[string]Severity{"ssl.expiry": SeverityInfo},
+		},
+		{
+			name: "expire dans 3 jours",
+			cert: func(t *testing.T) tls.Certificate {
+				return pki.issue(t, true, func(c *x509.Certificate) { c.NotAfter = time.Now().Add(3 * 24 * time.Hour) })
+			},
+			want: map[string]Severity{"ssl.expiry": SeverityHigh},
+		},
+		{
+			name: "nom non couvert",
+			cert: func(t *testing.T) tls.Certificate {
+				return pki.issue(t, true, func(c *x509.Certificate) {
+					c.IPAddresses = nil
+					c.DNSNames = []string{"example.com"}
+				})
+			},
+			want: map[string]Severity{"ssl.hostname": SeverityHigh, "ssl.chain": SeverityInfo},
+		},
+		{
+			// Le CommonName seul ne suffit plus : les SAN sont obligatoires
+			name: "CommonName seul",
+			cert: func(t *testing.T) tls.Certificate {
+				return pki.issue(t, true, func(c *x509.Certificate) {
+					c.IPAddresses = nil
+					c.Subject.CommonName = "127.0.0.1"
+				})
+			},
+			want: map[string]Severity{"ssl.hostname": SeverityHigh},
+		},
+		{
+			name: "signature SHA-1",
+			cert: func(t *testing.T) tls.Certificate {
+				return pki.issue(t, true, func(c *x509.Certificate) { c.SignatureAlgorithm = x509.ECDSAWithSHA1 })
+			},
+			want: map[string]Severity{"ssl.signature": SeverityHigh},
+		},
+		{
+			name: "SCT intégrés",
+			cert: func(t *testing.T) tls.Certificate {
+				return pki.issue(t, true, func(c *x509.Certificate) { c.ExtraExtensions = []pkix.Extension{sctExtension(t, 2)} })
+			},
+			want: map[string]Severity{"ssl.sct": SeverityInfo},
+			check: func(t *testing.T, info CertificateInfo) {
+				if info.SCTs != 2 {
+					t.Errorf("got %d SCTs, want 2", info.SCTs)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner, port := constrainedTLS(t, &tls.Config{Certificates: []tls.Certificate{tt.cert(t)}})
+			scanner.RootCAs = pki.roots
+			if tt.roots != nil {
+				scanner.RootCAs = tt.roots
+			}
+			opts := Options{"ports": []int{port}, "audit": false}
+			for name, value := range tt.opts {
+				opts[name] = value
+			}
+
+			result, err := scanner.Scan(context.Background(), "127.0.0.1", opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			byID := findingsByID(result)
+			for id, severity := range tt.want {
+				if f, ok := byID[id]; !ok || f.Severity != severity {
+					t.Errorf("%s : got %+v, want %s", id, f, severity)
+				}
+			}
+			if tt.check != nil {
+				tt.check(t, result.Data.([]CertificateInfo)[0])
+			}
+		})
+	}
+}
+
+// revokedSerial — numéro de série que le répondeur de test déclare révoqué
+const revokedSerial = 66
+
+// revocationServer démarre un répondeur OCSP (/ocsp) et un point de distribution CRL (/crl) locaux,
+// signés par l'intermédiaire de pki : seul le certificat de numéro revokedSerial est révoqué
+func revocationServer(t *testing.T, pki *testPKI) *httptest.Server {
+	t.Helper()
+	now := time.Now()
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /ocsp", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		req, err := ocsp.ParseRequest(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		status := ocsp.Good
+		if req.SerialNumber.Int64() == revokedSerial {
+			status = ocsp.Revoked
+		}
+		_, _ = w.Write(ocspResponse(t, pki, req.SerialNumber, status))
+	})
+	mux.HandleFunc("GET /crl", func(w http.ResponseWriter, r *http.Request) {
+		der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+			Number:     big.NewInt(1),
+			ThisUpdate: now.Add(-time.Hour),
+			NextUpdate: now.Add(24 * time.Hour),
+			RevokedCertificateEntries: []x509.RevocationListEntry{
+				{SerialNumber: big.NewInt(revokedSerial), RevocationTime: now.Add(-time.Hour)},
+			},
+		}, pki.intermediate, pki.intermediateKey)
+		if err != nil {
+			t.Error(err)
+		}
+		_, _ = w.Write(der)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// ocspResponse signe une réponse OCSP pour serial avec la clé de l'intermédiaire (répondeur = émetteur)
+func ocspResponse(t *testing.T, pki *testPKI, serial *big.Int, status int) []byte {
+	t.Helper()
+	der, err := ocsp.CreateResponse(pki.intermediate, pki.intermediate, ocsp.Response{
+		Status:       status,
+		SerialNumber: serial,
+		ThisUpdate:   time.Now().Add(-time.Hour),
+		NextUpdate:   time.Now().Add(24 * time.Hour),
+		RevokedAt:    time.Now().Add(-time.Hour),
+	}, pki.intermediateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+// TestSSLScanner_Scan_Revocation — OCSP agrafé, répondeur OCSP, CRL et Must-Staple, face à un répondeur local
+func TestSSLScanner_Scan_Revocation(t *testing.T) {
+	pki := newTestPKI(t)
+	responder := revocationServer(t, pki)
+	mustStapleExt := func(t *testing.T) pkix.Extension {
+		value, err := asn1.Marshal([]int{5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pkix.Extension{Id: oidTLSFeature, Value: value}
+	}
+	// stapled émet un certificat sans URL de révocation, accompagné d'une réponse OCSP agrafée
+	stapled := func(status int) func(t *testing.T) tls.Certificate {
+		return func(t *testing.T) tls.Certificate {
+			cert := pki.issue(t, true, nil)
+			cert.OCSPStaple = ocspResponse(t, pki, big.NewInt(3), status)
+			return cert
+		}
+	}
+
+	tests := []struct {
+		name string
+		cert func(t *testing.T) tls.Certificate
+		opts Options
+		want map[string]Severity
+	}{
+		{
+			name: "répondeur OCSP : valide",
+			cert: func(t *testing.T) tls.Certificate {
+				return pki.issue(t, true, func(c *x509.Certificate) { c.OCSPServer = []string{responder.URL + "/ocsp"} })
+			},
+			want: map[string]Severity{"ssl.revocation": SeverityInfo, "ssl.ocsp_stapling": SeverityLow},
+		},
+		{
+			name: "répondeur OCSP : révoqué",
+			cert: func(t *testing.T) tls.Certificate {
+				return pki.issue(t, true, func(c *x509.Certificate) {
+					c.SerialNumber = big.NewInt(revokedSerial)
+					c.OCSPServer = []string{responder.URL + "/ocsp"}
+				})
+			},
+			want: map[string]Severity{"ssl.revocation": SeverityCritical},
+		},
+		{
+			name: "CRL : valide",
+			cert: func(t *testing.T) tls.Certificate {
+				return pki.issue(t, true, func(c *x509.Certificate) { c.CRLDistributionPoints = []string{responder.URL + "/crl"} })
+			},
+			want: map[string]Severity{"ssl.revocation": SeverityInfo},
+		},
+		{
+			name: "CRL : révoqué",
+			cert: func(t *testing.T) tls.Certificate {
+				return pki.issue(t, true, func(c *x509.Certificate) {
+					c.SerialNumber = big.NewInt(revokedSerial)
+					c.CRLDistributionPoints = []string{responder.URL + "/crl"}
+				})
+			},
+			want: map[string]Severity{"ssl.revocation": SeverityCritical},
+		},
+		{
+			name: "réponse agrafée : valide",
+			cert: stapled(ocsp.Good),
+			want: map[string]Severity{"ssl.revocation": SeverityInfo, "ssl.ocsp_stapling": SeverityInfo},
+		},
+		{
+			name: "réponse agrafée : révoqué",
+			cert: stapled(ocsp.Revoked),
+			want: map[string]Severity{"ssl.revocation": SeverityCritical, "ssl.ocsp_stapling": SeverityInfo},
+		},
+		{
+			name: "Must-Staple sans réponse agrafée",
+			cert: func(t *testing.T) tls.Certificate {
+				return pki.issue(t, true, func(c *x509.Certificate) { c.ExtraExtensions = []pkix.Extension{mustStapleExt(t)} })
+			},
+			want: map[string]Severity{"ssl.ocsp_stapling": SeverityHigh},
+		},
+		{
+			name: "répondeur injoignable",
+			cert: func(t *testing.T) tls.Certificate {
+				return pki.issue(t, true, func(c *x509.Certificate) { c.OCSPServer = []string{responder.URL + "/absent"} })
+			},
+			want: map[string]Severity{"ssl.revocation": SeverityLow},
+		},
+		{
+			name: "aucune source",
+			cert: func(t *testing.T) tls.Certificate { return pki.issue(t, true, nil) },
+			want: map[string]Severity{"ssl.revocation": SeverityLow},
+		},
+		{
+			name: "option revocation désactivée",
+			cert: func(t *testing.T) tls.Certificate { return pki.issue(t, true, nil) },
+			opts: Options{"revocation": false},
+			want: map[string]Severity{"ssl.revocation": "", "ssl.ocsp_stapling": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner, port := constrainedTLS(t, &tls.Config{Certificates: []tls.Certificate{tt.cert(t)}})
+			scanner.RootCAs = pki.roots
+			opts := Options{"ports": []int{port}, "audit": false}
+			for name, value := range tt.opts {
+				opts[name] = value
+			}
+
+			result, err := scanner.Scan(context.Background(), "127.0.0.1", opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			byID := findingsByID(result)
+			for id, severity := range tt.want {
+				f, ok := byID[id]
+				if severity == "" {
+					if ok {
+						t.Errorf("%s : got %+v, want absent", id, f)
+					}
+					continue
+				}
+				if !ok || f.Severity != severity {
+					t.Errorf("%s : got %+v, want %s", id, f, severity)
+				}
+			}
+		})
+	}
+}
+
+// smtpServer démarre un serveur SMTP qui propose STARTTLS puis passe en TLS avec config — retourne son port
+// config nil : STARTTLS n'est pas annoncé
+func smtpServer(t *testing.T, config *tls.Config) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	session := func(conn net.Conn) {
+		defer func() { _ = conn.Close() }()
+		r := bufio.NewReader(conn)
+		_, _ = conn.Write([]byte("220 mail.example.com ESMTP\r\n"))
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch strings.ToUpper(strings.Fields(line + " x")[0]) {
+			case "EHLO":
+				if config == nil {
+					_, _ = conn.Write([]byte("250-mail.example.com\r\n250 PIPELINING\r\n"))
+					continue
+				}
+				_, _ = conn.Write([]byte("250-mail.example.com\r\n250-PIPELINING\r\n250 STARTTLS\r\n"))
+			case "STARTTLS":
+				_, _ = conn.Write([]byte("220 Ready to start TLS\r\n"))
+				// Le client attend le 220 avant d'envoyer son ClientHello : rien n'est resté dans r
+				tlsConn := tls.Server(conn, config)
+				_ = tlsConn.Handshake()
+				_ = tlsConn.Close()
+				return
+			default:
+				_, _ = conn.Write([]byte("500 unknown command\r\n"))
+			}
+		}
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go session(conn)
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+// TestSSLScanner_Scan_StartTLS — certificat et audit d'un service SMTP STARTTLS, à côté d'un port TLS direct
+func TestSSLScanner_Scan_StartTLS(t *testing.T) {
+	scanner, httpsPort := constrainedTLS(t, &tls.Config{})
+	smtpPort := smtpServer(t, &tls.Config{Certificates: []tls.Certificate{rsaCertificate(t, 2048)}})
+
+	opts := Options{"ports": []int{httpsPort}, "starttls": []string{"smtp:" + strconv.Itoa(smtpPort)}, "revocation": false}
+	result, err := scanner.Scan(context.Background(), "127.0.0.1", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	infos := result.Data.([]CertificateInfo)
+	if len(infos) != 2 {
+		t.Fatalf("got %d endpoints, want 2", len(infos))
+	}
+	if infos[0].Port != httpsPort || infos[0].Protocol != "" {
+		t.Errorf("got %d/%q, want direct TLS on %d", infos[0].Port, infos[0].Protocol, httpsPort)
+	}
+	smtp := infos[1]
+	if smtp.Port != smtpPort || smtp.Protocol != "smtp" {
+		t.Fatalf("got %+v, want smtp certificate on port %d", smtp, smtpPort)
+	}
+	// Certificat auto-signé, distinct de celui du serveur HTTPS : les findings sont rattachés au bon port
+	if smtp.Audit == nil || smtp.Audit.Grade == "" {
+		t.Errorf("got audit %+v, want a grade through STARTTLS", smtp.Audit)
+	}
+	asset := net.JoinHostPort("127.0.0.1", strconv.Itoa(smtpPort))
+	for _, f := range result.Findings {
+		if f.Asset != asset {
+			continue
+		}
+		if (f.ID == "ssl.chain" && f.Severity != SeverityHigh) || (f.ID == "ssl.starttls" && f.Severity != SeverityInfo) {
+			t.Errorf("got %+v on %s", f, asset)
+		}
+	}
+}
+
+// TestSSLScanner_Scan_StartTLSNotOffered — serveur SMTP sans STARTTLS : finding high, pas d'erreur de scan
+func TestSSLScanner_Scan_StartTLSNotOffered(t *testing.T) {
+	port := smtpServer(t, nil)
+	result, err := SSLScanner{}.Scan(context.Background(), "127.0.0.1", Options{"starttls": []string{"smtp:" + strconv.Itoa(port)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f, ok := findingsByID(result)["ssl.starttls"]; !ok || f.Severity != SeverityHigh {
+		t.Errorf("got %+v, want high ssl.starttls", f)
+	}
+}
+
+// TestSSLScanner_Schema_StartTLS — service STARTTLS inconnu refusé à la validation
+func TestSSLScanner_Schema_StartTLS(t *testing.T) {
+	if _, err := (SSLScanner{}).Schema().ParseQuery(url.Values{"starttls": {"xmpp"}}); err == nil {
+		t.Error("expected error for unknown STARTTLS service")
 	}
 }
@@ -1,11 +1,16 @@
 package scanner
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
+	"sort"
 	"strings"
+
+	"github.com/daviani/go__001/internal/dnsclient"
 )
 
 // CrtShEntry représente une entrée de la réponse JSON de l'API crt.sh
@@ -15,48 +20,147 @@ type CrtShEntry struct {
 }
 
 // SubdomainScanner - Scanner pour l'énumération de sous-domaines via Certificate Transparency
-type SubdomainScanner struct{}
+// et, quand un serveur faisant autorité l'accepte, via transfert de zone (AXFR)
+type SubdomainScanner struct {
+	Client *http.Client      // Client HTTP utilisé (nil = client par défaut)
+	DNS    *dnsclient.Client // Client DNS du transfert de zone (nil = résolveurs du système)
+}
+
+// subdomainOptions — options typées de SubdomainScanner
+type subdomainOptions struct {
+	IncludeWildcards bool // Garder les entrées "*.example.com" des certificats wildcard
+	AXFR             bool // Tenter un transfert de zone auprès des serveurs faisant autorité
+}
 
 // Name retourne l'identifiant du scanner Subdomain
 func (sb SubdomainScanner) Name() string { return "subdomain" }
 
+// Info retourne les métadonnées du scanner (GET /scanners)
+func (sb SubdomainScanner) Info() Info {
+	return Info{
+		Title:       "Sous-domaines",
+		Description: "Énumère les sous-domaines via Certificate Transparency (crt.sh) et transfert de zone (AXFR) ouvert",
+		Version:     "1.1.0",
+	}
+}
+
+// Schema publie les options acceptées par le scanner Subdomain
+func (sb SubdomainScanner) Schema() Schema {
+	return Schema{
+		{
+			Name:        "include_wildcards",
+			Type:        OptionBool,
+			Description: "Inclure les noms wildcard (*.example.com) issus des certificats",
+			Default:     false,
+		},
+		{
+			Name:        "axfr",
+			Type:        OptionBool,
+			Description: "Tenter un transfert de zone (AXFR) auprès des serveurs faisant autorité et ajouter les noms obtenus",
+			Default:     true,
+		},
+	}
+}
+
+// options convertit les Options génériques en subdomainOptions
+func (sb SubdomainScanner) options(opts Options) subdomainOptions {
+	opts = sb.Schema().Apply(opts)
+	return subdomainOptions{IncludeWildcards: opts.Bool("include_wildcards"), AXFR: opts.Bool("axfr")}
+}
+
 // Scan interroge l'API crt.sh pour trouver tous les sous-domaines
 // ayant un certificat SSL émis pour le domaine cible
-func (sb SubdomainScanner) Scan(domain string) (string, error) {
+func (sb SubdomainScanner) Scan(ctx context.Context, domain string, opts Options) (Result, error) {
+	o := sb.options(opts)
 
 	// Construction de l'URL crt.sh — %%25 = %25 encodé (wildcard %)
 	url := fmt.Sprintf("https://crt.sh/?q=%%25.%s&output=json", domain)
 
-	resp, err := http.Get(url)
+	resp, err := get(ctx, sb.Client, url)
 	if err != nil {
-		return "", fmt.Errorf("erreur de subdomain: %w", err)
+		return Result{}, fmt.Errorf("erreur de subdomain: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	// Lit le body HTTP en entier et le désérialise en slice de CrtShEntry
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("erreur de lecture: %w", err)
+		return Result{}, fmt.Errorf("erreur de lecture: %w", err)
 	}
 
 	// &results : pointeur nécessaire pour que Unmarshal puisse remplir la slice
 	var results []CrtShEntry
 	if err = json.Unmarshal(body, &results); err != nil {
-		return "", fmt.Errorf("erreur de désérialisation: %w", err)
+		return Result{}, fmt.Errorf("erreur de désérialisation: %w", err)
 	}
 
-	// Dédoublonnage — map[string]bool utilisée comme Set
-	unique := make(map[string]bool)
+	// Dédoublonnage — map nom → sources (un nom peut venir de crt.sh et du transfert de zone)
+	// name_value peut contenir plusieurs noms séparés par "\n" (certificats multi-SAN)
+	unique := make(map[string][]string)
+	add := func(name, source string) {
+		if name == "" || (!o.IncludeWildcards && strings.HasPrefix(name, "*.")) {
+			return
+		}
+		if !slices.Contains(unique[name], source) {
+			unique[name] = append(unique[name], source)
+		}
+	}
 	for _, entry := range results {
-		unique[entry.NameValue] = true
+		for _, name := range strings.Split(entry.NameValue, "\n") {
+			add(strings.TrimSpace(name), "Certificate Transparency (crt.sh)")
+		}
+	}
+
+	// Transfert de zone ouvert : la liste complète des noms, y compris ceux jamais passés par un certificat public
+	// Un échec (aucun NS joignable, transfert refusé) n'est pas une erreur : c'est le cas normal
+	if o.AXFR {
+		for _, name := range sb.transferHostnames(ctx, domain) {
+			add(name, "transfert de zone (AXFR)")
+		}
 	}
 
-	// Extrait les clés uniques et les joint en string
-	// Équivalent JS : Object.keys(unique).join(", ")
+	// Extrait les clés uniques et les trie — l'ordre d'une map Go est aléatoire
+	// Équivalent JS : Object.keys(unique).sort()
 	var keys []string
 	for key := range unique {
 		keys = append(keys, key)
 	}
+	sort.Strings(keys)
+
+	result := newResult(ctx, keys)
+	for _, key := range keys {
+		result.add(Finding{
+			ID:       "subdomain.found",
+			Title:    "Sous-domaine découvert",
+			Severity: SeverityInfo,
+			Category: "subdomain",
+			Evidence: strings.Join(unique[key], ", "),
+			Asset:    key,
+		})
+	}
+
+	return result, nil
+}
+
+// transferHostnames tente un transfert de zone auprès des serveurs faisant autorité du domaine
+// Retourne les noms d'hôte sous domain (la zone peut être plus large quand domain est lui-même un sous-domaine)
+func (sb SubdomainScanner) transferHostnames(ctx context.Context, domain string) []string {
+	client := sb.DNS
+	if client == nil {
+		client = &dnsclient.Client{}
+	}
+	zone, servers, err := client.Authoritative(ctx, domain)
+	if err != nil {
+		return nil
+	}
+	_, hostnames := transferZone(ctx, client, zone, servers)
 
-	return "Sous domaines: " + strings.Join(keys, " , "), nil
+	suffix := "." + strings.ToLower(strings.TrimSuffix(domain, "."))
+	var names []string
+	for _, name := range hostnames {
+		if strings.HasSuffix(name, suffix) {
+			names = append(names, name)
+		}
+	}
+	return names
 }
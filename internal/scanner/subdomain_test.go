@@ -1,8 +1,17 @@
 package scanner
 
 import (
+	"context"
+	"io"
+	"net/http"
 	"os"
+	"slices"
+	"strings"
 	"testing"
+
+	"github.com/daviani/go__001/internal/dnsclient"
+	"github.com/daviani/go__001/internal/dnsclient/dnstest"
+	"golang.org/x/net/dns/dnsmessage"
 )
 
 // TestSubdomainScanner_Name vérifie que le scanner retourne le bon identifiant
@@ -25,15 +34,15 @@ func TestSubdomainScanner_Scan(t *testing.T) {
 	}
 
 	// crt.sh interroge les Certificate Transparency logs pour trouver les sous-domaines
-	result, err := SubdomainScanner{}.Scan("google.com")
+	result, err := SubdomainScanner{}.Scan(context.Background(), "google.com", nil)
 
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	// Le résultat doit contenir "Sous domaines: " suivi d'au moins un sous-domaine
-	if result == "" {
-		t.Errorf("got empty result, want subdomains list")
+	// Le résultat doit contenir au moins un finding subdomain.found
+	if len(result.Findings) == 0 {
+		t.Errorf("got no findings, want subdomains list")
 	}
 }
 
@@ -41,15 +50,48 @@ func TestSubdomainScanner_Scan(t *testing.T) {
 // Note : un domaine bidon (ex: "false_url") ne cause PAS d'erreur car crt.sh répond avec []
 // On utilise \x00 (caractère de contrôle) car http.Get refuse les URL avec des caractères invalides
 func TestSubdomainScanner_InvalidDomain(t *testing.T) {
-	result, err := SubdomainScanner{}.Scan("\x00")
+	result, err := SubdomainScanner{}.Scan(context.Background(), "\x00", nil)
 
 	// http.Get refuse les URL contenant des caractères de contrôle → erreur immédiate
 	if err == nil {
 		t.Errorf("expected error for invalid domain, got nil")
 	}
 
-	// Contrat : en cas d'erreur, result doit être "" (string vide)
-	if result != "" {
-		t.Errorf("expected empty result, got %s", result)
+	// Contrat : en cas d'erreur, result ne contient aucun finding
+	if len(result.Findings) != 0 {
+		t.Errorf("expected no findings, got %v", result.Findings)
+	}
+}
+
+// TestSubdomainScanner_Scan_AXFR — noms du transfert de zone fusionnés avec ceux de crt.sh (simulé)
+func TestSubdomainScanner_Scan_AXFR(t *testing.T) {
+	srv := dnstest.NewServer()
+	defer srv.Close()
+	exampleZone(srv, [4]byte{192, 0, 2, 10})
+	srv.Add(dnstest.Record("www.example.com.", &dnsmessage.AResource{A: [4]byte{192, 0, 2, 10}}))
+	srv.AllowTransfer("example.com")
+
+	scanner := SubdomainScanner{
+		Client: &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			body := `[{"name_value": "www.example.com\napi.example.com"}]`
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Request: r}, nil
+		})},
+		DNS: &dnsclient.Client{
+			Servers:     []string{"192.0.2.1:53"},
+			DialContext: dnstest.Dialer(map[string]*dnstest.Server{"192.0.2.1": srv, "192.0.2.53": srv, "192.0.2.54": srv}),
+		},
+	}
+	result, err := scanner.Scan(context.Background(), "example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"api.example.com", "ns1.example.com", "ns2.example.com", "www.example.com"}
+	if got := result.Data.([]string); !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	f, _ := findingFor(result, "subdomain.found", "www.example.com")
+	if f.Evidence != "Certificate Transparency (crt.sh), transfert de zone (AXFR)" {
+		t.Errorf("got evidence %q", f.Evidence)
 	}
 }
@@ -0,0 +1,513 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/daviani/go__001/internal/csp"
+	"github.com/daviani/go__001/internal/hstspreload"
+	"golang.org/x/net/html"
+)
+
+// Limites du suivi des redirections et de la lecture de la page d'accueil
+const (
+	transportMaxHops    = 10      // Au-delà, la chaîne est considérée comme une boucle
+	transportMaxHTML    = 2 << 20 // Octets de HTML analysés pour le contenu mixte
+	transportLongChain  = 3       // Redirections au-delà desquelles la chaîne est signalée
+	transportHSTSHeader = "Strict-Transport-Security"
+)
+
+// TransportScanner - Scanner de transport : redirection HTTP → HTTPS de l'apex et du www,
+// chaîne de redirections, HSTS sur la première réponse HTTPS, contenu mixte et preload list
+// HeaderScanner ne demande que https:// : il ne voit pas un site qui sert encore du HTTP en clair
+type TransportScanner struct {
+	Client  *http.Client      // Client HTTP utilisé (nil = client par défaut) — les redirections sont suivies pas à pas
+	Preload *hstspreload.List // Preload list consultée (nil = snapshot embarqué)
+}
+
+// TransportReport — chaînes de redirections par hôte, contenu mixte et inscription sur la preload list
+type TransportReport struct {
+	Hosts        []TransportHost `json:"hosts"`
+	LandingURL   string          `json:"landing_url,omitempty"` // Page d'accueil HTTPS analysée pour le contenu mixte
+	MixedContent []MixedResource `json:"mixed_content,omitempty"`
+	Preload      PreloadStatus   `json:"preload"`
+}
+
+// TransportHost — requête http:// sur un hôte et redirections suivies
+type TransportHost struct {
+	Host       string        `json:"host"`
+	Chain      []RedirectHop `json:"chain"`
+	FinalURL   string        `json:"final_url,omitempty"`   // Vide si la chaîne n'aboutit pas
+	FirstHTTPS string        `json:"first_https,omitempty"` // Première réponse HTTPS de la chaîne
+	HSTS       string        `json:"hsts,omitempty"`        // Strict-Transport-Security de cette réponse
+	Error      string        `json:"error,omitempty"`
+}
+
+// RedirectHop — une réponse de la chaîne
+type RedirectHop struct {
+	URL      string `json:"url"`
+	Status   int    `json:"status"`
+	Location string `json:"location,omitempty"` // URL absolue de la redirection
+}
+
+// MixedResource — ressource http:// chargée par la page HTTPS
+// Active (script, style, iframe, formulaire) : bloquée par le navigateur ; passive (image, média) : réécrite ou affichée avec avertissement
+type MixedResource struct {
+	Tag    string `json:"tag"`
+	URL    string `json:"url"`
+	Active bool   `json:"active"`
+}
+
+// PreloadStatus — inscription du domaine sur la preload list (snapshot consulté)
+type PreloadStatus struct {
+	Preloaded         bool   `json:"preloaded"`
+	Entry             string `json:"entry,omitempty"` // Nom inscrit : le domaine ou un parent (TLD .dev, .app...)
+	IncludeSubdomains bool   `json:"include_subdomains"`
+	ListSize          int    `json:"list_size"` // Nombre d'entrées du snapshot
+	Partial           bool   `json:"partial"`   // Liste incomplète (snapshot illisible) : une absence n'est pas concluante
+}
+
+// transportOptions — options typées de TransportScanner
+type transportOptions struct {
+	WWW          bool // Tester aussi www.<domaine>
+	MixedContent bool // Analyser le HTML de la page d'accueil
+}
+
+// mixedContentTags — attributs qui chargent une ressource, et si elle est active
+// link n'est retenu que pour les feuilles de style (voir mixedContent)
+var mixedContentTags = map[string]struct {
+	attr   string
+	active bool
+}{
+	"script": {"src", true},
+	"link":   {"href", true},
+	"iframe": {"src", true},
+	"frame":  {"src", true},
+	"object": {"data", true},
+	"embed":  {"src", true},
+	"form":   {"action", true},
+	"img":    {"src", false},
+	"audio":  {"src", false},
+	"video":  {"src", false},
+	"source": {"src", false},
+}
+
+// Name retourne l'identifiant du scanner Transport
+func (t TransportScanner) Name() string { return "transport" }
+
+// Info retourne les métadonnées du scanner (GET /scanners)
+func (t TransportScanner) Info() Info {
+	return Info{
+		Title:       "Transport HTTP → HTTPS",
+		Description: "Suit la redirection HTTP → HTTPS de l'apex et du www (nombre de sauts, changements de domaine, 302 au lieu de 301/308, retour en HTTP), vérifie HSTS sur la première réponse HTTPS, le contenu mixte de la page d'accueil et l'inscription sur la preload list HSTS",
+		Version:     "1.1.0",
+	}
+}
+
+// Schema publie les options acceptées par le scanner Transport
+func (t TransportScanner) Schema() Schema {
+	return Schema{
+		{
+			Name:        "www",
+			Type:        OptionBool,
+			Description: "Tester aussi la redirection de www.<domaine>",
+			Default:     true,
+		},
+		{
+			Name:        "mixed_content",
+			Type:        OptionBool,
+			Description: "Rechercher les ressources http:// dans la page d'accueil HTTPS",
+			Default:     true,
+		},
+	}
+}
+
+// options convertit les Options génériques en transportOptions
+func (t TransportScanner) options(opts Options) transportOptions {
+	opts = t.Schema().Apply(opts)
+	return transportOptions{WWW: opts.Bool("www"), MixedContent: opts.Bool("mixed_content")}
+}
+
+// Scan demande http://<apex>/ et http://www.<apex>/ et suit chaque redirection une à une
+// Un hôte injoignable n'arrête pas le scan — erreur seulement si aucun ne répond
+func (t TransportScanner) Scan(ctx context.Context, domain string, opts Options) (Result, error) {
+	o := t.options(opts)
+	apex := strings.TrimPrefix(strings.ToLower(domain), "www.")
+	hosts := []string{apex}
+	if o.WWW {
+		hosts = append(hosts, "www."+apex)
+	}
+
+	// Copie du client : les redirections ne sont plus suivies automatiquement, chaque saut est relevé
+	client := *clientOrDefault(t.Client)
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	report := &TransportReport{}
+	result := newResult(ctx, report)
+	var landing *transportPage
+	var lastErr error
+	for _, host := range hosts {
+		h, page := follow(ctx, &client, host, o.MixedContent && landing == nil)
+		report.Hosts = append(report.Hosts, h)
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		if len(h.Chain) == 0 {
+			lastErr = fmt.Errorf("%s : %s", host, h.Error)
+			continue
+		}
+		if page != nil {
+			landing = page
+		}
+		checkRedirect(&result, h, apex)
+		checkFirstHSTS(&result, h)
+	}
+	if len(result.Findings) == 0 {
+		return Result{}, fmt.Errorf("erreur de transport: %w", lastErr)
+	}
+
+	if landing != nil {
+		report.LandingURL = landing.url
+		report.MixedContent = mixedContent(landing.body)
+		checkMixedContent(&result, landing, report.MixedContent)
+	}
+
+	list := t.Preload
+	if list == nil {
+		list = hstspreload.Snapshot()
+	}
+	report.Preload = PreloadStatus{ListSize: list.Len(), Partial: list.Partial()}
+	if e, ok := list.Lookup(apex); ok {
+		report.Preload.Preloaded = true
+		report.Preload.Entry = e.Name
+		report.Preload.IncludeSubdomains = e.IncludeSubdomains
+	}
+	checkPreload(&result, report, apex)
+	return result, nil
+}
+
+// transportPage — page HTTPS finale d'une chaîne, conservée pour l'analyse du contenu mixte
+type transportPage struct {
+	url    string
+	header http.Header
+	body   []byte
+}
+
+// follow demande http://host/ et suit les redirections jusqu'à une réponse finale
+// keepPage : lire le HTML de la réponse finale si elle arrive en HTTPS
+func follow(ctx context.Context, client *http.Client, host string, keepPage bool) (TransportHost, *transportPage) {
+	h := TransportHost{Host: host}
+	current := "http://" + host + "/"
+	for len(h.Chain) < transportMaxHops {
+		resp, err := get(ctx, client, current)
+		if err != nil {
+			h.Error = err.Error()
+			return h, nil
+		}
+		hop := RedirectHop{URL: current, Status: resp.StatusCode}
+		https := resp.Request.URL.Scheme == "https"
+		// Le navigateur n'applique HSTS que sur une réponse HTTPS, et seulement le premier header (RFC 6797 §8.1)
+		if https && h.FirstHTTPS == "" {
+			h.FirstHTTPS = current
+			h.HSTS = resp.Header.Get(transportHSTSHeader)
+		}
+
+		location := resp.Header.Get("Location")
+		if resp.StatusCode >= 300 && resp.StatusCode < 400 && location != "" {
+			_ = resp.Body.Close()
+			next, err := resp.Request.URL.Parse(location)
+			if err != nil {
+				h.Chain = append(h.Chain, hop)
+				h.Error = fmt.Sprintf("Location invalide : %q", location)
+				return h, nil
+			}
+			hop.Location = next.String()
+			h.Chain = append(h.Chain, hop)
+			current = hop.Location
+			continue
+		}
+
+		h.Chain = append(h.Chain, hop)
+		h.FinalURL = current
+		var page *transportPage
+		if keepPage && https && strings.Contains(resp.Header.Get("Content-Type"), "html") {
+			body, err := io.ReadAll(io.LimitReader(resp.Body, transportMaxHTML))
+			if err == nil {
+				page = &transportPage{url: current, header: resp.Header, body: body}
+			}
+		}
+		_ = resp.Body.Close()
+		return h, page
+	}
+	h.Error = fmt.Sprintf("plus de %d redirections (boucle ?)", transportMaxHops)
+	return h, nil
+}
+
+// chainEvidence retranscrit la chaîne : "http://a/ 301 → https://a/ 200"
+func chainEvidence(h TransportHost) string {
+	parts := make([]string, len(h.Chain))
+	for i, hop := range h.Chain {
+		parts[i] = fmt.Sprintf("%s %d", hop.URL, hop.Status)
+	}
+	evidence := strings.Join(parts, " → ")
+	if h.Error != "" {
+		evidence += " → " + h.Error
+	}
+	return evidence
+}
+
+// hostOf retourne l'hôte (sans port) d'une URL absolue
+func hostOf(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// checkRedirect — redirection HTTP → HTTPS et qualité de la chaîne
+// Correct ou non, chaque vérification garde son ID : seule la sévérité change
+func checkRedirect(result *Result, h TransportHost, apex string) {
+	asset := "http://" + h.Host + "/"
+	add := func(id, title string, severity Severity, evidence, remediation string) {
+		if severity == SeverityInfo {
+			remediation = ""
+		}
+		result.add(Finding{ID: id, Title: title, Severity: severity, Category: "transport", Evidence: evidence, Asset: asset, Remediation: remediation})
+	}
+	evidence := chainEvidence(h)
+
+	switch {
+	case h.FinalURL == "":
+		add("transport.redirect", "Chaîne de redirections interrompue", SeverityLow, evidence,
+			"Corriger la chaîne pour qu'elle aboutisse à une page HTTPS")
+	case !strings.HasPrefix(h.FinalURL, "https://"):
+		add("transport.redirect", "Site servi en HTTP sans redirection vers HTTPS", SeverityMedium, evidence,
+			"Rediriger tout le trafic HTTP vers HTTPS (301 ou 308)")
+	default:
+		add("transport.redirect", "Redirection HTTP → HTTPS", SeverityInfo, evidence, "")
+	}
+
+	// La qualité de la chaîne ne se juge que s'il y a eu au moins une redirection
+	if len(h.Chain) < 2 || h.Chain[0].Location == "" {
+		return
+	}
+	severity := SeverityInfo
+	var issues []string
+	raise := func(s Severity, issue string) {
+		if s.Rank() > severity.Rank() {
+			severity = s
+		}
+		issues = append(issues, issue)
+	}
+
+	// Premier saut : hstspreload.org exige http://hôte → https://même hôte, sinon HSTS n'est jamais posé sur l'hôte de départ
+	if first := h.Chain[0]; !strings.HasPrefix(first.Location, "https://") || hostOf(first.Location) != hostOf(first.URL) {
+		raise(SeverityLow, "premier saut vers "+first.Location+" au lieu de https://"+h.Host+"/")
+	}
+	redirects := 0
+	for _, hop := range h.Chain {
+		if hop.Location == "" {
+			continue
+		}
+		redirects++
+		from, to := hop.URL, hop.Location
+		switch {
+		case strings.HasPrefix(from, "https://") && strings.HasPrefix(to, "http://"):
+			raise(SeverityMedium, "retour en HTTP : "+from+" → "+to)
+		case strings.HasPrefix(from, "http://") && (hop.Status == http.StatusFound || hop.Status == http.StatusSeeOther || hop.Status == http.StatusTemporaryRedirect):
+			// Redirection temporaire : non mise en cache, chaque visite repasse par HTTP
+			raise(SeverityLow, fmt.Sprintf("%d (temporaire) sur %s au lieu de 301/308", hop.Status, from))
+		}
+		if target := hostOf(to); target != apex && target != "www."+apex {
+			raise(SeverityLow, "saut vers un autre domaine : "+target)
+		}
+	}
+	if redirects > transportLongChain {
+		raise(SeverityLow, fmt.Sprintf("%d redirections", redirects))
+	}
+
+	if len(issues) == 0 {
+		add("transport.redirect_chain", "Chaîne de redirections directe", SeverityInfo,
+			fmt.Sprintf("%s | %d redirection(s) permanente(s)", evidence, redirects), "")
+		return
+	}
+	add("transport.redirect_chain", "Chaîne de redirections à corriger", severity, evidence+" | "+strings.Join(issues, ", "),
+		"Rediriger http://"+h.Host+"/ directement vers https://"+h.Host+"/ en 301 ou 308, sans repasser par HTTP")
+}
+
+// checkFirstHSTS — Strict-Transport-Security sur la première réponse HTTPS de la chaîne
+// Si cette réponse est une redirection sans HSTS (https://apex → https://www), l'apex n'est jamais protégé
+func checkFirstHSTS(result *Result, h TransportHost) {
+	if h.FirstHTTPS == "" {
+		return
+	}
+	f := Finding{ID: "transport.hsts_first", Category: "transport", Asset: h.FirstHTTPS, Severity: SeverityMedium}
+	if h.HSTS == "" {
+		f.Title = "Première réponse HTTPS sans HSTS"
+		f.Evidence = h.FirstHTTPS + " : pas de " + transportHSTSHeader
+		f.Remediation = "Servir Strict-Transport-Security sur toutes les réponses HTTPS, redirections comprises"
+		result.add(f)
+		return
+	}
+	f.Evidence = transportHSTSHeader + ": " + h.HSTS
+	p, err := parseHSTS(h.HSTS)
+	switch {
+	case err != nil:
+		f.Title = "HSTS invalide sur la première réponse HTTPS"
+		f.Evidence += " | " + err.Error()
+		f.Remediation = "Corriger le header : max-age=31536000; includeSubDomains"
+	case p.MaxAge == 0:
+		f.Title = "HSTS désactivé sur la première réponse HTTPS"
+		f.Remediation = "Passer max-age à au moins 31536000 (un an)"
+	default:
+		f.Title = "HSTS sur la première réponse HTTPS"
+		f.Severity = SeverityInfo
+	}
+	result.add(f)
+}
+
+// mixedContent relève les ressources http:// que charge la page — une fois chacune
+func mixedContent(body []byte) []MixedResource {
+	var resources []MixedResource
+	seen := make(map[string]bool)
+	z := html.NewTokenizer(bytes.NewReader(body))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return resources
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			tag, ok := mixedContentTags[tok.Data]
+			if !ok {
+				continue
+			}
+			var value, rel string
+			for _, a := range tok.Attr {
+				switch strings.ToLower(a.Key) {
+				case tag.attr:
+					value = strings.TrimSpace(a.Val)
+				case "rel":
+					rel = strings.ToLower(a.Val)
+				}
+			}
+			// <link> : seules les feuilles de style se chargent (preconnect, canonical, alternate ne chargent rien)
+			if tok.Data == "link" && !strings.Contains(rel, "stylesheet") {
+				continue
+			}
+			if !strings.HasPrefix(strings.ToLower(value), "http://") || seen[value] {
+				continue
+			}
+			seen[value] = true
+			resources = append(resources, MixedResource{Tag: tok.Data, URL: value, Active: tag.active})
+		}
+	}
+}
+
+// checkMixedContent — ressources http:// de la page d'accueil HTTPS
+// La directive CSP upgrade-insecure-requests fait réécrire ces URL en https:// par le navigateur
+func checkMixedContent(result *Result, page *transportPage, resources []MixedResource) {
+	f := Finding{ID: "transport.mixed_content", Category: "transport", Asset: page.url}
+	var active, passive []string
+	for _, r := range resources {
+		if r.Active {
+			active = append(active, "<"+r.Tag+"> "+r.URL)
+		} else {
+			passive = append(passive, "<"+r.Tag+"> "+r.URL)
+		}
+	}
+	upgrade := false
+	for _, p := range csp.ParseHeaders(page.header.Values("Content-Security-Policy"), nil) {
+		if _, ok := p.Directive("upgrade-insecure-requests"); ok {
+			upgrade = true
+		}
+	}
+
+	switch {
+	case len(resources) == 0:
+		f.Title = "Pas de contenu mixte"
+		f.Severity = SeverityInfo
+		f.Evidence = "Aucune ressource http:// dans la page"
+		result.add(f)
+		return
+	case len(active) > 0:
+		f.Title = "Contenu mixte actif"
+		f.Severity = SeverityMedium
+		f.Evidence = fmt.Sprintf("%d ressource(s) active(s) en HTTP, bloquée(s) par le navigateur : %s", len(active), strings.Join(active, ", "))
+	default:
+		f.Title = "Contenu mixte passif"
+		f.Severity = SeverityLow
+		f.Evidence = fmt.Sprintf("%d ressource(s) passive(s) en HTTP, modifiable(s) en transit : %s", len(passive), strings.Join(passive, ", "))
+	}
+	if len(active) > 0 && len(passive) > 0 {
+		f.Evidence += fmt.Sprintf(" | %d passive(s) : %s", len(passive), strings.Join(passive, ", "))
+	}
+	if upgrade {
+		// Le navigateur réécrit les URL : reste le risque d'une ressource absente en HTTPS
+		f.Severity = SeverityLow
+		f.Evidence += " | upgrade-insecure-requests : URL réécrites en https:// par le navigateur"
+	}
+	f.Remediation = "Charger toutes les ressources en https:// (ou en URL relative au protocole de la page)"
+	result.add(f)
+}
+
+// checkPreload — inscription sur la preload list et conformité du header HSTS de l'apex
+// Avec une liste partielle (snapshot embarqué illisible), un domaine introuvable ne produit pas de finding :
+// son absence n'est pas établie
+func checkPreload(result *Result, report *TransportReport, apex string) {
+	status := report.Preload
+	if !status.Preloaded && status.Partial {
+		return
+	}
+	f := Finding{ID: "transport.preload", Category: "transport", Asset: apex}
+
+	// Header HSTS de la première réponse HTTPS de l'apex — celui que vérifie hstspreload.org
+	var policy *hstsPolicy
+	for _, h := range report.Hosts {
+		if h.Host == apex && h.HSTS != "" {
+			if p, err := parseHSTS(h.HSTS); err == nil {
+				policy = &p
+			}
+		}
+	}
+	var issues []string
+	if policy == nil {
+		issues = []string{"pas de header HSTS valide sur https://" + apex + "/"}
+	} else {
+		issues = policy.preloadIssues()
+	}
+	snapshot := fmt.Sprintf("snapshot de %d entrée(s)", status.ListSize)
+
+	switch {
+	case status.Preloaded && status.Entry != apex:
+		f.Title = "Domaine couvert par la preload list"
+		f.Severity = SeverityInfo
+		f.Evidence = fmt.Sprintf("Inscrit via %s (includeSubDomains) | %s", status.Entry, snapshot)
+	case status.Preloaded && len(issues) > 0:
+		// Les domaines qui ne remplissent plus les conditions peuvent être retirés de la liste
+		f.Title = "Inscrit sur la preload list, header non conforme"
+		f.Severity = SeverityLow
+		f.Evidence = fmt.Sprintf("Inscrit | %s | %s", strings.Join(issues, ", "), snapshot)
+		f.Remediation = "Servir Strict-Transport-Security: max-age=31536000; includeSubDomains; preload pour rester inscrit"
+	case status.Preloaded:
+		f.Title = "Domaine inscrit sur la preload list"
+		f.Severity = SeverityInfo
+		f.Evidence = fmt.Sprintf("Inscrit | %s", snapshot)
+	case len(issues) == 0:
+		f.Title = "Éligible mais absent de la preload list"
+		f.Severity = SeverityLow
+		f.Evidence = fmt.Sprintf("Header conforme, domaine absent du %s (inscription en attente ?)", snapshot)
+		f.Remediation = "Soumettre le domaine sur https://hstspreload.org"
+	default:
+		f.Title = "Domaine absent de la preload list"
+		f.Severity = SeverityLow
+		f.Evidence = fmt.Sprintf("Absent du %s — première visite non protégée | %s", snapshot, strings.Join(issues, ", "))
+		f.Remediation = "Servir Strict-Transport-Security: max-age=31536000; includeSubDomains; preload puis soumettre le domaine sur https://hstspreload.org"
+	}
+	result.add(f)
+}
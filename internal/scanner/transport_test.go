@@ -0,0 +1,182 @@
+package scanner
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/daviani/go__001/internal/hstspreload"
+)
+
+// TestTransportScanner_Name vérifie que le scanner retourne le bon identifiant
+func TestTransportScanner_Name(t *testing.T) {
+	if got := (TransportScanner{}).Name(); got != "transport" {
+		t.Errorf("got %s, want transport", got)
+	}
+}
+
+// transportServer démarre un serveur HTTP et un serveur TLS, joignables comme example.com:80 et example.com:443
+// Les handlers reçoivent l'hôte demandé dans r.Host (example.com ou www.example.com)
+func transportServer(t *testing.T, plain, secure http.HandlerFunc) TransportScanner {
+	t.Helper()
+	httpSrv := httptest.NewServer(plain)
+	tlsSrv := httptest.NewTLSServer(secure)
+	t.Cleanup(httpSrv.Close)
+	t.Cleanup(tlsSrv.Close)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(tlsSrv.Certificate())
+	dialer := &net.Dialer{}
+	transport := &http.Transport{
+		// Le port choisit le serveur, quel que soit l'hôte demandé
+		DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+			if strings.HasSuffix(address, ":443") {
+				return dialer.DialContext(ctx, network, tlsSrv.Listener.Addr().String())
+			}
+			return dialer.DialContext(ctx, network, httpSrv.Listener.Addr().String())
+		},
+		// Le certificat de test ne couvre que example.com : on le vérifie aussi pour www.example.com
+		TLSClientConfig: &tls.Config{RootCAs: roots, ServerName: "example.com"},
+	}
+	t.Cleanup(transport.CloseIdleConnections)
+	return TransportScanner{Client: &http.Client{Transport: transport}}
+}
+
+// redirectTo redirige vers scheme://<même hôte>/ avec le code status
+func redirectTo(scheme string, status int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, scheme+"://"+r.Host+"/", status)
+	}
+}
+
+// htmlPage sert body en HTML avec le header HSTS hsts ("" = aucun)
+func htmlPage(hsts, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if hsts != "" {
+			w.Header().Set("Strict-Transport-Security", hsts)
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+// TestTransportScanner_Scan — redirections, HSTS de la première réponse HTTPS, contenu mixte, preload list
+func TestTransportScanner_Scan(t *testing.T) {
+	const preloadHSTS = "max-age=63072000; includeSubDomains; preload"
+	tests := []struct {
+		name    string
+		plain   http.HandlerFunc
+		secure  http.HandlerFunc
+		preload string // Entrée ajoutée à la preload list de test ("" = aucune)
+		want    map[string]Severity
+	}{
+		{
+			name:    "redirection 301 et domaine préchargé",
+			plain:   redirectTo("https", http.StatusMovedPermanently),
+			secure:  htmlPage(preloadHSTS, `<html><img src="/logo.png"><link rel="canonical" href="http://example.com/"></html>`),
+			preload: "example.com",
+			want: map[string]Severity{
+				"transport.redirect": SeverityInfo, "transport.redirect_chain": SeverityInfo, "transport.hsts_first": SeverityInfo,
+				"transport.mixed_content": SeverityInfo, "transport.preload": SeverityInfo,
+			},
+		},
+		{
+			name:   "HTTP servi en clair",
+			plain:  htmlPage("", "<html></html>"),
+			secure: htmlPage(preloadHSTS, "<html></html>"),
+			want:   map[string]Severity{"transport.redirect": SeverityMedium, "transport.preload": SeverityLow},
+		},
+		{
+			name:  "302, saut vers www sans HSTS, contenu mixte actif",
+			plain: redirectTo("https", http.StatusFound),
+			secure: func(w http.ResponseWriter, r *http.Request) {
+				if r.Host == "example.com" {
+					http.Redirect(w, r, "https://www.example.com/", http.StatusMovedPermanently)
+					return
+				}
+				htmlPage("", `<script src="http://cdn.example.net/app.js"></script><img src="HTTP://cdn.example.net/a.png">`)(w, r)
+			},
+			want: map[string]Severity{
+				"transport.redirect": SeverityInfo, "transport.redirect_chain": SeverityLow, "transport.hsts_first": SeverityMedium,
+				"transport.mixed_content": SeverityMedium, "transport.preload": SeverityLow,
+			},
+		},
+		{
+			name:  "retour en HTTP",
+			plain: redirectTo("https", http.StatusMovedPermanently),
+			secure: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/" {
+					http.Redirect(w, r, "http://"+r.Host+"/home", http.StatusMovedPermanently)
+				}
+			},
+			want: map[string]Severity{"transport.redirect": SeverityLow, "transport.redirect_chain": SeverityMedium},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := transportServer(t, tt.plain, tt.secure)
+			list := `{"entries": [{"name": "other.org", "mode": "force-https"}]}`
+			if tt.preload != "" {
+				list = `{"entries": [{"name": "` + tt.preload + `", "mode": "force-https", "include_subdomains": true}]}`
+			}
+			var err error
+			if scanner.Preload, err = hstspreload.Parse([]byte(list)); err != nil {
+				t.Fatal(err)
+			}
+			result, err := scanner.Scan(context.Background(), "example.com", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			byID := findingsByID(result)
+			for id, severity := range tt.want {
+				if f, ok := byID[id]; !ok || f.Severity != severity {
+					t.Errorf("%s : got %+v, want %s", id, f, severity)
+				}
+			}
+		})
+	}
+}
+
+// TestTransportScanner_Scan_Report — chaîne relevée saut par saut, ressources mixtes dédoublonnées
+func TestTransportScanner_Scan_Report(t *testing.T) {
+	scanner := transportServer(t, redirectTo("https", http.StatusPermanentRedirect),
+		htmlPage("", `<script src="http://a.example/x.js"></script><script src="http://a.example/x.js"></script><link rel="stylesheet" href="http://a.example/s.css">`))
+	result, err := scanner.Scan(context.Background(), "www.example.com", Options{"www": false})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := result.Data.(*TransportReport)
+	if len(report.Hosts) != 1 || report.Hosts[0].Host != "example.com" {
+		t.Fatalf("got hosts %+v, want example.com only", report.Hosts)
+	}
+	if got := chainEvidence(report.Hosts[0]); got != "http://example.com/ 308 → https://example.com/ 200" {
+		t.Errorf("got chain %q", got)
+	}
+	if len(report.MixedContent) != 2 || report.MixedContent[1].Tag != "link" {
+		t.Errorf("got mixed content %+v, want script + link", report.MixedContent)
+	}
+	// Sans Preload, le snapshot embarqué (liste complète de Chromium) est consulté : example.com n'y figure pas
+	if report.Preload.Preloaded || report.Preload.Partial || report.Preload.ListSize < 100000 {
+		t.Errorf("got preload %+v, want absent from the full snapshot", report.Preload)
+	}
+	if f := findingsByID(result)["transport.preload"]; f.Severity != SeverityLow {
+		t.Errorf("got %+v, want a low preload finding", f)
+	}
+}
+
+// TestTransportScanner_Scan_Unreachable — aucun hôte ne répond : erreur, aucun finding
+func TestTransportScanner_Scan_Unreachable(t *testing.T) {
+	result, err := TransportScanner{}.Scan(context.Background(), "127.0.0.1:1", Options{"www": false})
+	if err == nil {
+		t.Error("expected error for unreachable target")
+	}
+	if len(result.Findings) != 0 {
+		t.Errorf("got %d findings, want none", len(result.Findings))
+	}
+}
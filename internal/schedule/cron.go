@@ -0,0 +1,206 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Spec — règle de récurrence d'une planification
+type Spec interface {
+	// Next retourne la prochaine occurrence strictement après t (zéro si aucune)
+	Next(t time.Time) time.Time
+}
+
+// every — récurrence à intervalle fixe ("interval": "6h")
+type every time.Duration
+
+// Next ajoute l'intervalle à t
+func (e every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+// cron — expression cron standard à 5 champs : minute heure jour-du-mois mois jour-de-semaine
+// Chaque champ est un ensemble de bits : le bit n est à 1 si la valeur n correspond
+// Équivalent JS : la lib "cron-parser", sans les secondes
+type cron struct {
+	minute, hour, dom, month, dow uint64
+	// Si jour-du-mois ET jour-de-semaine sont restreints, il suffit que l'un des deux corresponde
+	// (comportement historique de cron : "0 0 1 * 1" = le 1er du mois OU le lundi)
+	domStar, dowStar bool
+}
+
+// macros — raccourcis courants acceptés à la place des 5 champs
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// field — bornes et noms autorisés d'un champ cron
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "heure", min: 0, max: 23}
+	domField    = field{name: "jour du mois", min: 1, max: 31}
+	monthField  = field{name: "mois", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 est accepté comme dimanche (comme 0), puis ramené à 0
+	dowField = field{name: "jour de semaine", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// ParseCron parse une expression cron à 5 champs ou une macro (@daily, @hourly...)
+// Syntaxe par champ : *, 5, 1-5, */15, 0-30/10, listes séparées par des virgules
+// Les mois et jours de semaine acceptent aussi leurs noms anglais (jan, mon...)
+func ParseCron(expr string) (Spec, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := macros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+
+	// strings.Fields découpe sur les espaces multiples (contrairement à strings.Split)
+	parts := strings.Fields(expr)
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("expression cron invalide %q : 5 champs attendus (minute heure jour mois jour-semaine)", expr)
+	}
+
+	var c cron
+	var err error
+	fields := []struct {
+		dest *uint64
+		def  field
+	}{
+		{&c.minute, minuteField}, {&c.hour, hourField}, {&c.dom, domField}, {&c.month, monthField}, {&c.dow, dowField},
+	}
+	for i, f := range fields {
+		if *f.dest, err = parseField(parts[i], f.def); err != nil {
+			return nil, fmt.Errorf("expression cron invalide %q : %w", expr, err)
+		}
+	}
+
+	// Dimanche : le bit 7 est replié sur le bit 0
+	if c.dow&(1<<7) != 0 {
+		c.dow = c.dow&^(1<<7) | 1
+	}
+	// Comme cron : un champ commençant par "*" (y compris "*/2") compte comme non restreint
+	c.domStar = strings.HasPrefix(parts[2], "*")
+	c.dowStar = strings.HasPrefix(parts[4], "*")
+	return c, nil
+}
+
+// parseField convertit un champ cron en ensemble de bits
+func parseField(raw string, f field) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(raw, ",") {
+		// "a-b/n" → plage a-b, pas n
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("pas invalide %q pour le champ %s", stepPart, f.name)
+			}
+			step = n
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			a, b, _ := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = f.value(a); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(b); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("plage inversée %q pour le champ %s", rangePart, f.name)
+			}
+		default:
+			v, err := f.value(rangePart)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			// "5/10" = de 5 à la fin, par pas de 10 ; "5" seul = uniquement 5
+			if !hasStep {
+				hi = v
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// value convertit une valeur de champ (nombre ou nom) en entier borné
+func (f field) value(raw string) (int, error) {
+	if v, ok := f.names[strings.ToLower(raw)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("valeur %q hors limites pour le champ %s (%d-%d)", raw, f.name, f.min, f.max)
+	}
+	return v, nil
+}
+
+// maxSearch — au-delà, l'expression ne correspond à aucune date (ex: "0 0 30 2 *", un 30 février)
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// Next cherche la prochaine minute qui correspond à l'expression
+// On avance par grands pas (mois, jour, heure) tant qu'un champ ne correspond pas → quelques dizaines d'itérations
+func (c cron) Next(t time.Time) time.Time {
+	// Prochaine minute pleine strictement après t
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxSearch)
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches applique la règle jour-du-mois / jour-de-semaine de cron
+func (c cron) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,59 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseCron_Next — prochaine occurrence de quelques expressions courantes
+func TestParseCron_Next(t *testing.T) {
+	// Mercredi 15 janvier 2025, 10h07
+	from := time.Date(2025, 1, 15, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2025, 1, 15, 10, 15, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2025, 1, 16, 3, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"30 9 * * mon-fri", time.Date(2025, 1, 16, 9, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)}, // 7 = dimanche
+		{"0 12 1 jun *", time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)},
+		// Jour du mois ET jour de semaine restreints → l'un OU l'autre (vendredi 17)
+		{"0 0 20 * fri", time.Date(2025, 1, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		spec, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Errorf("%s: %v", tt.expr, err)
+			continue
+		}
+		if got := spec.Next(from); !got.Equal(tt.want) {
+			t.Errorf("%s: got %s, want %s", tt.expr, got, tt.want)
+		}
+	}
+}
+
+// TestParseCron_Invalid — expressions refusées
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("%q: got no error", expr)
+		}
+	}
+}
+
+// TestParseCron_Never — une date impossible ne boucle pas indéfiniment
+func TestParseCron_Never(t *testing.T) {
+	spec, err := ParseCron("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := spec.Next(time.Now()); !got.IsZero() {
+		t.Errorf("got %s, want zero time", got)
+	}
+}
@@ -0,0 +1,126 @@
+package schedule
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// ErrNotFound — aucune planification ne correspond à l'identifiant demandé
+var ErrNotFound = errors.New("planification introuvable")
+
+// ErrInvalid — configuration de planification refusée (cron invalide, intervalle trop court...)
+var ErrInvalid = errors.New("planification invalide")
+
+// MinInterval — intervalle minimal entre deux scans d'une même planification
+// Évite qu'une planification mal réglée ("interval": "1s") ne sature le pool de workers
+const MinInterval = time.Minute
+
+// MissedPolicy — que faire des occurrences manquées pendant que le serveur était arrêté
+type MissedPolicy string
+
+const (
+	MissedCatchUp MissedPolicy = "catch_up" // Un seul scan de rattrapage au démarrage, quel que soit le nombre d'occurrences manquées
+	MissedSkip    MissedPolicy = "skip"     // Les occurrences manquées sont ignorées, on attend la suivante
+)
+
+// Schedule — scan récurrent d'un domaine
+// Les champs de configuration (domain → missed) sont fournis par le client,
+// les suivants sont gérés par le scheduler
+type Schedule struct {
+	ID       string                     `json:"id"`
+	Name     string                     `json:"name,omitempty"`
+	Domain   string                     `json:"domain"`
+	Scanners []string                   `json:"scanners,omitempty"`                     // Vide = tous les scanners du registre
+	Options  map[string]json.RawMessage `json:"options,omitempty" swaggertype:"object"` // Options par scanner, comme POST /scans
+	Timeout  string                     `json:"timeout,omitempty"`                      // Deadline de chaque scan (ex: "2m")
+	Cron     string                     `json:"cron,omitempty"`                         // Expression cron (ex: "0 3 * * *") — exclusif avec interval
+	Interval string                     `json:"interval,omitempty"`                     // Intervalle fixe (ex: "6h") — exclusif avec cron
+	Jitter   string                     `json:"jitter,omitempty"`                       // Décalage aléatoire ajouté à chaque occurrence (ex: "5m")
+	Missed   MissedPolicy               `json:"missed,omitempty"`                       // catch_up (défaut) ou skip
+	Paused   bool                       `json:"paused"`                                 // true = planification suspendue
+
+	CreatedAt time.Time  `json:"created_at"`
+	NextRunAt *time.Time `json:"next_run_at,omitempty"` // Prochaine occurrence, jitter compris (nil si en pause)
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	LastJobID string     `json:"last_job_id,omitempty"` // Job du dernier scan : GET /scans/{id}
+	LastError string     `json:"last_error,omitempty"`  // Erreur du dernier lancement (file pleine...)
+}
+
+// Repository — persistance des planifications (implémentée par store.SQLite)
+// Définie ici, côté utilisateur : le package schedule ne dépend pas du stockage
+type Repository interface {
+	ListSchedules(ctx context.Context) ([]Schedule, error)
+	SaveSchedule(ctx context.Context, s Schedule) error
+	DeleteSchedule(ctx context.Context, id string) error
+}
+
+// spec valide la configuration de récurrence et retourne la règle correspondante
+func (s Schedule) spec() (Spec, error) {
+	if s.Domain == "" {
+		return nil, errors.New("paramètre 'domain' requis")
+	}
+	switch s.Missed {
+	case "", MissedCatchUp, MissedSkip:
+	default:
+		return nil, fmt.Errorf("paramètre 'missed' invalide : %s (catch_up ou skip)", s.Missed)
+	}
+	if _, err := s.jitter(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case s.Cron != "" && s.Interval != "":
+		return nil, errors.New("'cron' et 'interval' sont exclusifs")
+	case s.Cron != "":
+		return ParseCron(s.Cron)
+	case s.Interval != "":
+		d, err := time.ParseDuration(s.Interval)
+		if err != nil || d < MinInterval {
+			return nil, fmt.Errorf("paramètre 'interval' invalide (durée d'au moins %s attendue)", MinInterval)
+		}
+		return every(d), nil
+	default:
+		return nil, errors.New("paramètre 'cron' ou 'interval' requis")
+	}
+}
+
+// jitter retourne le décalage aléatoire maximal (0 si absent)
+func (s Schedule) jitter() (time.Duration, error) {
+	if s.Jitter == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s.Jitter)
+	if err != nil || d < 0 {
+		return 0, errors.New("paramètre 'jitter' invalide (durée positive attendue)")
+	}
+	return d, nil
+}
+
+// next calcule la prochaine occurrence après t, décalée d'un jitter aléatoire dans [0, jitter)
+// Le jitter étale les scans planifiés à la même heure ("0 3 * * *" sur 50 domaines)
+func (s Schedule) next(spec Spec, t time.Time) *time.Time {
+	next := spec.Next(t)
+	if next.IsZero() {
+		return nil
+	}
+	if spread, _ := s.jitter(); spread > 0 {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(spread)))
+		if err == nil {
+			next = next.Add(time.Duration(n.Int64()))
+		}
+	}
+	return &next
+}
+
+// newID génère un identifiant aléatoire de 16 caractères hexadécimaux
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
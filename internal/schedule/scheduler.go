@@ -0,0 +1,329 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/daviani/go__001/internal/jobs"
+)
+
+// defaultMaxConcurrent — nombre de scans planifiés simultanés par défaut
+const defaultMaxConcurrent = 2
+
+// idleWait — attente maximale de la boucle quand aucune occurrence n'est proche
+const idleWait = time.Hour
+
+// defaultRetryDelay — attente avant de relancer une occurrence dont le lancement a échoué (file pleine...)
+const defaultRetryDelay = 30 * time.Second
+
+// Launcher lance le scan d'une planification et retourne le job créé
+// Fourni par l'API : même résolution des scanners et des options que POST /scans
+type Launcher func(s Schedule) (*jobs.Job, error)
+
+// Config — réglages du scheduler
+type Config struct {
+	MaxConcurrent int           // Scans planifiés simultanés — les occurrences en trop attendent une place
+	RetryDelay    time.Duration // Attente avant de relancer une occurrence dont le lancement a échoué (défaut 30s)
+}
+
+// entry — planification chargée en mémoire avec sa règle de récurrence
+type entry struct {
+	schedule Schedule
+	spec     Spec
+	running  bool      // Un scan de cette planification est en cours
+	retryAt  time.Time // Lancement échoué : l'occurrence reste due mais n'est relancée qu'à partir de retryAt
+}
+
+// Scheduler — lance les scans planifiés à leur échéance
+// Une seule goroutine (loop) dort jusqu'à la prochaine occurrence, ou jusqu'à un réveil
+// (planification modifiée, scan terminé qui libère une place)
+type Scheduler struct {
+	repo   Repository
+	launch Launcher
+	cfg    Config
+
+	mu      sync.Mutex
+	entries map[string]*entry
+	running int           // Scans planifiés en cours, toutes planifications confondues
+	wake    chan struct{} // Buffer de 1 : plusieurs réveils rapprochés n'en font qu'un
+}
+
+// New crée le scheduler — Start charge les planifications et démarre la boucle
+func New(repo Repository, launch Launcher, cfg Config) *Scheduler {
+	if cfg.MaxConcurrent <= 0 {
+		cfg.MaxConcurrent = defaultMaxConcurrent
+	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = defaultRetryDelay
+	}
+	return &Scheduler{
+		repo:    repo,
+		launch:  launch,
+		cfg:     cfg,
+		entries: make(map[string]*entry),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Start charge les planifications persistées, traite les occurrences manquées
+// pendant l'arrêt du serveur, puis démarre la boucle jusqu'à l'annulation de ctx
+func (s *Scheduler) Start(ctx context.Context) error {
+	schedules, err := s.repo.ListSchedules(ctx)
+	if err != nil {
+		return fmt.Errorf("erreur de chargement des planifications: %w", err)
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	for _, sch := range schedules {
+		spec, err := sch.spec()
+		if err != nil {
+			log.Printf("planification %s ignorée : %v", sch.ID, err)
+			continue
+		}
+		e := &entry{schedule: sch, spec: spec}
+		s.entries[sch.ID] = e
+
+		// Occurrence manquée : catch_up la laisse dans le passé (lancée au premier tour de boucle),
+		// skip passe directement à la suivante
+		missed := !sch.Paused && (sch.NextRunAt == nil || sch.NextRunAt.Before(now))
+		if missed && (sch.Missed == MissedSkip || sch.NextRunAt == nil) {
+			e.schedule.NextRunAt = sch.next(spec, now)
+			s.persist(e.schedule)
+		}
+	}
+	s.mu.Unlock()
+
+	go s.loop(ctx)
+	return nil
+}
+
+// loop lance les occurrences échues puis dort jusqu'à la prochaine
+func (s *Scheduler) loop(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		timer.Reset(s.dispatch(time.Now()))
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// notify réveille la boucle sans bloquer (un réveil déjà en attente suffit)
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// dispatch lance les planifications échues, de la plus en retard à la plus récente
+// Retourne le temps d'attente jusqu'à la prochaine occurrence connue
+func (s *Scheduler) dispatch(now time.Time) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []*entry
+	wait := idleWait
+	for _, e := range s.entries {
+		next := e.dueAt()
+		if e.schedule.Paused || next == nil {
+			continue
+		}
+		if !next.After(now) {
+			due = append(due, e)
+		} else if d := next.Sub(now); d < wait {
+			wait = d
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].schedule.NextRunAt.Before(*due[j].schedule.NextRunAt) })
+
+	for _, e := range due {
+		// Pas de chevauchement : une occurrence qui tombe pendant le scan précédent est sautée
+		if e.running {
+			e.schedule.NextRunAt = e.schedule.next(e.spec, now)
+			s.persist(e.schedule)
+			if e.schedule.NextRunAt != nil && e.schedule.NextRunAt.Sub(now) < wait {
+				wait = e.schedule.NextRunAt.Sub(now)
+			}
+			continue
+		}
+		// Plus de place : les occurrences restantes attendent la fin d'un scan (qui réveille la boucle)
+		if s.running >= s.cfg.MaxConcurrent {
+			break
+		}
+		s.start(e, now)
+		if next := e.dueAt(); next != nil && next.Sub(now) < wait {
+			wait = next.Sub(now)
+		}
+	}
+	return wait
+}
+
+// dueAt retourne l'instant du prochain lancement : l'occurrence, ou sa relance après un lancement échoué
+func (e *entry) dueAt() *time.Time {
+	if e.schedule.NextRunAt != nil && e.retryAt.After(*e.schedule.NextRunAt) {
+		return &e.retryAt
+	}
+	return e.schedule.NextRunAt
+}
+
+// start lance le scan d'une planification et calcule sa prochaine occurrence
+// Un lancement refusé (file pleine...) ne perd pas l'occurrence : elle reste due et est relancée
+// après cfg.RetryDelay — un redémarrage entre-temps la traite comme une occurrence manquée
+// Appelé avec s.mu verrouillé
+func (s *Scheduler) start(e *entry, now time.Time) {
+	job, err := s.launch(e.schedule)
+	if err != nil {
+		log.Printf("planification %s : %v (nouvel essai dans %s)", e.schedule.ID, err, s.cfg.RetryDelay)
+		e.schedule.LastError = err.Error()
+		e.retryAt = now.Add(s.cfg.RetryDelay)
+		s.persist(e.schedule)
+		return
+	}
+
+	e.retryAt = time.Time{}
+	e.schedule.LastRunAt = &now
+	e.schedule.NextRunAt = e.schedule.next(e.spec, now)
+	e.schedule.LastError = ""
+	e.schedule.LastJobID = job.ID()
+	e.running = true
+	s.running++
+	go s.release(e, job)
+	s.persist(e.schedule)
+}
+
+// release attend la fin du scan puis libère sa place
+func (s *Scheduler) release(e *entry, job *jobs.Job) {
+	<-job.Done()
+
+	s.mu.Lock()
+	e.running = false
+	s.running--
+	s.mu.Unlock()
+
+	s.notify()
+}
+
+// persist enregistre une planification — une erreur est journalisée sans bloquer la boucle
+func (s *Scheduler) persist(sch Schedule) {
+	if err := s.repo.SaveSchedule(context.Background(), sch); err != nil {
+		log.Println(err)
+	}
+}
+
+// List retourne toutes les planifications, de la plus ancienne à la plus récente
+func (s *Scheduler) List() []Schedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedules := make([]Schedule, 0, len(s.entries))
+	for _, e := range s.entries {
+		schedules = append(schedules, e.schedule)
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].CreatedAt.Before(schedules[j].CreatedAt) })
+	return schedules
+}
+
+// Get retourne la planification id
+func (s *Scheduler) Get(id string) (Schedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[id]
+	if !ok {
+		return Schedule{}, ErrNotFound
+	}
+	return e.schedule, nil
+}
+
+// Create valide et enregistre une nouvelle planification
+// Les champs gérés par le scheduler (id, dates, dernier job) fournis par le client sont ignorés
+func (s *Scheduler) Create(ctx context.Context, sch Schedule) (Schedule, error) {
+	spec, err := sch.spec()
+	if err != nil {
+		return Schedule{}, fmt.Errorf("%w : %v", ErrInvalid, err)
+	}
+
+	now := time.Now()
+	sch.ID = newID()
+	sch.CreatedAt = now
+	sch.LastRunAt = nil
+	sch.LastJobID = ""
+	sch.LastError = ""
+	sch.NextRunAt = nil
+	if !sch.Paused {
+		sch.NextRunAt = sch.next(spec, now)
+	}
+
+	if err := s.repo.SaveSchedule(ctx, sch); err != nil {
+		return Schedule{}, err
+	}
+
+	s.mu.Lock()
+	s.entries[sch.ID] = &entry{schedule: sch, spec: spec}
+	s.mu.Unlock()
+
+	s.notify()
+	return sch, nil
+}
+
+// Update remplace la configuration de la planification id
+// La prochaine occurrence est recalculée à partir de maintenant, l'historique (dernier job) est conservé
+func (s *Scheduler) Update(ctx context.Context, id string, sch Schedule) (Schedule, error) {
+	spec, err := sch.spec()
+	if err != nil {
+		return Schedule{}, fmt.Errorf("%w : %v", ErrInvalid, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[id]
+	if !ok {
+		return Schedule{}, ErrNotFound
+	}
+
+	sch.ID = id
+	sch.CreatedAt = e.schedule.CreatedAt
+	sch.LastRunAt = e.schedule.LastRunAt
+	sch.LastJobID = e.schedule.LastJobID
+	sch.LastError = e.schedule.LastError
+	sch.NextRunAt = nil
+	if !sch.Paused {
+		sch.NextRunAt = sch.next(spec, time.Now())
+	}
+
+	if err := s.repo.SaveSchedule(ctx, sch); err != nil {
+		return Schedule{}, err
+	}
+	e.schedule = sch
+	e.spec = spec
+
+	s.notify()
+	return sch, nil
+}
+
+// Delete supprime la planification id — un scan en cours n'est pas annulé
+func (s *Scheduler) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.entries[id]; !ok {
+		return ErrNotFound
+	}
+	if err := s.repo.DeleteSchedule(ctx, id); err != nil {
+		return err
+	}
+	delete(s.entries, id)
+	return nil
+}
@@ -0,0 +1,228 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/daviani/go__001/internal/jobs"
+	"github.com/daviani/go__001/internal/scanner"
+)
+
+// memRepo — Repository en mémoire pour les tests
+type memRepo struct {
+	mu        sync.Mutex
+	schedules map[string]Schedule
+}
+
+func newMemRepo(schedules ...Schedule) *memRepo {
+	r := &memRepo{schedules: make(map[string]Schedule)}
+	for _, s := range schedules {
+		r.schedules[s.ID] = s
+	}
+	return r
+}
+
+func (r *memRepo) ListSchedules(ctx context.Context) ([]Schedule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var list []Schedule
+	for _, s := range r.schedules {
+		list = append(list, s)
+	}
+	return list, nil
+}
+
+func (r *memRepo) SaveSchedule(ctx context.Context, s Schedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schedules[s.ID] = s
+	return nil
+}
+
+func (r *memRepo) DeleteSchedule(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.schedules, id)
+	return nil
+}
+
+// sleepScanner — scanner de test qui dure delay
+type sleepScanner struct{ delay time.Duration }
+
+func (s sleepScanner) Name() string           { return "sleep" }
+func (s sleepScanner) Info() scanner.Info     { return scanner.Info{Title: "sleep"} }
+func (s sleepScanner) Schema() scanner.Schema { return nil }
+
+func (s sleepScanner) Scan(ctx context.Context, domain string, opts scanner.Options) (scanner.Result, error) {
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+	}
+	return scanner.Result{}, nil
+}
+
+// launcher retourne un Launcher qui soumet un job au manager et publie chaque domaine lancé sur launched
+func launcher(delay time.Duration, launched chan<- string) Launcher {
+	m := jobs.NewManager(jobs.Config{Workers: 4})
+	return func(s Schedule) (*jobs.Job, error) {
+		launched <- s.Domain
+		return m.Submit(jobs.Request{Domain: s.Domain, Scanners: []scanner.Scanner{sleepScanner{delay}}})
+	}
+}
+
+// overdue — planification persistée dont l'occurrence est passée (serveur arrêté pendant l'échéance)
+func overdue(id string, missed MissedPolicy) Schedule {
+	past := time.Now().Add(-time.Hour)
+	return Schedule{ID: id, Domain: id + ".com", Interval: "1h", Missed: missed, CreatedAt: past, NextRunAt: &past}
+}
+
+// receive attend un domaine lancé (échec au-delà d'une seconde)
+func receive(t *testing.T, launched <-chan string) string {
+	t.Helper()
+	select {
+	case d := <-launched:
+		return d
+	case <-time.After(time.Second):
+		t.Fatal("no scan launched after 1s")
+		return ""
+	}
+}
+
+// TestScheduler_MissedRuns — catch_up rattrape l'occurrence manquée, skip passe à la suivante
+func TestScheduler_MissedRuns(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	launched := make(chan string, 10)
+	repo := newMemRepo(overdue("catchup", MissedCatchUp), overdue("skip", MissedSkip))
+	s := New(repo, launcher(0, launched), Config{})
+	if err := s.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := receive(t, launched); got != "catchup.com" {
+		t.Errorf("got %s, want catchup.com", got)
+	}
+	select {
+	case d := <-launched:
+		t.Errorf("got unexpected scan of %s", d)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// La prochaine occurrence est persistée, dans le futur pour les deux
+	for _, id := range []string{"catchup", "skip"} {
+		sch, _ := s.Get(id)
+		if sch.NextRunAt == nil || !sch.NextRunAt.After(time.Now()) {
+			t.Errorf("%s: got next run %v, want a future time", id, sch.NextRunAt)
+		}
+		if saved := repo.schedules[id]; !saved.NextRunAt.Equal(*sch.NextRunAt) {
+			t.Errorf("%s: next run not persisted", id)
+		}
+	}
+	if sch, _ := s.Get("catchup"); sch.LastJobID == "" {
+		t.Error("last job not recorded")
+	}
+}
+
+// TestScheduler_MaxConcurrent — au-delà de la limite, les occurrences attendent la fin d'un scan
+func TestScheduler_MaxConcurrent(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	launched := make(chan string, 10)
+	repo := newMemRepo(overdue("a", MissedCatchUp), overdue("b", MissedCatchUp))
+	s := New(repo, launcher(200*time.Millisecond, launched), Config{MaxConcurrent: 1})
+	if err := s.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	receive(t, launched)
+	receive(t, launched)
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("second scan launched after %s, want it to wait for the first one", elapsed)
+	}
+}
+
+// TestScheduler_LaunchRetry — un lancement refusé (file pleine) garde l'occurrence due et la relance
+func TestScheduler_LaunchRetry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	launched := make(chan string, 10)
+	next := launcher(0, launched)
+	var calls int
+	failOnce := func(sch Schedule) (*jobs.Job, error) {
+		calls++
+		if calls == 1 {
+			return nil, jobs.ErrQueueFull
+		}
+		return next(sch)
+	}
+
+	repo := newMemRepo(overdue("retry", MissedCatchUp))
+	s := New(repo, failOnce, Config{RetryDelay: 50 * time.Millisecond})
+	if err := s.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if got := receive(t, launched); got != "retry.com" {
+		t.Errorf("got %s, want retry.com", got)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("relaunched after %s, want the retry delay", elapsed)
+	}
+
+	sch, _ := s.Get("retry")
+	if sch.LastJobID == "" || sch.LastError != "" {
+		t.Errorf("got job %q, error %q, want the retried job recorded", sch.LastJobID, sch.LastError)
+	}
+	if sch.NextRunAt == nil || !sch.NextRunAt.After(time.Now()) {
+		t.Errorf("got next run %v, want a future time", sch.NextRunAt)
+	}
+}
+
+// TestScheduler_Create — validation, calcul de la prochaine occurrence et persistance
+func TestScheduler_Create(t *testing.T) {
+	repo := newMemRepo()
+	s := New(repo, launcher(0, make(chan string, 1)), Config{})
+
+	invalid := []Schedule{
+		{Domain: "example.com"},
+		{Domain: "example.com", Interval: "10s"},
+		{Domain: "example.com", Cron: "@daily", Interval: "1h"},
+		{Domain: "example.com", Cron: "61 * * * *"},
+		{Cron: "@daily"},
+	}
+	for _, sch := range invalid {
+		if _, err := s.Create(context.Background(), sch); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%+v: got %v, want ErrInvalid", sch, err)
+		}
+	}
+
+	sch, err := s.Create(context.Background(), Schedule{Domain: "example.com", Cron: "@hourly", Jitter: "5m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sch.ID == "" || sch.NextRunAt == nil {
+		t.Fatalf("got %+v, want an ID and a next run", sch)
+	}
+	hour := time.Now().Truncate(time.Hour).Add(time.Hour)
+	if sch.NextRunAt.Before(hour) || !sch.NextRunAt.Before(hour.Add(5*time.Minute)) {
+		t.Errorf("got next run %s, want within 5m after %s", sch.NextRunAt, hour)
+	}
+	if _, ok := repo.schedules[sch.ID]; !ok {
+		t.Error("schedule not persisted")
+	}
+
+	if err := s.Delete(context.Background(), sch.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get(sch.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v, want ErrNotFound", err)
+	}
+}
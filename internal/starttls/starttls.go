@@ -0,0 +1,227 @@
+package starttls
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultTimeout — délai de la négociation quand le contexte n'a pas de deadline
+const defaultTimeout = 10 * time.Second
+
+// Ports — protocoles pris en charge et leur port STARTTLS standard
+// SMTP sert aussi la soumission (587) : le port se précise alors explicitement ("smtp:587")
+var Ports = map[string]int{
+	"smtp": 25,
+	"imap": 143,
+	"pop3": 110,
+	"ftp":  21,
+}
+
+// Endpoint — service à analyser : connexion en clair sur Port, puis passage en TLS selon Protocol
+type Endpoint struct {
+	Protocol string `json:"protocol"`
+	Port     int    `json:"port"`
+}
+
+// ParseEndpoints normalise une liste de services : "smtp" → smtp:25, "smtp:587" → smtp:587
+func ParseEndpoints(raw []string) ([]Endpoint, error) {
+	endpoints := make([]Endpoint, 0, len(raw))
+	for _, s := range raw {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if s == "" {
+			continue
+		}
+		protocol, port, hasPort := strings.Cut(s, ":")
+		ep := Endpoint{Protocol: protocol, Port: Ports[protocol]}
+		if ep.Port == 0 {
+			return nil, fmt.Errorf("service STARTTLS invalide : %q (attendu smtp, imap, pop3 ou ftp)", s)
+		}
+		if hasPort {
+			n, err := strconv.Atoi(port)
+			if err != nil || n < 1 || n > 65535 {
+				return nil, fmt.Errorf("service STARTTLS invalide : %q (port entre 1 et 65535)", s)
+			}
+			ep.Port = n
+		}
+		endpoints = append(endpoints, ep)
+	}
+	return endpoints, nil
+}
+
+// ErrNotOffered — le serveur répond mais ne propose pas (ou refuse) le passage en TLS
+var ErrNotOffered = errors.New("STARTTLS non proposé par le serveur")
+
+// Negotiate mène l'échange en clair qui précède le handshake TLS (RFC 3207, 2595, 4217) sur conn
+// Au retour sans erreur, le prochain octet attendu par le serveur est le ClientHello : conn peut être
+// passée à tls.Client, ou servir de connexion brute à une sonde
+// serverName est annoncé dans le EHLO SMTP
+// Équivalent JS : le dialogue ligne à ligne qu'un client comme nodemailer mène avant tls.connect({socket})
+func Negotiate(ctx context.Context, conn net.Conn, protocol, serverName string) error {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultTimeout)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	// Deadline levée au retour : le handshake TLS gère ensuite la sienne
+	defer func() { _ = conn.SetDeadline(time.Time{}) }()
+
+	s := &session{conn: conn, r: bufio.NewReader(conn)}
+	var err error
+	switch protocol {
+	case "smtp":
+		err = s.smtp(serverName)
+	case "imap":
+		err = s.imap()
+	case "pop3":
+		err = s.pop3()
+	case "ftp":
+		err = s.ftp()
+	default:
+		return fmt.Errorf("protocole STARTTLS inconnu : %q", protocol)
+	}
+	if err != nil {
+		return fmt.Errorf("erreur STARTTLS %s: %w", protocol, err)
+	}
+	// Des octets déjà lus mais non consommés seraient perdus pour le handshake TLS
+	if s.r.Buffered() > 0 {
+		return fmt.Errorf("erreur STARTTLS %s: données inattendues après la réponse du serveur", protocol)
+	}
+	return nil
+}
+
+// session — dialogue texte ligne à ligne sur la connexion en clair
+type session struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// send écrit une commande terminée par CRLF
+func (s *session) send(command string) error {
+	_, err := s.conn.Write([]byte(command + "\r\n"))
+	return err
+}
+
+// line lit une ligne de réponse, sans CRLF
+func (s *session) line() (string, error) {
+	line, err := s.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// reply lit une réponse SMTP ou FTP, éventuellement multi-lignes ("250-..." jusqu'à "250 ..."),
+// et vérifie son code — retourne les lignes sans le code
+func (s *session) reply(code string) ([]string, error) {
+	var lines []string
+	for {
+		line, err := s.line()
+		if err != nil {
+			return nil, err
+		}
+		if len(line) < 3 || line[:3] != code {
+			return nil, fmt.Errorf("réponse inattendue %q (attendu %s)", line, code)
+		}
+		lines = append(lines, strings.TrimSpace(line[min(len(line), 4):]))
+		if len(line) == 3 || line[3] != '-' {
+			return lines, nil
+		}
+	}
+}
+
+// smtp — bannière 220, EHLO, capacité STARTTLS annoncée, puis STARTTLS → 220 (RFC 3207)
+func (s *session) smtp(serverName string) error {
+	if _, err := s.reply("220"); err != nil {
+		return err
+	}
+	if err := s.send("EHLO " + serverName); err != nil {
+		return err
+	}
+	capabilities, err := s.reply("250")
+	if err != nil {
+		return err
+	}
+	// La première ligne est le nom du serveur, les suivantes ses extensions (une par ligne)
+	if !slices.ContainsFunc(capabilities, func(c string) bool { return strings.EqualFold(c, "STARTTLS") }) {
+		return ErrNotOffered
+	}
+	if err := s.send("STARTTLS"); err != nil {
+		return err
+	}
+	if _, err := s.reply("220"); err != nil {
+		return fmt.Errorf("%w : %w", ErrNotOffered, err)
+	}
+	return nil
+}
+
+// imap — bannière "* OK", puis commande étiquetée STARTTLS → "<tag> OK" (RFC 2595)
+// Les réponses non étiquetées ("* ...") qui précèdent la réponse finale sont ignorées
+func (s *session) imap() error {
+	greeting, err := s.line()
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(greeting, "* OK") {
+		return fmt.Errorf("bannière inattendue %q", greeting)
+	}
+	if err := s.send("a1 STARTTLS"); err != nil {
+		return err
+	}
+	for {
+		line, err := s.line()
+		if err != nil {
+			return err
+		}
+		switch {
+		case strings.HasPrefix(line, "a1 OK"):
+			return nil
+		case strings.HasPrefix(line, "a1 "):
+			return fmt.Errorf("%w : %s", ErrNotOffered, line)
+		}
+	}
+}
+
+// pop3 — bannière "+OK", puis STLS → "+OK" (RFC 2595)
+func (s *session) pop3() error {
+	greeting, err := s.line()
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(greeting, "+OK") {
+		return fmt.Errorf("bannière inattendue %q", greeting)
+	}
+	if err := s.send("STLS"); err != nil {
+		return err
+	}
+	line, err := s.line()
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "+OK") {
+		return fmt.Errorf("%w : %s", ErrNotOffered, line)
+	}
+	return nil
+}
+
+// ftp — bannière 220, puis AUTH TLS → 234 (RFC 4217)
+func (s *session) ftp() error {
+	if _, err := s.reply("220"); err != nil {
+		return err
+	}
+	if err := s.send("AUTH TLS"); err != nil {
+		return err
+	}
+	if _, err := s.reply("234"); err != nil {
+		return fmt.Errorf("%w : %w", ErrNotOffered, err)
+	}
+	return nil
+}
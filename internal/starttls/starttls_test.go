@@ -0,0 +1,169 @@
+package starttls
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// serve joue un dialogue scripté côté serveur : "S: ..." est envoyé, "C: ..." est la ligne attendue du client
+// Une fois le script terminé, le serveur lit un octet : le premier octet du handshake TLS (0x16)
+func serve(t *testing.T, conn net.Conn, script []string) <-chan byte {
+	t.Helper()
+	next := make(chan byte, 1)
+	go func() {
+		defer func() { _ = conn.Close() }()
+		defer close(next)
+		r := bufio.NewReader(conn)
+		for _, step := range script {
+			kind, text, _ := strings.Cut(step, ": ")
+			if kind == "S" {
+				if _, err := conn.Write([]byte(text + "\r\n")); err != nil {
+					return
+				}
+				continue
+			}
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if got := strings.TrimRight(line, "\r\n"); got != text {
+				t.Errorf("got client line %q, want %q", got, text)
+				return
+			}
+		}
+		b, err := r.ReadByte()
+		if err == nil {
+			next <- b
+		}
+	}()
+	return next
+}
+
+// TestNegotiate — échanges STARTTLS de chaque protocole, et refus du serveur
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name     string
+		protocol string
+		script   []string
+		wantErr  error // nil = négociation réussie ; ErrNotOffered = refus détecté comme tel
+	}{
+		{
+			name:     "smtp",
+			protocol: "smtp",
+			script: []string{
+				"S: 220-mail.example.com ESMTP", "S: 220 bienvenue",
+				"C: EHLO example.com",
+				"S: 250-mail.example.com", "S: 250-PIPELINING", "S: 250-STARTTLS", "S: 250 8BITMIME",
+				"C: STARTTLS",
+				"S: 220 2.0.0 Ready to start TLS",
+			},
+		},
+		{
+			name:     "smtp sans STARTTLS",
+			protocol: "smtp",
+			script: []string{
+				"S: 220 mail.example.com ESMTP",
+				"C: EHLO example.com",
+				"S: 250-mail.example.com", "S: 250 8BITMIME",
+			},
+			wantErr: ErrNotOffered,
+		},
+		{
+			name:     "imap",
+			protocol: "imap",
+			script: []string{
+				"S: * OK [CAPABILITY IMAP4rev1 STARTTLS] ready",
+				"C: a1 STARTTLS",
+				"S: * CAPABILITY IMAP4rev1",
+				"S: a1 OK Begin TLS negotiation now",
+			},
+		},
+		{
+			name:     "imap refusé",
+			protocol: "imap",
+			script: []string{
+				"S: * OK ready",
+				"C: a1 STARTTLS",
+				"S: a1 BAD unknown command",
+			},
+			wantErr: ErrNotOffered,
+		},
+		{
+			name:     "pop3",
+			protocol: "pop3",
+			script:   []string{"S: +OK POP3 ready", "C: STLS", "S: +OK Begin TLS"},
+		},
+		{
+			name:     "ftp",
+			protocol: "ftp",
+			script:   []string{"S: 220 FTP ready", "C: AUTH TLS", "S: 234 AUTH TLS OK"},
+		},
+		{
+			name:     "ftp refusé",
+			protocol: "ftp",
+			script:   []string{"S: 220 FTP ready", "C: AUTH TLS", "S: 500 unknown command"},
+			wantErr:  ErrNotOffered,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := net.Pipe()
+			defer func() { _ = client.Close() }()
+			next := serve(t, server, tt.script)
+
+			err := Negotiate(context.Background(), client, tt.protocol, "example.com")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("got %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Le serveur doit recevoir le handshake TLS immédiatement après la négociation
+			if _, err := client.Write([]byte{0x16}); err != nil {
+				t.Fatal(err)
+			}
+			if b := <-next; b != 0x16 {
+				t.Errorf("got first TLS byte %#x, want 0x16", b)
+			}
+		})
+	}
+}
+
+// TestNegotiate_UnknownProtocol — protocole non pris en charge : erreur sans rien envoyer
+func TestNegotiate_UnknownProtocol(t *testing.T) {
+	client, server := net.Pipe()
+	defer func() { _ = server.Close() }()
+	go func() { _, _ = io.Copy(io.Discard, server) }()
+
+	if err := Negotiate(context.Background(), client, "xmpp", "example.com"); err == nil {
+		t.Error("expected error for unknown protocol")
+	}
+}
+
+// TestParseEndpoints — port standard par défaut, port explicite, protocole inconnu
+func TestParseEndpoints(t *testing.T) {
+	got, err := ParseEndpoints([]string{"smtp", " SMTP:587 ", "imap", ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Endpoint{{"smtp", 25}, {"smtp", 587}, {"imap", 143}}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	for _, raw := range []string{"xmpp", "smtp:0", "smtp:abc"} {
+		if _, err := ParseEndpoints([]string{raw}); err == nil {
+			t.Errorf("%q : expected error", raw)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/daviani/go__001/internal/schedule"
+)
+
+// schedulesSchema — table des planifications
+// La planification est stockée en JSON : c'est un document de configuration lu en entier au démarrage,
+// jamais filtré champ par champ
+const schedulesSchema = `
+CREATE TABLE IF NOT EXISTS schedules (
+	id         TEXT PRIMARY KEY,
+	created_at DATETIME NOT NULL,
+	data       TEXT NOT NULL
+);
+`
+
+// SQLite stocke aussi les planifications du scheduler
+var _ schedule.Repository = (*SQLite)(nil)
+
+// ListSchedules retourne toutes les planifications (implémente schedule.Repository)
+func (s *SQLite) ListSchedules(ctx context.Context) ([]schedule.Schedule, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT data FROM schedules ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("erreur de lecture des planifications: %w", err)
+	}
+	defer rows.Close()
+
+	var schedules []schedule.Schedule
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("erreur de lecture des planifications: %w", err)
+		}
+		var sch schedule.Schedule
+		if err := json.Unmarshal([]byte(data), &sch); err != nil {
+			return nil, fmt.Errorf("planification illisible: %w", err)
+		}
+		schedules = append(schedules, sch)
+	}
+	return schedules, rows.Err()
+}
+
+// SaveSchedule crée ou remplace une planification
+func (s *SQLite) SaveSchedule(ctx context.Context, sch schedule.Schedule) error {
+	data, err := json.Marshal(sch)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`REPLACE INTO schedules (id, created_at, data) VALUES (?, ?, ?)`,
+		sch.ID, sch.CreatedAt.UTC(), string(data),
+	)
+	if err != nil {
+		return fmt.Errorf("erreur de sauvegarde de la planification %s: %w", sch.ID, err)
+	}
+	return nil
+}
+
+// DeleteSchedule supprime une planification (sans effet si elle n'existe pas)
+func (s *SQLite) DeleteSchedule(ctx context.Context, id string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM schedules WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("erreur de suppression de la planification %s: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,378 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/daviani/go__001/internal/jobs"
+	"github.com/daviani/go__001/internal/scanner"
+	_ "github.com/mattn/go-sqlite3" // Blank import — enregistre le driver "sqlite3" auprès de database/sql
+)
+
+// schema — tables de l'historique, créées au démarrage si elles n'existent pas
+// scans (1) → runs (N, un par scanner) → findings (N par run)
+// ON DELETE CASCADE : supprimer un scan supprime ses runs et ses findings
+const schema = `
+CREATE TABLE IF NOT EXISTS scans (
+	id           TEXT PRIMARY KEY,
+	domain       TEXT NOT NULL,
+	status       TEXT NOT NULL,
+	created_at   DATETIME NOT NULL,
+	started_at   DATETIME,
+	finished_at  DATETIME,
+	findings     INTEGER NOT NULL DEFAULT 0,
+	max_severity TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS scans_domain ON scans (domain, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS runs (
+	scan_id     TEXT NOT NULL REFERENCES scans (id) ON DELETE CASCADE,
+	position    INTEGER NOT NULL,
+	scanner     TEXT NOT NULL,
+	status      TEXT NOT NULL,
+	started_at  DATETIME,
+	finished_at DATETIME,
+	has_result  BOOLEAN NOT NULL DEFAULT 0,
+	partial     BOOLEAN NOT NULL DEFAULT 0,
+	data        TEXT,
+	error       TEXT NOT NULL DEFAULT '',
+	PRIMARY KEY (scan_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS findings (
+	scan_id     TEXT NOT NULL REFERENCES scans (id) ON DELETE CASCADE,
+	run         INTEGER NOT NULL,
+	position    INTEGER NOT NULL,
+	finding_id  TEXT NOT NULL,
+	title       TEXT NOT NULL,
+	severity    TEXT NOT NULL,
+	category    TEXT NOT NULL,
+	evidence    TEXT NOT NULL,
+	asset       TEXT NOT NULL,
+	remediation TEXT NOT NULL DEFAULT '',
+	PRIMARY KEY (scan_id, run, position)
+);
+CREATE INDEX IF NOT EXISTS findings_key ON findings (finding_id, asset);
+`
+
+// SQLite — implémentation de Store sur une base SQLite embarquée (un seul fichier, aucun serveur)
+type SQLite struct {
+	db *sql.DB
+}
+
+// Vérification à la compilation : *SQLite implémente bien Store
+var _ Store = (*SQLite)(nil)
+
+// OpenSQLite ouvre (ou crée) la base au chemin donné et applique le schéma
+// ":memory:" donne une base en mémoire, perdue à la fermeture (utile pour les tests)
+func OpenSQLite(path string) (*SQLite, error) {
+	// _foreign_keys : SQLite n'applique pas les clés étrangères par défaut
+	// _busy_timeout : attend jusqu'à 5s qu'un verrou se libère au lieu d'échouer immédiatement
+	dsn := fmt.Sprintf("file:%s?_foreign_keys=on&_busy_timeout=5000&_journal_mode=WAL", path)
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("erreur d'ouverture de la base: %w", err)
+	}
+
+	// Une seule connexion : SQLite n'accepte qu'un écrivain à la fois,
+	// et chaque connexion à ":memory:" ouvrirait une base différente
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema + schedulesSchema + webhooksSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("erreur de création du schéma: %w", err)
+	}
+	return &SQLite{db: db}, nil
+}
+
+// Close ferme la base
+func (s *SQLite) Close() error {
+	return s.db.Close()
+}
+
+// SaveScan enregistre un scan et tous ses résultats dans une transaction
+// Tout ou rien : un crash au milieu ne laisse pas un scan à moitié enregistré
+func (s *SQLite) SaveScan(ctx context.Context, snap jobs.Snapshot) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("erreur de sauvegarde du scan %s: %w", snap.ID, err)
+	}
+	// Rollback après Commit est sans effet → on peut le différer sans condition
+	defer tx.Rollback()
+
+	if err := saveScan(ctx, tx, snap); err != nil {
+		return fmt.Errorf("erreur de sauvegarde du scan %s: %w", snap.ID, err)
+	}
+	return tx.Commit()
+}
+
+// saveScan écrit les lignes scans, runs et findings d'un scan
+func saveScan(ctx context.Context, tx *sql.Tx, snap jobs.Snapshot) error {
+	summary := summarize(snap)
+
+	// REPLACE supprime l'ancienne ligne → la cascade supprime aussi ses runs et findings
+	_, err := tx.ExecContext(ctx,
+		`REPLACE INTO scans (id, domain, status, created_at, started_at, finished_at, findings, max_severity)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		snap.ID, snap.Domain, snap.Status, snap.CreatedAt.UTC(), nullTime(snap.StartedAt), nullTime(snap.FinishedAt),
+		summary.Findings, summary.MaxSeverity,
+	)
+	if err != nil {
+		return err
+	}
+
+	for i, run := range snap.Runs {
+		var data sql.NullString
+		var partial bool
+		if run.Result != nil {
+			partial = run.Result.Partial
+			if run.Result.Data != nil {
+				raw, err := json.Marshal(run.Result.Data)
+				if err != nil {
+					return err
+				}
+				data = sql.NullString{String: string(raw), Valid: true}
+			}
+		}
+
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO runs (scan_id, position, scanner, status, started_at, finished_at, has_result, partial, data, error)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			snap.ID, i, run.Scanner, run.Status, nullTime(run.StartedAt), nullTime(run.FinishedAt),
+			run.Result != nil, partial, data, run.Error,
+		)
+		if err != nil {
+			return err
+		}
+
+		if run.Result == nil {
+			continue
+		}
+		for k, f := range run.Result.Findings {
+			_, err := tx.ExecContext(ctx,
+				`INSERT INTO findings (scan_id, run, position, finding_id, title, severity, category, evidence, asset, remediation)
+				 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				snap.ID, i, k, f.ID, f.Title, f.Severity, f.Category, f.Evidence, f.Asset, f.Remediation,
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetScan relit un scan complet : ligne scans, puis ses runs, puis leurs findings
+func (s *SQLite) GetScan(ctx context.Context, id string) (jobs.Snapshot, error) {
+	var snap jobs.Snapshot
+	var startedAt, finishedAt sql.NullTime
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id, domain, status, created_at, started_at, finished_at FROM scans WHERE id = ?`, id,
+	).Scan(&snap.ID, &snap.Domain, &snap.Status, &snap.CreatedAt, &startedAt, &finishedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return jobs.Snapshot{}, ErrNotFound
+	}
+	if err != nil {
+		return jobs.Snapshot{}, fmt.Errorf("erreur de lecture du scan %s: %w", id, err)
+	}
+	snap.StartedAt = timePtr(startedAt)
+	snap.FinishedAt = timePtr(finishedAt)
+
+	if snap.Runs, err = s.runs(ctx, id); err != nil {
+		return jobs.Snapshot{}, fmt.Errorf("erreur de lecture du scan %s: %w", id, err)
+	}
+
+	snap.Progress.Total = len(snap.Runs)
+	for _, run := range snap.Runs {
+		if run.Status.Finished() {
+			snap.Progress.Done++
+		}
+	}
+	return snap, nil
+}
+
+// runs relit les exécutions de scanners d'un scan, findings compris, dans l'ordre d'origine
+func (s *SQLite) runs(ctx context.Context, id string) ([]jobs.Run, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT scanner, status, started_at, finished_at, has_result, partial, data, error
+		 FROM runs WHERE scan_id = ? ORDER BY position`, id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := []jobs.Run{}
+	for rows.Next() {
+		var run jobs.Run
+		var startedAt, finishedAt sql.NullTime
+		var hasResult, partial bool
+		var data sql.NullString
+		err := rows.Scan(&run.Scanner, &run.Status, &startedAt, &finishedAt, &hasResult, &partial, &data, &run.Error)
+		if err != nil {
+			return nil, err
+		}
+		run.StartedAt = timePtr(startedAt)
+		run.FinishedAt = timePtr(finishedAt)
+		if hasResult {
+			run.Result = &scanner.Result{Findings: []scanner.Finding{}, Partial: partial}
+			// json.RawMessage : les données brutes sont renvoyées telles quelles, sans les retyper
+			if data.Valid {
+				run.Result.Data = json.RawMessage(data.String)
+			}
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return runs, s.attachFindings(ctx, id, runs)
+}
+
+// attachFindings relit les findings d'un scan et les range dans le résultat de leur run
+func (s *SQLite) attachFindings(ctx context.Context, id string, runs []jobs.Run) error {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT run, finding_id, title, severity, category, evidence, asset, remediation
+		 FROM findings WHERE scan_id = ? ORDER BY run, position`, id,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i int
+		var f scanner.Finding
+		if err := rows.Scan(&i, &f.ID, &f.Title, &f.Severity, &f.Category, &f.Evidence, &f.Asset, &f.Remediation); err != nil {
+			return err
+		}
+		if i < 0 || i >= len(runs) || runs[i].Result == nil {
+			continue
+		}
+		runs[i].Result.Findings = append(runs[i].Result.Findings, f)
+	}
+	return rows.Err()
+}
+
+// ListScans retourne une page de l'historique d'un domaine (plus récent en premier)
+func (s *SQLite) ListScans(ctx context.Context, domain string, page Page) (ScanPage, error) {
+	page = page.normalize()
+	result := ScanPage{Scans: []ScanSummary{}, Limit: page.Limit, Offset: page.Offset}
+
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM scans WHERE domain = ?`, domain).Scan(&result.Total)
+	if err != nil {
+		return ScanPage{}, fmt.Errorf("erreur de lecture de l'historique: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT s.id, s.domain, s.status, s.created_at, s.finished_at, s.findings, s.max_severity,
+		        (SELECT COUNT(*) FROM runs r WHERE r.scan_id = s.id)
+		 FROM scans s WHERE s.domain = ?
+		 ORDER BY s.created_at DESC LIMIT ? OFFSET ?`,
+		domain, page.Limit, page.Offset,
+	)
+	if err != nil {
+		return ScanPage{}, fmt.Errorf("erreur de lecture de l'historique: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var summary ScanSummary
+		var finishedAt sql.NullTime
+		err := rows.Scan(&summary.ID, &summary.Domain, &summary.Status, &summary.CreatedAt, &finishedAt,
+			&summary.Findings, &summary.MaxSeverity, &summary.Scanners)
+		if err != nil {
+			return ScanPage{}, fmt.Errorf("erreur de lecture de l'historique: %w", err)
+		}
+		summary.FinishedAt = timePtr(finishedAt)
+		result.Scans = append(result.Scans, summary)
+	}
+	if err := rows.Err(); err != nil {
+		return ScanPage{}, fmt.Errorf("erreur de lecture de l'historique: %w", err)
+	}
+	return result, nil
+}
+
+// PreviousScan retourne le dernier scan du domaine créé avant snap
+func (s *SQLite) PreviousScan(ctx context.Context, snap jobs.Snapshot) (jobs.Snapshot, error) {
+	var id string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id FROM scans WHERE domain = ? AND created_at < ? AND id != ?
+		 ORDER BY created_at DESC LIMIT 1`,
+		snap.Domain, snap.CreatedAt.UTC(), snap.ID,
+	).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return jobs.Snapshot{}, ErrNotFound
+	}
+	if err != nil {
+		return jobs.Snapshot{}, fmt.Errorf("erreur de lecture de l'historique: %w", err)
+	}
+	return s.GetScan(ctx, id)
+}
+
+// PreviousRuns assemble une référence scanner par scanner : pour chaque scanner de snap,
+// son dernier run terminé avec résultat dans un scan antérieur du domaine
+// Un scan partiel (/scan/dns) ne masque donc pas l'historique des autres scanners
+// ID = scan le plus récent ayant fourni un run ("" et aucun run si le domaine n'a pas d'historique)
+func (s *SQLite) PreviousRuns(ctx context.Context, snap jobs.Snapshot) (jobs.Snapshot, error) {
+	reference := jobs.Snapshot{Domain: snap.Domain, Runs: []jobs.Run{}}
+	loaded := map[string][]jobs.Run{} // Runs déjà relus, par scan
+	var latest time.Time
+
+	for _, run := range snap.Runs {
+		var id string
+		var position int
+		var createdAt time.Time
+		err := s.db.QueryRowContext(ctx,
+			`SELECT r.scan_id, r.position, s.created_at FROM runs r JOIN scans s ON s.id = r.scan_id
+			 WHERE s.domain = ? AND s.created_at < ? AND s.id != ? AND r.scanner = ? AND r.status = ? AND r.has_result
+			 ORDER BY s.created_at DESC LIMIT 1`,
+			snap.Domain, snap.CreatedAt.UTC(), snap.ID, run.Scanner, jobs.StatusDone,
+		).Scan(&id, &position, &createdAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return jobs.Snapshot{}, fmt.Errorf("erreur de lecture de l'historique: %w", err)
+		}
+
+		runs, ok := loaded[id]
+		if !ok {
+			if runs, err = s.runs(ctx, id); err != nil {
+				return jobs.Snapshot{}, fmt.Errorf("erreur de lecture du scan %s: %w", id, err)
+			}
+			loaded[id] = runs
+		}
+		if position < 0 || position >= len(runs) {
+			continue
+		}
+		reference.Runs = append(reference.Runs, runs[position])
+		if createdAt.After(latest) {
+			latest = createdAt
+			reference.ID = id
+		}
+	}
+	return reference, nil
+}
+
+// nullTime convertit un *time.Time (nil = pas encore arrivé) en valeur SQL (NULL si nil)
+// Les dates sont stockées en UTC : l'ordre alphabétique des colonnes DATETIME reste chronologique
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
+
+// timePtr est l'inverse de nullTime : NULL → nil
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,306 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/daviani/go__001/internal/jobs"
+	"github.com/daviani/go__001/internal/notify"
+	"github.com/daviani/go__001/internal/scanner"
+	"github.com/daviani/go__001/internal/schedule"
+)
+
+// openTest ouvre une base SQLite dans un dossier temporaire (supprimé à la fin du test)
+func openTest(t *testing.T) *SQLite {
+	t.Helper()
+	s, err := OpenSQLite(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// snapshot construit un scan terminé de deux scanners (dns réussi, ssl échoué)
+func snapshot(id string, created time.Time) jobs.Snapshot {
+	finished := created.Add(time.Second)
+	return jobs.Snapshot{
+		ID:         id,
+		Domain:     "example.com",
+		Status:     jobs.StatusDone,
+		CreatedAt:  created,
+		StartedAt:  &created,
+		FinishedAt: &finished,
+		Progress:   jobs.Progress{Done: 2, Total: 2},
+		Runs: []jobs.Run{
+			{
+				Scanner:    "dns",
+				Status:     jobs.StatusDone,
+				StartedAt:  &created,
+				FinishedAt: &finished,
+				Result: &scanner.Result{
+					Findings: []scanner.Finding{
+						{ID: "dns.record.a", Title: "Record A", Severity: scanner.SeverityInfo, Asset: "1.2.3.4"},
+						{ID: "dns.record.mx", Title: "Record MX", Severity: scanner.SeverityLow, Asset: "mx.example.com"},
+					},
+					Data: map[string][]string{"a": {"1.2.3.4"}},
+				},
+			},
+			{Scanner: "ssl", Status: jobs.StatusFailed, Error: "erreur interne du serveur"},
+		},
+	}
+}
+
+// TestSQLite_SaveScan — un scan relu est identique au scan enregistré
+func TestSQLite_SaveScan(t *testing.T) {
+	s := openTest(t)
+	ctx := context.Background()
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	if err := s.SaveScan(ctx, snapshot("abc", created)); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.GetScan(ctx, "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Domain != "example.com" || got.Status != jobs.StatusDone || !got.CreatedAt.Equal(created) {
+		t.Errorf("got %+v", got)
+	}
+	if got.Progress != (jobs.Progress{Done: 2, Total: 2}) {
+		t.Errorf("got progress %+v, want 2/2", got.Progress)
+	}
+	if len(got.Runs) != 2 {
+		t.Fatalf("got %d runs, want 2", len(got.Runs))
+	}
+
+	dns := got.Runs[0]
+	if dns.Scanner != "dns" || dns.Result == nil || len(dns.Result.Findings) != 2 {
+		t.Fatalf("got dns run %+v", dns)
+	}
+	if dns.Result.Findings[1].ID != "dns.record.mx" || dns.Result.Findings[1].Severity != scanner.SeverityLow {
+		t.Errorf("got finding %+v", dns.Result.Findings[1])
+	}
+	if dns.Result.Data == nil {
+		t.Error("raw data lost")
+	}
+
+	ssl := got.Runs[1]
+	if ssl.Result != nil || ssl.Error == "" {
+		t.Errorf("got ssl run %+v, want an error without result", ssl)
+	}
+
+	// Réenregistrer le même ID remplace le scan (pas de findings en double)
+	if err := s.SaveScan(ctx, snapshot("abc", created)); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = s.GetScan(ctx, "abc")
+	if n := len(got.Runs[0].Result.Findings); n != 2 {
+		t.Errorf("got %d findings after re-save, want 2", n)
+	}
+}
+
+// TestSQLite_GetScan_NotFound — un ID inconnu retourne ErrNotFound
+func TestSQLite_GetScan_NotFound(t *testing.T) {
+	s := openTest(t)
+	if _, err := s.GetScan(context.Background(), "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v, want ErrNotFound", err)
+	}
+}
+
+// TestSQLite_ListScans — historique paginé, du plus récent au plus ancien
+func TestSQLite_ListScans(t *testing.T) {
+	s := openTest(t)
+	ctx := context.Background()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i, id := range []string{"s1", "s2", "s3"} {
+		if err := s.SaveScan(ctx, snapshot(id, start.Add(time.Duration(i)*time.Hour))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	page, err := s.ListScans(ctx, "example.com", Page{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 3 || len(page.Scans) != 2 {
+		t.Fatalf("got total %d, %d scans, want 3 and 2", page.Total, len(page.Scans))
+	}
+	if page.Scans[0].ID != "s3" || page.Scans[1].ID != "s2" {
+		t.Errorf("got %s, %s, want s3, s2", page.Scans[0].ID, page.Scans[1].ID)
+	}
+
+	first := page.Scans[0]
+	if first.Findings != 2 || first.MaxSeverity != scanner.SeverityLow || first.Scanners != 2 {
+		t.Errorf("got summary %+v", first)
+	}
+
+	page, _ = s.ListScans(ctx, "example.com", Page{Limit: 2, Offset: 2})
+	if len(page.Scans) != 1 || page.Scans[0].ID != "s1" {
+		t.Errorf("got second page %+v, want s1", page.Scans)
+	}
+
+	page, _ = s.ListScans(ctx, "other.com", Page{})
+	if page.Total != 0 || page.Scans == nil {
+		t.Errorf("got %+v, want an empty page", page)
+	}
+}
+
+// TestSQLite_PreviousScan — le scan précédent du même domaine, ErrNotFound pour le premier
+func TestSQLite_PreviousScan(t *testing.T) {
+	s := openTest(t)
+	ctx := context.Background()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	first := snapshot("s1", start)
+	second := snapshot("s2", start.Add(time.Hour))
+	other := snapshot("o1", start.Add(30*time.Minute))
+	other.Domain = "other.com"
+	for _, snap := range []jobs.Snapshot{first, second, other} {
+		if err := s.SaveScan(ctx, snap); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	prev, err := s.PreviousScan(ctx, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prev.ID != "s1" {
+		t.Errorf("got %s, want s1", prev.ID)
+	}
+	if _, err := s.PreviousScan(ctx, first); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v, want ErrNotFound", err)
+	}
+}
+
+// TestSQLite_PreviousRuns — chaque scanner est pris dans son dernier scan réussi, même si un scan
+// plus récent ne l'a pas lancé ; un scanner jamais lancé n'a pas de run de référence
+func TestSQLite_PreviousRuns(t *testing.T) {
+	s := openTest(t)
+	ctx := context.Background()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	full := snapshot("s1", start)
+	full.Runs[1] = jobs.Run{Scanner: "ssl", Status: jobs.StatusDone, Result: &scanner.Result{
+		Findings: []scanner.Finding{{ID: "ssl.expiry", Severity: scanner.SeverityLow, Asset: "example.com"}},
+	}}
+	dnsOnly := snapshot("s2", start.Add(time.Hour))
+	dnsOnly.Runs = dnsOnly.Runs[:1]
+	for _, snap := range []jobs.Snapshot{full, dnsOnly} {
+		if err := s.SaveScan(ctx, snap); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	current := snapshot("s3", start.Add(2*time.Hour))
+	current.Runs = append(current.Runs, jobs.Run{Scanner: "never", Status: jobs.StatusDone, Result: &scanner.Result{}})
+	reference, err := s.PreviousRuns(ctx, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reference.ID != "s2" || len(reference.Runs) != 2 {
+		t.Fatalf("got %s with %d runs, want s2 with dns and ssl", reference.ID, len(reference.Runs))
+	}
+	// dns vient du scan s2, ssl du scan complet s1
+	if ssl := reference.Runs[1]; ssl.Scanner != "ssl" || len(ssl.Result.Findings) != 1 {
+		t.Errorf("got %+v, want the ssl run of s1", ssl)
+	}
+
+	first, err := s.PreviousRuns(ctx, full)
+	if err != nil || first.ID != "" || len(first.Runs) != 0 {
+		t.Errorf("got %+v (%v), want no reference", first, err)
+	}
+}
+
+// TestSQLite_Schedules — une planification enregistrée est relue à l'identique, puis supprimée
+func TestSQLite_Schedules(t *testing.T) {
+	s := openTest(t)
+	ctx := context.Background()
+	next := time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC)
+
+	sch := schedule.Schedule{
+		ID: "nightly", Domain: "example.com", Scanners: []string{"dns", "ssl"},
+		Cron: "0 3 * * *", Jitter: "5m", CreatedAt: next.Add(-time.Hour), NextRunAt: &next,
+	}
+	if err := s.SaveSchedule(ctx, sch); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := s.ListSchedules(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Cron != "0 3 * * *" || len(list[0].Scanners) != 2 || !list[0].NextRunAt.Equal(next) {
+		t.Fatalf("got %+v", list)
+	}
+
+	if err := s.DeleteSchedule(ctx, "nightly"); err != nil {
+		t.Fatal(err)
+	}
+	if list, _ := s.ListSchedules(ctx); len(list) != 0 {
+		t.Errorf("got %d schedules after delete, want 0", len(list))
+	}
+}
+
+// TestSQLite_Webhooks — mise à jour sans perte du journal, pagination, reprise et suppression en cascade
+func TestSQLite_Webhooks(t *testing.T) {
+	s := openTest(t)
+	ctx := context.Background()
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	hook := notify.Webhook{ID: "ops", URL: "https://hooks.example.com", Secret: "s3cr3t", CreatedAt: created}
+	if err := s.SaveWebhook(ctx, hook); err != nil {
+		t.Fatal(err)
+	}
+	for i, status := range []notify.DeliveryStatus{notify.DeliverySucceeded, notify.DeliveryPending, notify.DeliveryFailed} {
+		d := notify.Delivery{
+			ID: string(rune('a' + i)), WebhookID: "ops", Status: status,
+			Payload: []byte(`{"event":"scan.findings"}`), CreatedAt: created.Add(time.Duration(i) * time.Minute),
+		}
+		if err := s.SaveDelivery(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Modifier le webhook ne doit pas effacer ses livraisons
+	hook.Paused = true
+	if err := s.SaveWebhook(ctx, hook); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.GetWebhook(ctx, "ops")
+	if err != nil || !got.Paused || got.Secret != "s3cr3t" {
+		t.Fatalf("got %+v, %v", got, err)
+	}
+
+	page, err := s.ListDeliveries(ctx, "ops", 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 3 || len(page.Deliveries) != 2 || page.Deliveries[0].ID != "c" {
+		t.Fatalf("got %+v", page)
+	}
+
+	pending, err := s.PendingDeliveries(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].ID != "b" || string(pending[0].Payload) != `{"event":"scan.findings"}` {
+		t.Fatalf("got %+v", pending)
+	}
+
+	if err := s.DeleteWebhook(ctx, "ops"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetWebhook(ctx, "ops"); !errors.Is(err, notify.ErrNotFound) {
+		t.Errorf("got %v, want ErrNotFound", err)
+	}
+	if _, err := s.GetDelivery(ctx, "a"); !errors.Is(err, notify.ErrDeliveryNotFound) {
+		t.Errorf("got %v, want ErrDeliveryNotFound (cascade)", err)
+	}
+}
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/daviani/go__001/internal/jobs"
+	"github.com/daviani/go__001/internal/scanner"
+)
+
+// ErrNotFound — aucun scan ne correspond à l'identifiant demandé
+var ErrNotFound = errors.New("scan introuvable")
+
+// Valeurs de pagination de l'historique
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Store — stockage de l'historique des scans
+// Interface (et pas directement SQLite) : on pourra brancher PostgreSQL sans toucher à l'API
+// Équivalent JS/Express : un "repository" injecté dans les routes
+type Store interface {
+	// SaveScan enregistre un scan terminé (écrase un enregistrement existant de même ID)
+	SaveScan(ctx context.Context, snap jobs.Snapshot) error
+	// GetScan retourne un scan complet (scanners, résultats, findings) — ErrNotFound s'il n'existe pas
+	GetScan(ctx context.Context, id string) (jobs.Snapshot, error)
+	// ListScans retourne l'historique d'un domaine, du plus récent au plus ancien
+	ListScans(ctx context.Context, domain string, page Page) (ScanPage, error)
+	// PreviousScan retourne le scan du même domaine qui précède snap — ErrNotFound s'il n'y en a pas
+	PreviousScan(ctx context.Context, snap jobs.Snapshot) (jobs.Snapshot, error)
+	// PreviousRuns retourne, pour chaque scanner de snap, son dernier run réussi dans un scan antérieur du domaine
+	PreviousRuns(ctx context.Context, snap jobs.Snapshot) (jobs.Snapshot, error)
+	// Close libère la connexion à la base
+	Close() error
+}
+
+// Page — fenêtre de pagination (limit/offset)
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// normalize borne la page : limit entre 1 et MaxLimit (DefaultLimit si absent), offset >= 0
+func (p Page) normalize() Page {
+	if p.Limit <= 0 {
+		p.Limit = DefaultLimit
+	}
+	if p.Limit > MaxLimit {
+		p.Limit = MaxLimit
+	}
+	if p.Offset < 0 {
+		p.Offset = 0
+	}
+	return p
+}
+
+// ScanSummary — résumé d'un scan dans l'historique d'un domaine
+type ScanSummary struct {
+	ID          string           `json:"id"`
+	Domain      string           `json:"domain"`
+	Status      jobs.Status      `json:"status"`
+	CreatedAt   time.Time        `json:"created_at"`
+	FinishedAt  *time.Time       `json:"finished_at,omitempty"`
+	Scanners    int              `json:"scanners"`               // Nombre de scanners lancés
+	Findings    int              `json:"findings"`               // Nombre total de findings
+	MaxSeverity scanner.Severity `json:"max_severity,omitempty"` // Sévérité la plus haute du scan
+}
+
+// ScanPage — page de l'historique d'un domaine (GET /domains/{domain}/scans)
+type ScanPage struct {
+	Scans  []ScanSummary `json:"scans"`
+	Total  int           `json:"total"` // Nombre total de scans du domaine
+	Limit  int           `json:"limit"`
+	Offset int           `json:"offset"`
+}
+
+// summarize calcule le résumé d'un scan (nombre de findings, sévérité max)
+func summarize(snap jobs.Snapshot) ScanSummary {
+	summary := ScanSummary{
+		ID:         snap.ID,
+		Domain:     snap.Domain,
+		Status:     snap.Status,
+		CreatedAt:  snap.CreatedAt,
+		FinishedAt: snap.FinishedAt,
+		Scanners:   len(snap.Runs),
+	}
+	for _, run := range snap.Runs {
+		if run.Result == nil {
+			continue
+		}
+		summary.Findings += len(run.Result.Findings)
+		if severity := run.Result.MaxSeverity(); severity.Rank() > summary.MaxSeverity.Rank() {
+			summary.MaxSeverity = severity
+		}
+	}
+	return summary
+}
@@ -0,0 +1,188 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/daviani/go__001/internal/notify"
+)
+
+// webhooksSchema — webhooks et journal de leurs livraisons
+// Comme les planifications, chaque ligne stocke le document JSON complet ;
+// seules les colonnes filtrées (webhook, statut, date) sont extraites
+const webhooksSchema = `
+CREATE TABLE IF NOT EXISTS webhooks (
+	id         TEXT PRIMARY KEY,
+	created_at DATETIME NOT NULL,
+	data       TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS deliveries (
+	id         TEXT PRIMARY KEY,
+	webhook_id TEXT NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+	status     TEXT NOT NULL,
+	created_at DATETIME NOT NULL,
+	data       TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS deliveries_webhook ON deliveries (webhook_id, created_at DESC);
+`
+
+// SQLite stocke aussi les webhooks du notifier
+var _ notify.Repository = (*SQLite)(nil)
+
+// ListWebhooks retourne tous les webhooks, secrets compris (implémente notify.Repository)
+func (s *SQLite) ListWebhooks(ctx context.Context) ([]notify.Webhook, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT data FROM webhooks ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("erreur de lecture des webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var webhooks []notify.Webhook
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("erreur de lecture des webhooks: %w", err)
+		}
+		var w notify.Webhook
+		if err := json.Unmarshal([]byte(data), &w); err != nil {
+			return nil, fmt.Errorf("webhook illisible: %w", err)
+		}
+		webhooks = append(webhooks, w)
+	}
+	return webhooks, rows.Err()
+}
+
+// GetWebhook retourne un webhook (notify.ErrNotFound s'il n'existe pas)
+func (s *SQLite) GetWebhook(ctx context.Context, id string) (notify.Webhook, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx, `SELECT data FROM webhooks WHERE id = ?`, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return notify.Webhook{}, notify.ErrNotFound
+	}
+	if err != nil {
+		return notify.Webhook{}, fmt.Errorf("erreur de lecture du webhook %s: %w", id, err)
+	}
+
+	var w notify.Webhook
+	if err := json.Unmarshal([]byte(data), &w); err != nil {
+		return notify.Webhook{}, fmt.Errorf("webhook illisible: %w", err)
+	}
+	return w, nil
+}
+
+// SaveWebhook crée ou met à jour un webhook
+// UPSERT plutôt que REPLACE : REPLACE supprime puis réinsère la ligne, ce qui effacerait les livraisons (cascade)
+func (s *SQLite) SaveWebhook(ctx context.Context, w notify.Webhook) error {
+	data, err := json.Marshal(w)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO webhooks (id, created_at, data) VALUES (?, ?, ?)
+		 ON CONFLICT (id) DO UPDATE SET data = excluded.data`,
+		w.ID, w.CreatedAt.UTC(), string(data),
+	)
+	if err != nil {
+		return fmt.Errorf("erreur de sauvegarde du webhook %s: %w", w.ID, err)
+	}
+	return nil
+}
+
+// DeleteWebhook supprime un webhook et, par cascade, son journal de livraisons
+func (s *SQLite) DeleteWebhook(ctx context.Context, id string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("erreur de suppression du webhook %s: %w", id, err)
+	}
+	return nil
+}
+
+// SaveDelivery crée ou met à jour une livraison (appelé après chaque tentative)
+func (s *SQLite) SaveDelivery(ctx context.Context, d notify.Delivery) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO deliveries (id, webhook_id, status, created_at, data) VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT (id) DO UPDATE SET status = excluded.status, data = excluded.data`,
+		d.ID, d.WebhookID, string(d.Status), d.CreatedAt.UTC(), string(data),
+	)
+	if err != nil {
+		return fmt.Errorf("erreur de sauvegarde de la livraison %s: %w", d.ID, err)
+	}
+	return nil
+}
+
+// GetDelivery retourne une livraison (notify.ErrDeliveryNotFound si elle n'existe pas)
+func (s *SQLite) GetDelivery(ctx context.Context, id string) (notify.Delivery, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx, `SELECT data FROM deliveries WHERE id = ?`, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return notify.Delivery{}, notify.ErrDeliveryNotFound
+	}
+	if err != nil {
+		return notify.Delivery{}, fmt.Errorf("erreur de lecture de la livraison %s: %w", id, err)
+	}
+
+	var d notify.Delivery
+	if err := json.Unmarshal([]byte(data), &d); err != nil {
+		return notify.Delivery{}, fmt.Errorf("livraison illisible: %w", err)
+	}
+	return d, nil
+}
+
+// ListDeliveries retourne une page du journal d'un webhook, de la plus récente à la plus ancienne
+func (s *SQLite) ListDeliveries(ctx context.Context, webhookID string, limit, offset int) (notify.DeliveryPage, error) {
+	page := Page{Limit: limit, Offset: offset}.normalize()
+	result := notify.DeliveryPage{Deliveries: []notify.Delivery{}, Limit: page.Limit, Offset: page.Offset}
+
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM deliveries WHERE webhook_id = ?`, webhookID).Scan(&result.Total)
+	if err != nil {
+		return notify.DeliveryPage{}, fmt.Errorf("erreur de lecture des livraisons: %w", err)
+	}
+
+	deliveries, err := s.queryDeliveries(ctx,
+		`SELECT data FROM deliveries WHERE webhook_id = ? ORDER BY created_at DESC, id LIMIT ? OFFSET ?`,
+		webhookID, page.Limit, page.Offset,
+	)
+	if err != nil {
+		return notify.DeliveryPage{}, err
+	}
+	result.Deliveries = append(result.Deliveries, deliveries...)
+	return result, nil
+}
+
+// PendingDeliveries retourne les livraisons non terminées, à reprendre au démarrage
+func (s *SQLite) PendingDeliveries(ctx context.Context) ([]notify.Delivery, error) {
+	return s.queryDeliveries(ctx,
+		`SELECT data FROM deliveries WHERE status = ? ORDER BY created_at`,
+		string(notify.DeliveryPending),
+	)
+}
+
+// queryDeliveries exécute une requête SELECT data FROM deliveries et décode chaque ligne
+func (s *SQLite) queryDeliveries(ctx context.Context, query string, args ...any) ([]notify.Delivery, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erreur de lecture des livraisons: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []notify.Delivery
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("erreur de lecture des livraisons: %w", err)
+		}
+		var d notify.Delivery
+		if err := json.Unmarshal([]byte(data), &d); err != nil {
+			return nil, fmt.Errorf("livraison illisible: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
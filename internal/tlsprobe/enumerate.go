@@ -0,0 +1,85 @@
+package tlsprobe
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"slices"
+)
+
+// Protocol — ce qu'un serveur accepte pour une version donnée
+type Protocol struct {
+	Version   uint16
+	Supported bool
+	Suites    []Suite // Suites acceptées, dans l'ordre du serveur s'il impose sa préférence
+
+	// ServerPreference — le serveur choisit selon son propre ordre, quel que soit celui du client
+	// Faux : le premier choix dépend du client (un vieux navigateur peut obtenir la pire suite)
+	ServerPreference bool
+
+	Groups       []uint16            // Groupes ECDHE/FFDHE vus pendant l'énumération
+	DHBits       int                 // Plus petit module DH rencontré (0 = aucune suite DHE acceptée)
+	Certificates []*x509.Certificate // Chaîne vue en clair (TLS ≤ 1.2), nil en TLS 1.3
+}
+
+// Enumerate liste les suites acceptées pour version : on propose toutes les suites, on retire celle
+// choisie par le serveur, et on recommence jusqu'au refus — une connexion par suite acceptée, plus une
+func (p *Prober) Enumerate(ctx context.Context, address, serverName string, version uint16) (Protocol, error) {
+	proto := Protocol{Version: version}
+	remaining := Suites(version)
+	for len(remaining) > 0 {
+		ids := make([]uint16, len(remaining))
+		for i, s := range remaining {
+			ids[i] = s.ID
+		}
+		sh, err := p.Hello(ctx, address, serverName, version, ids)
+		if errors.Is(err, ErrRejected) {
+			break
+		}
+		if err != nil {
+			return proto, err
+		}
+
+		// Suite non proposée : serveur non conforme, on arrête plutôt que de boucler
+		i := slices.IndexFunc(remaining, func(s Suite) bool { return s.ID == sh.CipherSuite })
+		if i < 0 {
+			break
+		}
+		proto.Supported = true
+		proto.Suites = append(proto.Suites, remaining[i])
+		remaining = slices.Delete(remaining, i, i+1)
+
+		if sh.Group != 0 && !slices.Contains(proto.Groups, sh.Group) {
+			proto.Groups = append(proto.Groups, sh.Group)
+		}
+		if sh.DHBits > 0 && (proto.DHBits == 0 || sh.DHBits < proto.DHBits) {
+			proto.DHBits = sh.DHBits
+		}
+		if proto.Certificates == nil {
+			proto.Certificates = sh.Certificates
+		}
+	}
+
+	if len(proto.Suites) >= 2 {
+		preference, err := p.serverPreference(ctx, address, serverName, version, proto.Suites)
+		if err != nil {
+			return proto, err
+		}
+		proto.ServerPreference = preference
+	}
+	return proto, nil
+}
+
+// serverPreference propose la dernière suite acceptée avant la première :
+// un serveur qui impose son ordre choisit quand même la première
+func (p *Prober) serverPreference(ctx context.Context, address, serverName string, version uint16, accepted []Suite) (bool, error) {
+	first, last := accepted[0].ID, accepted[len(accepted)-1].ID
+	sh, err := p.Hello(ctx, address, serverName, version, []uint16{last, first})
+	if errors.Is(err, ErrRejected) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return sh.CipherSuite == first, nil
+}
@@ -0,0 +1,435 @@
+package tlsprobe
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Versions du protocole (valeurs du champ version de TLS)
+const (
+	VersionSSL30 uint16 = 0x0300
+	VersionTLS10 uint16 = 0x0301
+	VersionTLS11 uint16 = 0x0302
+	VersionTLS12 uint16 = 0x0303
+	VersionTLS13 uint16 = 0x0304
+)
+
+// Versions — versions sondées, de la plus ancienne à la plus récente
+var Versions = []uint16{VersionSSL30, VersionTLS10, VersionTLS11, VersionTLS12, VersionTLS13}
+
+// VersionName retourne le nom usuel d'une version ("TLS 1.2")
+func VersionName(v uint16) string {
+	switch v {
+	case VersionSSL30:
+		return "SSLv3"
+	case VersionTLS10:
+		return "TLS 1.0"
+	case VersionTLS11:
+		return "TLS 1.1"
+	case VersionTLS12:
+		return "TLS 1.2"
+	case VersionTLS13:
+		return "TLS 1.3"
+	}
+	return fmt.Sprintf("0x%04X", v)
+}
+
+// Groupes d'échange de clés (supported_groups, RFC 8422 et RFC 7919)
+const (
+	groupSecp256r1 uint16 = 23
+	groupSecp384r1 uint16 = 24
+	groupSecp521r1 uint16 = 25
+	groupX25519    uint16 = 29
+	groupFFDHE2048 uint16 = 256
+	groupFFDHE3072 uint16 = 257
+)
+
+// GroupName retourne le nom d'un groupe ECDHE/FFDHE ("X25519")
+func GroupName(g uint16) string {
+	switch g {
+	case groupSecp256r1:
+		return "P-256"
+	case groupSecp384r1:
+		return "P-384"
+	case groupSecp521r1:
+		return "P-521"
+	case groupX25519:
+		return "X25519"
+	case groupFFDHE2048:
+		return "ffdhe2048"
+	case groupFFDHE3072:
+		return "ffdhe3072"
+	}
+	return fmt.Sprintf("0x%04X", g)
+}
+
+// Valeurs par défaut de la sonde
+const (
+	defaultTimeout = 10 * time.Second
+	// maxHandshake — volume lu avant ServerHelloDone : une chaîne de certificats dépasse rarement 16 Ko
+	maxHandshake = 64 * 1024
+)
+
+// Types de records et de messages de handshake (RFC 5246 §6.2.1 et §7.4)
+const (
+	recordAlert     = 21
+	recordHandshake = 22
+
+	msgServerHello       = 2
+	msgCertificate       = 11
+	msgServerKeyExchange = 12
+	msgServerHelloDone   = 14
+)
+
+// ErrRejected — le serveur refuse la version ou toutes les suites proposées (alerte, connexion fermée, autre version)
+var ErrRejected = errors.New("handshake refusé")
+
+// Prober — sonde TLS bas niveau : envoie un ClientHello construit à la main et lit la réponse du serveur
+// crypto/tls ne sait ni proposer SSLv3 ni les suites qu'il n'implémente pas : la sonde s'arrête avant
+// l'échange de clés, sans jamais chiffrer, ce qui suffit pour savoir ce que le serveur accepte
+// Équivalent JS : un net.Socket qui écrit des octets bruts, là où tls.connect ferait la négociation complète
+type Prober struct {
+	Timeout time.Duration // Délai par handshake (0 = 10s)
+
+	// DialContext ouvre les connexions (nil = net.Dialer) — comme dnsclient.Client.DialContext
+	DialContext func(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// ServerHello — ce que le serveur a choisi parmi les propositions du client
+type ServerHello struct {
+	Version     uint16
+	CipherSuite uint16
+	Group       uint16 // Groupe ECDHE/FFDHE choisi (ServerKeyExchange, ou key_share en TLS 1.3) — 0 si inconnu
+	DHBits      int    // Taille du module Diffie-Hellman (suites DHE en TLS ≤ 1.2)
+
+	// Certificates — chaîne envoyée en clair (TLS ≤ 1.2) ; chiffrée en TLS 1.3, donc vide
+	Certificates []*x509.Certificate
+}
+
+// Hello propose version et suites à address, et retourne le choix du serveur
+// serverName est envoyé en SNI (ignoré pour une adresse IP)
+// ErrRejected si le serveur refuse ; toute autre erreur est une erreur réseau (hôte injoignable, timeout)
+func (p *Prober) Hello(ctx context.Context, address, serverName string, version uint16, suites []uint16) (*ServerHello, error) {
+	hello, err := clientHello(version, serverName, suites)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := p.dial(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(p.timeout())
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	if _, err := conn.Write(hello); err != nil {
+		return nil, err
+	}
+	sh, err := readServerHello(conn, version)
+	if err != nil && closedByPeer(err) {
+		return nil, ErrRejected
+	}
+	return sh, err
+}
+
+// dial ouvre la connexion TCP avec DialContext s'il est défini, sinon net.Dialer
+func (p *Prober) dial(ctx context.Context, address string) (net.Conn, error) {
+	if p.DialContext != nil {
+		return p.DialContext(ctx, "tcp", address)
+	}
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, "tcp", address)
+}
+
+// timeout retourne le délai par handshake configuré, sinon la valeur par défaut
+func (p *Prober) timeout() time.Duration {
+	if p.Timeout > 0 {
+		return p.Timeout
+	}
+	return defaultTimeout
+}
+
+// closedByPeer indique une connexion fermée par le serveur : beaucoup de serveurs refusent ainsi, sans alerte
+func closedByPeer(err error) bool {
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// clientHello construit le record contenant le ClientHello
+// SSLv3 : aucune extension ; TLS 1.3 : supported_versions et key_share X25519 (clé éphémère jetable)
+func clientHello(version uint16, serverName string, suites []uint16) ([]byte, error) {
+	legacy := min(version, VersionTLS12)
+	var body []byte
+	body = binary.BigEndian.AppendUint16(body, legacy)
+	random := make([]byte, 32)
+	_, _ = rand.Read(random)
+	body = append(body, random...)
+
+	// Session ID de 32 octets en TLS 1.3 : mode compatibilité, attendu par certains équipements intermédiaires
+	if version == VersionTLS13 {
+		sessionID := make([]byte, 32)
+		_, _ = rand.Read(sessionID)
+		body = append(body, 32)
+		body = append(body, sessionID...)
+	} else {
+		body = append(body, 0)
+	}
+
+	cipherList := suites
+	if version < VersionTLS13 {
+		// TLS_EMPTY_RENEGOTIATION_INFO_SCSV : certains serveurs exigent un signe de renégociation sécurisée
+		cipherList = append(append([]uint16(nil), suites...), 0x00ff)
+	}
+	body = binary.BigEndian.AppendUint16(body, uint16(2*len(cipherList)))
+	for _, s := range cipherList {
+		body = binary.BigEndian.AppendUint16(body, s)
+	}
+	body = append(body, 1, 0) // Compression : méthode "null" uniquement
+
+	if version > VersionSSL30 {
+		ext, err := extensions(version, serverName)
+		if err != nil {
+			return nil, err
+		}
+		body = binary.BigEndian.AppendUint16(body, uint16(len(ext)))
+		body = append(body, ext...)
+	}
+
+	handshake := []byte{1, byte(len(body) >> 16), byte(len(body) >> 8), byte(len(body))}
+	handshake = append(handshake, body...)
+
+	// Version du record : 0x0300 pour SSLv3, 0x0301 sinon (valeur la plus tolérée par les serveurs)
+	recordVersion := VersionTLS10
+	if version == VersionSSL30 {
+		recordVersion = VersionSSL30
+	}
+	record := []byte{recordHandshake, byte(recordVersion >> 8), byte(recordVersion)}
+	record = binary.BigEndian.AppendUint16(record, uint16(len(handshake)))
+	return append(record, handshake...), nil
+}
+
+// extensions construit les extensions du ClientHello (TLS 1.0 et plus)
+func extensions(version uint16, serverName string) ([]byte, error) {
+	var ext []byte
+	add := func(typ uint16, data []byte) {
+		ext = binary.BigEndian.AppendUint16(ext, typ)
+		ext = binary.BigEndian.AppendUint16(ext, uint16(len(data)))
+		ext = append(ext, data...)
+	}
+
+	// server_name (SNI) — jamais pour une adresse IP (RFC 6066 §3)
+	if serverName != "" && net.ParseIP(serverName) == nil {
+		name := []byte(serverName)
+		data := binary.BigEndian.AppendUint16(nil, uint16(len(name)+3))
+		data = append(data, 0)
+		data = binary.BigEndian.AppendUint16(data, uint16(len(name)))
+		add(0, append(data, name...))
+	}
+
+	groups := []uint16{groupX25519, groupSecp256r1, groupSecp384r1, groupSecp521r1, groupFFDHE2048, groupFFDHE3072}
+	data := binary.BigEndian.AppendUint16(nil, uint16(2*len(groups)))
+	for _, g := range groups {
+		data = binary.BigEndian.AppendUint16(data, g)
+	}
+	add(10, data)         // supported_groups
+	add(11, []byte{1, 0}) // ec_point_formats : non compressé
+
+	// signature_algorithms : ECDSA, RSA-PSS, Ed25519, RSA PKCS#1, puis SHA-1 pour les vieux serveurs
+	sigAlgs := []uint16{0x0403, 0x0503, 0x0603, 0x0804, 0x0805, 0x0806, 0x0807, 0x0401, 0x0501, 0x0601, 0x0201, 0x0203}
+	data = binary.BigEndian.AppendUint16(nil, uint16(2*len(sigAlgs)))
+	for _, alg := range sigAlgs {
+		data = binary.BigEndian.AppendUint16(data, alg)
+	}
+	add(13, data)
+
+	if version < VersionTLS13 {
+		add(0xff01, []byte{0}) // renegotiation_info vide
+		return ext, nil
+	}
+
+	add(43, []byte{2, 0x03, 0x04}) // supported_versions : TLS 1.3 uniquement
+	add(45, []byte{1, 1})          // psk_key_exchange_modes : psk_dhe_ke
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	pub := key.PublicKey().Bytes()
+	share := binary.BigEndian.AppendUint16(nil, groupX25519)
+	share = binary.BigEndian.AppendUint16(share, uint16(len(pub)))
+	share = append(share, pub...)
+	add(51, append(binary.BigEndian.AppendUint16(nil, uint16(len(share))), share...)) // key_share
+	return ext, nil
+}
+
+// readServerHello lit les messages de handshake jusqu'au ServerHello (TLS 1.3) ou au ServerHelloDone (TLS ≤ 1.2)
+// Les messages peuvent être fragmentés sur plusieurs records, ou plusieurs messages partager un record
+func readServerHello(conn net.Conn, version uint16) (*ServerHello, error) {
+	var buf []byte // octets de handshake reçus, pas encore analysés
+	var sh *ServerHello
+	read := 0
+	for {
+		// Analyse des messages complets déjà reçus
+		for len(buf) >= 4 {
+			length := int(buf[1])<<16 | int(buf[2])<<8 | int(buf[3])
+			if len(buf) < 4+length {
+				break
+			}
+			typ, msg := buf[0], buf[4:4+length]
+			buf = buf[4+length:]
+
+			switch {
+			case typ == msgServerHello:
+				parsed, err := parseServerHello(msg)
+				if err != nil {
+					return nil, err
+				}
+				if parsed.Version != version {
+					return nil, fmt.Errorf("%w : %s négocié", ErrRejected, VersionName(parsed.Version))
+				}
+				sh = parsed
+				// TLS 1.3 : tout ce qui suit le ServerHello est chiffré
+				if version == VersionTLS13 {
+					return sh, nil
+				}
+			case sh == nil:
+				return nil, errors.New("message de handshake inattendu avant le ServerHello")
+			case typ == msgCertificate:
+				sh.Certificates = parseCertificates(msg)
+			case typ == msgServerKeyExchange:
+				parseKeyExchange(sh, msg)
+			case typ == msgServerHelloDone:
+				return sh, nil
+			}
+		}
+
+		header := make([]byte, 5)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return nil, err
+		}
+		length := int(binary.BigEndian.Uint16(header[3:]))
+		read += length
+		if read > maxHandshake {
+			return nil, errors.New("handshake trop volumineux")
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(conn, payload); err != nil {
+			return nil, err
+		}
+		switch header[0] {
+		case recordAlert:
+			return nil, ErrRejected
+		case recordHandshake:
+			buf = append(buf, payload...)
+		default:
+			// Réponse SSLv2 ou autre protocole sur ce port
+			if sh == nil {
+				return nil, fmt.Errorf("%w : réponse non TLS (type de record %d)", ErrRejected, header[0])
+			}
+			return sh, nil
+		}
+	}
+}
+
+// parseServerHello extrait version, suite et groupe d'un ServerHello
+// Un HelloRetryRequest TLS 1.3 a le même format et porte déjà la suite choisie : il vaut acceptation pour la sonde
+// La version réelle d'un TLS 1.3 est dans l'extension supported_versions, le champ legacy valant 0x0303
+func parseServerHello(msg []byte) (*ServerHello, error) {
+	errInvalid := errors.New("ServerHello invalide")
+	if len(msg) < 38 {
+		return nil, errInvalid
+	}
+	sh := &ServerHello{Version: binary.BigEndian.Uint16(msg)}
+	sessionLen := int(msg[34])
+	rest := msg[35:]
+	if len(rest) < sessionLen+3 {
+		return nil, errInvalid
+	}
+	rest = rest[sessionLen:]
+	sh.CipherSuite = binary.BigEndian.Uint16(rest)
+	rest = rest[3:] // suite + méthode de compression
+
+	if len(rest) < 2 {
+		return sh, nil // Pas d'extensions (SSLv3, vieux TLS)
+	}
+	extLen := int(binary.BigEndian.Uint16(rest))
+	rest = rest[2:]
+	if len(rest) < extLen {
+		return nil, errInvalid
+	}
+	rest = rest[:extLen]
+	for len(rest) >= 4 {
+		typ, length := binary.BigEndian.Uint16(rest), int(binary.BigEndian.Uint16(rest[2:]))
+		if len(rest) < 4+length {
+			return nil, errInvalid
+		}
+		data := rest[4 : 4+length]
+		rest = rest[4+length:]
+		switch {
+		case typ == 43 && length == 2: // supported_versions
+			sh.Version = binary.BigEndian.Uint16(data)
+		case typ == 51 && length >= 2: // key_share : groupe seul dans un HelloRetryRequest, groupe + clé sinon
+			sh.Group = binary.BigEndian.Uint16(data)
+		}
+	}
+	return sh, nil
+}
+
+// parseCertificates décode le message Certificate (TLS ≤ 1.2) : liste de certificats DER préfixés sur 3 octets
+func parseCertificates(msg []byte) []*x509.Certificate {
+	if len(msg) < 3 {
+		return nil
+	}
+	rest := msg[3:]
+	var certs []*x509.Certificate
+	for len(rest) >= 3 {
+		length := int(rest[0])<<16 | int(rest[1])<<8 | int(rest[2])
+		if len(rest) < 3+length {
+			break
+		}
+		if cert, err := x509.ParseCertificate(rest[3 : 3+length]); err == nil {
+			certs = append(certs, cert)
+		}
+		rest = rest[3+length:]
+	}
+	return certs
+}
+
+// parseKeyExchange lit les paramètres du ServerKeyExchange selon la suite choisie
+// ECDHE : curve_type (3 = named_curve) puis le groupe ; DHE : longueur et valeur du module p
+func parseKeyExchange(sh *ServerHello, msg []byte) {
+	suite := SuiteByID(sh.CipherSuite)
+	switch {
+	case suite.DHE() || strings.HasPrefix(suite.Name, "TLS_DH_anon_"):
+		if len(msg) < 2 {
+			return
+		}
+		length := int(binary.BigEndian.Uint16(msg))
+		if len(msg) < 2+length {
+			return
+		}
+		p := bytes.TrimLeft(msg[2:2+length], "\x00")
+		if len(p) > 0 {
+			sh.DHBits = (len(p)-1)*8 + bits.Len8(p[0])
+		}
+	case len(msg) >= 3 && msg[0] == 3:
+		sh.Group = binary.BigEndian.Uint16(msg[1:])
+	}
+}
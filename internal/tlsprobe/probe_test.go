@@ -0,0 +1,101 @@
+package tlsprobe
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// tlsServer démarre un serveur httptest avec une configuration TLS restreinte
+func tlsServer(t *testing.T, config *tls.Config) string {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = config
+	// La sonde coupe chaque handshake avant la fin : le serveur le journaliserait comme une erreur
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv.Listener.Addr().String()
+}
+
+// TestProber_Enumerate — versions acceptées et suites listées dans l'ordre imposé par le serveur
+func TestProber_Enumerate(t *testing.T) {
+	addr := tlsServer(t, &tls.Config{
+		MinVersion: tls.VersionTLS10,
+		MaxVersion: tls.VersionTLS12,
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_RSA_WITH_3DES_EDE_CBC_SHA,
+			tls.TLS_RSA_WITH_RC4_128_SHA,
+		},
+	})
+	var p Prober
+	ctx := context.Background()
+
+	tls12, err := p.Enumerate(ctx, addr, "example.com", VersionTLS12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, s := range tls12.Suites {
+		names = append(names, s.Name)
+	}
+	// crypto/tls impose son propre ordre : AEAD d'abord, RC4 en dernier
+	want := []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_RSA_WITH_3DES_EDE_CBC_SHA", "TLS_RSA_WITH_RC4_128_SHA"}
+	if !slices.Equal(names, want) {
+		t.Errorf("got suites %q, want %q", names, want)
+	}
+	if !tls12.ServerPreference {
+		t.Error("got ServerPreference false, want true")
+	}
+	if len(tls12.Certificates) == 0 || !slices.Contains(tls12.Groups, groupX25519) {
+		t.Errorf("got %d certificates, groups %v", len(tls12.Certificates), tls12.Groups)
+	}
+
+	// TLS 1.0 : suites AEAD indisponibles
+	tls10, err := p.Enumerate(ctx, addr, "example.com", VersionTLS10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tls10.Supported || len(tls10.Suites) != 2 {
+		t.Errorf("got TLS 1.0 %+v, want 2 suites", tls10.Suites)
+	}
+
+	for _, v := range []uint16{VersionSSL30, VersionTLS13} {
+		if _, err := p.Hello(ctx, addr, "example.com", v, ids(Suites(v))); !errors.Is(err, ErrRejected) {
+			t.Errorf("%s : got %v, want ErrRejected", VersionName(v), err)
+		}
+	}
+}
+
+// TestProber_Hello_TLS13 — ServerHello TLS 1.3 : version lue dans supported_versions, groupe du key_share
+func TestProber_Hello_TLS13(t *testing.T) {
+	addr := tlsServer(t, &tls.Config{MinVersion: tls.VersionTLS13})
+	var p Prober
+
+	sh, err := p.Hello(context.Background(), addr, "example.com", VersionTLS13, ids(Suites(VersionTLS13)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sh.Version != VersionTLS13 || sh.Group != groupX25519 || !SuiteByID(sh.CipherSuite).TLS13() {
+		t.Errorf("got %+v", sh)
+	}
+	if _, err := p.Hello(context.Background(), addr, "example.com", VersionTLS12, ids(Suites(VersionTLS12))); !errors.Is(err, ErrRejected) {
+		t.Errorf("TLS 1.2 : got %v, want ErrRejected", err)
+	}
+}
+
+// ids extrait les identifiants d'une liste de suites
+func ids(suites []Suite) []uint16 {
+	out := make([]uint16, len(suites))
+	for i, s := range suites {
+		out[i] = s.ID
+	}
+	return out
+}
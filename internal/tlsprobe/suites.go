@@ -0,0 +1,184 @@
+package tlsprobe
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Suite — suite de chiffrement TLS (registre IANA)
+// crypto/tls n'implémente qu'une vingtaine de suites : la table couvre aussi celles qu'il refuse de négocier
+// (NULL, EXPORT, DES, anonymes, DHE...) puisque c'est justement leur présence côté serveur qu'on cherche
+type Suite struct {
+	ID   uint16
+	Name string
+	Bits int // Taille effective de la clé de chiffrement (0 = NULL, 40 = EXPORT, 112 = 3DES)
+}
+
+// suites — suites sondées, TLS 1.3 d'abord puis les plus récentes : l'ordre sert de préférence client
+var suites = []Suite{
+	// TLS 1.3 (RFC 8446) — échange de clés et authentification négociés à part
+	{0x1301, "TLS_AES_128_GCM_SHA256", 128},
+	{0x1302, "TLS_AES_256_GCM_SHA384", 256},
+	{0x1303, "TLS_CHACHA20_POLY1305_SHA256", 256},
+	{0x1304, "TLS_AES_128_CCM_SHA256", 128},
+	{0x1305, "TLS_AES_128_CCM_8_SHA256", 128},
+
+	// ECDHE AEAD
+	{0xc02b, "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256", 128},
+	{0xc02c, "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384", 256},
+	{0xc02f, "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", 128},
+	{0xc030, "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384", 256},
+	{0xcca9, "TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256", 256},
+	{0xcca8, "TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256", 256},
+	{0xc0ac, "TLS_ECDHE_ECDSA_WITH_AES_128_CCM", 128},
+	{0xc0ad, "TLS_ECDHE_ECDSA_WITH_AES_256_CCM", 256},
+
+	// DHE AEAD
+	{0x009e, "TLS_DHE_RSA_WITH_AES_128_GCM_SHA256", 128},
+	{0x009f, "TLS_DHE_RSA_WITH_AES_256_GCM_SHA384", 256},
+	{0xccaa, "TLS_DHE_RSA_WITH_CHACHA20_POLY1305_SHA256", 256},
+	{0xc09e, "TLS_DHE_RSA_WITH_AES_128_CCM", 128},
+	{0xc09f, "TLS_DHE_RSA_WITH_AES_256_CCM", 256},
+
+	// ECDHE CBC
+	{0xc023, "TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA256", 128},
+	{0xc024, "TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA384", 256},
+	{0xc027, "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA256", 128},
+	{0xc028, "TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA384", 256},
+	{0xc009, "TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA", 128},
+	{0xc00a, "TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA", 256},
+	{0xc013, "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA", 128},
+	{0xc014, "TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA", 256},
+	{0xc072, "TLS_ECDHE_ECDSA_WITH_CAMELLIA_128_CBC_SHA256", 128},
+	{0xc076, "TLS_ECDHE_RSA_WITH_CAMELLIA_128_CBC_SHA256", 128},
+
+	// DHE CBC
+	{0x0067, "TLS_DHE_RSA_WITH_AES_128_CBC_SHA256", 128},
+	{0x006b, "TLS_DHE_RSA_WITH_AES_256_CBC_SHA256", 256},
+	{0x0033, "TLS_DHE_RSA_WITH_AES_128_CBC_SHA", 128},
+	{0x0039, "TLS_DHE_RSA_WITH_AES_256_CBC_SHA", 256},
+	{0x0032, "TLS_DHE_DSS_WITH_AES_128_CBC_SHA", 128},
+	{0x0038, "TLS_DHE_DSS_WITH_AES_256_CBC_SHA", 256},
+	{0x0045, "TLS_DHE_RSA_WITH_CAMELLIA_128_CBC_SHA", 128},
+	{0x0088, "TLS_DHE_RSA_WITH_CAMELLIA_256_CBC_SHA", 256},
+	{0x009a, "TLS_DHE_RSA_WITH_SEED_CBC_SHA", 128},
+
+	// Échange de clés RSA (pas de confidentialité persistante)
+	{0x009c, "TLS_RSA_WITH_AES_128_GCM_SHA256", 128},
+	{0x009d, "TLS_RSA_WITH_AES_256_GCM_SHA384", 256},
+	{0xc09c, "TLS_RSA_WITH_AES_128_CCM", 128},
+	{0xc09d, "TLS_RSA_WITH_AES_256_CCM", 256},
+	{0x003c, "TLS_RSA_WITH_AES_128_CBC_SHA256", 128},
+	{0x003d, "TLS_RSA_WITH_AES_256_CBC_SHA256", 256},
+	{0x002f, "TLS_RSA_WITH_AES_128_CBC_SHA", 128},
+	{0x0035, "TLS_RSA_WITH_AES_256_CBC_SHA", 256},
+	{0x0041, "TLS_RSA_WITH_CAMELLIA_128_CBC_SHA", 128},
+	{0x0084, "TLS_RSA_WITH_CAMELLIA_256_CBC_SHA", 256},
+	{0x0096, "TLS_RSA_WITH_SEED_CBC_SHA", 128},
+	{0x0007, "TLS_RSA_WITH_IDEA_CBC_SHA", 128},
+
+	// ECDH / DH statiques (clé du certificat : pas de confidentialité persistante)
+	{0xc004, "TLS_ECDH_ECDSA_WITH_AES_128_CBC_SHA", 128},
+	{0xc005, "TLS_ECDH_ECDSA_WITH_AES_256_CBC_SHA", 256},
+	{0xc00e, "TLS_ECDH_RSA_WITH_AES_128_CBC_SHA", 128},
+	{0xc00f, "TLS_ECDH_RSA_WITH_AES_256_CBC_SHA", 256},
+
+	// 3DES (blocs de 64 bits : Sweet32)
+	{0xc008, "TLS_ECDHE_ECDSA_WITH_3DES_EDE_CBC_SHA", 112},
+	{0xc012, "TLS_ECDHE_RSA_WITH_3DES_EDE_CBC_SHA", 112},
+	{0x0016, "TLS_DHE_RSA_WITH_3DES_EDE_CBC_SHA", 112},
+	{0x0013, "TLS_DHE_DSS_WITH_3DES_EDE_CBC_SHA", 112},
+	{0x000a, "TLS_RSA_WITH_3DES_EDE_CBC_SHA", 112},
+
+	// RC4 (biais statistiques exploitables, RFC 7465)
+	{0xc007, "TLS_ECDHE_ECDSA_WITH_RC4_128_SHA", 128},
+	{0xc011, "TLS_ECDHE_RSA_WITH_RC4_128_SHA", 128},
+	{0x0005, "TLS_RSA_WITH_RC4_128_SHA", 128},
+	{0x0004, "TLS_RSA_WITH_RC4_128_MD5", 128},
+
+	// DES simple et suites EXPORT (clés de 40 à 56 bits : FREAK, Logjam)
+	{0x0015, "TLS_DHE_RSA_WITH_DES_CBC_SHA", 56},
+	{0x0009, "TLS_RSA_WITH_DES_CBC_SHA", 56},
+	{0x0014, "TLS_DHE_RSA_EXPORT_WITH_DES40_CBC_SHA", 40},
+	{0x0008, "TLS_RSA_EXPORT_WITH_DES40_CBC_SHA", 40},
+	{0x0006, "TLS_RSA_EXPORT_WITH_RC2_CBC_40_MD5", 40},
+	{0x0003, "TLS_RSA_EXPORT_WITH_RC4_40_MD5", 40},
+
+	// Anonymes (aucune authentification du serveur : interception triviale)
+	{0xc018, "TLS_ECDH_anon_WITH_AES_128_CBC_SHA", 128},
+	{0xc019, "TLS_ECDH_anon_WITH_AES_256_CBC_SHA", 256},
+	{0x0034, "TLS_DH_anon_WITH_AES_128_CBC_SHA", 128},
+	{0x003a, "TLS_DH_anon_WITH_AES_256_CBC_SHA", 256},
+	{0x0018, "TLS_DH_anon_WITH_RC4_128_MD5", 128},
+
+	// NULL (aucun chiffrement)
+	{0xc010, "TLS_ECDHE_RSA_WITH_NULL_SHA", 0},
+	{0x003b, "TLS_RSA_WITH_NULL_SHA256", 0},
+	{0x0002, "TLS_RSA_WITH_NULL_SHA", 0},
+	{0x0001, "TLS_RSA_WITH_NULL_MD5", 0},
+}
+
+// Suites retourne les suites sondées pour une version : les 5 suites TLS 1.3, ou toutes les autres
+func Suites(version uint16) []Suite {
+	var out []Suite
+	for _, s := range suites {
+		if s.TLS13() == (version == VersionTLS13) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// SuiteByID retourne la suite d'identifiant id ; une suite absente de la table garde son code hexadécimal
+func SuiteByID(id uint16) Suite {
+	for _, s := range suites {
+		if s.ID == id {
+			return s
+		}
+	}
+	return Suite{ID: id, Name: fmt.Sprintf("0x%04X", id), Bits: -1}
+}
+
+// SuiteByName retourne la suite nommée name (ok = false si elle est absente de la table)
+func SuiteByName(name string) (Suite, bool) {
+	for _, s := range suites {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Suite{}, false
+}
+
+// TLS13 indique une suite TLS 1.3 (pas de "_WITH_" : l'échange de clés est négocié séparément)
+func (s Suite) TLS13() bool { return s.ID>>8 == 0x13 }
+
+// ForwardSecrecy indique un échange de clés éphémère : la clé privée du certificat ne suffit pas à déchiffrer le trafic enregistré
+func (s Suite) ForwardSecrecy() bool {
+	return s.TLS13() || strings.HasPrefix(s.Name, "TLS_ECDHE_") || strings.HasPrefix(s.Name, "TLS_DHE_")
+}
+
+// DHE indique un échange Diffie-Hellman éphémère classique (paramètres choisis par le serveur)
+func (s Suite) DHE() bool { return strings.HasPrefix(s.Name, "TLS_DHE_") }
+
+// Anonymous indique une suite sans authentification du serveur
+func (s Suite) Anonymous() bool { return strings.Contains(s.Name, "_anon_") }
+
+// Export indique une suite bridée à 40 bits par l'ancienne réglementation américaine
+func (s Suite) Export() bool { return strings.Contains(s.Name, "_EXPORT_") }
+
+// Null indique une suite sans chiffrement
+func (s Suite) Null() bool { return strings.Contains(s.Name, "_WITH_NULL_") }
+
+// RC4 indique un chiffrement RC4
+func (s Suite) RC4() bool { return strings.Contains(s.Name, "_RC4_") }
+
+// DES indique un DES simple (56 bits), hors 3DES
+func (s Suite) DES() bool {
+	return strings.Contains(s.Name, "_DES_") || strings.Contains(s.Name, "_DES40_")
+}
+
+// TripleDES indique un 3DES (blocs de 64 bits)
+func (s Suite) TripleDES() bool { return strings.Contains(s.Name, "_3DES_") }
+
+// CBC indique un chiffrement par blocs en mode CBC (BEAST en TLS 1.0, Lucky13)
+func (s Suite) CBC() bool { return strings.Contains(s.Name, "_CBC") }
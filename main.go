@@ -1,12 +1,27 @@
 package main
 
 import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/daviani/go__001/internal/api"
+	"github.com/daviani/go__001/internal/dnsclient"
+	"github.com/daviani/go__001/internal/hstspreload"
+	"github.com/daviani/go__001/internal/jobs"
+	"github.com/daviani/go__001/internal/notify"
 	"github.com/daviani/go__001/internal/scanner"
+	"github.com/daviani/go__001/internal/schedule"
+	"github.com/daviani/go__001/internal/store"
 	"github.com/joho/godotenv"
 )
 
@@ -23,12 +38,37 @@ func init() {
 // @host            localhost:8082
 // @BasePath        /
 func main() {
-	// Initialisation des scanners (structs vides qui implémentent l'interface Scanner)
-	dns := scanner.DNSScanner{}
-	ssl := scanner.SSLScanner{}
+	// DNS_RESOLVERS : résolveurs des scanners DNS, sous-domaines, Email et CAA (ex: "1.1.1.1,9.9.9.9:53") — vide = /etc/resolv.conf
+	resolvers, err := dnsclient.ParseServers(strings.Split(os.Getenv("DNS_RESOLVERS"), ","))
+	if err != nil {
+		log.Fatal("DNS_RESOLVERS invalide : ", err)
+	}
+
+	// TLS_ROOT_CAS : fichier PEM de racines ajoutées au magasin du système (AC internes) pour vérifier les chaînes
+	rootCAs, err := loadRootCAs(os.Getenv("TLS_ROOT_CAS"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// HSTS_PRELOAD_LIST : preload list complète de Chromium (transport_security_state_static.json) — vide = snapshot embarqué
+	var preload *hstspreload.List
+	if path := os.Getenv("HSTS_PRELOAD_LIST"); path != "" {
+		if preload, err = hstspreload.Load(path); err != nil {
+			log.Fatal("HSTS_PRELOAD_LIST invalide : ", err)
+		}
+	}
+
+	// Initialisation des scanners (structs qui implémentent l'interface Scanner)
+	dnsClient := &dnsclient.Client{Servers: resolvers}
+	dns := scanner.DNSScanner{DNS: dnsClient}
+	ssl := scanner.SSLScanner{RootCAs: rootCAs}
 	header := scanner.HeaderScanner{}
-	subdomain := scanner.SubdomainScanner{}
+	subdomain := scanner.SubdomainScanner{DNS: dnsClient}
 	sensitive := scanner.SensitiveScanner{}
+	email := scanner.EmailScanner{Resolver: dnsClient}
+	caa := scanner.CAAScanner{DNS: dnsClient}
+	cors := scanner.CORSScanner{}
+	transport := scanner.TransportScanner{Preload: preload}
 	port := os.Getenv("PORT")
 
 	if port == "" {
@@ -38,9 +78,166 @@ func main() {
 	if err != nil {
 		log.Fatal("PORT invalide : " + port)
 	}
-	// Slice contenant tous les scanners - on peut en ajouter autant qu'on veut
-	scanners := []scanner.Scanner{dns, ssl, header, subdomain, sensitive}
+	// Registre des scanners - on peut en ajouter autant qu'on veut
+	// Chaque scanner enregistré obtient sa route /scan/<nom>, sa doc Swagger et sa place dans le front
+	scanners := scanner.NewRegistry(dns, ssl, header, subdomain, sensitive, email, caa, cors, transport)
+
+	// SCAN_TIMEOUT : deadline d'un scan complet (ex: "60s") — vide = défaut du serveur
+	var scanTimeout time.Duration
+	if raw := os.Getenv("SCAN_TIMEOUT"); raw != "" {
+		scanTimeout, err = time.ParseDuration(raw)
+		if err != nil {
+			log.Fatal("SCAN_TIMEOUT invalide : " + raw)
+		}
+	}
+
+	// SCANNER_TIMEOUTS : timeout par scanner (ex: "subdomain=45s,ssl=10s")
+	scannerTimeouts, err := parseTimeouts(os.Getenv("SCANNER_TIMEOUTS"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// SCAN_WORKERS : nombre de scanners exécutés en parallèle (tous scans confondus)
+	// SCAN_QUEUE : nombre de scanners en attente avant de répondre 503
+	workers, err := envInt("SCAN_WORKERS")
+	if err != nil {
+		log.Fatal(err)
+	}
+	queueSize, err := envInt("SCAN_QUEUE")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// DB_PATH : fichier SQLite de l'historique des scans (créé s'il n'existe pas)
+	dbPath := os.Getenv("DB_PATH")
+	if dbPath == "" {
+		dbPath = "gosentry.db"
+	}
+	history, err := store.OpenSQLite(dbPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer history.Close()
+
+	// SIGINT (Ctrl+C) ou SIGTERM (docker stop) annulent ctx : le serveur s'arrête proprement
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Webhooks : les livraisons interrompues par un arrêt du serveur sont reprises au démarrage
+	notifier := notify.New(history, notify.Config{})
+	if err := notifier.Start(ctx); err != nil {
+		log.Fatal(err)
+	}
+
+	manager := jobs.NewManager(jobs.Config{
+		Workers:         workers,
+		QueueSize:       queueSize,
+		MaxJobScanners:  scanners.Len(),
+		ScannerTimeouts: scannerTimeouts,
+		// Chaque job terminé (synchrone, asynchrone ou streamé) est enregistré dans l'historique
+		OnFinish: onScanFinished(history, notifier),
+	})
+
+	server := &api.Server{
+		Port:        portInt,
+		Scanners:    scanners,
+		Jobs:        manager,
+		ScanTimeout: scanTimeout,
+		Store:       history,
+		Webhooks:    notifier,
+	}
+
+	// SCHEDULE_CONCURRENCY : nombre de scans planifiés lancés en même temps
+	concurrency, err := envInt("SCHEDULE_CONCURRENCY")
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Le scheduler lance ses scans via le serveur : mêmes validations et même historique que POST /scans
+	server.Schedules = schedule.New(history, server.LaunchSchedule, schedule.Config{MaxConcurrent: concurrency})
+	if err := server.Schedules.Start(ctx); err != nil {
+		log.Fatal(err)
+	}
+
+	// Retour de Start = arrêt demandé : main se termine normalement et history.Close (defer) s'exécute
+	if err := server.Start(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Println(err)
+	}
+}
+
+// onScanFinished enregistre un job terminé puis notifie les webhooks de ses findings nouveaux ou aggravés
+// Chaque scanner est comparé à son dernier run enregistré ; sans précédent, tous ses findings sont nouveaux
+func onScanFinished(history *store.SQLite, notifier *notify.Notifier) func(jobs.Snapshot) {
+	return func(snap jobs.Snapshot) {
+		ctx := context.Background()
+		if err := history.SaveScan(ctx, snap); err != nil {
+			log.Println(err)
+			return
+		}
 
-	server := api.Server{Port: portInt, Scanners: scanners}
-	server.Start()
+		reference, err := history.PreviousRuns(ctx, snap)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		if err := notifier.ScanFinished(ctx, reference, snap); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// envInt lit une variable d'environnement entière (0 si absente → valeur par défaut du composant)
+func envInt(name string) (int, error) {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s invalide : %s", name, raw)
+	}
+	return n, nil
+}
+
+// loadRootCAs retourne le magasin de certificats du système complété par les racines du fichier PEM path
+// path vide → nil : le scanner utilise directement le magasin du système
+func loadRootCAs(path string) (*x509.CertPool, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("TLS_ROOT_CAS invalide : %w", err)
+	}
+	// Magasin du système indisponible (Windows anciens, conteneurs minimaux) → racines du fichier seules
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("TLS_ROOT_CAS invalide : aucun certificat PEM dans %s", path)
+	}
+	return pool, nil
+}
+
+// parseTimeouts parse une liste "nom=durée" séparée par des virgules
+// Ex: "subdomain=45s,ssl=10s" → map[subdomain:45s ssl:10s]
+func parseTimeouts(raw string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration)
+	if raw == "" {
+		return timeouts, nil
+	}
+
+	for _, pair := range strings.Split(raw, ",") {
+		// strings.Cut coupe au premier "=" — ok vaut false s'il n'y en a pas
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, fmt.Errorf("SCANNER_TIMEOUTS invalide : %q (format nom=durée)", pair)
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("SCANNER_TIMEOUTS invalide : durée %q pour %s", value, name)
+		}
+		timeouts[name] = d
+	}
+	return timeouts, nil
 }
@@ -3,21 +3,53 @@ import ScanForm from "./components/ScanForm.tsx";
 import {Toaster, toaster} from "./components/ui/toaster.tsx";
 import {useState} from "react";
 import ScanResults from "./components/ScanResults.tsx";
-import {scanDomain, type ScanResult} from "./services/scanner.ts";
+import ScanDiff from "./components/ScanDiff.tsx";
+import {getDiff, scanDomain, streamScan, type ScanDiff as Diff, type ScanResult} from "./services/scanner.ts";
 import {Box} from "@chakra-ui/react";
 
 function App() {
     const [results, setResults] = useState<ScanResult[]>([])
     const [loading, setLoading] = useState(false)
+    const [diff, setDiff] = useState<Diff | null>(null)
+
+    // Le diff est secondaire : en cas d'erreur on ne l'affiche simplement pas
+    const loadDiff = async (domain: string) => {
+        try {
+            setDiff(await getDiff(domain))
+        } catch (e) {
+            console.error("Diff failed:", e)
+        }
+    }
 
     const handleScan = async (
         domain:string,
         scanType:string
     ) => {
         setLoading(true)
+        setResults([])
+        setDiff(null)
+
+        // "all" → flux SSE : chaque scanner s'affiche dès qu'il a terminé
+        if (scanType === "all") {
+            streamScan(
+                domain,
+                (result) => setResults((prev) => [...prev, result]),
+                () => {
+                    setLoading(false)
+                    loadDiff(domain)
+                },
+                () => {
+                    toaster.create({ title: "Erreur lors du scan", type: "error" })
+                    setLoading(false)
+                },
+            )
+            return
+        }
+
         try {
             const data = await scanDomain(domain, scanType);
             setResults(data);
+            loadDiff(domain)
         } catch (e) {
             console.error("Scan failed:", e)
             toaster.create({ title: "Erreur lors du scan", type: "error" })
@@ -29,6 +61,7 @@ function App() {
         <Box bg="bg.page" minH="100vh">
             <Header />
             <ScanForm onScan={handleScan} />
+            {diff && <ScanDiff diff={diff} />}
             <ScanResults results={results} loading={loading} />
             <Toaster />
         </Box>
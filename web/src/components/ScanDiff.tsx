@@ -0,0 +1,59 @@
+import {Badge, Card, Flex, Text} from "@chakra-ui/react";
+import type {Change, ChangeKind, ScanDiff as Diff} from "../services/scanner.ts";
+
+// Libellé et couleur du badge par nature de changement (palette Nord du thème)
+const kindLabel: Record<ChangeKind, string> = {
+    added: "nouveau",
+    removed: "disparu",
+    changed: "modifié",
+}
+
+const kindColor: Record<ChangeKind, string> = {
+    added: "nord.yellow",
+    removed: "nord.frost2",
+    changed: "nord.orange",
+}
+
+function ChangeRow({ change }: { change: Change }) {
+    const finding = change.after ?? change.before
+    return (
+        <Flex direction="column" gap="1" py="2" borderBottomWidth="1px" borderColor="nord.polar3">
+            <Flex gap="2" align="center">
+                <Badge bg={kindColor[change.kind]} color="nord.polar0">{kindLabel[change.kind]}</Badge>
+                {change.worsened && <Badge bg="nord.red" color="nord.polar0">aggravé</Badge>}
+                <Text fontWeight="bold">{finding?.title ?? change.id}</Text>
+            </Flex>
+            <Text color="text.muted" fontSize="sm" wordBreak="break-all">{change.scanner} · {change.asset}</Text>
+            {change.kind === "changed" &&
+                <Text fontSize="sm" wordBreak="break-all">
+                    {change.before?.severity} → {change.after?.severity} : {change.before?.evidence} → {change.after?.evidence}
+                </Text>
+            }
+        </Flex>
+    )
+}
+
+function ScanDiff({ diff }: { diff: Diff }) {
+    const { added, removed, changed } = diff.summary
+    return (
+        <Flex direction="column" maxW="600px" mx="auto" px="8">
+            <Card.Root bg="bg.card" borderColor="nord.polar3">
+                <Card.Header textAlign="center">Depuis le scan précédent :</Card.Header>
+                <Card.Body>
+                    <Text color="text.muted" fontSize="sm" mb="2">
+                        {added} nouveau(x), {removed} disparu(s), {changed} modifié(s)
+                    </Text>
+                    {diff.changes.length === 0 && <Text color="text.muted">Aucun changement</Text>}
+                    {diff.changes.map((c, i) => (
+                        <ChangeRow key={`${c.kind}-${c.id}-${c.asset}-${i}`} change={c} />
+                    ))}
+                    {diff.skipped.length > 0 &&
+                        <Text color="text.muted" fontSize="sm" mt="2">Non comparés : {diff.skipped.join(", ")}</Text>
+                    }
+                </Card.Body>
+            </Card.Root>
+        </Flex>
+    )
+}
+
+export default ScanDiff;
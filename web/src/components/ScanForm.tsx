@@ -1,11 +1,20 @@
 import {Button, Flex, Input, NativeSelect, Text} from "@chakra-ui/react";
-import {useState} from "react";
+import {useEffect, useState} from "react";
 import {isValidDomain, isValidExtension} from "../utils/validation.ts";
 import {toaster} from "./ui/toaster.tsx";
+import {listScanners, type ScannerDescriptor} from "../services/scanner.ts";
 
 function ScanForm({ onScan }: { onScan: (domain: string, scanType: string) => void }) {
     const [domain, setDomain] = useState("")
     const [scanType, setScanType] = useState("all")
+    const [scanners, setScanners] = useState<ScannerDescriptor[]>([])
+
+    // Le menu reflète le registre du serveur : un nouveau scanner Go apparaît sans modifier le front
+    useEffect(() => {
+        listScanners()
+            .then(setScanners)
+            .catch((e) => console.error("Chargement des scanners impossible:", e))
+    }, [])
 
     const handleClick = () => {
         const trimmed = domain.trim()
@@ -37,11 +46,9 @@ function ScanForm({ onScan }: { onScan: (domain: string, scanType: string) => vo
                     borderColor="nord.polar3" color="text.main"
                 >
                     <option value="all">Tous les scanners</option>
-                    <option value="dns">DNS</option>
-                    <option value="ssl">SSL/TLS</option>
-                    <option value="header">Headers HTTP</option>
-                    <option value="subdomain">Sous-domaines</option>
-                    <option value="sensitive">Fichiers sensibles</option>
+                    {scanners.map((s) => (
+                        <option key={s.name} value={s.name} title={s.description}>{s.title}</option>
+                    ))}
                 </NativeSelect.Field>
             </NativeSelect.Root>
             <Button disabled={domain.trim() === ""} bg="accent" color="nord.polar0" onClick={handleClick}>Scanner</Button>
@@ -1,24 +1,51 @@
-import {Card, Flex, Spinner} from "@chakra-ui/react";
-import type {ScanResult} from "../services/scanner.ts";
+import {Badge, Card, Flex, Spinner, Text} from "@chakra-ui/react";
+import type {Finding, ScanResult, Severity} from "../services/scanner.ts";
 
+// Couleur du badge par sévérité (palette Nord du thème)
+const severityColor: Record<Severity, string> = {
+    info: "nord.frost2",
+    low: "nord.green",
+    medium: "nord.yellow",
+    high: "nord.orange",
+    critical: "nord.red",
+}
+
+function FindingRow({ finding }: { finding: Finding }) {
+    return (
+        <Flex direction="column" gap="1" py="2" borderBottomWidth="1px" borderColor="nord.polar3">
+            <Flex gap="2" align="center">
+                <Badge bg={severityColor[finding.severity]} color="nord.polar0">{finding.severity}</Badge>
+                <Text fontWeight="bold">{finding.title}</Text>
+            </Flex>
+            <Text color="text.muted" fontSize="sm" wordBreak="break-all">{finding.evidence}</Text>
+            {finding.remediation && <Text fontSize="sm">→ {finding.remediation}</Text>}
+        </Flex>
+    )
+}
 
 function ScanResults({ results, loading }: { results: ScanResult[], loading: boolean }) {
     return (
         <Flex direction="column" gap="4" maxW="600px" mx="auto" p="8" >
-            {loading ?
-                <Spinner mx="auto"  display="block" color="accent" />
-                :
+            {/* En streaming, les résultats arrivent pendant le chargement → affichés au-dessus du spinner */}
+            {(!loading || results.length > 0) &&
                 <Flex direction="column" gap="4">
                     {results.map((r) => (
                         <Card.Root key={r.scanner} bg="bg.card" borderColor="nord.polar3" >
                             <Card.Header textAlign="center">{r.scanner} :</Card.Header>
-                            <Card.Body>{r.result}</Card.Body>
+                            <Card.Body>
+                                {r.error && <Text color={r.partial ? "warning" : "error"}>{r.error}</Text>}
+                                {!r.error && r.findings.length === 0 && <Text color="text.muted">Aucun constat</Text>}
+                                {r.findings.map((f, i) => (
+                                    <FindingRow key={`${f.id}-${f.asset}-${i}`} finding={f} />
+                                ))}
+                            </Card.Body>
                         </Card.Root>
                     ))}
                 </Flex>
             }
+            {loading && <Spinner mx="auto"  display="block" color="accent" />}
         </Flex>
     )
 }
 
-export default ScanResults;
\ No newline at end of file
+export default ScanResults;
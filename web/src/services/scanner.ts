@@ -1,9 +1,83 @@
 const API_URL = "http://localhost:8082"
 
+export type Severity = "info" | "low" | "medium" | "high" | "critical"
+
+export interface Finding {
+    id: string
+    title: string
+    severity: Severity
+    category: string
+    evidence: string
+    asset: string
+    remediation?: string
+}
+
 export interface ScanResult {
     scanner: string
     domain: string
-    result: string
+    findings: Finding[]
+    data?: unknown
+    partial?: boolean
+    error?: string
+}
+
+export interface ScannerOption {
+    name: string
+    type: string
+    description: string
+    default?: unknown
+    enum?: string[]
+}
+
+export interface ScannerDescriptor {
+    name: string
+    title: string
+    description: string
+    version: string
+    options: ScannerOption[]
+}
+
+export type ChangeKind = "added" | "removed" | "changed"
+
+export interface Change {
+    kind: ChangeKind
+    scanner: string
+    id: string
+    asset: string
+    before?: Finding
+    after?: Finding
+    worsened: boolean
+}
+
+export interface ScanDiff {
+    domain: string
+    from: string
+    to: string
+    summary: { added: number, removed: number, changed: number, worsened: number }
+    changes: Change[]
+    skipped: string[]
+}
+
+// Liste des scanners enregistrés côté Go — alimente le menu déroulant du formulaire
+export async function listScanners(): Promise<ScannerDescriptor[]> {
+    const res = await fetch(`${API_URL}/scanners`)
+    if (!res.ok) {
+        throw new Error('Erreur serveur')
+    }
+    return res.json()
+}
+
+// Changements entre le dernier scan du domaine et le précédent
+// null si le domaine n'a pas encore deux scans dans l'historique (404)
+export async function getDiff(domain: string): Promise<ScanDiff | null> {
+    const res = await fetch(`${API_URL}/domains/${encodeURIComponent(domain)}/diff`)
+    if (res.status === 404) {
+        return null
+    }
+    if (!res.ok) {
+        throw new Error('Erreur serveur')
+    }
+    return res.json()
 }
 
 export async function scanDomain(domain: string, scanType: string): Promise<ScanResult[]> {
@@ -15,4 +89,49 @@ export async function scanDomain(domain: string, scanType: string): Promise<Scan
     }
     const data = await res.json()
     return Array.isArray(data) ? data : [data]
-}
\ No newline at end of file
+}
+
+// Run — exécution d'un scanner telle que renvoyée dans les événements scanner.finished / scanner.failed
+interface Run {
+    scanner: string
+    status: string
+    result?: { findings: Finding[], data?: unknown, partial?: boolean }
+    error?: string
+}
+
+// Scan de tous les scanners en Server-Sent Events : onResult est appelé dès qu'un scanner termine
+// Retourne une fonction qui ferme le flux (la fermeture annule le scan côté Go)
+export function streamScan(
+    domain: string,
+    onResult: (result: ScanResult) => void,
+    onDone: () => void,
+    onError: () => void,
+): () => void {
+    const params = new URLSearchParams({ domain })
+    const source = new EventSource(`${API_URL}/scan/all/stream?${params}`)
+
+    const handleRun = (e: MessageEvent) => {
+        const { run } = JSON.parse(e.data) as { run: Run }
+        onResult({
+            scanner: run.scanner,
+            domain,
+            findings: run.result?.findings ?? [],
+            data: run.result?.data,
+            partial: run.result?.partial,
+            error: run.error,
+        })
+    }
+    source.addEventListener("scanner.finished", handleRun)
+    source.addEventListener("scanner.failed", handleRun)
+    source.addEventListener("job.finished", () => {
+        source.close()
+        onDone()
+    })
+    // Sans close(), EventSource se reconnecte automatiquement et relancerait un scan
+    source.onerror = () => {
+        source.close()
+        onError()
+    }
+
+    return () => source.close()
+}